@@ -14,6 +14,14 @@ type Config struct {
 	Server   ServerConfig   `yaml:"server"`
 	Logging  LoggingConfig  `yaml:"logging"`
 	Redis    RedisConfig    `yaml:"redis"`
+	JWT      JWTConfig      `yaml:"jwt"`
+	Mail     MailConfig     `yaml:"mail"`
+	Admin    AdminConfig    `yaml:"admin"`
+	Privacy  PrivacyConfig  `yaml:"privacy"`
+	OIDC     OIDCConfig     `yaml:"oidc"`
+	Password PasswordConfig `yaml:"password"`
+	Upload   UploadConfig   `yaml:"upload"`
+	Storage  StorageConfig  `yaml:"storage"`
 }
 
 // DatabaseConfig 代表資料庫配置
@@ -30,8 +38,9 @@ type DatabaseConfig struct {
 
 // ServerConfig 代表伺服器配置
 type ServerConfig struct {
-	Port int    `yaml:"port"`
-	Mode string `yaml:"mode"`
+	Port    int    `yaml:"port"`
+	Mode    string `yaml:"mode"`
+	BaseURL string `yaml:"base_url"`
 }
 
 // LoggingConfig 代表日誌配置
@@ -48,6 +57,123 @@ type RedisConfig struct {
 	DB       int    `yaml:"db"`
 }
 
+// JWTConfig 代表 JWT 配置
+// 權杖以 keys 中的非對稱金鑰簽發；secret 僅用於衍生下載連結等 HMAC 簽章金鑰
+type JWTConfig struct {
+	Secret              string         `yaml:"secret"`
+	AccessExpiryMinutes int            `yaml:"access_expiry_minutes"`
+	RefreshExpiryDays   int            `yaml:"refresh_expiry_days"`
+	Issuer              string         `yaml:"issuer"`
+	SigningKeyID        string         `yaml:"signing_key_id"` // 留空時使用第一把含私鑰的金鑰
+	Keys                []JWTKeyConfig `yaml:"keys"`
+}
+
+// JWTKeyConfig 代表單一 JWT 簽章金鑰（Ed25519 或 RSA）
+// 只提供公鑰的金鑰僅用於驗證輪替前簽發的權杖
+type JWTKeyConfig struct {
+	ID             string `yaml:"id"`
+	PrivateKey     string `yaml:"private_key"` // PEM 內容，通常以環境變數提供
+	PrivateKeyFile string `yaml:"private_key_file"`
+	PublicKey      string `yaml:"public_key"`
+	PublicKeyFile  string `yaml:"public_key_file"`
+}
+
+// SigningKeys 返回展開環境變數（例如 "${JWT_SIGNING_KEY_ID}"）後的簽發金鑰 ID 與金鑰配置
+func (j *JWTConfig) SigningKeys() (string, []JWTKeyConfig) {
+	keys := make([]JWTKeyConfig, 0, len(j.Keys))
+	for _, key := range j.Keys {
+		keys = append(keys, JWTKeyConfig{
+			ID:             os.ExpandEnv(key.ID),
+			PrivateKey:     os.ExpandEnv(key.PrivateKey),
+			PrivateKeyFile: os.ExpandEnv(key.PrivateKeyFile),
+			PublicKey:      os.ExpandEnv(key.PublicKey),
+			PublicKeyFile:  os.ExpandEnv(key.PublicKeyFile),
+		})
+	}
+	return os.ExpandEnv(j.SigningKeyID), keys
+}
+
+// MailConfig 代表郵件發送配置
+type MailConfig struct {
+	Driver      string `yaml:"driver"` // smtp 或 log
+	SMTPHost    string `yaml:"smtp_host"`
+	SMTPPort    int    `yaml:"smtp_port"`
+	Username    string `yaml:"username"`
+	Password    string `yaml:"password"`
+	FromAddress string `yaml:"from_address"`
+	OutputDir   string `yaml:"output_dir"`
+}
+
+// AdminConfig 代表管理員相關配置
+// 初始管理員僅在系統中尚無管理員時使用，建議以環境變數提供
+type AdminConfig struct {
+	BootstrapEmail    string `yaml:"bootstrap_email"`
+	BootstrapPassword string `yaml:"bootstrap_password"`
+	RequireTwoFactor  bool   `yaml:"require_two_factor"` // 審核員與管理員必須啟用兩步驟驗證
+}
+
+// PrivacyConfig 代表個人資料相關配置
+type PrivacyConfig struct {
+	DeletionGraceDays int `yaml:"deletion_grace_days"` // 申請刪除帳號後的寬限天數，期間內可取消
+}
+
+// OIDCConfig 代表外部身分登入配置
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig `yaml:"providers"`
+}
+
+// OIDCProviderConfig 代表單一 OIDC 提供者配置
+// redirect_url 留空時使用 base_url 加上 /oidc/callback
+type OIDCProviderConfig struct {
+	Name         string   `yaml:"name"`
+	DisplayName  string   `yaml:"display_name"`
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	RedirectURL  string   `yaml:"redirect_url"`
+	Scopes       []string `yaml:"scopes"`
+}
+
+// PasswordConfig 代表密碼規則配置
+// 未設定的欄位使用預設值；min_strength 為 0-4 的強度評分，設為 0 表示不檢查
+type PasswordConfig struct {
+	MinLength      int    `yaml:"min_length"`
+	MaxLength      int    `yaml:"max_length"` // 不可超過 bcrypt 的 72 位元組
+	MinCharClasses *int   `yaml:"min_char_classes"`
+	MinStrength    *int   `yaml:"min_strength"`
+	RejectPersonal *bool  `yaml:"reject_personal_info"` // 不可包含 Email 或顯示名稱
+	BreachedDir    string `yaml:"breached_dir"`         // 依前綴分檔的完整外洩密碼清單目錄，留空時僅使用內建清單
+}
+
+// UploadConfig 代表檔案上傳配置
+// 未設定的欄位使用預設值；上傳的照片一律重新編碼，原圖最長邊超過 max_image_dimension 時等比例縮小
+type UploadConfig struct {
+	MaxFileSizeMB     int `yaml:"max_file_size_mb"`
+	MaxImageDimension int `yaml:"max_image_dimension"`
+	JPEGQuality       int `yaml:"jpeg_quality"`
+
+	// DuplicateMaxDistance 感知雜湊漢明距離在此以內視為相同圖片（64 位元，預設 10）
+	DuplicateMaxDistance int `yaml:"duplicate_max_distance"`
+
+	// MaxPhotosPerUser 每位用戶可保存的照片數量（預設 6），MaxPhotosByTier 依訂閱方案覆寫
+	MaxPhotosPerUser int            `yaml:"max_photos_per_user"`
+	MaxPhotosByTier  map[string]int `yaml:"max_photos_by_tier"`
+}
+
+// StorageConfig 代表上傳檔案儲存配置
+// driver 為 local 或 s3；多個應用副本時必須使用 s3（AWS S3、MinIO 等 S3 相容服務），金鑰建議以環境變數提供
+type StorageConfig struct {
+	Driver           string `yaml:"driver"`
+	LocalDir         string `yaml:"local_dir"` // 留空時使用伺服器上傳目錄
+	URLExpiryMinutes int    `yaml:"url_expiry_minutes"`
+	S3Endpoint       string `yaml:"s3_endpoint"`
+	S3Region         string `yaml:"s3_region"`
+	S3Bucket         string `yaml:"s3_bucket"`
+	S3AccessKeyID    string `yaml:"s3_access_key_id"`
+	S3SecretKey      string `yaml:"s3_secret_access_key"`
+	S3UsePathStyle   bool   `yaml:"s3_use_path_style"`
+}
+
 // GetDSN 建構資料庫連線字串
 func (db *DatabaseConfig) GetDSN() string {
 	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=%t&loc=%s",
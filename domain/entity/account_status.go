@@ -0,0 +1,34 @@
+package entity
+
+// AccountStatus 帳號停權狀態枚舉
+type AccountStatus string
+
+const (
+	AccountStatusActive    AccountStatus = "active"    // 正常
+	AccountStatusSuspended AccountStatus = "suspended" // 暫停（有期限）
+	AccountStatusBanned    AccountStatus = "banned"    // 永久封禁
+)
+
+// IsValid 檢查帳號狀態是否有效
+func (s AccountStatus) IsValid() bool {
+	switch s {
+	case AccountStatusActive, AccountStatusSuspended, AccountStatusBanned:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetDisplayName 獲取帳號狀態的顯示名稱
+func (s AccountStatus) GetDisplayName() string {
+	switch s {
+	case AccountStatusActive:
+		return "正常"
+	case AccountStatusSuspended:
+		return "暫停中"
+	case AccountStatusBanned:
+		return "已封禁"
+	default:
+		return string(s)
+	}
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// BlockedImage 禁用圖片清單項目
+// 以感知雜湊記錄已知的盜用或違規圖片，新上傳的照片與清單相似時會標記待審核
+type BlockedImage struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	PerceptualHash uint64    `gorm:"not null;index" json:"-"`
+	HashBand0      uint16    `gorm:"not null;default:0;index" json:"-"` // 感知雜湊區段，由 SyncHashBands 設定
+	HashBand1      uint16    `gorm:"not null;default:0;index" json:"-"`
+	HashBand2      uint16    `gorm:"not null;default:0;index" json:"-"`
+	HashBand3      uint16    `gorm:"not null;default:0;index" json:"-"`
+	Reason         string    `gorm:"not null;size:255" json:"reason"`
+	SourcePhotoID  *uint     `json:"source_photo_id,omitempty"` // 加入清單時依據的照片，照片刪除後仍保留雜湊
+	CreatedBy      uint      `gorm:"not null" json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// SyncHashBands 依感知雜湊設定區段欄位
+func (b *BlockedImage) SyncHashBands() {
+	bands := HashBands(b.PerceptualHash)
+	b.HashBand0, b.HashBand1, b.HashBand2, b.HashBand3 = bands[0], bands[1], bands[2], bands[3]
+}
+
+// Validate 驗證禁用圖片資料
+func (b *BlockedImage) Validate() error {
+	b.Reason = strings.TrimSpace(b.Reason)
+	if b.Reason == "" {
+		return errors.New("加入禁用清單必須提供原因")
+	}
+	if len(b.Reason) > 255 {
+		return errors.New("原因長度不能超過255字元")
+	}
+	if b.CreatedBy == 0 {
+		return errors.New("建立者ID不能為空")
+	}
+	return nil
+}
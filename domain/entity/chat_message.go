@@ -50,6 +50,7 @@ type ChatMessage struct {
 	FileName *string `gorm:"size:255" json:"file_name,omitempty"`
 	FileSize *int64  `json:"file_size,omitempty"`
 	FilePath *string `gorm:"size:500" json:"file_path,omitempty"`
+	FileURL  *string `gorm:"-" json:"file_url,omitempty"` // 有期限的下載連結，讀取時由服務層簽發，不儲存
 
 	// 時間戳記
 	CreatedAt   time.Time  `json:"created_at"`
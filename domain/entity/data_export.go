@@ -0,0 +1,50 @@
+package entity
+
+import "time"
+
+// DataExportStatus 個人資料匯出狀態枚舉
+type DataExportStatus string
+
+const (
+	DataExportStatusPending    DataExportStatus = "pending"    // 等待處理
+	DataExportStatusProcessing DataExportStatus = "processing" // 處理中
+	DataExportStatusCompleted  DataExportStatus = "completed"  // 可下載
+	DataExportStatusFailed     DataExportStatus = "failed"     // 處理失敗
+	DataExportStatusExpired    DataExportStatus = "expired"    // 已過期並刪除封存檔
+)
+
+// IsInProgress 檢查匯出是否仍在處理中
+func (s DataExportStatus) IsInProgress() bool {
+	return s == DataExportStatusPending || s == DataExportStatusProcessing
+}
+
+// DataExport 個人資料匯出記錄
+// 封存檔由背景工作產生，完成後在有效期限內可透過簽名連結下載
+type DataExport struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	UserID      uint             `gorm:"not null;index" json:"user_id"`
+	Status      DataExportStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	FileName    string           `gorm:"type:varchar(255)" json:"-"` // 封存檔在儲存區中的名稱
+	FileSize    int64            `gorm:"not null;default:0" json:"file_size"`
+	Error       string           `gorm:"type:varchar(500)" json:"error,omitempty"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time       `gorm:"index" json:"expires_at,omitempty"`
+
+	// 背景工作認領資訊，處理逾時的記錄重新排入佇列，超過次數上限則標記失敗
+	ProcessingStartedAt *time.Time `gorm:"index" json:"-"`
+	Attempts            int        `gorm:"not null;default:0" json:"-"`
+
+	// 時間戳記
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定資料表名稱
+func (DataExport) TableName() string {
+	return "data_exports"
+}
+
+// IsDownloadable 檢查封存檔在指定時間是否可下載
+func (e *DataExport) IsDownloadable(now time.Time) bool {
+	return e.Status == DataExportStatusCompleted && e.ExpiresAt != nil && now.Before(*e.ExpiresAt)
+}
@@ -2,10 +2,18 @@ package entity
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 )
 
+// 興趣標籤限制
+const (
+	MaxUserInterests      = 10  // 每位用戶最多選擇的興趣數
+	MaxInterestNameLength = 100 // 名稱、在地化名稱與同義詞長度上限
+	MaxInterestSynonyms   = 20  // 每個興趣最多的同義詞數
+)
+
 // InterestCategory 興趣類別枚舉
 type InterestCategory string
 
@@ -85,6 +93,27 @@ func (ic InterestCategory) GetDisplayName() string {
 	}
 }
 
+// ParseInterestCategory 解析興趣類別，接受類別代碼或顯示名稱，例如 "music" 或 "音樂"
+func ParseInterestCategory(value string) (InterestCategory, bool) {
+	value = strings.TrimSpace(value)
+	category := InterestCategory(strings.ToLower(value))
+	if category.IsValid() {
+		return category, true
+	}
+
+	for _, candidate := range []InterestCategory{
+		InterestCategoryHobbies, InterestCategorySports, InterestCategoryMusic, InterestCategoryMovies,
+		InterestCategoryFood, InterestCategoryTravel, InterestCategoryReading, InterestCategoryTechnology,
+		InterestCategoryArt, InterestCategoryFitness, InterestCategoryGaming, InterestCategoryNature,
+		InterestCategoryOther,
+	} {
+		if candidate.GetDisplayName() == value {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
 // Interest 興趣標籤實體
 type Interest struct {
 	ID          uint             `gorm:"primaryKey" json:"id"`
@@ -92,7 +121,10 @@ type Interest struct {
 	Category    InterestCategory `gorm:"not null" json:"category"`
 	Description *string          `gorm:"size:300" json:"description,omitempty"`
 	IsActive    bool             `gorm:"default:true" json:"is_active"`
-	UsageCount  int              `gorm:"default:0" json:"usage_count"` // 使用此興趣的用戶數量
+	UsageCount  int              `gorm:"default:0" json:"usage_count"` // 使用此興趣的用戶數量，由 SetUserInterests 維護
+
+	Translations []InterestTranslation `gorm:"foreignKey:InterestID;constraint:OnDelete:CASCADE" json:"translations"`
+	Synonyms     []InterestSynonym     `gorm:"foreignKey:InterestID;constraint:OnDelete:CASCADE" json:"synonyms"`
 
 	// 時間戳記
 	CreatedAt time.Time `json:"created_at"`
@@ -102,13 +134,38 @@ type Interest struct {
 	// Users []UserProfile `gorm:"many2many:user_interests;" json:"users,omitempty"`
 }
 
+// InterestTranslation 興趣的在地化名稱
+type InterestTranslation struct {
+	ID         uint   `gorm:"primaryKey" json:"-"`
+	InterestID uint   `gorm:"not null;uniqueIndex:idx_interest_translations_interest_locale" json:"-"`
+	Locale     string `gorm:"not null;size:10;uniqueIndex:idx_interest_translations_interest_locale" json:"locale"` // 小寫 BCP 47 語系，例如 zh-tw、en
+	Name       string `gorm:"not null;size:100;index" json:"name"`
+}
+
+// InterestSynonym 興趣的同義詞，搜尋時與名稱一併比對
+type InterestSynonym struct {
+	ID         uint   `gorm:"primaryKey" json:"-"`
+	InterestID uint   `gorm:"not null;uniqueIndex:idx_interest_synonyms_interest_term" json:"-"`
+	Term       string `gorm:"not null;size:100;uniqueIndex:idx_interest_synonyms_interest_term;index" json:"term"`
+}
+
+// TableName 指定資料表名稱
+func (InterestTranslation) TableName() string {
+	return "interest_translations"
+}
+
+// TableName 指定資料表名稱
+func (InterestSynonym) TableName() string {
+	return "interest_synonyms"
+}
+
 // Validate 驗證興趣標籤資料
 func (i *Interest) Validate() error {
 	if strings.TrimSpace(i.Name) == "" {
 		return errors.New("name 是必填欄位")
 	}
 
-	if len(i.Name) > 100 {
+	if len(i.Name) > MaxInterestNameLength {
 		return errors.New("name 不能超過 100 字元")
 	}
 
@@ -116,6 +173,42 @@ func (i *Interest) Validate() error {
 		return errors.New("category 必須是有效的興趣類別")
 	}
 
+	seenLocales := make(map[string]bool, len(i.Translations))
+	for _, translation := range i.Translations {
+		if !languageCodePattern.MatchString(translation.Locale) {
+			return fmt.Errorf("無效的語系: %s", translation.Locale)
+		}
+		if seenLocales[translation.Locale] {
+			return fmt.Errorf("語系 %s 重複", translation.Locale)
+		}
+		seenLocales[translation.Locale] = true
+
+		if strings.TrimSpace(translation.Name) == "" {
+			return fmt.Errorf("語系 %s 的名稱不能為空", translation.Locale)
+		}
+		if len(translation.Name) > MaxInterestNameLength {
+			return fmt.Errorf("語系 %s 的名稱不能超過 %d 字元", translation.Locale, MaxInterestNameLength)
+		}
+	}
+
+	if len(i.Synonyms) > MaxInterestSynonyms {
+		return fmt.Errorf("同義詞最多 %d 個", MaxInterestSynonyms)
+	}
+	seenTerms := make(map[string]bool, len(i.Synonyms))
+	for _, synonym := range i.Synonyms {
+		term := strings.ToLower(synonym.Term)
+		if strings.TrimSpace(term) == "" {
+			return errors.New("同義詞不能為空")
+		}
+		if len(synonym.Term) > MaxInterestNameLength {
+			return fmt.Errorf("同義詞不能超過 %d 字元", MaxInterestNameLength)
+		}
+		if seenTerms[term] {
+			return fmt.Errorf("同義詞 %s 重複", synonym.Term)
+		}
+		seenTerms[term] = true
+	}
+
 	// 檢查描述長度（如果有提供）
 	if i.Description != nil && len(*i.Description) > 300 {
 		return errors.New("description 不能超過 300 字元")
@@ -129,6 +222,51 @@ func (i *Interest) Validate() error {
 	return nil
 }
 
+// LocalizedName 獲取指定語系的名稱
+// 依序嘗試完整語系、主要語言（zh-tw 取 zh），都沒有時使用標準名稱
+func (i *Interest) LocalizedName(locale string) string {
+	locale = NormalizeLocale(locale)
+	candidates := []string{locale}
+	if base, _, found := strings.Cut(locale, "-"); found {
+		candidates = append(candidates, base)
+	}
+
+	for _, candidate := range candidates {
+		for _, translation := range i.Translations {
+			if translation.Locale == candidate {
+				return translation.Name
+			}
+		}
+	}
+	return i.Name
+}
+
+// MatchesPrefix 檢查名稱、在地化名稱或同義詞是否以指定字首開頭，不分大小寫
+func (i *Interest) MatchesPrefix(prefix string) bool {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" {
+		return false
+	}
+
+	hasPrefix := func(value string) bool {
+		return strings.HasPrefix(strings.ToLower(value), prefix)
+	}
+	if hasPrefix(i.Name) {
+		return true
+	}
+	for _, translation := range i.Translations {
+		if hasPrefix(translation.Name) {
+			return true
+		}
+	}
+	for _, synonym := range i.Synonyms {
+		if hasPrefix(synonym.Term) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsActiveInterest 檢查興趣是否啟用
 func (i *Interest) IsActiveInterest() bool {
 	return i.IsActive
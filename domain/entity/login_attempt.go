@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// LoginFailureReason 登入失敗原因枚舉
+type LoginFailureReason string
+
+const (
+	LoginFailureInvalidCredentials  LoginFailureReason = "invalid_credentials"   // 帳號或密碼錯誤
+	LoginFailureThrottled           LoginFailureReason = "throttled"             // 嘗試過於頻繁被延遲
+	LoginFailureLocked              LoginFailureReason = "locked"                // 帳號或來源 IP 已鎖定
+	LoginFailureInvalidSecondFactor LoginFailureReason = "invalid_second_factor" // 第二步驗證碼或恢復碼錯誤
+)
+
+// LoginAttempt 登入嘗試記錄
+// 無論帳號是否存在都會記錄，用於稽核與追查撞庫攻擊
+type LoginAttempt struct {
+	ID            uint               `gorm:"primaryKey" json:"id"`
+	UserID        *uint              `gorm:"index" json:"user_id,omitempty"` // 信箱不屬於任何帳號時為 nil
+	Email         string             `gorm:"type:varchar(255);not null;index" json:"email"`
+	IPAddress     string             `gorm:"type:varchar(45);index" json:"ip_address"`
+	UserAgent     string             `gorm:"type:varchar(500)" json:"user_agent"`
+	Success       bool               `gorm:"not null;default:false" json:"success"`
+	FailureReason LoginFailureReason `gorm:"type:varchar(30)" json:"failure_reason,omitempty"`
+	CreatedAt     time.Time          `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定資料表名稱
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
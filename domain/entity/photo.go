@@ -1,23 +1,32 @@
 package entity
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math/bits"
 	"path/filepath"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+// MaxPhotoCaptionLength 照片說明長度上限（字元）
+const MaxPhotoCaptionLength = 200
+
 // PhotoType 照片類型枚舉
 type PhotoType string
 
 const (
 	PhotoTypeProfile PhotoType = "profile" // 個人檔案照片
 	PhotoTypeGallery PhotoType = "gallery" // 相簿照片
+	PhotoTypePrivate PhotoType = "private" // 私人相簿照片，只有獲得授權的配對對象可查看
 )
 
 // IsValid 檢查照片類型是否有效
 func (pt PhotoType) IsValid() bool {
-	return pt == PhotoTypeProfile || pt == PhotoTypeGallery
+	return pt == PhotoTypeProfile || pt == PhotoTypeGallery || pt == PhotoTypePrivate
 }
 
 // PhotoStatus 照片狀態枚舉
@@ -34,25 +43,118 @@ func (ps PhotoStatus) IsValid() bool {
 	return ps == PhotoStatusPending || ps == PhotoStatusApproved || ps == PhotoStatusRejected
 }
 
+// ThumbnailSize 縮圖尺寸名稱
+type ThumbnailSize string
+
+const (
+	ThumbnailSmall  ThumbnailSize = "small"  // 列表與聊天頭像
+	ThumbnailMedium ThumbnailSize = "medium" // 配對卡片
+	ThumbnailLarge  ThumbnailSize = "large"  // 檔案頁大圖
+)
+
+// ThumbnailSizes 各縮圖尺寸的最長邊像素，依尺寸由小到大排列
+var ThumbnailSizes = []struct {
+	Name      ThumbnailSize
+	MaxLength int
+}{
+	{ThumbnailSmall, 160},
+	{ThumbnailMedium, 480},
+	{ThumbnailLarge, 1080},
+}
+
+// PhotoThumbnails 縮圖尺寸對應的檔案路徑，以 JSON 字串儲存
+type PhotoThumbnails map[ThumbnailSize]string
+
+// Value 實作 driver.Valuer
+func (t PhotoThumbnails) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(map[ThumbnailSize]string(t))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 實作 sql.Scanner
+func (t *PhotoThumbnails) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("無法解析照片縮圖: %T", value)
+	}
+
+	*t = nil
+	if len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, (*map[ThumbnailSize]string)(t))
+}
+
+// HammingDistance 計算兩個感知雜湊不同的位元數，距離越小圖片越相似
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// HashBandCount 感知雜湊切成的區段數，每段 16 位元
+const HashBandCount = 4
+
+// HashBands 將感知雜湊由低位元起切成 16 位元區段
+// 兩個雜湊距離在 d 以內時，至少有一個區段的距離不超過 d / HashBandCount，可用區段索引預先篩選
+func HashBands(hash uint64) [HashBandCount]uint16 {
+	var bands [HashBandCount]uint16
+	for i := range bands {
+		bands[i] = uint16(hash >> (16 * i))
+	}
+	return bands
+}
+
 // Photo 用戶照片實體
 type Photo struct {
-	ID           uint        `gorm:"primaryKey" json:"id"`
-	UserID       uint        `gorm:"not null;index" json:"user_id"`
-	Type         PhotoType   `gorm:"not null" json:"type"`
-	FileName     string      `gorm:"not null;size:255" json:"file_name"`
-	FilePath     string      `gorm:"not null;size:500" json:"file_path"`
-	FileSize     int64       `gorm:"not null" json:"file_size"`
-	MimeType     string      `gorm:"not null;size:100" json:"mime_type"`
-	Width        int         `json:"width"`
-	Height       int         `json:"height"`
-	IsMain       bool        `gorm:"default:false" json:"is_main"`   // 是否為主照片
-	DisplayOrder int         `gorm:"default:0" json:"display_order"` // 顯示順序
-	Status       PhotoStatus `gorm:"not null;default:'pending'" json:"status"`
+	ID         uint            `gorm:"primaryKey" json:"id"`
+	UserID     uint            `gorm:"not null;index" json:"user_id"`
+	Type       PhotoType       `gorm:"not null" json:"type"`
+	FileName   string          `gorm:"not null;size:255" json:"file_name"`
+	FilePath   string          `gorm:"not null;size:500" json:"file_path"`
+	FileSize   int64           `gorm:"not null" json:"file_size"`
+	MimeType   string          `gorm:"not null;size:100" json:"mime_type"`
+	Width      int             `json:"width"`
+	Height     int             `json:"height"`
+	Thumbnails PhotoThumbnails `gorm:"type:text" json:"thumbnails,omitempty"` // 縮圖尺寸 -> 檔案路徑
+
+	// 感知雜湊（dHash），用於找出其他帳號重複使用的照片；舊照片沒有雜湊
+	PerceptualHash *uint64 `gorm:"index" json:"-"`
+	HashBand0      *uint16 `gorm:"index" json:"-"` // 感知雜湊區段，由 SyncHashBands 設定
+	HashBand1      *uint16 `gorm:"index" json:"-"`
+	HashBand2      *uint16 `gorm:"index" json:"-"`
+	HashBand3      *uint16 `gorm:"index" json:"-"`
+
+	// 有期限的下載連結，讀取時由服務層簽發，不儲存
+	URL           string                   `gorm:"-" json:"url,omitempty"`
+	ThumbnailURLs map[ThumbnailSize]string `gorm:"-" json:"thumbnail_urls,omitempty"`
+	IsMain        bool                     `gorm:"default:false" json:"is_main"`   // 是否為主照片
+	DisplayOrder  int                      `gorm:"default:0" json:"display_order"` // 顯示順序
+	Caption       string                   `gorm:"size:200" json:"caption"`        // 照片說明
+	Status        PhotoStatus              `gorm:"not null;default:'pending'" json:"status"`
 
 	// 審核資訊
 	ReviewerID  *uint   `gorm:"index" json:"reviewer_id,omitempty"`
 	ReviewNotes *string `gorm:"size:500" json:"review_notes,omitempty"`
 
+	// 自動偵測標記，例如疑似盜用他人照片，標記的照片在審核佇列中優先顯示
+	FlagReason string   `gorm:"size:50" json:"flag_reason,omitempty"`
+	FlagScore  *float64 `json:"flag_score,omitempty"` // 信心度（0-1）
+
+	// 審核佇列認領資訊，認領逾時後其他審核員可再認領
+	ClaimedBy *uint      `gorm:"index" json:"claimed_by,omitempty"`
+	ClaimedAt *time.Time `json:"claimed_at,omitempty"`
+
 	// 時間戳記
 	CreatedAt  time.Time  `json:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at"`
@@ -63,6 +165,16 @@ type Photo struct {
 	// Reviewer *User `gorm:"foreignKey:ReviewerID" json:"reviewer,omitempty"`
 }
 
+// SyncHashBands 依感知雜湊設定區段欄位，沒有雜湊時清除
+func (p *Photo) SyncHashBands() {
+	if p.PerceptualHash == nil {
+		p.HashBand0, p.HashBand1, p.HashBand2, p.HashBand3 = nil, nil, nil, nil
+		return
+	}
+	bands := HashBands(*p.PerceptualHash)
+	p.HashBand0, p.HashBand1, p.HashBand2, p.HashBand3 = &bands[0], &bands[1], &bands[2], &bands[3]
+}
+
 // Validate 驗證照片資料
 func (p *Photo) Validate() error {
 	if p.UserID == 0 {
@@ -70,7 +182,7 @@ func (p *Photo) Validate() error {
 	}
 
 	if !p.Type.IsValid() {
-		return errors.New("type 必須是 profile 或 gallery")
+		return errors.New("type 必須是 profile、gallery 或 private")
 	}
 
 	if strings.TrimSpace(p.FileName) == "" {
@@ -114,6 +226,14 @@ func (p *Photo) Validate() error {
 		return errors.New("display_order 不能為負數")
 	}
 
+	if utf8.RuneCountInString(p.Caption) > MaxPhotoCaptionLength {
+		return fmt.Errorf("caption 不能超過 %d 字", MaxPhotoCaptionLength)
+	}
+
+	if p.IsMain && !p.CanBeMain() {
+		return errors.New("只有已通過審核的個人檔案照片可以設為主要照片")
+	}
+
 	// 審核備註長度檢查
 	if p.ReviewNotes != nil && len(*p.ReviewNotes) > 500 {
 		return errors.New("review_notes 不能超過 500 字元")
@@ -124,6 +244,11 @@ func (p *Photo) Validate() error {
 
 // IsValidImageType 檢查是否為有效的圖片類型
 func (p *Photo) IsValidImageType() bool {
+	return IsValidImageMimeType(p.MimeType)
+}
+
+// IsValidImageMimeType 檢查 MIME 類型是否為允許上傳的圖片格式
+func IsValidImageMimeType(mimeType string) bool {
 	validTypes := []string{
 		"image/jpeg",
 		"image/jpg",
@@ -133,13 +258,24 @@ func (p *Photo) IsValidImageType() bool {
 	}
 
 	for _, validType := range validTypes {
-		if p.MimeType == validType {
+		if mimeType == validType {
 			return true
 		}
 	}
 	return false
 }
 
+// FilePaths 獲取照片原圖與所有縮圖的檔案路徑，用於刪除檔案
+func (p *Photo) FilePaths() []string {
+	paths := []string{p.FilePath}
+	for _, size := range ThumbnailSizes {
+		if path := p.Thumbnails[size.Name]; path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
 // GetFileExtension 獲取檔案副檔名
 func (p *Photo) GetFileExtension() string {
 	return strings.ToLower(filepath.Ext(p.FileName))
@@ -155,6 +291,26 @@ func (p *Photo) IsGalleryPhoto() bool {
 	return p.Type == PhotoTypeGallery
 }
 
+// IsPrivatePhoto 檢查是否為私人相簿照片
+func (p *Photo) IsPrivatePhoto() bool {
+	return p.Type == PhotoTypePrivate
+}
+
+// CanBeMain 檢查照片是否可作為主要照片，必須是已通過審核的個人檔案照片
+func (p *Photo) CanBeMain() bool {
+	return p.IsApproved() && p.IsProfilePhoto()
+}
+
+// SetCaption 設定照片說明，去除前後空白
+func (p *Photo) SetCaption(caption string) error {
+	caption = strings.TrimSpace(caption)
+	if utf8.RuneCountInString(caption) > MaxPhotoCaptionLength {
+		return fmt.Errorf("caption 不能超過 %d 字", MaxPhotoCaptionLength)
+	}
+	p.Caption = caption
+	return nil
+}
+
 // IsMainPhoto 檢查是否為主照片
 func (p *Photo) IsMainPhoto() bool {
 	return p.IsMain
@@ -204,6 +360,7 @@ func (p *Photo) Approve(reviewerID uint, notes string) error {
 	now := time.Now()
 	p.ReviewedAt = &now
 	p.UpdatedAt = now
+	p.ReleaseClaim()
 
 	return nil
 }
@@ -214,22 +371,35 @@ func (p *Photo) Reject(reviewerID uint, notes string) error {
 		return errors.New("照片已被拒絕")
 	}
 
-	p.Status = PhotoStatusRejected
-	p.ReviewerID = &reviewerID
-
 	cleanNotes := strings.TrimSpace(notes)
 	if cleanNotes == "" {
 		return errors.New("拒絕照片必須提供原因")
 	}
+
+	p.Status = PhotoStatusRejected
+	p.ReviewerID = &reviewerID
 	p.ReviewNotes = &cleanNotes
 
 	now := time.Now()
 	p.ReviewedAt = &now
 	p.UpdatedAt = now
+	p.ReleaseClaim()
 
 	return nil
 }
 
+// IsClaimedByOther 檢查照片是否由其他審核員認領中，認領時間早於 staleBefore 視為已逾時
+func (p *Photo) IsClaimedByOther(moderatorID uint, staleBefore time.Time) bool {
+	return p.ClaimedBy != nil && *p.ClaimedBy != moderatorID &&
+		p.ClaimedAt != nil && p.ClaimedAt.After(staleBefore)
+}
+
+// ReleaseClaim 釋出審核佇列認領
+func (p *Photo) ReleaseClaim() {
+	p.ClaimedBy = nil
+	p.ClaimedAt = nil
+}
+
 // UpdateDisplayOrder 更新顯示順序
 func (p *Photo) UpdateDisplayOrder(order int) error {
 	if order < 0 {
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// PrivateAlbumGrant 私人相簿授權實體
+// 照片擁有者授權特定配對對象查看私人相簿，取消配對或封鎖時自動撤銷
+type PrivateAlbumGrant struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	OwnerID   uint      `gorm:"not null;uniqueIndex:idx_album_grant_owner_viewer" json:"owner_id"`
+	ViewerID  uint      `gorm:"not null;uniqueIndex:idx_album_grant_owner_viewer;index" json:"viewer_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate 驗證私人相簿授權資料
+func (g *PrivateAlbumGrant) Validate() error {
+	if g.OwnerID == 0 {
+		return errors.New("owner_id 是必填欄位")
+	}
+	if g.ViewerID == 0 {
+		return errors.New("viewer_id 是必填欄位")
+	}
+	if g.OwnerID == g.ViewerID {
+		return errors.New("不能授權自己查看私人相簿")
+	}
+	return nil
+}
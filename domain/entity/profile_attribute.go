@@ -0,0 +1,377 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ProfileAttribute 檔案屬性名稱
+type ProfileAttribute string
+
+const (
+	AttributeHeight           ProfileAttribute = "height"
+	AttributeEducation        ProfileAttribute = "education"
+	AttributeOccupation       ProfileAttribute = "occupation"
+	AttributeRelationshipGoal ProfileAttribute = "relationship_goal"
+	AttributeChildren         ProfileAttribute = "children"
+	AttributeSmoking          ProfileAttribute = "smoking"
+	AttributeDrinking         ProfileAttribute = "drinking"
+	AttributeReligion         ProfileAttribute = "religion"
+	AttributeLanguages        ProfileAttribute = "languages"
+)
+
+// 屬性限制
+const (
+	MinHeightCm         = 100
+	MaxHeightCm         = 250
+	MaxOccupationLength = 100
+	MaxLanguages        = 10
+)
+
+// habitOptions 吸菸與飲酒共用的頻率選項
+var habitOptions = []string{"never", "socially", "regularly"}
+
+// attributeOptions 各選項型屬性可用的值
+var attributeOptions = map[ProfileAttribute][]string{
+	AttributeEducation:        {"high_school", "vocational", "bachelor", "master", "doctorate"},
+	AttributeRelationshipGoal: {"long_term", "short_term", "marriage", "friendship", "not_sure"},
+	AttributeChildren:         {"have", "want", "dont_want", "not_sure"},
+	AttributeSmoking:          habitOptions,
+	AttributeDrinking:         habitOptions,
+	AttributeReligion: {
+		"agnostic", "atheist", "buddhist", "catholic", "christian", "hindu",
+		"jewish", "muslim", "taoist", "spiritual", "other",
+	},
+}
+
+// languageCodePattern ISO 639 語言代碼，可附加地區，例如 zh-tw
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+// IsValid 檢查屬性名稱是否有效
+func (a ProfileAttribute) IsValid() bool {
+	switch a {
+	case AttributeHeight, AttributeOccupation, AttributeLanguages:
+		return true
+	}
+	_, ok := attributeOptions[a]
+	return ok
+}
+
+// IsFilterable 檢查屬性是否可作為配對條件；職業為自由文字，不提供篩選
+func (a ProfileAttribute) IsFilterable() bool {
+	return a.IsValid() && a != AttributeOccupation
+}
+
+// AttributeOptions 選項型屬性可用的值，供前端顯示選單
+func AttributeOptions() map[ProfileAttribute][]string {
+	options := make(map[ProfileAttribute][]string, len(attributeOptions))
+	for attribute, values := range attributeOptions {
+		options[attribute] = append([]string(nil), values...)
+	}
+	return options
+}
+
+// isAttributeOption 檢查值是否為屬性的有效選項
+func isAttributeOption(attribute ProfileAttribute, value string) bool {
+	if attribute == AttributeLanguages {
+		return languageCodePattern.MatchString(value)
+	}
+	for _, option := range attributeOptions[attribute] {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
+
+// StringList 字串清單，以逗號分隔的字串儲存以便以 FIND_IN_SET 查詢
+type StringList []string
+
+// Contains 檢查清單是否包含指定值
+func (l StringList) Contains(value string) bool {
+	for _, v := range l {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Value 實作 driver.Valuer
+func (l StringList) Value() (driver.Value, error) {
+	return strings.Join(l, ","), nil
+}
+
+// Scan 實作 sql.Scanner
+func (l *StringList) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+		raw = ""
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("無法解析字串清單: %T", value)
+	}
+
+	*l = nil
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*l = append(*l, part)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON 空清單輸出為空陣列
+func (l StringList) MarshalJSON() ([]byte, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(l))
+}
+
+// ProfileAttributes 用戶檔案的結構化屬性，未填寫的欄位為零值
+// HiddenAttributes 中的屬性不會顯示給其他用戶，也不會被他人的配對條件使用
+type ProfileAttributes struct {
+	Height           int        `json:"height,omitempty"` // 公分
+	Education        string     `gorm:"size:20" json:"education,omitempty"`
+	Occupation       string     `gorm:"size:100" json:"occupation,omitempty"`
+	RelationshipGoal string     `gorm:"size:20" json:"relationship_goal,omitempty"`
+	Children         string     `gorm:"size:20" json:"children,omitempty"`
+	Smoking          string     `gorm:"size:20" json:"smoking,omitempty"`
+	Drinking         string     `gorm:"size:20" json:"drinking,omitempty"`
+	Religion         string     `gorm:"size:20" json:"religion,omitempty"`
+	Languages        StringList `gorm:"size:100;not null;default:''" json:"languages"`
+	HiddenAttributes StringList `gorm:"size:200;not null;default:''" json:"hidden_attributes"`
+}
+
+// Validate 驗證屬性資料
+func (a *ProfileAttributes) Validate() error {
+	if a.Height != 0 && (a.Height < MinHeightCm || a.Height > MaxHeightCm) {
+		return fmt.Errorf("height 必須在 %d 到 %d 公分之間", MinHeightCm, MaxHeightCm)
+	}
+
+	if utf8.RuneCountInString(a.Occupation) > MaxOccupationLength {
+		return fmt.Errorf("occupation 不能超過 %d 字元", MaxOccupationLength)
+	}
+
+	for attribute, value := range map[ProfileAttribute]string{
+		AttributeEducation:        a.Education,
+		AttributeRelationshipGoal: a.RelationshipGoal,
+		AttributeChildren:         a.Children,
+		AttributeSmoking:          a.Smoking,
+		AttributeDrinking:         a.Drinking,
+		AttributeReligion:         a.Religion,
+	} {
+		if value != "" && !isAttributeOption(attribute, value) {
+			return fmt.Errorf("%s 的值無效: %s", attribute, value)
+		}
+	}
+
+	if len(a.Languages) > MaxLanguages {
+		return fmt.Errorf("languages 最多 %d 種", MaxLanguages)
+	}
+	for _, language := range a.Languages {
+		if !isAttributeOption(AttributeLanguages, language) {
+			return fmt.Errorf("無效的語言代碼: %s", language)
+		}
+	}
+
+	for _, hidden := range a.HiddenAttributes {
+		if !ProfileAttribute(hidden).IsValid() {
+			return fmt.Errorf("無效的屬性名稱: %s", hidden)
+		}
+	}
+
+	return nil
+}
+
+// Normalize 統一語言代碼為小寫並去除重複
+func (a *ProfileAttributes) Normalize() {
+	a.Occupation = strings.TrimSpace(a.Occupation)
+	a.Languages = uniqueLowercase(a.Languages)
+	a.HiddenAttributes = uniqueLowercase(a.HiddenAttributes)
+}
+
+// IsVisible 檢查屬性是否對其他用戶公開
+func (a *ProfileAttributes) IsVisible(attribute ProfileAttribute) bool {
+	return !a.HiddenAttributes.Contains(string(attribute))
+}
+
+// Public 其他用戶可見的屬性，隱藏的欄位清空
+func (a ProfileAttributes) Public() ProfileAttributes {
+	public := ProfileAttributes{Languages: StringList{}, HiddenAttributes: StringList{}}
+	if a.IsVisible(AttributeHeight) {
+		public.Height = a.Height
+	}
+	if a.IsVisible(AttributeEducation) {
+		public.Education = a.Education
+	}
+	if a.IsVisible(AttributeOccupation) {
+		public.Occupation = a.Occupation
+	}
+	if a.IsVisible(AttributeRelationshipGoal) {
+		public.RelationshipGoal = a.RelationshipGoal
+	}
+	if a.IsVisible(AttributeChildren) {
+		public.Children = a.Children
+	}
+	if a.IsVisible(AttributeSmoking) {
+		public.Smoking = a.Smoking
+	}
+	if a.IsVisible(AttributeDrinking) {
+		public.Drinking = a.Drinking
+	}
+	if a.IsVisible(AttributeReligion) {
+		public.Religion = a.Religion
+	}
+	if a.IsVisible(AttributeLanguages) {
+		public.Languages = append(public.Languages, a.Languages...)
+	}
+	return public
+}
+
+// optionValue 選項型屬性的值
+func (a *ProfileAttributes) optionValue(attribute ProfileAttribute) string {
+	switch attribute {
+	case AttributeEducation:
+		return a.Education
+	case AttributeRelationshipGoal:
+		return a.RelationshipGoal
+	case AttributeChildren:
+		return a.Children
+	case AttributeSmoking:
+		return a.Smoking
+	case AttributeDrinking:
+		return a.Drinking
+	case AttributeReligion:
+		return a.Religion
+	default:
+		return ""
+	}
+}
+
+// AttributePreference 用戶對他人檔案屬性的配對條件
+// Dealbreaker 為 true 時作為硬性篩選，否則作為相容性分數的加分條件
+type AttributePreference struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	UserID      uint             `gorm:"not null;uniqueIndex:idx_attribute_preferences_user_attribute" json:"user_id"`
+	Attribute   ProfileAttribute `gorm:"size:30;not null;uniqueIndex:idx_attribute_preferences_user_attribute" json:"attribute"`
+	Values      StringList       `gorm:"size:255;not null;default:''" json:"values"` // 可接受的值；語言為任一相符即可
+	MinHeight   int              `json:"min_height,omitempty"`                       // 僅用於身高，0 表示不限
+	MaxHeight   int              `json:"max_height,omitempty"`                       // 僅用於身高，0 表示不限
+	Dealbreaker bool             `gorm:"not null;default:false" json:"dealbreaker"`
+
+	// 時間戳記
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定資料表名稱
+func (AttributePreference) TableName() string {
+	return "user_attribute_preferences"
+}
+
+// Validate 驗證配對條件
+func (p *AttributePreference) Validate() error {
+	if !p.Attribute.IsValid() {
+		return fmt.Errorf("無效的屬性名稱: %s", p.Attribute)
+	}
+	if !p.Attribute.IsFilterable() {
+		return fmt.Errorf("%s 無法設為配對條件", p.Attribute)
+	}
+
+	if p.Attribute == AttributeHeight {
+		if p.MinHeight == 0 && p.MaxHeight == 0 {
+			return errors.New("身高條件至少需設定最小或最大值")
+		}
+		for _, height := range []int{p.MinHeight, p.MaxHeight} {
+			if height != 0 && (height < MinHeightCm || height > MaxHeightCm) {
+				return fmt.Errorf("身高條件必須在 %d 到 %d 公分之間", MinHeightCm, MaxHeightCm)
+			}
+		}
+		if p.MinHeight != 0 && p.MaxHeight != 0 && p.MinHeight > p.MaxHeight {
+			return errors.New("最小身高不能大於最大身高")
+		}
+		return nil
+	}
+
+	if len(p.Values) == 0 {
+		return fmt.Errorf("%s 條件至少需選擇一個值", p.Attribute)
+	}
+	for _, value := range p.Values {
+		if !isAttributeOption(p.Attribute, value) {
+			return fmt.Errorf("%s 的值無效: %s", p.Attribute, value)
+		}
+	}
+	return nil
+}
+
+// Accepts 檢查對方的屬性是否符合條件
+// 對方未填寫或設為隱藏的屬性一律視為不符合
+func (p *AttributePreference) Accepts(attributes *ProfileAttributes) bool {
+	if !attributes.IsVisible(p.Attribute) {
+		return false
+	}
+
+	switch p.Attribute {
+	case AttributeHeight:
+		if attributes.Height == 0 {
+			return false
+		}
+		return (p.MinHeight == 0 || attributes.Height >= p.MinHeight) &&
+			(p.MaxHeight == 0 || attributes.Height <= p.MaxHeight)
+	case AttributeLanguages:
+		for _, language := range attributes.Languages {
+			if p.Values.Contains(language) {
+				return true
+			}
+		}
+		return false
+	default:
+		value := attributes.optionValue(p.Attribute)
+		return value != "" && p.Values.Contains(value)
+	}
+}
+
+// AttributePreferenceScore 計算對方符合非硬性條件的比例
+// 沒有任何非硬性條件時返回 false
+func AttributePreferenceScore(preferences []*AttributePreference, attributes *ProfileAttributes) (float64, bool) {
+	var total, matched int
+	for _, preference := range preferences {
+		if preference.Dealbreaker {
+			continue
+		}
+		total++
+		if preference.Accepts(attributes) {
+			matched++
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return float64(matched) / float64(total), true
+}
+
+// uniqueLowercase 轉為小寫並去除空白與重複
+func uniqueLowercase(values StringList) StringList {
+	seen := make(map[string]bool, len(values))
+	result := make(StringList, 0, len(values))
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value != "" && !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,240 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 檔案問答限制
+const (
+	MaxProfilePrompts     = 3   // 每位用戶最多回答的題目數
+	MaxPromptAnswerLength = 300 // 回答長度上限
+	MaxPromptTextLength   = 200 // 題目長度上限
+	DefaultPromptLocale   = "zh-tw"
+)
+
+// Prompt 檔案問答題目，由管理員維護
+// 題目文字依語系儲存於 PromptTranslation
+type Prompt struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	Key          string `gorm:"column:prompt_key;uniqueIndex;not null;size:50" json:"key"` // 穩定識別碼，例如 "ideal_weekend"
+	Category     string `gorm:"size:50" json:"category"`
+	IsActive     bool   `gorm:"default:true" json:"is_active"` // 停用後不再提供新回答，既有回答保留
+	DisplayOrder int    `gorm:"default:0" json:"display_order"`
+
+	Translations []PromptTranslation `gorm:"foreignKey:PromptID;constraint:OnDelete:CASCADE" json:"translations"`
+
+	// 時間戳記
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PromptTranslation 題目的在地化文字
+type PromptTranslation struct {
+	ID       uint   `gorm:"primaryKey" json:"-"`
+	PromptID uint   `gorm:"not null;uniqueIndex:idx_prompt_translations_prompt_locale" json:"-"`
+	Locale   string `gorm:"not null;size:10;uniqueIndex:idx_prompt_translations_prompt_locale" json:"locale"` // 小寫 BCP 47 語系，例如 zh-tw、en
+	Text     string `gorm:"not null;size:200" json:"text"`
+}
+
+// TableName 指定資料表名稱
+func (Prompt) TableName() string {
+	return "prompts"
+}
+
+// TableName 指定資料表名稱
+func (PromptTranslation) TableName() string {
+	return "prompt_translations"
+}
+
+// NormalizeLocale 將語系統一為小寫並以連字號分隔，例如 zh_TW 轉為 zh-tw
+func NormalizeLocale(locale string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(locale)), "_", "-")
+}
+
+// Validate 驗證題目資料
+func (p *Prompt) Validate() error {
+	if strings.TrimSpace(p.Key) == "" {
+		return errors.New("key 是必填欄位")
+	}
+
+	if len(p.Key) > 50 {
+		return errors.New("key 不能超過 50 字元")
+	}
+
+	if len(p.Category) > 50 {
+		return errors.New("category 不能超過 50 字元")
+	}
+
+	if len(p.Translations) == 0 {
+		return errors.New("題目至少需要一種語系的文字")
+	}
+
+	seen := make(map[string]bool, len(p.Translations))
+	for _, translation := range p.Translations {
+		if !languageCodePattern.MatchString(translation.Locale) {
+			return fmt.Errorf("無效的語系: %s", translation.Locale)
+		}
+		if seen[translation.Locale] {
+			return fmt.Errorf("語系 %s 重複", translation.Locale)
+		}
+		seen[translation.Locale] = true
+
+		if strings.TrimSpace(translation.Text) == "" {
+			return fmt.Errorf("語系 %s 的題目文字不能為空", translation.Locale)
+		}
+		if len(translation.Text) > MaxPromptTextLength {
+			return fmt.Errorf("題目文字不能超過 %d 字元", MaxPromptTextLength)
+		}
+	}
+
+	return nil
+}
+
+// Text 獲取指定語系的題目文字
+// 依序嘗試完整語系、主要語言（zh-tw 取 zh）、預設語系，都沒有時使用第一筆翻譯
+func (p *Prompt) Text(locale string) string {
+	locale = NormalizeLocale(locale)
+	candidates := []string{locale}
+	if base, _, found := strings.Cut(locale, "-"); found {
+		candidates = append(candidates, base)
+	}
+	candidates = append(candidates, DefaultPromptLocale)
+
+	for _, candidate := range candidates {
+		for _, translation := range p.Translations {
+			if translation.Locale == candidate {
+				return translation.Text
+			}
+		}
+	}
+
+	if len(p.Translations) > 0 {
+		return p.Translations[0].Text
+	}
+	return ""
+}
+
+// PromptAnswerStatus 回答審核狀態枚舉
+type PromptAnswerStatus string
+
+const (
+	PromptAnswerStatusPending  PromptAnswerStatus = "pending"  // 待審核
+	PromptAnswerStatusApproved PromptAnswerStatus = "approved" // 已通過
+	PromptAnswerStatusRejected PromptAnswerStatus = "rejected" // 已拒絕
+)
+
+// ProfilePrompt 用戶對檔案問答題目的回答
+// 新增或修改回答後需重新審核，通過前只有本人看得到
+type ProfilePrompt struct {
+	ID           uint               `gorm:"primaryKey" json:"id"`
+	UserID       uint               `gorm:"not null;uniqueIndex:idx_profile_prompts_user_prompt" json:"user_id"`
+	PromptID     uint               `gorm:"not null;uniqueIndex:idx_profile_prompts_user_prompt" json:"prompt_id"`
+	Answer       string             `gorm:"not null;size:300" json:"answer"`
+	DisplayOrder int                `gorm:"default:0" json:"display_order"`
+	Status       PromptAnswerStatus `gorm:"not null;size:20;default:'pending';index" json:"status"`
+
+	// 審核資訊
+	ReviewerID  *uint      `json:"reviewer_id,omitempty"`
+	ReviewNotes *string    `gorm:"size:500" json:"review_notes,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+
+	// 時間戳記
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// 關聯
+	Prompt *Prompt `gorm:"foreignKey:PromptID" json:"prompt,omitempty"`
+}
+
+// TableName 指定資料表名稱
+func (ProfilePrompt) TableName() string {
+	return "profile_prompts"
+}
+
+// Validate 驗證回答資料，長度規則與 UserProfile.Validate 的個人簡介一致
+func (pp *ProfilePrompt) Validate() error {
+	if pp.PromptID == 0 {
+		return errors.New("prompt_id 是必填欄位")
+	}
+
+	if strings.TrimSpace(pp.Answer) == "" {
+		return errors.New("answer 是必填欄位")
+	}
+
+	if len(pp.Answer) > MaxPromptAnswerLength {
+		return fmt.Errorf("answer 不能超過 %d 字元", MaxPromptAnswerLength)
+	}
+
+	if pp.DisplayOrder < 0 {
+		return errors.New("display_order 不能為負數")
+	}
+
+	return nil
+}
+
+// IsApproved 檢查回答是否已通過審核
+func (pp *ProfilePrompt) IsApproved() bool {
+	return pp.Status == PromptAnswerStatusApproved
+}
+
+// Approve 通過回答審核
+func (pp *ProfilePrompt) Approve(reviewerID uint, notes string) error {
+	if pp.Status == PromptAnswerStatusApproved {
+		return errors.New("回答已通過審核")
+	}
+
+	pp.markReviewed(PromptAnswerStatusApproved, reviewerID, strings.TrimSpace(notes))
+	return nil
+}
+
+// Reject 拒絕回答，必須提供原因
+func (pp *ProfilePrompt) Reject(reviewerID uint, notes string) error {
+	if pp.Status == PromptAnswerStatusRejected {
+		return errors.New("回答已被拒絕")
+	}
+
+	cleanNotes := strings.TrimSpace(notes)
+	if cleanNotes == "" {
+		return errors.New("拒絕回答必須提供原因")
+	}
+
+	pp.markReviewed(PromptAnswerStatusRejected, reviewerID, cleanNotes)
+	return nil
+}
+
+// markReviewed 寫入審核結果
+func (pp *ProfilePrompt) markReviewed(status PromptAnswerStatus, reviewerID uint, notes string) {
+	pp.Status = status
+	pp.ReviewerID = &reviewerID
+	pp.ReviewNotes = nil
+	if notes != "" {
+		pp.ReviewNotes = &notes
+	}
+
+	now := time.Now()
+	pp.ReviewedAt = &now
+	pp.UpdatedAt = now
+}
+
+// ValidateProfilePrompts 驗證整組回答：數量上限、題目不可重複
+func ValidateProfilePrompts(prompts []*ProfilePrompt) error {
+	if len(prompts) > MaxProfilePrompts {
+		return fmt.Errorf("最多只能回答 %d 個題目", MaxProfilePrompts)
+	}
+
+	seen := make(map[uint]bool, len(prompts))
+	for _, prompt := range prompts {
+		if err := prompt.Validate(); err != nil {
+			return err
+		}
+		if seen[prompt.PromptID] {
+			return errors.New("同一題目只能回答一次")
+		}
+		seen[prompt.PromptID] = true
+	}
+
+	return nil
+}
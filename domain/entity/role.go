@@ -0,0 +1,106 @@
+package entity
+
+// UserRole 用戶角色枚舉
+type UserRole string
+
+const (
+	RoleUser       UserRole = "user"       // 一般用戶
+	RoleModerator  UserRole = "moderator"  // 內容審核員
+	RoleAdmin      UserRole = "admin"      // 管理員
+	RoleSuperAdmin UserRole = "superadmin" // 超級管理員
+)
+
+// Permission 權限枚舉
+type Permission string
+
+const (
+	PermissionReportReview   Permission = "reports:review"   // 查看與審核檢舉
+	PermissionReportStats    Permission = "reports:stats"    // 查看檢舉統計
+	PermissionRoleAssign     Permission = "roles:assign"     // 指派用戶角色
+	PermissionUserSuspend    Permission = "users:suspend"    // 暫停與恢復用戶帳號
+	PermissionUserBan        Permission = "users:ban"        // 永久封禁用戶帳號
+	PermissionContentReview  Permission = "content:review"   // 審核用戶提交的檔案內容
+	PermissionPromptManage   Permission = "prompts:manage"   // 維護檔案問答題目
+	PermissionInterestManage Permission = "interests:manage" // 維護興趣標籤目錄
+)
+
+// rolePermissions 各角色擁有的權限
+var rolePermissions = map[UserRole][]Permission{
+	RoleUser: {},
+	RoleModerator: {
+		PermissionReportReview,
+		PermissionUserSuspend,
+		PermissionContentReview,
+	},
+	RoleAdmin: {
+		PermissionReportReview,
+		PermissionReportStats,
+		PermissionRoleAssign,
+		PermissionUserSuspend,
+		PermissionUserBan,
+		PermissionContentReview,
+		PermissionPromptManage,
+		PermissionInterestManage,
+	},
+	RoleSuperAdmin: {
+		PermissionReportReview,
+		PermissionReportStats,
+		PermissionRoleAssign,
+		PermissionUserSuspend,
+		PermissionUserBan,
+		PermissionContentReview,
+		PermissionPromptManage,
+		PermissionInterestManage,
+	},
+}
+
+// IsValid 檢查角色是否有效
+func (r UserRole) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Level 角色等級，數值越大權限越高
+func (r UserRole) Level() int {
+	switch r {
+	case RoleModerator:
+		return 1
+	case RoleAdmin:
+		return 2
+	case RoleSuperAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// AtLeast 檢查角色等級是否不低於指定角色
+func (r UserRole) AtLeast(other UserRole) bool {
+	return r.IsValid() && r.Level() >= other.Level()
+}
+
+// HasPermission 檢查角色是否擁有指定權限
+func (r UserRole) HasPermission(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// GetDisplayName 獲取角色的顯示名稱
+func (r UserRole) GetDisplayName() string {
+	switch r {
+	case RoleUser:
+		return "一般用戶"
+	case RoleModerator:
+		return "審核員"
+	case RoleAdmin:
+		return "管理員"
+	case RoleSuperAdmin:
+		return "超級管理員"
+	default:
+		return string(r)
+	}
+}
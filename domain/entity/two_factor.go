@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// TwoFactorCredential 用戶的 TOTP 兩步驟驗證設定
+// 建立後需以驗證碼確認才會啟用
+type TwoFactorCredential struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	UserID       uint       `gorm:"not null;uniqueIndex" json:"user_id"`
+	Secret       string     `gorm:"type:varchar(64);not null" json:"-"` // Base32 編碼的共享金鑰
+	Enabled      bool       `gorm:"not null;default:false" json:"enabled"`
+	LastUsedStep int64      `gorm:"not null;default:0" json:"-"` // 最後使用的時間步，防止驗證碼重放
+	EnabledAt    *time.Time `json:"enabled_at"`
+
+	// 時間戳記
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定資料表名稱
+func (TwoFactorCredential) TableName() string {
+	return "user_two_factor_credentials"
+}
+
+// RecoveryCode 兩步驟驗證備用恢復碼，只儲存雜湊且僅能使用一次
+type RecoveryCode struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"type:char(64);not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName 指定資料表名稱
+func (RecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
+
+// IsUsed 檢查恢復碼是否已使用
+func (r *RecoveryCode) IsUsed() bool {
+	return r.UsedAt != nil
+}
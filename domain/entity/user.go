@@ -16,6 +16,28 @@ type User struct {
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 
+	// 電子郵件驗證狀態（與年齡驗證 IsVerified 分開）
+	EmailVerified   bool       `gorm:"default:false" json:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+
+	// 角色與權限
+	Role UserRole `gorm:"type:varchar(20);not null;default:'user';index" json:"role"`
+
+	// 帳號停權狀態（與用戶自行停用的 IsActive 分開）
+	AccountStatus   AccountStatus `gorm:"type:varchar(20);not null;default:'active';index" json:"account_status"`
+	SuspendedUntil  *time.Time    `gorm:"index" json:"suspended_until,omitempty"`
+	StatusReason    string        `gorm:"type:varchar(500)" json:"status_reason,omitempty"`
+	StatusActorID   *uint         `json:"status_actor_id,omitempty"`
+	StatusChangedAt *time.Time    `json:"status_changed_at,omitempty"`
+
+	// 帳號刪除（寬限期內可取消，到期後由排程清除個人資料）
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at,omitempty"`
+	DeletionScheduledAt *time.Time `gorm:"index" json:"deletion_scheduled_at,omitempty"`
+	ErasedAt            *time.Time `json:"erased_at,omitempty"`
+
+	// 外部登入建立的帳號需補填出生日期後才完成註冊
+	RegistrationPending bool `gorm:"not null;default:false" json:"registration_pending"`
+
 	// 關聯 - 將在其他實體建立後添加
 	// Profile        *UserProfile     `gorm:"foreignKey:UserID" json:"profile,omitempty"`
 	// Photos         []Photo          `gorm:"foreignKey:UserID" json:"photos,omitempty"`
@@ -50,7 +72,7 @@ func (u *User) GetAge() int {
 
 // IsEligible 檢查用戶是否符合使用應用程式的條件
 func (u *User) IsEligible() bool {
-	return u.IsActive && u.IsVerified && u.IsAdult()
+	return u.IsActive && u.IsVerified && !u.RegistrationPending && u.IsAdult()
 }
 
 // Validate 驗證用戶資料的完整性
@@ -87,6 +109,110 @@ func (u *User) Activate() {
 	u.UpdatedAt = time.Now()
 }
 
+// MarkEmailVerified 標記用戶電子郵件已驗證
+func (u *User) MarkEmailVerified() {
+	now := time.Now()
+	u.EmailVerified = true
+	u.EmailVerifiedAt = &now
+	u.UpdatedAt = now
+}
+
+// GetAccountStatus 獲取帳號在指定時間的實際狀態
+// 暫停期限已過但尚未被排程解除時，視為正常狀態
+func (u *User) GetAccountStatus(now time.Time) AccountStatus {
+	switch u.AccountStatus {
+	case AccountStatusBanned:
+		return AccountStatusBanned
+	case AccountStatusSuspended:
+		if u.SuspendedUntil != nil && !now.Before(*u.SuspendedUntil) {
+			return AccountStatusActive
+		}
+		return AccountStatusSuspended
+	default:
+		return AccountStatusActive
+	}
+}
+
+// Suspend 暫停用戶帳號直到指定時間
+func (u *User) Suspend(until time.Time, reason string, actorID *uint) {
+	now := time.Now()
+	u.AccountStatus = AccountStatusSuspended
+	u.SuspendedUntil = &until
+	u.StatusReason = reason
+	u.StatusActorID = actorID
+	u.StatusChangedAt = &now
+	u.UpdatedAt = now
+}
+
+// Ban 永久封禁用戶帳號
+func (u *User) Ban(reason string, actorID *uint) {
+	now := time.Now()
+	u.AccountStatus = AccountStatusBanned
+	u.SuspendedUntil = nil
+	u.StatusReason = reason
+	u.StatusActorID = actorID
+	u.StatusChangedAt = &now
+	u.UpdatedAt = now
+}
+
+// Reinstate 恢復用戶帳號為正常狀態
+func (u *User) Reinstate(reason string, actorID *uint) {
+	now := time.Now()
+	u.AccountStatus = AccountStatusActive
+	u.SuspendedUntil = nil
+	u.StatusReason = reason
+	u.StatusActorID = actorID
+	u.StatusChangedAt = &now
+	u.UpdatedAt = now
+}
+
+// ScheduleDeletion 排定帳號刪除，寬限期間帳號停用
+func (u *User) ScheduleDeletion(at time.Time) {
+	now := time.Now()
+	u.IsActive = false
+	u.DeletionRequestedAt = &now
+	u.DeletionScheduledAt = &at
+	u.UpdatedAt = now
+}
+
+// CancelDeletion 取消排定的帳號刪除並重新啟用帳號
+func (u *User) CancelDeletion() {
+	u.IsActive = true
+	u.DeletionRequestedAt = nil
+	u.DeletionScheduledAt = nil
+	u.UpdatedAt = time.Now()
+}
+
+// IsPendingDeletion 檢查帳號是否在刪除寬限期內
+func (u *User) IsPendingDeletion() bool {
+	return u.DeletionScheduledAt != nil && u.ErasedAt == nil
+}
+
+// IsErased 檢查帳號個人資料是否已清除
+func (u *User) IsErased() bool {
+	return u.ErasedAt != nil
+}
+
+// GetRole 獲取用戶角色，未設定時視為一般用戶
+func (u *User) GetRole() UserRole {
+	if u.Role == "" {
+		return RoleUser
+	}
+	return u.Role
+}
+
+// HasPermission 檢查用戶是否擁有指定權限
+func (u *User) HasPermission(permission Permission) bool {
+	return u.GetRole().HasPermission(permission)
+}
+
+// CompleteRegistration 補填出生日期並完成註冊
+func (u *User) CompleteRegistration(birthDate time.Time) {
+	u.BirthDate = birthDate
+	u.RegistrationPending = false
+	u.UpdatedAt = time.Now()
+}
+
 // MarkAsVerified 標記用戶已通過年齡驗證
 func (u *User) MarkAsVerified() {
 	u.IsVerified = true
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// UserIdentity 外部身分提供者（OIDC）帳號與用戶的連結
+// 同一提供者的同一帳號（subject）只能連結一位用戶
+type UserIdentity struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	Provider    string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_identity_provider_subject" json:"provider"`
+	Subject     string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_identity_provider_subject" json:"-"` // 提供者的用戶識別碼（sub）
+	Email       string     `gorm:"type:varchar(255)" json:"email"`                                                // 連結時提供者回報的信箱
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+
+	// 時間戳記
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定資料表名稱
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
@@ -1,7 +1,11 @@
 package entity
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
@@ -20,21 +24,101 @@ func (g Gender) IsValid() bool {
 	return g == GenderMale || g == GenderFemale || g == GenderOther
 }
 
+// GenderSet 性別集合，以逗號分隔的字串儲存（例如 "female,male"），空集合表示不限性別
+type GenderSet []Gender
+
+// NewGenderSet 建立去除重複並排序的性別集合
+func NewGenderSet(genders ...Gender) (GenderSet, error) {
+	seen := make(map[Gender]bool, len(genders))
+	set := make(GenderSet, 0, len(genders))
+	for _, gender := range genders {
+		if !gender.IsValid() {
+			return nil, fmt.Errorf("無效的性別: %s", gender)
+		}
+		if !seen[gender] {
+			seen[gender] = true
+			set = append(set, gender)
+		}
+	}
+	sort.Slice(set, func(i, j int) bool { return set[i] < set[j] })
+	return set, nil
+}
+
+// Includes 檢查集合是否包含指定性別，空集合包含所有性別
+func (s GenderSet) Includes(gender Gender) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, g := range s {
+		if g == gender {
+			return true
+		}
+	}
+	return false
+}
+
+// Value 實作 driver.Valuer，存成逗號分隔字串以便以 FIND_IN_SET 查詢
+func (s GenderSet) Value() (driver.Value, error) {
+	parts := make([]string, len(s))
+	for i, gender := range s {
+		parts[i] = string(gender)
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// Scan 實作 sql.Scanner
+func (s *GenderSet) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+		raw = ""
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("無法解析性別集合: %T", value)
+	}
+
+	*s = nil
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*s = append(*s, Gender(part))
+		}
+	}
+	return nil
+}
+
+// MarshalJSON 空集合輸出為空陣列
+func (s GenderSet) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]Gender(s))
+}
+
 // UserProfile 用戶檔案實體
 type UserProfile struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	UserID      uint      `gorm:"uniqueIndex;not null" json:"user_id"`
-	DisplayName string    `gorm:"not null;size:50" json:"display_name"`
-	Bio         string    `gorm:"size:500" json:"bio"`
-	Gender      Gender    `gorm:"not null" json:"gender"`
-	ShowAge     bool      `gorm:"default:true" json:"show_age"`
-	LocationLat *float64  `json:"location_lat"`
-	LocationLng *float64  `json:"location_lng"`
-	MaxDistance int       `gorm:"default:50" json:"max_distance"` // km
-	AgeRangeMin int       `gorm:"default:18" json:"age_range_min"`
-	AgeRangeMax int       `gorm:"default:99" json:"age_range_max"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `gorm:"uniqueIndex;not null" json:"user_id"`
+	DisplayName  string    `gorm:"not null;size:50" json:"display_name"`
+	Bio          string    `gorm:"size:500" json:"bio"`
+	Gender       Gender    `gorm:"not null" json:"gender"`
+	ShowAge      bool      `gorm:"default:true" json:"show_age"`
+	LocationLat  *float64  `json:"location_lat"`
+	LocationLng  *float64  `json:"location_lng"`
+	MaxDistance  int       `gorm:"default:50" json:"max_distance"` // km
+	AgeRangeMin  int       `gorm:"default:18" json:"age_range_min"`
+	AgeRangeMax  int       `gorm:"default:99" json:"age_range_max"`
+	InterestedIn GenderSet `gorm:"size:32;not null;default:''" json:"interested_in"` // 想認識的性別，空集合表示不限
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// 結構化屬性，欄位直接存於 user_profiles 資料表
+	Attributes ProfileAttributes `gorm:"embedded" json:"attributes"`
+
+	// 檔案問答，存於 profile_prompts 資料表，由服務層載入
+	Prompts []*ProfilePrompt `gorm:"-" json:"prompts,omitempty"`
 
 	// 關聯 - 將在User實體完成後添加
 	// User User `gorm:"constraint:OnDelete:CASCADE" json:"user"`
@@ -93,7 +177,13 @@ func (up *UserProfile) Validate() error {
 		return errors.New("age_range_min 不能大於 age_range_max")
 	}
 
-	return nil
+	for _, gender := range up.InterestedIn {
+		if !gender.IsValid() {
+			return errors.New("interested_in 只能包含 male、female 或 other")
+		}
+	}
+
+	return up.Attributes.Validate()
 }
 
 // HasLocation 檢查是否設定了位置資訊
@@ -157,3 +247,34 @@ func (up *UserProfile) UpdateMaxDistance(distance int) error {
 
 	return nil
 }
+
+// UpdateInterestedIn 更新想認識的性別，傳入空集合表示不限
+func (up *UserProfile) UpdateInterestedIn(genders []Gender) error {
+	set, err := NewGenderSet(genders...)
+	if err != nil {
+		return err
+	}
+
+	up.InterestedIn = set
+	up.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// IsInterestedIn 檢查對方的性別是否符合自己的偏好
+func (up *UserProfile) IsInterestedIn(gender Gender) bool {
+	return up.InterestedIn.Includes(gender)
+}
+
+// UpdateAttributes 更新結構化屬性
+func (up *UserProfile) UpdateAttributes(attributes ProfileAttributes) error {
+	attributes.Normalize()
+	if err := attributes.Validate(); err != nil {
+		return err
+	}
+
+	up.Attributes = attributes
+	up.UpdatedAt = time.Now()
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// AccountErasureRepository 帳號資料清除儲存庫介面
+// 提供刪除帳號時跨資料表的個人資料清除，審核所需的紀錄以匿名化形式保留
+type AccountErasureRepository interface {
+	// EraseUser 清除用戶個人資料
+	// 在單一交易中刪除檔案、照片、興趣、年齡驗證、配對、聊天、封鎖與兩步驟驗證資料，
+	// 並將用戶列匿名化為僅保留 ID 的墓碑紀錄，使檢舉與審核日誌仍可關聯
+	EraseUser(ctx context.Context, userID uint, erasedAt time.Time) (*ErasureResult, error)
+}
+
+// ErasureResult 資料清除結果
+// 資料庫交易完成後，由業務層刪除檔案並清除快取
+type ErasureResult struct {
+	FilePaths []string // 照片、驗證文件與聊天附件的檔案路徑
+	MatchIDs  []uint   // 已刪除的配對 ID，用於清除聊天快取
+	Archives  []string // 個人資料匯出封存檔名稱
+}
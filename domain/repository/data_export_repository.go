@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+)
+
+// DataExportRepository 個人資料匯出記錄儲存庫介面
+// 提供匯出請求的建立、狀態查詢與背景工作認領
+type DataExportRepository interface {
+	// Create 創建匯出記錄
+	Create(ctx context.Context, export *entity.DataExport) error
+
+	// GetByID 根據 ID 獲取匯出記錄，不存在時返回 nil
+	GetByID(ctx context.Context, id uint) (*entity.DataExport, error)
+
+	// GetLatestByUserID 獲取用戶最近一次匯出記錄，不存在時返回 nil
+	// 用於狀態查詢與每日次數限制
+	GetLatestByUserID(ctx context.Context, userID uint) (*entity.DataExport, error)
+
+	// GetPending 獲取等待處理的匯出記錄
+	// 包含在 staleBefore 之前開始處理但仍未完成的記錄，視為處理中斷
+	GetPending(ctx context.Context, staleBefore time.Time, limit int) ([]*entity.DataExport, error)
+
+	// ClaimPending 將等待中或處理中斷的匯出標記為處理中，記錄開始時間並累加嘗試次數
+	// 返回 false 表示已被其他工作認領，用於多個實例同時執行排程
+	ClaimPending(ctx context.Context, id uint, now, staleBefore time.Time) (bool, error)
+
+	// Update 更新匯出記錄
+	Update(ctx context.Context, export *entity.DataExport) error
+
+	// GetExpired 獲取下載期限已過且封存檔尚未刪除的匯出記錄
+	GetExpired(ctx context.Context, now time.Time, limit int) ([]*entity.DataExport, error)
+}
+
+// PersonalDataRepository 個人資料讀取儲存庫介面
+// 一次讀取用戶在各資料表中的資料，用於產生匯出封存檔
+type PersonalDataRepository interface {
+	// CollectUserData 收集用戶個人資料
+	CollectUserData(ctx context.Context, userID uint) (*PersonalData, error)
+}
+
+// PersonalData 用戶個人資料集合
+type PersonalData struct {
+	User      *entity.User
+	Profile   *entity.UserProfile // 尚未建立檔案時為 nil
+	Photos    []*entity.Photo
+	Grants    []*entity.PrivateAlbumGrant // 用戶授權查看私人相簿的對象
+	Interests []*entity.Interest
+	Matches   []*entity.Match // 用戶參與的所有滑動與配對記錄
+	Messages  []*entity.ChatMessage
+	Blocks    []*entity.Block  // 用戶建立的封鎖
+	Reports   []*entity.Report // 用戶提出的檢舉
+
+	AttributePreferences []*entity.AttributePreference // 用戶對他人屬性的配對條件
+	Prompts              []*entity.ProfilePrompt       // 檔案問答回答
+}
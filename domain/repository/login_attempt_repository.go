@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+)
+
+// LoginAttemptRepository 登入嘗試記錄儲存庫介面
+type LoginAttemptRepository interface {
+	// Create 記錄一次登入嘗試
+	Create(ctx context.Context, attempt *entity.LoginAttempt) error
+
+	// DeleteBefore 刪除指定時間之前的記錄，返回刪除筆數
+	// 用於定期清除超過保留期的記錄
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -85,8 +85,11 @@ type PotentialMatchParams struct {
 	MinAge *int // 最小年齡
 	MaxAge *int // 最大年齡
 
-	// 性別偏好
-	PreferredGender *entity.Gender // 偏好性別
+	// 性別偏好：本次查詢額外限定的性別，留空時依雙方檔案中儲存的偏好
+	PreferredGenders []entity.Gender
+
+	// 屬性硬性條件：對方屬性未填寫或隱藏時視為不符合
+	Dealbreakers []*entity.AttributePreference
 
 	// 興趣篩選
 	RequireCommonInterests bool // 是否要求共同興趣
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"golang_dev_docker/domain/entity"
+)
+
+// PromptRepository 檔案問答題目數據儲存庫介面
+// 提供題目與在地化文字的持久化操作
+type PromptRepository interface {
+	// GetAll 獲取題目與所有語系文字
+	// activeOnly 為 true 時只返回啟用中的題目，用於用戶選題；管理後台傳入 false
+	GetAll(ctx context.Context, activeOnly bool) ([]*entity.Prompt, error)
+
+	// GetByID 根據 ID 獲取題目與所有語系文字
+	// 題目不存在時返回 nil, nil
+	GetByID(ctx context.Context, id uint) (*entity.Prompt, error)
+
+	// Create 創建題目與語系文字
+	// 用於管理後台新增題目
+	Create(ctx context.Context, prompt *entity.Prompt) error
+
+	// Update 更新題目並以新的語系文字取代原有文字
+	// 用於管理後台修改與停用題目
+	Update(ctx context.Context, prompt *entity.Prompt) error
+}
+
+// ProfilePromptRepository 用戶檔案問答數據儲存庫介面
+// 提供用戶回答與審核狀態的持久化操作
+type ProfilePromptRepository interface {
+	// GetByUserID 獲取用戶的所有回答，依顯示順序排列並載入題目
+	// 用於檔案展示與編輯
+	GetByUserID(ctx context.Context, userID uint) ([]*entity.ProfilePrompt, error)
+
+	// GetByID 根據 ID 獲取回答
+	// 回答不存在時返回 nil, nil
+	GetByID(ctx context.Context, id uint) (*entity.ProfilePrompt, error)
+
+	// ReplaceForUser 以新的回答取代用戶所有回答
+	// 已有 ID 的回答就地更新，其餘新增，不在清單中的回答刪除
+	ReplaceForUser(ctx context.Context, userID uint, prompts []*entity.ProfilePrompt) error
+
+	// GetPending 獲取待審核的回答，依提交時間排列
+	// 用於內容審核
+	GetPending(ctx context.Context, limit int) ([]*entity.ProfilePrompt, error)
+
+	// UpdateReview 更新回答的審核結果
+	// 用於審核通過或拒絕
+	UpdateReview(ctx context.Context, prompt *entity.ProfilePrompt) error
+}
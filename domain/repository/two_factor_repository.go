@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"golang_dev_docker/domain/entity"
+)
+
+// TwoFactorRepository 兩步驟驗證數據儲存庫介面
+// 提供 TOTP 設定與備用恢復碼的持久化操作
+type TwoFactorRepository interface {
+	// GetByUserID 獲取用戶的 TOTP 設定
+	// 用戶尚未設定時返回 nil, nil
+	GetByUserID(ctx context.Context, userID uint) (*entity.TwoFactorCredential, error)
+
+	// Save 建立或更新 TOTP 設定
+	// 用於開始綁定與確認啟用
+	Save(ctx context.Context, credential *entity.TwoFactorCredential) error
+
+	// Delete 刪除用戶的 TOTP 設定與所有恢復碼
+	// 用於停用兩步驟驗證
+	Delete(ctx context.Context, userID uint) error
+
+	// MarkStepUsed 記錄已使用的時間步
+	// 只有時間步大於上次使用值時才會更新並返回 true，用於防止驗證碼重放
+	MarkStepUsed(ctx context.Context, userID uint, step int64) (bool, error)
+
+	// ReplaceRecoveryCodes 以新的恢復碼雜湊取代用戶所有恢復碼
+	// 用於啟用時產生與重新產生恢復碼
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error
+
+	// UseRecoveryCode 標記恢復碼為已使用
+	// 恢復碼存在且未使用時返回 true
+	UseRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error)
+
+	// CountUnusedRecoveryCodes 統計剩餘可用的恢復碼數量
+	CountUnusedRecoveryCodes(ctx context.Context, userID uint) (int64, error)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"golang_dev_docker/domain/entity"
+)
+
+// UserIdentityRepository 外部身分連結儲存庫介面
+type UserIdentityRepository interface {
+	// Create 建立外部身分連結
+	Create(ctx context.Context, identity *entity.UserIdentity) error
+
+	// CreateWithUser 在同一交易中建立用戶與其外部身分連結，任一失敗時皆不寫入
+	// 用於首次外部登入建立帳號，identity.UserID 由新用戶 ID 填入
+	CreateWithUser(ctx context.Context, user *entity.User, identity *entity.UserIdentity) error
+
+	// GetByProviderSubject 根據提供者與 subject 獲取連結，不存在時返回 nil
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error)
+
+	// ListByUserID 獲取用戶所有外部身分連結
+	ListByUserID(ctx context.Context, userID uint) ([]*entity.UserIdentity, error)
+
+	// Update 更新外部身分連結
+	Update(ctx context.Context, identity *entity.UserIdentity) error
+
+	// Delete 解除用戶與指定提供者的連結，返回是否有刪除
+	Delete(ctx context.Context, userID uint, provider string) (bool, error)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"golang_dev_docker/domain/entity"
 )
@@ -33,9 +34,41 @@ type UserRepository interface {
 	// 用於年齡驗證完成後更新狀態
 	SetVerified(ctx context.Context, id uint, verified bool) error
 
+	// SetEmailVerified 標記用戶電子郵件已驗證
+	// 用於電子郵件驗證碼確認後更新狀態
+	SetEmailVerified(ctx context.Context, id uint) error
+
+	// UpdatePassword 更新用戶密碼雜湊
+	// 用於變更密碼與重設密碼
+	UpdatePassword(ctx context.Context, id uint, passwordHash string) error
+
 	// SetActive 設定用戶啟用狀態
 	// 用於帳戶啟用/停用管理
 	SetActive(ctx context.Context, id uint, active bool) error
+
+	// UpdateRole 更新用戶角色
+	// 用於管理員指派角色與初始管理員建立
+	UpdateRole(ctx context.Context, id uint, role entity.UserRole) error
+
+	// UpdateAccountStatus 更新用戶帳號停權狀態
+	// 寫入狀態、暫停期限、原因與操作者
+	UpdateAccountStatus(ctx context.Context, user *entity.User) error
+
+	// GetExpiredSuspensions 獲取暫停期限已過的用戶
+	// 用於排程自動恢復帳號
+	GetExpiredSuspensions(ctx context.Context, now time.Time, limit int) ([]*entity.User, error)
+
+	// UpdateDeletionSchedule 更新帳號刪除排程
+	// 寫入啟用狀態、申請時間與預定清除時間
+	UpdateDeletionSchedule(ctx context.Context, user *entity.User) error
+
+	// GetDueDeletions 獲取刪除寬限期已過且 ID 大於 afterID 的用戶，依 ID 排序
+	// 用於排程清除個人資料，以 afterID 分批略過清除失敗的帳號
+	GetDueDeletions(ctx context.Context, now time.Time, afterID uint, limit int) ([]*entity.User, error)
+
+	// CountByRoles 統計擁有指定角色的用戶數量
+	// 用於判斷是否已存在管理員
+	CountByRoles(ctx context.Context, roles ...entity.UserRole) (int64, error)
 }
 
 // UserProfileRepository 用戶檔案數據儲存庫介面
@@ -66,6 +99,18 @@ type UserProfileRepository interface {
 	UpdateMatchingPreferences(ctx context.Context, userID uint, maxDistance, ageMin, ageMax int) error
 }
 
+// AttributePreferenceRepository 檔案屬性配對條件數據儲存庫介面
+// 提供用戶對他人屬性的硬性條件與加分條件的持久化操作
+type AttributePreferenceRepository interface {
+	// GetByUserID 獲取用戶的所有屬性配對條件
+	// 用於條件編輯、潛在配對篩選與相容性計算
+	GetByUserID(ctx context.Context, userID uint) ([]*entity.AttributePreference, error)
+
+	// ReplaceForUser 以新的條件取代用戶所有屬性配對條件
+	// 用於一次儲存整份條件設定
+	ReplaceForUser(ctx context.Context, userID uint, preferences []*entity.AttributePreference) error
+}
+
 // PhotoRepository 用戶照片數據儲存庫介面
 // 提供用戶照片的持久化操作，包括上傳、排序、刪除等功能
 type PhotoRepository interface {
@@ -89,42 +134,144 @@ type PhotoRepository interface {
 	// 用於照片移除功能
 	Delete(ctx context.Context, id uint) error
 
-	// SetPrimary 設定主要照片
+	// SetPrimary 設定主要照片，同時取消用戶其他照片的主要照片設定
 	// 用於變更用戶主要展示照片
 	SetPrimary(ctx context.Context, userID, photoID uint) error
 
-	// UpdateOrder 更新照片排序
+	// UpdateOrder 更新照片排序，全部成功或全部不變
 	// 用於照片順序調整功能
 	UpdateOrder(ctx context.Context, userID uint, photoOrders []struct {
 		PhotoID uint
 		Order   int
 	}) error
+
+	// GetReviewQueue 獲取待審核照片佇列，依上傳時間排序並返回符合條件的總數
+	// 用於管理後台的照片審核佇列
+	GetReviewQueue(ctx context.Context, params PhotoQueueParams) ([]*entity.Photo, int64, error)
+
+	// Claim 認領待審核照片，照片未被認領、認領已逾時或已由同一審核員認領時才成功
+	// 用於避免多位審核員同時處理同一張照片
+	Claim(ctx context.Context, photoID, moderatorID uint, claimedAt, staleBefore time.Time) (bool, error)
+
+	// ReleaseClaim 釋出審核員對照片的認領
+	// 用於審核員放棄處理照片
+	ReleaseClaim(ctx context.Context, photoID, moderatorID uint) error
+
+	// UpdateReview 更新照片的審核結果並清除認領
+	// 用於照片審核
+	UpdateReview(ctx context.Context, photo *entity.Photo) error
+
+	// FindSimilar 找出其他用戶感知雜湊漢明距離在 maxDistance 以內的照片，依距離由近到遠排序
+	// 用於偵測重複使用他人照片的假帳號
+	FindSimilar(ctx context.Context, hash uint64, maxDistance int, excludeUserID uint, limit int) ([]*entity.Photo, error)
+
+	// Flag 記錄照片的自動偵測標記
+	// 用於將疑似問題照片優先排入審核佇列
+	Flag(ctx context.Context, photoID uint, reason string, score float64) error
+}
+
+// BlockedImageRepository 禁用圖片清單數據儲存庫介面
+// 提供已知盜用或違規圖片感知雜湊的持久化操作
+type BlockedImageRepository interface {
+	// Create 加入禁用圖片
+	// 用於審核員將圖片加入禁用清單
+	Create(ctx context.Context, image *entity.BlockedImage) error
+
+	// GetByID 根據 ID 獲取禁用圖片，不存在時返回 nil
+	// 用於移除前確認項目存在
+	GetByID(ctx context.Context, id uint) (*entity.BlockedImage, error)
+
+	// List 分頁獲取禁用圖片，依加入時間由新到舊排序
+	// 用於管理後台檢視清單
+	List(ctx context.Context, limit, offset int) ([]*entity.BlockedImage, int64, error)
+
+	// Delete 移除禁用圖片
+	// 用於撤銷誤加入的項目
+	Delete(ctx context.Context, id uint) error
+
+	// FindSimilar 找出感知雜湊漢明距離在 maxDistance 以內的禁用圖片，依距離由近到遠排序
+	// 用於比對新上傳的照片
+	FindSimilar(ctx context.Context, hash uint64, maxDistance int) ([]*entity.BlockedImage, error)
+}
+
+// PrivateAlbumRepository 私人相簿授權數據儲存庫介面
+// 提供私人相簿查看授權的持久化操作
+type PrivateAlbumRepository interface {
+	// Grant 建立授權，已存在時不重複建立
+	// 用於擁有者授權配對對象查看私人相簿
+	Grant(ctx context.Context, grant *entity.PrivateAlbumGrant) error
+
+	// Revoke 撤銷擁有者對特定用戶的授權，授權不存在時視為成功
+	// 用於擁有者收回授權
+	Revoke(ctx context.Context, ownerID, viewerID uint) error
+
+	// RevokeBetween 撤銷兩位用戶之間雙向的授權
+	// 用於取消配對或封鎖時自動收回授權
+	RevokeBetween(ctx context.Context, user1ID, user2ID uint) error
+
+	// HasAccess 檢查用戶是否獲得擁有者授權
+	// 用於私人相簿照片與下載連結的權限檢查
+	HasAccess(ctx context.Context, ownerID, viewerID uint) (bool, error)
+
+	// GetByOwnerID 獲取擁有者的所有授權，依授權時間由新到舊排序
+	// 用於擁有者管理授權名單
+	GetByOwnerID(ctx context.Context, ownerID uint) ([]*entity.PrivateAlbumGrant, error)
+}
+
+// PhotoQueueParams 照片審核佇列查詢參數
+type PhotoQueueParams struct {
+	ModeratorID uint              // 查詢的審核員
+	Type        *entity.PhotoType // 照片類型
+	UserID      *uint             // 特定用戶的照片
+	OnlyMine    bool              // 只列出查詢者認領中的照片
+	OnlyFlagged bool              // 只列出自動偵測標記的照片
+	StaleBefore time.Time         // 認領時間早於此時間視為已逾時
+
+	// IncludeClaimed 包含其他審核員認領中的照片，預設只列出可認領的照片
+	IncludeClaimed bool
+
+	Limit  int
+	Offset int
 }
 
 // InterestRepository 興趣標籤數據儲存庫介面
 // 提供興趣標籤的持久化操作，支援配對演算法
 type InterestRepository interface {
-	// GetAll 獲取所有可用興趣標籤
-	// 用於註冊和檔案編輯時的選項展示
-	GetAll(ctx context.Context) ([]*entity.Interest, error)
+	// GetAll 獲取興趣標籤與在地化名稱、同義詞，activeOnly 為 true 時只回傳啟用中的興趣
+	// 用於註冊和檔案編輯時的選項展示，以及管理後台
+	GetAll(ctx context.Context, activeOnly bool) ([]*entity.Interest, error)
+
+	// GetByID 根據 ID 獲取興趣標籤，不存在時回傳 nil
+	GetByID(ctx context.Context, id uint) (*entity.Interest, error)
+
+	// GetByIDs 根據 ID 批次獲取興趣標籤，不存在的 ID 略過
+	GetByIDs(ctx context.Context, ids []uint) ([]*entity.Interest, error)
+
+	// GetByName 根據標準名稱獲取興趣標籤，不存在時回傳 nil
+	// 用於檢查名稱重複與 CSV 匯入
+	GetByName(ctx context.Context, name string) (*entity.Interest, error)
+
+	// Search 以字首搜尋啟用中的興趣，比對名稱、在地化名稱與同義詞，依使用人數排序
+	// 用於輸入時的自動完成
+	Search(ctx context.Context, prefix string, limit int) ([]*entity.Interest, error)
 
 	// GetByUserID 獲取用戶的興趣標籤
 	// 用於檔案展示和配對演算法
 	GetByUserID(ctx context.Context, userID uint) ([]*entity.Interest, error)
 
 	// SetUserInterests 設定用戶興趣標籤
-	// 用於更新用戶興趣偏好
+	// 與受影響興趣的使用人數在同一交易中更新
 	SetUserInterests(ctx context.Context, userID uint, interestIDs []uint) error
 
-	// Create 創建新興趣標籤
+	// Create 創建新興趣標籤與在地化名稱、同義詞
 	// 用於系統管理功能
 	Create(ctx context.Context, interest *entity.Interest) error
 
-	// Update 更新興趣標籤
-	// 用於系統管理功能
+	// Update 更新興趣標籤，並以新的在地化名稱與同義詞取代原有資料
+	// 不變更使用人數，用於系統管理功能
 	Update(ctx context.Context, interest *entity.Interest) error
 
-	// Delete 刪除興趣標籤
+	// Delete 刪除興趣標籤，一併移除用戶的興趣關聯
 	// 用於系統管理功能
 	Delete(ctx context.Context, id uint) error
 }
@@ -0,0 +1,431 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+)
+
+// 帳號刪除相關錯誤
+var (
+	ErrAccountPendingDeletion = errors.New("帳戶已排定刪除")
+	ErrDeletionNotScheduled   = errors.New("帳戶未排定刪除")
+	ErrInvalidCredentials     = errors.New("Email 或密碼錯誤")
+
+	ErrDeletionConfirmationRequired    = errors.New("帳號未設定密碼，請透過 Email 確認")
+	ErrDeletionPasswordRequired        = errors.New("帳號已設定密碼，請以密碼確認")
+	ErrDeletionConfirmationUnavailable = errors.New("Email 確認服務不可用")
+	ErrInvalidDeletionToken            = errors.New("確認權杖無效或已過期")
+)
+
+const (
+	// DefaultDeletionGracePeriod 預設刪除寬限期，期間內可取消刪除
+	DefaultDeletionGracePeriod = 30 * 24 * time.Hour
+	erasureBatchSize           = 50
+
+	deletionTokenTTL      = 30 * time.Minute
+	deletionRequestWindow = time.Minute
+)
+
+// 確認信用途，寫入權杖儲存鍵以區分刪除與取消刪除
+const (
+	deletionPurposeDelete = "delete"
+	deletionPurposeCancel = "cancel"
+)
+
+// FileRemover 檔案刪除介面
+// 由上傳目錄適配器實作，檔案不存在時應視為成功
+type FileRemover interface {
+	RemoveFile(path string) error
+}
+
+// UserDataCache 用戶資料快取清除介面
+// 由 Redis 快取適配器實作
+type UserDataCache interface {
+	ClearUserCache(userID uint) error
+	ClearChatCache(chatID uint) error
+}
+
+// DeletionConfirmationStore 刪除確認權杖儲存介面
+// 由 Redis CacheService 實作，Consume 必須為原子操作以確保權杖只能使用一次
+type DeletionConfirmationStore interface {
+	SetVerificationCode(identifier, code string, expiration time.Duration) error
+	ConsumeVerificationCode(identifier string) (string, error)
+	IncrementRateLimit(identifier string, expiration time.Duration) (int64, error)
+}
+
+// AccountDeletionService 帳號刪除業務邏輯服務
+// 負責刪除申請、寬限期內取消，以及寬限期後清除個人資料
+// 未設定密碼的帳號（外部登入建立）以 Email 確認信代替密碼驗證身分
+type AccountDeletionService struct {
+	userRepo    repository.UserRepository
+	erasureRepo repository.AccountErasureRepository
+	sessions    SessionRevoker
+	gracePeriod time.Duration
+	files       FileRemover          // 可選，未設定時不刪除上傳檔案
+	cache       UserDataCache        // 可選，未設定時不清除快取
+	connections ConnectionTerminator // 可選，未設定時不中斷即時連線
+	archives    ArchiveStore         // 可選，未設定時不刪除資料匯出封存檔
+
+	// 可選，未設定時未設定密碼的帳號無法刪除或取消刪除
+	mailer Mailer
+	tokens DeletionConfirmationStore
+}
+
+// NewAccountDeletionService 創建新的帳號刪除服務實例
+func NewAccountDeletionService(
+	userRepo repository.UserRepository,
+	erasureRepo repository.AccountErasureRepository,
+	sessions SessionRevoker,
+	gracePeriod time.Duration,
+) *AccountDeletionService {
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultDeletionGracePeriod
+	}
+	return &AccountDeletionService{
+		userRepo:    userRepo,
+		erasureRepo: erasureRepo,
+		sessions:    sessions,
+		gracePeriod: gracePeriod,
+	}
+}
+
+// SetFileRemover 設定上傳檔案刪除器
+func (s *AccountDeletionService) SetFileRemover(files FileRemover) {
+	s.files = files
+}
+
+// SetArchiveStore 設定資料匯出封存檔儲存
+func (s *AccountDeletionService) SetArchiveStore(archives ArchiveStore) {
+	s.archives = archives
+}
+
+// SetUserDataCache 設定用戶資料快取
+func (s *AccountDeletionService) SetUserDataCache(cache UserDataCache) {
+	s.cache = cache
+}
+
+// SetConnectionTerminator 設定即時連線中斷器
+func (s *AccountDeletionService) SetConnectionTerminator(connections ConnectionTerminator) {
+	s.connections = connections
+}
+
+// SetConfirmationMailer 設定無密碼帳號使用的確認信寄送與權杖儲存
+func (s *AccountDeletionService) SetConfirmationMailer(mailer Mailer, tokens DeletionConfirmationStore) {
+	s.mailer = mailer
+	s.tokens = tokens
+}
+
+// RequestDeletion 申請刪除帳號，需確認密碼
+// 帳號立即停用並登出所有裝置，寬限期後清除個人資料，返回預定清除時間
+// 未設定密碼的帳號返回 ErrDeletionConfirmationRequired，需改用 SendDeletionConfirmation
+func (s *AccountDeletionService) RequestDeletion(ctx context.Context, userID uint, password string) (time.Time, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("用戶不存在: %w", err)
+	}
+
+	if user.IsPendingDeletion() || user.IsErased() {
+		return time.Time{}, ErrAccountPendingDeletion
+	}
+
+	if user.PasswordHash == "" {
+		return time.Time{}, ErrDeletionConfirmationRequired
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return time.Time{}, ErrIncorrectPassword
+	}
+
+	return s.scheduleDeletion(ctx, user)
+}
+
+// SendDeletionConfirmation 寄送刪除帳號確認信給未設定密碼的已登入用戶
+// 請求過於頻繁時靜默成功
+func (s *AccountDeletionService) SendDeletionConfirmation(ctx context.Context, userID uint) error {
+	if s.mailer == nil || s.tokens == nil {
+		return ErrDeletionConfirmationUnavailable
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("用戶不存在: %w", err)
+	}
+	if user.IsPendingDeletion() || user.IsErased() {
+		return ErrAccountPendingDeletion
+	}
+	if user.PasswordHash != "" {
+		return ErrDeletionPasswordRequired
+	}
+
+	return s.sendConfirmation(ctx, user, deletionPurposeDelete)
+}
+
+// ConfirmDeletion 以確認信中的權杖申請刪除帳號，返回預定清除時間
+func (s *AccountDeletionService) ConfirmDeletion(ctx context.Context, token string) (time.Time, error) {
+	user, err := s.consumeConfirmation(ctx, token, deletionPurposeDelete)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if user.IsPendingDeletion() || user.IsErased() {
+		return time.Time{}, ErrAccountPendingDeletion
+	}
+	return s.scheduleDeletion(ctx, user)
+}
+
+// scheduleDeletion 停用帳號、排定清除時間並登出所有裝置
+func (s *AccountDeletionService) scheduleDeletion(ctx context.Context, user *entity.User) (time.Time, error) {
+	userID := user.ID
+	scheduledAt := time.Now().Add(s.gracePeriod)
+	user.ScheduleDeletion(scheduledAt)
+	if err := s.userRepo.UpdateDeletionSchedule(ctx, user); err != nil {
+		return time.Time{}, fmt.Errorf("排定帳號刪除失敗: %w", err)
+	}
+
+	if err := s.sessions.RevokeAllSessions(ctx, userID); err != nil {
+		return time.Time{}, err
+	}
+	if s.connections != nil {
+		s.connections.DisconnectUser(userID)
+	}
+
+	return scheduledAt, nil
+}
+
+// CancelDeletion 在寬限期內取消刪除並重新啟用帳號
+// 申請刪除時已登出所有裝置，因此以帳號密碼驗證身分
+func (s *AccountDeletionService) CancelDeletion(ctx context.Context, email, password string) error {
+	user, err := s.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		return ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return ErrInvalidCredentials
+	}
+
+	if !user.IsPendingDeletion() {
+		return ErrDeletionNotScheduled
+	}
+
+	return s.cancelDeletion(ctx, user)
+}
+
+// SendCancellationConfirmation 寄送取消刪除確認信給未設定密碼且排定刪除的帳號
+// 申請刪除後已登出所有裝置，因此以 Email 識別帳號；帳號不存在或請求過於頻繁時靜默成功，避免洩漏帳號狀態
+func (s *AccountDeletionService) SendCancellationConfirmation(ctx context.Context, email string) error {
+	if s.mailer == nil || s.tokens == nil {
+		return ErrDeletionConfirmationUnavailable
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil || !user.IsPendingDeletion() || user.PasswordHash != "" {
+		return nil
+	}
+
+	return s.sendConfirmation(ctx, user, deletionPurposeCancel)
+}
+
+// ConfirmCancellation 以確認信中的權杖取消刪除並重新啟用帳號
+func (s *AccountDeletionService) ConfirmCancellation(ctx context.Context, token string) error {
+	user, err := s.consumeConfirmation(ctx, token, deletionPurposeCancel)
+	if err != nil {
+		return err
+	}
+	if !user.IsPendingDeletion() {
+		return ErrDeletionNotScheduled
+	}
+	return s.cancelDeletion(ctx, user)
+}
+
+// cancelDeletion 取消排定的刪除並重新啟用帳號
+func (s *AccountDeletionService) cancelDeletion(ctx context.Context, user *entity.User) error {
+	user.CancelDeletion()
+	if err := s.userRepo.UpdateDeletionSchedule(ctx, user); err != nil {
+		return fmt.Errorf("取消帳號刪除失敗: %w", err)
+	}
+	return nil
+}
+
+// EraseDueAccounts 清除所有寬限期已過的帳號資料
+// 單一帳號清除失敗時記錄日誌並略過，留待下次排程重試，不影響其他帳號
+func (s *AccountDeletionService) EraseDueAccounts(ctx context.Context, now time.Time) (int, error) {
+	erased := 0
+	var afterID uint
+	for {
+		users, err := s.userRepo.GetDueDeletions(ctx, now, afterID, erasureBatchSize)
+		if err != nil {
+			return erased, err
+		}
+
+		for _, user := range users {
+			afterID = user.ID
+			if err := s.eraseAccount(ctx, user.ID, now); err != nil {
+				log.Printf("清除用戶 %d 資料失敗，下次排程重試: %v", user.ID, err)
+				continue
+			}
+			erased++
+		}
+
+		if len(users) < erasureBatchSize {
+			return erased, nil
+		}
+	}
+}
+
+// StartErasureJob 啟動定期清除到期帳號的背景工作，直到 ctx 取消
+func (s *AccountDeletionService) StartErasureJob(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				count, err := s.EraseDueAccounts(ctx, now)
+				if err != nil {
+					log.Printf("清除到期刪除帳號失敗: %v", err)
+				}
+				if count > 0 {
+					log.Printf("已清除 %d 個到期刪除的帳號", count)
+				}
+			}
+		}
+	}()
+}
+
+// 私有輔助方法
+
+// sendConfirmation 產生單次使用的確認權杖並寄出確認信
+func (s *AccountDeletionService) sendConfirmation(ctx context.Context, user *entity.User, purpose string) error {
+	count, err := s.tokens.IncrementRateLimit(fmt.Sprintf("account_deletion_request:%s:%d", purpose, user.ID), deletionRequestWindow)
+	if err != nil {
+		return fmt.Errorf("檢查發送頻率失敗: %w", err)
+	}
+	if count > 1 {
+		return nil
+	}
+
+	token, err := generateSecureToken(32)
+	if err != nil {
+		return fmt.Errorf("生成確認權杖失敗: %w", err)
+	}
+	if err := s.tokens.SetVerificationCode(deletionTokenKey(purpose, token), strconv.FormatUint(uint64(user.ID), 10), deletionTokenTTL); err != nil {
+		return fmt.Errorf("儲存確認權杖失敗: %w", err)
+	}
+
+	msg := &EmailMessage{
+		To:      user.Email,
+		Subject: "確認刪除您的帳號",
+		Body:    buildDeletionBody(token, purpose),
+	}
+	if purpose == deletionPurposeCancel {
+		msg.Subject = "確認取消刪除您的帳號"
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("發送確認信失敗: %w", err)
+	}
+	return nil
+}
+
+// consumeConfirmation 使用確認權杖並返回對應的用戶，權杖只能使用一次
+func (s *AccountDeletionService) consumeConfirmation(ctx context.Context, token, purpose string) (*entity.User, error) {
+	if s.tokens == nil {
+		return nil, ErrDeletionConfirmationUnavailable
+	}
+	if token == "" {
+		return nil, ErrInvalidDeletionToken
+	}
+
+	value, err := s.tokens.ConsumeVerificationCode(deletionTokenKey(purpose, token))
+	if err != nil {
+		return nil, ErrInvalidDeletionToken
+	}
+	userID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidDeletionToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, uint(userID))
+	if err != nil || user.PasswordHash != "" {
+		// 確認信寄出後才設定密碼的帳號改以密碼驗證
+		return nil, ErrInvalidDeletionToken
+	}
+	return user, nil
+}
+
+// deletionTokenKey 確認權杖的儲存鍵
+func deletionTokenKey(purpose, token string) string {
+	return "account_deletion:" + purpose + ":" + hashToken(token)
+}
+
+// buildDeletionBody 組裝刪除或取消刪除確認信內容
+func buildDeletionBody(token, purpose string) string {
+	var b strings.Builder
+	b.WriteString("您好，\n\n")
+	if purpose == deletionPurposeCancel {
+		b.WriteString("我們收到了取消刪除您帳號的請求，確認後帳號將重新啟用。\n")
+	} else {
+		b.WriteString("我們收到了刪除您帳號的請求，確認後帳號將立即停用，並於寬限期後清除所有個人資料。\n")
+	}
+	b.WriteString(fmt.Sprintf("您的確認權杖為：%s\n", token))
+	b.WriteString(fmt.Sprintf("此權杖將於 %d 分鐘後失效，且只能使用一次。\n", int(deletionTokenTTL.Minutes())))
+	b.WriteString("\n如果這不是您本人的操作，請忽略此郵件。\n")
+	return b.String()
+}
+
+// eraseAccount 清除單一帳號的資料庫資料、上傳檔案、會話與快取
+// 資料庫交易成功後，檔案與快取清除失敗只記錄日誌
+func (s *AccountDeletionService) eraseAccount(ctx context.Context, userID uint, now time.Time) error {
+	// 寬限期間用戶可能重新取得會話，清除前再次撤銷
+	if err := s.sessions.RevokeAllSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	result, err := s.erasureRepo.EraseUser(ctx, userID, now)
+	if err != nil {
+		return err
+	}
+
+	if s.files != nil {
+		for _, path := range result.FilePaths {
+			if err := s.files.RemoveFile(path); err != nil {
+				log.Printf("刪除用戶 %d 檔案失敗 (%s): %v", userID, path, err)
+			}
+		}
+	}
+
+	if s.archives != nil {
+		for _, name := range result.Archives {
+			if err := s.archives.RemoveArchive(name); err != nil {
+				log.Printf("刪除用戶 %d 資料匯出檔案失敗 (%s): %v", userID, name, err)
+			}
+		}
+	}
+
+	if s.cache != nil {
+		if err := s.cache.ClearUserCache(userID); err != nil {
+			log.Printf("清除用戶 %d 快取失敗: %v", userID, err)
+		}
+		for _, matchID := range result.MatchIDs {
+			if err := s.cache.ClearChatCache(matchID); err != nil {
+				log.Printf("清除配對 %d 聊天快取失敗: %v", matchID, err)
+			}
+		}
+	}
+
+	if s.connections != nil {
+		s.connections.DisconnectUser(userID)
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+)
+
+// 帳號停權相關錯誤
+var (
+	ErrAccountSuspended   = errors.New("帳戶已被暫停")
+	ErrAccountBanned      = errors.New("帳戶已被封禁")
+	ErrInvalidSuspension  = errors.New("暫停天數必須介於 1 到 365 天")
+	ErrModerationReason   = errors.New("必須提供處分原因")
+	ErrAccountAlreadyOpen = errors.New("帳戶目前未被停權")
+)
+
+const (
+	maxSuspensionDays      = 365
+	reinstatementBatchSize = 100
+)
+
+// ConnectionTerminator 即時連線中斷介面
+// 由 WebSocket 管理器適配器實作，帳號停權時中斷其連線
+type ConnectionTerminator interface {
+	DisconnectUser(userID uint)
+}
+
+// AccountModerationService 帳號停權業務邏輯服務
+// 負責暫停、封禁、恢復帳號，以及到期暫停的自動恢復
+type AccountModerationService struct {
+	userRepo       repository.UserRepository
+	moderationRepo repository.ModerationRepository
+	sessions       SessionRevoker
+	connections    ConnectionTerminator // 可選，未設定時不中斷即時連線
+}
+
+// NewAccountModerationService 創建新的帳號停權服務實例
+func NewAccountModerationService(
+	userRepo repository.UserRepository,
+	moderationRepo repository.ModerationRepository,
+	sessions SessionRevoker,
+) *AccountModerationService {
+	return &AccountModerationService{
+		userRepo:       userRepo,
+		moderationRepo: moderationRepo,
+		sessions:       sessions,
+	}
+}
+
+// SetConnectionTerminator 設定即時連線中斷器
+func (s *AccountModerationService) SetConnectionTerminator(connections ConnectionTerminator) {
+	s.connections = connections
+}
+
+// SuspendUser 暫停用戶帳號指定天數
+// actorID 為 nil 時代表系統自動處分
+func (s *AccountModerationService) SuspendUser(ctx context.Context, userID uint, actorID *uint, days int, reason string) error {
+	if days <= 0 || days > maxSuspensionDays {
+		return ErrInvalidSuspension
+	}
+
+	user, err := s.authorize(ctx, userID, actorID, entity.PermissionUserSuspend, reason)
+	if err != nil {
+		return err
+	}
+
+	// 暫停不能覆蓋永久封禁，需先解除封禁
+	if user.AccountStatus == entity.AccountStatusBanned {
+		return ErrAccountBanned
+	}
+
+	until := time.Now().AddDate(0, 0, days)
+	user.Suspend(until, strings.TrimSpace(reason), actorID)
+	if err := s.applyRestriction(ctx, user); err != nil {
+		return err
+	}
+
+	s.writeLog(ctx, user, actorID, "suspended", fmt.Sprintf("暫停 %d 天，至 %s", days, until.Format(time.RFC3339)))
+	return nil
+}
+
+// BanUser 永久封禁用戶帳號
+func (s *AccountModerationService) BanUser(ctx context.Context, userID uint, actorID *uint, reason string) error {
+	user, err := s.authorize(ctx, userID, actorID, entity.PermissionUserBan, reason)
+	if err != nil {
+		return err
+	}
+
+	user.Ban(strings.TrimSpace(reason), actorID)
+	if err := s.applyRestriction(ctx, user); err != nil {
+		return err
+	}
+
+	s.writeLog(ctx, user, actorID, "banned", "永久封禁")
+	return nil
+}
+
+// ReinstateUser 手動恢復被暫停或封禁的帳號
+// 解除封禁需要封禁權限，解除暫停只需要暫停權限
+func (s *AccountModerationService) ReinstateUser(ctx context.Context, userID uint, actorID *uint, reason string) error {
+	target, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("用戶不存在: %w", err)
+	}
+
+	permission := entity.PermissionUserSuspend
+	if target.AccountStatus == entity.AccountStatusBanned {
+		permission = entity.PermissionUserBan
+	}
+
+	user, err := s.authorize(ctx, userID, actorID, permission, reason)
+	if err != nil {
+		return err
+	}
+
+	if user.AccountStatus == entity.AccountStatusActive || user.AccountStatus == "" {
+		return ErrAccountAlreadyOpen
+	}
+
+	user.Reinstate(strings.TrimSpace(reason), actorID)
+	if err := s.userRepo.UpdateAccountStatus(ctx, user); err != nil {
+		return fmt.Errorf("恢復帳號失敗: %w", err)
+	}
+
+	s.writeLog(ctx, user, actorID, "reinstated", "手動恢復帳號")
+	return nil
+}
+
+// GetAccountStatus 獲取用戶目前的帳號狀態與暫停期限
+func (s *AccountModerationService) GetAccountStatus(ctx context.Context, userID uint) (entity.AccountStatus, *time.Time, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", nil, fmt.Errorf("用戶不存在: %w", err)
+	}
+
+	status := user.GetAccountStatus(time.Now())
+	if status == entity.AccountStatusSuspended {
+		return status, user.SuspendedUntil, nil
+	}
+	return status, nil, nil
+}
+
+// ReinstateExpiredSuspensions 恢復所有暫停期限已過的帳號，並寫入審核日誌
+func (s *AccountModerationService) ReinstateExpiredSuspensions(ctx context.Context, now time.Time) (int, error) {
+	reinstated := 0
+	for {
+		users, err := s.userRepo.GetExpiredSuspensions(ctx, now, reinstatementBatchSize)
+		if err != nil {
+			return reinstated, err
+		}
+
+		for _, user := range users {
+			user.Reinstate("暫停期限已到", nil)
+			if err := s.userRepo.UpdateAccountStatus(ctx, user); err != nil {
+				return reinstated, fmt.Errorf("恢復用戶 %d 失敗: %w", user.ID, err)
+			}
+			s.writeLog(ctx, user, nil, "reinstated", "暫停期限已到，自動恢復帳號")
+			reinstated++
+		}
+
+		if len(users) < reinstatementBatchSize {
+			return reinstated, nil
+		}
+	}
+}
+
+// StartReinstatementJob 啟動定期恢復到期暫停帳號的背景工作，直到 ctx 取消
+func (s *AccountModerationService) StartReinstatementJob(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				count, err := s.ReinstateExpiredSuspensions(ctx, now)
+				if err != nil {
+					log.Printf("自動恢復暫停帳號失敗: %v", err)
+				}
+				if count > 0 {
+					log.Printf("已自動恢復 %d 個暫停到期的帳號", count)
+				}
+			}
+		}
+	}()
+}
+
+// 私有輔助方法
+
+// checkAccountStatus 檢查帳號是否可正常使用
+// 暫停中返回 ErrAccountSuspended，封禁返回 ErrAccountBanned
+func checkAccountStatus(user *entity.User) error {
+	switch user.GetAccountStatus(time.Now()) {
+	case entity.AccountStatusSuspended:
+		if user.SuspendedUntil != nil {
+			return fmt.Errorf("%w，至 %s", ErrAccountSuspended, user.SuspendedUntil.Format("2006-01-02 15:04"))
+		}
+		return ErrAccountSuspended
+	case entity.AccountStatusBanned:
+		return ErrAccountBanned
+	default:
+		return nil
+	}
+}
+
+// authorize 檢查處分原因與操作者權限，返回目標用戶
+// 操作者必須擁有指定權限，且只能處分角色等級低於自己的用戶
+func (s *AccountModerationService) authorize(ctx context.Context, userID uint, actorID *uint, permission entity.Permission, reason string) (*entity.User, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, ErrModerationReason
+	}
+
+	target, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("用戶不存在: %w", err)
+	}
+
+	// 系統自動處分不需檢查操作者
+	if actorID == nil {
+		return target, nil
+	}
+
+	if *actorID == userID {
+		return nil, ErrPermissionDenied
+	}
+
+	actor, err := s.userRepo.GetByID(ctx, *actorID)
+	if err != nil {
+		return nil, fmt.Errorf("操作者不存在: %w", err)
+	}
+
+	if !actor.IsActive || !actor.HasPermission(permission) {
+		return nil, ErrPermissionDenied
+	}
+
+	if target.GetRole().Level() >= actor.GetRole().Level() {
+		return nil, ErrPermissionDenied
+	}
+
+	return target, nil
+}
+
+// applyRestriction 保存停權狀態並撤銷用戶所有會話與即時連線
+func (s *AccountModerationService) applyRestriction(ctx context.Context, user *entity.User) error {
+	if err := s.userRepo.UpdateAccountStatus(ctx, user); err != nil {
+		return fmt.Errorf("更新帳號狀態失敗: %w", err)
+	}
+
+	if err := s.sessions.RevokeAllSessions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if s.connections != nil {
+		s.connections.DisconnectUser(user.ID)
+	}
+	return nil
+}
+
+// writeLog 寫入帳號處分審核日誌，失敗只記錄日誌不影響處分結果
+func (s *AccountModerationService) writeLog(ctx context.Context, user *entity.User, actorID *uint, action, notes string) {
+	moderationLog := &repository.ModerationLog{
+		ContentType: "user",
+		ContentID:   user.ID,
+		UserID:      user.ID,
+		ModeratorID: actorID,
+		Action:      action,
+		Reason:      user.StatusReason,
+		IsAutomatic: actorID == nil,
+		Notes:       notes,
+	}
+
+	if err := s.moderationRepo.CreateModerationLog(ctx, moderationLog); err != nil {
+		log.Printf("寫入帳號處分日誌失敗 (用戶 %d): %v", user.ID, err)
+	}
+}
@@ -0,0 +1,591 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+)
+
+// 認證相關錯誤
+var (
+	ErrInvalidRefreshToken     = errors.New("無效或已過期的刷新權杖")
+	ErrRefreshTokenReused      = errors.New("刷新權杖已被使用，該會話已撤銷")
+	ErrSessionStoreUnavailable = errors.New("會話服務不可用")
+	ErrInvalidMFAToken         = errors.New("兩步驟驗證權杖無效或已過期")
+	ErrSessionNotFound         = errors.New("會話不存在或已失效")
+)
+
+// sessionActivityInterval 更新會話最後活躍時間的最小間隔，避免每個請求都寫入
+const sessionActivityInterval = time.Minute
+
+// mfaTokenTTL 密碼驗證通過後完成第二步驗證的期限
+const mfaTokenTTL = 5 * time.Minute
+
+// AccessTokenIssuer 存取權杖簽發介面
+// 由 JWT 中間件實作，業務層不直接處理簽章細節
+type AccessTokenIssuer interface {
+	IssueAccessToken(userID uint, email, role, sessionID string, duration time.Duration) (token string, tokenID string, err error)
+}
+
+// MFATokenIssuer 兩步驟驗證暫時權杖簽發介面
+// 暫時權杖只能用於完成第二步驗證，不能存取其他 API；解析時返回用戶 ID 與權杖的 jti
+type MFATokenIssuer interface {
+	IssueMFAToken(userID uint, duration time.Duration) (string, error)
+	ParseMFAToken(token string) (userID uint, tokenID string, err error)
+}
+
+// AuthSessionStore 認證會話儲存介面
+// 每個會話即為一個刷新權杖家族
+type AuthSessionStore interface {
+	CreateSession(session *AuthSession) error
+	GetSession(sessionID string) (*AuthSession, error)
+	ListUserSessions(userID uint) ([]*AuthSession, error)
+	TouchSession(userID uint, sessionID, tokenID string) error // tokenID 非空時更新綁定的存取權杖
+	DeleteSession(userID uint, sessionID string) error
+	DeleteAllUserSessions(userID uint) error
+	DeleteOtherUserSessions(userID uint, keepSessionID string) error
+	StoreRefreshToken(tokenHash string, record *RefreshTokenRecord, ttl time.Duration) error
+	ConsumeRefreshToken(tokenHash string) (record *RefreshTokenRecord, firstUse bool, err error)
+}
+
+// TokenBlacklist 存取權杖黑名單介面
+type TokenBlacklist interface {
+	AddTokenToBlacklist(tokenID string, expiration time.Duration) error
+	IsTokenBlacklisted(tokenID string) (bool, error)
+}
+
+// AuthSession 認證會話
+type AuthSession struct {
+	SessionID  string    `json:"session_id"`
+	UserID     uint      `json:"user_id"`
+	Email      string    `json:"email"`
+	Role       string    `json:"role"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	TokenID    string    `json:"-"` // 目前綁定的存取權杖 jti，撤銷會話時一併列入黑名單
+	LoginTime  time.Time `json:"login_time"`
+	LastActive time.Time `json:"last_active"`
+}
+
+// RefreshTokenRecord 刷新權杖記錄
+type RefreshTokenRecord struct {
+	UserID    uint      `json:"user_id"`
+	SessionID string    `json:"session_id"`
+	IssuedAt  time.Time `json:"issued_at"`
+}
+
+// ClientInfo 發起請求的用戶端資訊
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// AuthTokens 簽發給用戶端的權杖組
+type AuthTokens struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type"`
+	ExpiresIn    int64     `json:"expires_in"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	SessionID    string    `json:"-"`
+	TokenID      string    `json:"-"`
+}
+
+// MFAChallenge 登入第二步驗證挑戰
+type MFAChallenge struct {
+	Token              string `json:"mfa_token"`
+	ExpiresIn          int64  `json:"expires_in"`
+	EnrollmentRequired bool   `json:"enrollment_required"` // 必須先綁定驗證器
+}
+
+// LoginResult 登入結果
+// 需要兩步驟驗證時只返回 MFAChallenge，不簽發權杖
+type LoginResult struct {
+	Tokens        *AuthTokens
+	User          *UserResponse
+	MFAChallenge  *MFAChallenge
+	RecoveryCodes []string // 登入時完成強制綁定所產生的恢復碼
+}
+
+// AuthService 認證業務邏輯服務
+// 負責登入會話、存取權杖與刷新權杖的簽發、輪換與撤銷
+type AuthService struct {
+	userService     *UserService
+	issuer          AccessTokenIssuer
+	sessions        AuthSessionStore      // 可選，未設定時不支援刷新權杖
+	blacklist       TokenBlacklist        // 可選，未設定時不檢查黑名單
+	twoFactor       *TwoFactorService     // 可選，未設定時僅以密碼登入
+	throttle        *LoginThrottleService // 可選，未設定時不限制登入失敗次數
+	mfaTokens       MFATokenIssuer
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+// NewAuthService 創建新的認證服務實例
+func NewAuthService(
+	userService *UserService,
+	issuer AccessTokenIssuer,
+	accessTokenTTL time.Duration,
+	refreshTokenTTL time.Duration,
+) *AuthService {
+	return &AuthService{
+		userService:     userService,
+		issuer:          issuer,
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+	}
+}
+
+// SetSessionStore 設定會話儲存
+func (s *AuthService) SetSessionStore(sessions AuthSessionStore) {
+	s.sessions = sessions
+}
+
+// SetTokenBlacklist 設定權杖黑名單
+func (s *AuthService) SetTokenBlacklist(blacklist TokenBlacklist) {
+	s.blacklist = blacklist
+}
+
+// SetTwoFactor 設定兩步驟驗證服務與暫時權杖簽發器
+func (s *AuthService) SetTwoFactor(twoFactor *TwoFactorService, mfaTokens MFATokenIssuer) {
+	s.twoFactor = twoFactor
+	s.mfaTokens = mfaTokens
+}
+
+// SetLoginThrottle 設定登入暴力破解防護
+func (s *AuthService) SetLoginThrottle(throttle *LoginThrottleService) {
+	s.throttle = throttle
+}
+
+// Login 驗證帳號密碼並建立新的登入會話
+// 用戶需要兩步驟驗證時返回暫時權杖，待 CompleteMFALogin 驗證後才建立會話
+// 帳號或來源 IP 被限制時，即使密碼正確也會拒絕；失敗計數在實際建立會話後才清除
+func (s *AuthService) Login(ctx context.Context, req *LoginRequest, client ClientInfo) (*LoginResult, error) {
+	if s.throttle != nil {
+		if err := s.throttle.Check(ctx, req.Email, client); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := s.userService.Login(ctx, req)
+	if err != nil {
+		if s.throttle != nil && errors.Is(err, ErrInvalidCredentials) {
+			s.throttle.RecordFailure(ctx, req.Email, client)
+		}
+		return nil, err
+	}
+
+	result, err := s.completeLogin(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	// 需要第二步驗證時只通過了密碼，待 CompleteMFALogin 建立會話後才清除失敗計數
+	if s.throttle != nil && result.Tokens != nil {
+		s.throttle.RecordSuccess(ctx, req.Email, user.ID, client)
+	}
+
+	return result, nil
+}
+
+// LoginWithIdentity 為已通過外部身分驗證的用戶建立登入會話
+// 與密碼登入相同，啟用兩步驟驗證的用戶仍需完成第二步
+func (s *AuthService) LoginWithIdentity(ctx context.Context, user *entity.User, client ClientInfo) (*LoginResult, error) {
+	return s.completeLogin(ctx, newUserResponse(user, nil), client)
+}
+
+// completeLogin 第一步驗證通過後，視需要要求兩步驟驗證，否則建立會話
+func (s *AuthService) completeLogin(ctx context.Context, user *UserResponse, client ClientInfo) (*LoginResult, error) {
+	if s.twoFactor != nil {
+		challenge, enrollmentRequired, err := s.twoFactor.RequiresChallenge(ctx, user.ID, user.Role)
+		if err != nil {
+			return nil, fmt.Errorf("檢查兩步驟驗證狀態失敗: %w", err)
+		}
+
+		if challenge {
+			token, err := s.mfaTokens.IssueMFAToken(user.ID, mfaTokenTTL)
+			if err != nil {
+				return nil, fmt.Errorf("簽發兩步驟驗證權杖失敗: %w", err)
+			}
+			return &LoginResult{
+				MFAChallenge: &MFAChallenge{
+					Token:              token,
+					ExpiresIn:          int64(mfaTokenTTL.Seconds()),
+					EnrollmentRequired: enrollmentRequired,
+				},
+			}, nil
+		}
+	}
+
+	tokens, err := s.StartSession(ctx, user.ID, user.Email, string(user.Role), client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{Tokens: tokens, User: user}, nil
+}
+
+// CompleteMFALogin 以暫時權杖與驗證碼（或恢復碼）完成登入第二步並建立會話
+// 暫時權杖只能成功使用一次；驗證失敗依帳號累計延遲或鎖定，同一權杖失敗過多次後作廢
+func (s *AuthService) CompleteMFALogin(ctx context.Context, mfaToken, code string, client ClientInfo) (*LoginResult, error) {
+	userID, tokenID, err := s.parseMFAToken(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.throttle != nil {
+		if err := s.throttle.CheckSecondFactor(ctx, userID, client); err != nil {
+			return nil, err
+		}
+	}
+
+	user, recoveryCodes, err := s.twoFactor.VerifyLogin(ctx, userID, code)
+	if err != nil {
+		if s.throttle != nil && errors.Is(err, ErrInvalidTwoFactorCode) &&
+			s.throttle.RecordSecondFactorFailure(ctx, userID, tokenID, client) {
+			s.revokeMFAToken(tokenID)
+		}
+		return nil, err
+	}
+	s.revokeMFAToken(tokenID)
+
+	tokens, err := s.StartSession(ctx, user.ID, user.Email, string(user.GetRole()), client)
+	if err != nil {
+		return nil, err
+	}
+	if s.throttle != nil {
+		s.throttle.RecordSecondFactorSuccess(ctx, user.Email, user.ID, client)
+	}
+
+	return &LoginResult{
+		Tokens:        tokens,
+		User:          newUserResponse(user, nil),
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// BeginMFAEnrollment 以暫時權杖開始綁定驗證器
+// 用於必須啟用兩步驟驗證但尚未綁定的用戶在登入過程中完成綁定
+func (s *AuthService) BeginMFAEnrollment(ctx context.Context, mfaToken string) (*TOTPEnrollment, error) {
+	userID, _, err := s.parseMFAToken(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.twoFactor.BeginEnrollment(ctx, userID)
+}
+
+// StartSession 為已通過驗證的用戶建立會話並簽發權杖
+// 角色會寫入會話與存取權杖，角色變更時需撤銷用戶的所有會話
+func (s *AuthService) StartSession(ctx context.Context, userID uint, email, role string, client ClientInfo) (*AuthTokens, error) {
+	// 沒有會話儲存時只簽發存取權杖
+	if s.sessions == nil {
+		return s.issueAccessToken(userID, email, role, "")
+	}
+
+	sessionID, err := generateSecureToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("生成會話ID失敗: %w", err)
+	}
+
+	// 先簽發存取權杖，讓會話綁定其 jti
+	tokens, err := s.issueAccessToken(userID, email, role, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &AuthSession{
+		SessionID:  sessionID,
+		UserID:     userID,
+		Email:      email,
+		Role:       role,
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+		TokenID:    tokens.TokenID,
+		LoginTime:  now,
+		LastActive: now,
+	}
+	if err := s.sessions.CreateSession(session); err != nil {
+		return nil, fmt.Errorf("建立會話失敗: %w", err)
+	}
+
+	if err := s.attachRefreshToken(tokens, userID, sessionID); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Refresh 以刷新權杖換發新的權杖組
+// 每個刷新權杖只能使用一次；重複使用代表權杖可能外洩，會撤銷整個權杖家族
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*AuthTokens, error) {
+	if s.sessions == nil {
+		return nil, ErrSessionStoreUnavailable
+	}
+
+	if refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	record, firstUse, err := s.sessions.ConsumeRefreshToken(hashToken(refreshToken))
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if !firstUse {
+		if err := s.sessions.DeleteSession(record.UserID, record.SessionID); err != nil {
+			return nil, fmt.Errorf("撤銷會話失敗: %w", err)
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	session, err := s.sessions.GetSession(record.SessionID)
+	if err != nil || session.UserID != record.UserID {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	tokens, err := s.issueAccessToken(session.UserID, session.Email, session.Role, session.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessions.TouchSession(session.UserID, session.SessionID, tokens.TokenID); err != nil {
+		return nil, fmt.Errorf("更新會話失敗: %w", err)
+	}
+
+	if err := s.attachRefreshToken(tokens, session.UserID, session.SessionID); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// ListSessions 列出用戶所有有效的登入會話，最近活躍的排在最前面
+func (s *AuthService) ListSessions(ctx context.Context, userID uint) ([]*AuthSession, error) {
+	if s.sessions == nil {
+		return nil, ErrSessionStoreUnavailable
+	}
+
+	sessions, err := s.sessions.ListUserSessions(userID)
+	if err != nil {
+		return nil, fmt.Errorf("獲取會話列表失敗: %w", err)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastActive.After(sessions[j].LastActive)
+	})
+	return sessions, nil
+}
+
+// RevokeSession 撤銷用戶的單一會話，並將該會話目前的存取權杖列入黑名單
+// 只能撤銷屬於自己的會話
+func (s *AuthService) RevokeSession(ctx context.Context, userID uint, sessionID string) error {
+	if s.sessions == nil {
+		return ErrSessionStoreUnavailable
+	}
+
+	session, err := s.sessions.GetSession(sessionID)
+	if err != nil || session.UserID != userID {
+		return ErrSessionNotFound
+	}
+
+	if err := s.sessions.DeleteSession(userID, sessionID); err != nil {
+		return fmt.Errorf("撤銷會話失敗: %w", err)
+	}
+
+	// 會話刪除後權杖已無法通過驗證，黑名單作為額外保護，保留到權杖最長有效期
+	return s.revokeAccessToken(session.TokenID, time.Now().Add(s.accessTokenTTL))
+}
+
+// Logout 撤銷目前的會話，並將目前的存取權杖列入黑名單
+func (s *AuthService) Logout(ctx context.Context, userID uint, sessionID, tokenID string, tokenExpiresAt time.Time) error {
+	if s.sessions != nil && sessionID != "" {
+		if err := s.sessions.DeleteSession(userID, sessionID); err != nil {
+			return fmt.Errorf("撤銷會話失敗: %w", err)
+		}
+	}
+
+	return s.revokeAccessToken(tokenID, tokenExpiresAt)
+}
+
+// LogoutAll 撤銷用戶在所有裝置上的會話
+func (s *AuthService) LogoutAll(ctx context.Context, userID uint, tokenID string, tokenExpiresAt time.Time) error {
+	if s.sessions == nil {
+		return ErrSessionStoreUnavailable
+	}
+
+	if err := s.sessions.DeleteAllUserSessions(userID); err != nil {
+		return fmt.Errorf("撤銷所有會話失敗: %w", err)
+	}
+
+	return s.revokeAccessToken(tokenID, tokenExpiresAt)
+}
+
+// RevokeAllSessions 撤銷用戶的所有會話（例如重設密碼後）
+func (s *AuthService) RevokeAllSessions(ctx context.Context, userID uint) error {
+	if s.sessions == nil {
+		return nil
+	}
+
+	if err := s.sessions.DeleteAllUserSessions(userID); err != nil {
+		return fmt.Errorf("撤銷所有會話失敗: %w", err)
+	}
+	return nil
+}
+
+// RevokeOtherSessions 撤銷用戶除目前會話以外的所有會話（例如變更密碼後）
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, userID uint, currentSessionID string) error {
+	if s.sessions == nil {
+		return nil
+	}
+
+	if err := s.sessions.DeleteOtherUserSessions(userID, currentSessionID); err != nil {
+		return fmt.Errorf("撤銷其他會話失敗: %w", err)
+	}
+	return nil
+}
+
+// IsTokenRevoked 檢查存取權杖是否已被撤銷
+// 權杖被列入黑名單，或其所屬會話已不存在時視為已撤銷
+func (s *AuthService) IsTokenRevoked(tokenID, sessionID string) (bool, error) {
+	if s.blacklist != nil && tokenID != "" {
+		blacklisted, err := s.blacklist.IsTokenBlacklisted(tokenID)
+		if err != nil {
+			return false, fmt.Errorf("檢查權杖黑名單失敗: %w", err)
+		}
+		if blacklisted {
+			return true, nil
+		}
+	}
+
+	if s.sessions == nil {
+		return false, nil
+	}
+
+	// 啟用會話後簽發的權杖都必須綁定會話
+	if sessionID == "" {
+		return true, nil
+	}
+
+	session, err := s.sessions.GetSession(sessionID)
+	if err != nil {
+		return true, nil
+	}
+
+	// 順帶更新會話最後活躍時間，失敗不影響驗證結果
+	if time.Since(session.LastActive) >= sessionActivityInterval {
+		_ = s.sessions.TouchSession(session.UserID, sessionID, "")
+	}
+
+	return false, nil
+}
+
+// 私有輔助方法
+
+// parseMFAToken 解析兩步驟驗證暫時權杖，返回用戶 ID 與 jti，已作廢的權杖視為無效
+func (s *AuthService) parseMFAToken(mfaToken string) (uint, string, error) {
+	if s.twoFactor == nil || mfaToken == "" {
+		return 0, "", ErrInvalidMFAToken
+	}
+
+	userID, tokenID, err := s.mfaTokens.ParseMFAToken(mfaToken)
+	if err != nil {
+		return 0, "", ErrInvalidMFAToken
+	}
+
+	if s.blacklist != nil && tokenID != "" {
+		revoked, err := s.blacklist.IsTokenBlacklisted(tokenID)
+		if err != nil {
+			return 0, "", fmt.Errorf("檢查兩步驟驗證權杖失敗: %w", err)
+		}
+		if revoked {
+			return 0, "", ErrInvalidMFAToken
+		}
+	}
+	return userID, tokenID, nil
+}
+
+// revokeMFAToken 將暫時權杖加入黑名單直到過期，失敗只記錄日誌
+func (s *AuthService) revokeMFAToken(tokenID string) {
+	if s.blacklist == nil || tokenID == "" {
+		return
+	}
+	if err := s.blacklist.AddTokenToBlacklist(tokenID, mfaTokenTTL); err != nil {
+		log.Printf("作廢兩步驟驗證權杖失敗: %v", err)
+	}
+}
+
+// attachRefreshToken 為會話簽發新的刷新權杖並附加到權杖組
+func (s *AuthService) attachRefreshToken(tokens *AuthTokens, userID uint, sessionID string) error {
+	refreshToken, err := generateSecureToken(32)
+	if err != nil {
+		return fmt.Errorf("生成刷新權杖失敗: %w", err)
+	}
+
+	record := &RefreshTokenRecord{
+		UserID:    userID,
+		SessionID: sessionID,
+		IssuedAt:  time.Now(),
+	}
+	if err := s.sessions.StoreRefreshToken(hashToken(refreshToken), record, s.refreshTokenTTL); err != nil {
+		return fmt.Errorf("儲存刷新權杖失敗: %w", err)
+	}
+
+	tokens.RefreshToken = refreshToken
+	return nil
+}
+
+// issueAccessToken 簽發存取權杖
+func (s *AuthService) issueAccessToken(userID uint, email, role, sessionID string) (*AuthTokens, error) {
+	accessToken, tokenID, err := s.issuer.IssueAccessToken(userID, email, role, sessionID, s.accessTokenTTL)
+	if err != nil {
+		return nil, fmt.Errorf("簽發存取權杖失敗: %w", err)
+	}
+
+	return &AuthTokens{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.accessTokenTTL.Seconds()),
+		ExpiresAt:   time.Now().Add(s.accessTokenTTL),
+		SessionID:   sessionID,
+		TokenID:     tokenID,
+	}, nil
+}
+
+// revokeAccessToken 將存取權杖列入黑名單直到其自然過期
+func (s *AuthService) revokeAccessToken(tokenID string, expiresAt time.Time) error {
+	if s.blacklist == nil || tokenID == "" {
+		return nil
+	}
+
+	remaining := time.Until(expiresAt)
+	if remaining <= 0 {
+		return nil
+	}
+
+	if err := s.blacklist.AddTokenToBlacklist(tokenID, remaining); err != nil {
+		return fmt.Errorf("撤銷存取權杖失敗: %w", err)
+	}
+	return nil
+}
+
+// generateSecureToken 生成指定位元組長度的隨機十六進位字串
+func generateSecureToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashToken 計算權杖的 SHA-256 雜湊，儲存時不保留明文
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -5,13 +5,40 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"path"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"golang_dev_docker/domain/entity"
 	"golang_dev_docker/domain/repository"
 )
 
+// 聊天附件相關錯誤
+var (
+	ErrAttachmentUnavailable     = errors.New("未設定檔案儲存，無法傳送附件")
+	ErrAttachmentEmpty           = errors.New("附件不能為空")
+	ErrAttachmentTooLarge        = errors.New("附件檔案過大")
+	ErrAttachmentCaptionTooLong  = errors.New("訊息內容不能超過1000個字符")
+	ErrUnsupportedAttachmentType = errors.New("不支援的附件格式，僅接受 JPEG、PNG、GIF、WebP 圖片與 PDF")
+	ErrChatMatchNotFound         = errors.New("配對關係不存在")
+	ErrChatForbidden             = errors.New("無權限在此配對中發送訊息")
+	ErrChatNotMatched            = errors.New("配對尚未成功，無法發送訊息")
+)
+
+const (
+	// DefaultMaxAttachmentSize 聊天附件大小上限
+	DefaultMaxAttachmentSize int64 = 10 << 20
+
+	// chatAttachmentKeyPrefix 聊天附件的儲存路徑前綴，格式為 chat/<配對ID>/<檔名>
+	// 路徑由伺服器產生，下載時檢查查看者是否為該配對的一方
+	chatAttachmentKeyPrefix = "chat/"
+
+	// maxAttachmentNameLength 附件顯示檔名的長度上限，與 chat_messages.file_name 欄位一致
+	maxAttachmentNameLength = 255
+)
+
 // WebSocketNotifier WebSocket 通知介面
 type WebSocketNotifier interface {
 	SendToUser(userID uint, message interface{}) error
@@ -27,6 +54,7 @@ type ChatService struct {
 	matchRepo     repository.MatchRepository
 	userRepo      repository.UserRepository
 	wsNotifier    WebSocketNotifier // 可選的 WebSocket 通知器
+	files         *FileService      // 可選，設定後可傳送附件並為檔案訊息附上下載連結
 }
 
 // NewChatService 創建新的聊天服務實例
@@ -52,6 +80,11 @@ func (s *ChatService) SetWebSocketNotifier(notifier WebSocketNotifier) {
 	s.wsNotifier = notifier
 }
 
+// SetFileService 設定檔案服務，用於儲存聊天附件與簽發檔案訊息的下載連結
+func (s *ChatService) SetFileService(files *FileService) {
+	s.files = files
+}
+
 // SendMessageRequest 發送訊息請求
 type SendMessageRequest struct {
 	SenderID   uint               `json:"sender_id" validate:"required"`
@@ -59,9 +92,16 @@ type SendMessageRequest struct {
 	MatchID    uint               `json:"match_id" validate:"required"`
 	Type       entity.MessageType `json:"type" validate:"required"`
 	Content    string             `json:"content" validate:"required"`
-	FileName   *string            `json:"file_name,omitempty"`
-	FileSize   *int64             `json:"file_size,omitempty"`
-	FilePath   *string            `json:"file_path,omitempty"`
+}
+
+// SendAttachmentRequest 傳送聊天附件請求
+// 檔案內容由伺服器寫入儲存區，儲存路徑不由客戶端指定
+type SendAttachmentRequest struct {
+	SenderID uint   `json:"sender_id" validate:"required"`
+	MatchID  uint   `json:"match_id" validate:"required"`
+	FileName string `json:"file_name"`
+	Caption  string `json:"caption"`
+	Data     []byte `json:"-"`
 }
 
 // MessageResponse 訊息回應
@@ -140,13 +180,6 @@ func (s *ChatService) SendMessage(ctx context.Context, req *SendMessageRequest)
 		Status:     entity.MessageStatusSent,
 	}
 
-	// 處理檔案訊息的額外欄位
-	if req.Type == entity.MessageTypeImage || req.Type == entity.MessageTypeFile {
-		message.FileName = req.FileName
-		message.FileSize = req.FileSize
-		message.FilePath = req.FilePath
-	}
-
 	// 保存訊息
 	if err := s.chatRepo.CreateMessage(ctx, message); err != nil {
 		return &MessageResponse{
@@ -162,18 +195,7 @@ func (s *ChatService) SendMessage(ctx context.Context, req *SendMessageRequest)
 	}
 
 	// 發送 WebSocket 通知給接收者
-	if s.wsNotifier != nil {
-		notificationData := map[string]interface{}{
-			"type":      "new_message",
-			"message":   message,
-			"match_id":  req.MatchID,
-			"sender_id": req.SenderID,
-		}
-
-		if err := s.wsNotifier.SendToUser(req.ReceiverID, notificationData); err != nil {
-			log.Printf("發送 WebSocket 通知失敗 (用戶 %d): %v", req.ReceiverID, err)
-		}
-	}
+	s.notifyNewMessage(ctx, message)
 
 	return &MessageResponse{
 		Message: message,
@@ -181,6 +203,88 @@ func (s *ChatService) SendMessage(ctx context.Context, req *SendMessageRequest)
 	}, nil
 }
 
+// SendAttachment 傳送圖片或 PDF 附件
+// 依檔案內容判斷格式，寫入 chat/<配對ID>/ 下由伺服器產生的路徑後建立檔案訊息；
+// 下載連結綁定查看者，只有配對雙方可以下載
+func (s *ChatService) SendAttachment(ctx context.Context, req *SendAttachmentRequest) (*entity.ChatMessage, error) {
+	if s.files == nil {
+		return nil, ErrAttachmentUnavailable
+	}
+	if len(req.Data) == 0 {
+		return nil, ErrAttachmentEmpty
+	}
+	if int64(len(req.Data)) > DefaultMaxAttachmentSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	caption := strings.TrimSpace(req.Caption)
+	if len(caption) > 1000 {
+		return nil, ErrAttachmentCaptionTooLong
+	}
+
+	mimeType, extension, ok := detectDocumentType(req.Data)
+	if !ok {
+		return nil, ErrUnsupportedAttachmentType
+	}
+	messageType := entity.MessageTypeFile
+	if entity.IsValidImageMimeType(mimeType) {
+		messageType = entity.MessageTypeImage
+	}
+
+	match, err := s.matchRepo.GetMatchByID(ctx, req.MatchID)
+	if err != nil || match == nil {
+		return nil, ErrChatMatchNotFound
+	}
+	if !s.isUserInMatch(match, req.SenderID) {
+		return nil, ErrChatForbidden
+	}
+	if match.Status != entity.MatchStatusMatched {
+		return nil, ErrChatNotMatched
+	}
+	receiverID := match.User1ID
+	if receiverID == req.SenderID {
+		receiverID = match.User2ID
+	}
+
+	token, err := generateSecureToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("生成附件檔名失敗: %w", err)
+	}
+	key := chatAttachmentPrefix(req.MatchID) + token + extension
+	if err := s.files.Put(ctx, key, req.Data, mimeType); err != nil {
+		return nil, fmt.Errorf("儲存附件失敗: %w", err)
+	}
+
+	fileName := attachmentFileName(req.FileName, token+extension)
+	if caption == "" {
+		caption = fileName
+	}
+	message := &entity.ChatMessage{
+		MatchID:    req.MatchID,
+		SenderID:   req.SenderID,
+		ReceiverID: receiverID,
+		Type:       messageType,
+		Content:    caption,
+		Status:     entity.MessageStatusSent,
+	}
+	message.SetFileInfo(fileName, int64(len(req.Data)), key)
+
+	if err := s.chatRepo.CreateMessage(ctx, message); err != nil {
+		if removeErr := s.files.RemoveFile(key); removeErr != nil {
+			log.Printf("刪除未保存的聊天附件失敗 (%s): %v", key, removeErr)
+		}
+		return nil, fmt.Errorf("保存訊息失敗: %w", err)
+	}
+
+	if err := s.chatListRepo.UpdateChatActivity(ctx, req.MatchID, time.Now()); err != nil {
+		log.Printf("更新聊天活躍狀態失敗: %v", err)
+	}
+
+	s.notifyNewMessage(ctx, message)
+	s.signFileURLs(ctx, req.SenderID, message)
+	return message, nil
+}
+
 // GetChatHistory 獲取聊天歷史
 // 支援分頁載入聊天記錄
 func (s *ChatService) GetChatHistory(ctx context.Context, req *ChatHistoryRequest) (*ChatHistoryResponse, error) {
@@ -214,6 +318,7 @@ func (s *ChatService) GetChatHistory(ctx context.Context, req *ChatHistoryReques
 	if hasMore {
 		messages = messages[:limit] // 移除多餘的一條訊息
 	}
+	s.signFileURLs(ctx, req.UserID, messages...)
 
 	return &ChatHistoryResponse{
 		Messages:   messages,
@@ -368,6 +473,32 @@ func (s *ChatService) GetLastSeen(ctx context.Context, userID uint) (*time.Time,
 	return s.websocketRepo.GetLastSeen(ctx, userID)
 }
 
+// IsRestricted 檢查檔案是否為聊天附件
+// 實作 FileAccessChecker
+func (s *ChatService) IsRestricted(key string) bool {
+	return strings.HasPrefix(key, chatAttachmentKeyPrefix)
+}
+
+// CanAccess 檢查用戶是否可以下載聊天附件
+// 配對雙方可以下載，具內容審核權限的人員可下載以處理檢舉；實作 FileAccessChecker
+func (s *ChatService) CanAccess(ctx context.Context, key string, viewerID uint) (bool, error) {
+	matchID, ok := chatAttachmentMatch(key)
+	if !ok {
+		return false, nil
+	}
+
+	match, err := s.matchRepo.GetMatchByID(ctx, matchID)
+	if err == nil && match != nil && s.isUserInMatch(match, viewerID) {
+		return true, nil
+	}
+
+	viewer, err := s.userRepo.GetByID(ctx, viewerID)
+	if err != nil || viewer == nil {
+		return false, nil
+	}
+	return viewer.IsActive && viewer.HasPermission(entity.PermissionContentReview), nil
+}
+
 // 私有輔助方法
 
 // validateSendMessageRequest 驗證發送訊息請求
@@ -400,18 +531,47 @@ func (s *ChatService) validateSendMessageRequest(req *SendMessageRequest) error
 		return errors.New("訊息內容不能超過1000個字符")
 	}
 
-	// 檔案訊息的額外驗證
+	// 檔案只能經由附件上傳寫入伺服器產生的路徑，不接受客戶端指定的檔案路徑
 	if req.Type == entity.MessageTypeImage || req.Type == entity.MessageTypeFile {
-		if req.FileName == nil || strings.TrimSpace(*req.FileName) == "" {
-			return errors.New("檔案訊息必須提供檔案名稱")
-		}
+		return errors.New("圖片與檔案訊息必須透過附件上傳傳送")
+	}
+
+	return nil
+}
 
-		if req.FilePath == nil || strings.TrimSpace(*req.FilePath) == "" {
-			return errors.New("檔案訊息必須提供檔案路徑")
+// signFileURLs 為檔案訊息簽發綁定查看者的下載連結
+// 只簽發存放於該配對附件路徑下的檔案，其他路徑不產生連結
+func (s *ChatService) signFileURLs(ctx context.Context, viewerID uint, messages ...*entity.ChatMessage) {
+	if s.files == nil {
+		return
+	}
+	for _, message := range messages {
+		if message.FilePath == nil || !strings.HasPrefix(*message.FilePath, chatAttachmentPrefix(message.MatchID)) {
+			continue
 		}
+		url := s.files.SignedURLFor(ctx, *message.FilePath, viewerID)
+		message.FileURL = &url
 	}
+}
 
-	return nil
+// notifyNewMessage 以 WebSocket 通知接收者新訊息，檔案連結綁定接收者
+func (s *ChatService) notifyNewMessage(ctx context.Context, message *entity.ChatMessage) {
+	if s.wsNotifier == nil {
+		return
+	}
+
+	delivered := *message
+	s.signFileURLs(ctx, message.ReceiverID, &delivered)
+	notificationData := map[string]interface{}{
+		"type":      "new_message",
+		"message":   &delivered,
+		"match_id":  message.MatchID,
+		"sender_id": message.SenderID,
+	}
+
+	if err := s.wsNotifier.SendToUser(message.ReceiverID, notificationData); err != nil {
+		log.Printf("發送 WebSocket 通知失敗 (用戶 %d): %v", message.ReceiverID, err)
+	}
 }
 
 // isUserInMatch 檢查用戶是否為配對的一方
@@ -424,3 +584,35 @@ func (s *ChatService) isValidChatPair(match *entity.Match, senderID, receiverID
 	return (match.User1ID == senderID && match.User2ID == receiverID) ||
 		(match.User1ID == receiverID && match.User2ID == senderID)
 }
+
+// chatAttachmentPrefix 配對聊天附件的儲存路徑前綴
+func chatAttachmentPrefix(matchID uint) string {
+	return fmt.Sprintf("%s%d/", chatAttachmentKeyPrefix, matchID)
+}
+
+// chatAttachmentMatch 從聊天附件的儲存路徑取出配對 ID
+func chatAttachmentMatch(key string) (uint, bool) {
+	rest := strings.TrimPrefix(key, chatAttachmentKeyPrefix)
+	match, _, found := strings.Cut(rest, "/")
+	if !found || rest == key {
+		return 0, false
+	}
+	matchID, err := strconv.ParseUint(match, 10, 32)
+	if err != nil || matchID == 0 {
+		return 0, false
+	}
+	return uint(matchID), true
+}
+
+// attachmentFileName 整理客戶端提供的顯示檔名，去除路徑並限制長度，未提供時使用儲存檔名
+func attachmentFileName(name, fallback string) string {
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return fallback
+	}
+	for len(name) > maxAttachmentNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
@@ -0,0 +1,481 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+)
+
+// 個人資料匯出相關錯誤
+var (
+	ErrExportInProgress    = errors.New("已有資料匯出正在處理中")
+	ErrExportRateLimited   = errors.New("每天只能申請一次資料匯出")
+	ErrExportNotFound      = errors.New("尚未申請資料匯出")
+	ErrExportNotReady      = errors.New("匯出檔案尚未完成或已過期")
+	ErrInvalidDownloadLink = errors.New("下載連結無效或已過期")
+)
+
+const (
+	dataExportInterval  = 24 * time.Hour     // 每位用戶兩次匯出的最短間隔
+	dataExportRetention = 7 * 24 * time.Hour // 封存檔保留時間
+	dataExportLinkTTL   = time.Hour          // 單一下載連結有效時間
+	dataExportBatchSize = 10
+	dataExportErrorMax  = 500
+
+	// 處理超過此時間仍未完成視為工作中斷（例如程序重新啟動），重新排入佇列
+	dataExportProcessingTimeout = 30 * time.Minute
+	dataExportMaxAttempts       = 3
+)
+
+// ArchiveStore 匯出封存檔儲存介面
+// 由伺服器以上傳檔案儲存實作，多個實例共用，封存檔不應放在公開的靜態目錄
+type ArchiveStore interface {
+	SaveArchive(name string, data []byte) error
+	OpenArchive(name string) (io.ReadCloser, error)
+	RemoveArchive(name string) error
+}
+
+// SessionLister 會話列表介面
+// 由 AuthService 實作
+type SessionLister interface {
+	ListSessions(ctx context.Context, userID uint) ([]*AuthSession, error)
+}
+
+// DataExportInfo 匯出狀態與下載連結
+type DataExportInfo struct {
+	*entity.DataExport
+	DownloadURL   string     `json:"download_url,omitempty"`
+	LinkExpiresAt *time.Time `json:"link_expires_at,omitempty"`
+}
+
+// SwipeRecord 匯出用的滑動記錄，只包含用戶自己的動作
+type SwipeRecord struct {
+	MatchID      uint               `json:"match_id"`
+	TargetUserID uint               `json:"target_user_id"`
+	Action       entity.SwipeAction `json:"action"`
+	Matched      bool               `json:"matched"`
+	MatchedAt    *time.Time         `json:"matched_at,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+}
+
+// DataExportService 個人資料匯出業務邏輯服務
+// 負責受理匯出申請、背景產生 ZIP 封存檔，以及簽發有期限的下載連結
+type DataExportService struct {
+	exportRepo repository.DataExportRepository
+	dataRepo   repository.PersonalDataRepository
+	archives   ArchiveStore
+	sessions   SessionLister // 可選，未設定時不匯出登入會話
+	signingKey []byte
+	baseURL    string
+	wake       chan struct{}
+}
+
+// NewDataExportService 創建新的個人資料匯出服務實例
+// 下載連結的簽名金鑰由 secret 衍生，與其他用途的金鑰分開
+func NewDataExportService(
+	exportRepo repository.DataExportRepository,
+	dataRepo repository.PersonalDataRepository,
+	archives ArchiveStore,
+	secret string,
+	baseURL string,
+) *DataExportService {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("data-export-download"))
+
+	return &DataExportService{
+		exportRepo: exportRepo,
+		dataRepo:   dataRepo,
+		archives:   archives,
+		signingKey: mac.Sum(nil),
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// SetSessionLister 設定登入會話來源
+func (s *DataExportService) SetSessionLister(sessions SessionLister) {
+	s.sessions = sessions
+}
+
+// RequestExport 申請匯出個人資料
+// 每位用戶每天限一次，失敗的匯出不計入次數
+func (s *DataExportService) RequestExport(ctx context.Context, userID uint) (*entity.DataExport, error) {
+	latest, err := s.exportRepo.GetLatestByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if latest != nil {
+		if latest.Status.IsInProgress() {
+			return nil, ErrExportInProgress
+		}
+		next := latest.CreatedAt.Add(dataExportInterval)
+		if latest.Status != entity.DataExportStatusFailed && time.Now().Before(next) {
+			return nil, fmt.Errorf("%w，請於 %s 後再試", ErrExportRateLimited, next.Format("2006-01-02 15:04"))
+		}
+	}
+
+	export := &entity.DataExport{
+		UserID: userID,
+		Status: entity.DataExportStatusPending,
+	}
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+
+	// 通知背景工作立即處理，已有通知時不重複送出
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	return export, nil
+}
+
+// GetLatestExport 獲取用戶最近一次匯出狀態，完成時附上新簽發的下載連結
+func (s *DataExportService) GetLatestExport(ctx context.Context, userID uint) (*DataExportInfo, error) {
+	export, err := s.exportRepo.GetLatestByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if export == nil {
+		return nil, ErrExportNotFound
+	}
+
+	info := &DataExportInfo{DataExport: export}
+	now := time.Now()
+	if export.IsDownloadable(now) {
+		linkExpiresAt := now.Add(dataExportLinkTTL)
+		if export.ExpiresAt.Before(linkExpiresAt) {
+			linkExpiresAt = *export.ExpiresAt
+		}
+		info.DownloadURL = s.downloadURL(export.ID, linkExpiresAt.Unix())
+		info.LinkExpiresAt = &linkExpiresAt
+	}
+	return info, nil
+}
+
+// OpenDownload 驗證下載連結簽名與期限，返回匯出記錄與封存檔內容
+func (s *DataExportService) OpenDownload(ctx context.Context, exportID uint, expires int64, signature string) (*entity.DataExport, io.ReadCloser, error) {
+	now := time.Now()
+	if now.Unix() > expires {
+		return nil, nil, ErrInvalidDownloadLink
+	}
+
+	expected := s.signDownload(exportID, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, nil, ErrInvalidDownloadLink
+	}
+
+	export, err := s.exportRepo.GetByID(ctx, exportID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if export == nil {
+		return nil, nil, ErrExportNotFound
+	}
+	if !export.IsDownloadable(now) {
+		return nil, nil, ErrExportNotReady
+	}
+
+	reader, err := s.archives.OpenArchive(export.FileName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("開啟匯出檔案失敗: %w", err)
+	}
+	return export, reader, nil
+}
+
+// ProcessPendingExports 產生所有等待中的匯出封存檔
+// 處理中斷的匯出重新處理，嘗試次數超過上限時標記為失敗，讓用戶可以重新申請
+func (s *DataExportService) ProcessPendingExports(ctx context.Context, now time.Time) (int, error) {
+	processed := 0
+	staleBefore := now.Add(-dataExportProcessingTimeout)
+	for {
+		exports, err := s.exportRepo.GetPending(ctx, staleBefore, dataExportBatchSize)
+		if err != nil {
+			return processed, err
+		}
+
+		for _, export := range exports {
+			claimed, err := s.exportRepo.ClaimPending(ctx, export.ID, now, staleBefore)
+			if err != nil {
+				return processed, err
+			}
+			if !claimed {
+				continue
+			}
+
+			startedAt := now
+			export.Status = entity.DataExportStatusProcessing
+			export.ProcessingStartedAt = &startedAt
+			export.Attempts++
+			if export.Attempts > dataExportMaxAttempts {
+				log.Printf("用戶 %d 資料匯出多次處理中斷，標記為失敗", export.UserID)
+				export.Status = entity.DataExportStatusFailed
+				export.Error = "處理逾時，請重新申請"
+				if err := s.exportRepo.Update(ctx, export); err != nil {
+					return processed, err
+				}
+				continue
+			}
+
+			if err := s.processExport(ctx, export, now); err != nil {
+				return processed, err
+			}
+			processed++
+		}
+
+		if len(exports) < dataExportBatchSize {
+			return processed, nil
+		}
+	}
+}
+
+// PurgeExpiredExports 刪除下載期限已過的封存檔
+func (s *DataExportService) PurgeExpiredExports(ctx context.Context, now time.Time) (int, error) {
+	purged := 0
+	for {
+		exports, err := s.exportRepo.GetExpired(ctx, now, dataExportBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, export := range exports {
+			if err := s.archives.RemoveArchive(export.FileName); err != nil {
+				return purged, fmt.Errorf("刪除匯出檔案失敗 (匯出 %d): %w", export.ID, err)
+			}
+			export.Status = entity.DataExportStatusExpired
+			export.FileName = ""
+			if err := s.exportRepo.Update(ctx, export); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+
+		if len(exports) < dataExportBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// StartExportJob 啟動處理匯出與清除過期封存檔的背景工作，直到 ctx 取消
+// 收到新申請時立即處理，其餘時間依 interval 定期執行
+func (s *DataExportService) StartExportJob(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+
+			now := time.Now()
+			if _, err := s.ProcessPendingExports(ctx, now); err != nil {
+				log.Printf("處理資料匯出失敗: %v", err)
+			}
+			if count, err := s.PurgeExpiredExports(ctx, now); err != nil {
+				log.Printf("清除過期資料匯出失敗: %v", err)
+			} else if count > 0 {
+				log.Printf("已清除 %d 個過期的資料匯出", count)
+			}
+		}
+	}()
+}
+
+// 私有輔助方法
+
+// processExport 產生並保存單一匯出封存檔，失敗時將記錄標記為失敗
+func (s *DataExportService) processExport(ctx context.Context, export *entity.DataExport, now time.Time) error {
+	archive, err := s.buildArchive(ctx, export.UserID, now)
+	if err == nil {
+		token, tokenErr := generateSecureToken(16)
+		if tokenErr != nil {
+			err = tokenErr
+		} else {
+			export.FileName = fmt.Sprintf("export-%d-%s.zip", export.UserID, token)
+			err = s.archives.SaveArchive(export.FileName, archive)
+		}
+	}
+
+	if err != nil {
+		log.Printf("產生用戶 %d 資料匯出失敗: %v", export.UserID, err)
+		message := err.Error()
+		if len(message) > dataExportErrorMax {
+			message = message[:dataExportErrorMax]
+		}
+		export.Status = entity.DataExportStatusFailed
+		export.FileName = ""
+		export.Error = message
+		return s.exportRepo.Update(ctx, export)
+	}
+
+	completedAt := now
+	expiresAt := now.Add(dataExportRetention)
+	export.Status = entity.DataExportStatusCompleted
+	export.FileSize = int64(len(archive))
+	export.CompletedAt = &completedAt
+	export.ExpiresAt = &expiresAt
+	return s.exportRepo.Update(ctx, export)
+}
+
+// buildArchive 收集用戶資料並寫入 ZIP，每類資料一個 JSON 檔案
+func (s *DataExportService) buildArchive(ctx context.Context, userID uint, now time.Time) ([]byte, error) {
+	data, err := s.dataRepo.CollectUserData(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []*AuthSession{}
+	if s.sessions != nil {
+		listed, err := s.sessions.ListSessions(ctx, userID)
+		if err != nil && !errors.Is(err, ErrSessionStoreUnavailable) {
+			return nil, err
+		}
+		if listed != nil {
+			sessions = listed
+		}
+	}
+
+	// 審核員、審核備註、認領與自動偵測標記屬於內部資料，不包含在匯出中
+	// 只保留已告知用戶的拒絕原因
+	reports := make([]*entity.Report, 0, len(data.Reports))
+	for _, report := range data.Reports {
+		copied := *report
+		copied.ReviewerID = nil
+		copied.ReviewNotes = nil
+		reports = append(reports, &copied)
+	}
+
+	photos := make([]*entity.Photo, 0, len(data.Photos))
+	for _, photo := range data.Photos {
+		copied := *photo
+		copied.ReviewerID = nil
+		if !copied.IsRejected() {
+			copied.ReviewNotes = nil
+		}
+		copied.FlagReason = ""
+		copied.FlagScore = nil
+		copied.ClaimedBy = nil
+		copied.ClaimedAt = nil
+		photos = append(photos, &copied)
+	}
+
+	prompts := make([]*entity.ProfilePrompt, 0, len(data.Prompts))
+	for _, prompt := range data.Prompts {
+		copied := *prompt
+		copied.ReviewerID = nil
+		if copied.Status != entity.PromptAnswerStatusRejected {
+			copied.ReviewNotes = nil
+		}
+		prompts = append(prompts, &copied)
+	}
+
+	var user *entity.User
+	if data.User != nil {
+		copied := *data.User
+		copied.StatusActorID = nil
+		user = &copied
+	}
+
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{"manifest.json", map[string]interface{}{"user_id": userID, "generated_at": now}},
+		{"user.json", user},
+		{"profile.json", data.Profile},
+		{"attribute_preferences.json", data.AttributePreferences},
+		{"prompts.json", prompts},
+		{"photos.json", photos},
+		{"private_album_grants.json", data.Grants},
+		{"interests.json", data.Interests},
+		{"swipes.json", buildSwipeRecords(userID, data.Matches)},
+		{"messages.json", data.Messages},
+		{"blocks.json", data.Blocks},
+		{"reports.json", reports},
+		{"sessions.json", sessions},
+	}
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, file := range files {
+		content, err := json.MarshalIndent(file.content, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("序列化 %s 失敗: %w", file.name, err)
+		}
+
+		entry, err := writer.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return nil, fmt.Errorf("建立 %s 失敗: %w", file.name, err)
+		}
+		if _, err := entry.Write(content); err != nil {
+			return nil, fmt.Errorf("寫入 %s 失敗: %w", file.name, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("產生 ZIP 失敗: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// buildSwipeRecords 將配對記錄轉為用戶自己的滑動記錄
+// 對方的動作屬於對方的個人資料，只在雙向配對成功時才揭露配對結果
+func buildSwipeRecords(userID uint, matches []*entity.Match) []*SwipeRecord {
+	records := make([]*SwipeRecord, 0, len(matches))
+	for _, match := range matches {
+		record := &SwipeRecord{
+			MatchID:   match.ID,
+			Matched:   match.Status == entity.MatchStatusMatched,
+			CreatedAt: match.CreatedAt,
+		}
+		if record.Matched {
+			record.MatchedAt = match.MatchedAt
+		}
+
+		switch {
+		case match.User1ID == userID:
+			record.TargetUserID = match.User2ID
+			record.Action = match.User1Action
+		case match.User2ID == userID && match.User2Action != nil:
+			record.TargetUserID = match.User1ID
+			record.Action = *match.User2Action
+			record.CreatedAt = match.UpdatedAt
+		default:
+			// 對方已滑動但用戶尚未回應，沒有屬於用戶的動作
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// signDownload 計算下載連結簽名
+func (s *DataExportService) signDownload(exportID uint, expires int64) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	fmt.Fprintf(mac, "%d:%d", exportID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// downloadURL 組合帶簽名與期限的下載連結
+func (s *DataExportService) downloadURL(exportID uint, expires int64) string {
+	return fmt.Sprintf("%s/api/exports/%d/download?expires=%d&signature=%s",
+		s.baseURL, exportID, expires, s.signDownload(exportID, expires))
+}
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang_dev_docker/domain/repository"
+)
+
+// 電子郵件驗證相關錯誤
+var (
+	ErrInvalidVerificationCode   = errors.New("驗證碼錯誤或已過期")
+	ErrTooManyVerifyAttempts     = errors.New("驗證碼嘗試次數過多，請重新發送驗證碼")
+	ErrVerificationResendTooSoon = errors.New("驗證碼發送過於頻繁，請稍後再試")
+	ErrVerificationUnavailable   = errors.New("電子郵件驗證服務不可用")
+)
+
+const (
+	verificationCodeTTL      = 30 * time.Minute
+	verificationResendWindow = time.Minute
+	maxVerificationAttempts  = 5
+)
+
+// EmailMessage 電子郵件內容
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer 郵件發送介面
+type Mailer interface {
+	Send(ctx context.Context, msg *EmailMessage) error
+}
+
+// VerificationCodeStore 驗證碼儲存介面
+// 由 Redis CacheService 實作
+type VerificationCodeStore interface {
+	SetVerificationCode(identifier, code string, expiration time.Duration) error
+	GetVerificationCode(identifier string) (string, error)
+	DeleteVerificationCode(identifier string) error
+	SetRateLimit(identifier string, count int64, expiration time.Duration) error
+	IncrementRateLimit(identifier string, expiration time.Duration) (int64, error)
+}
+
+// EmailVerificationService 電子郵件驗證業務邏輯服務
+// 負責發送驗證碼、重新發送與確認電子郵件
+type EmailVerificationService struct {
+	userRepo repository.UserRepository
+	mailer   Mailer
+	codes    VerificationCodeStore // 可選，未設定時無法進行驗證
+	baseURL  string
+}
+
+// NewEmailVerificationService 創建新的電子郵件驗證服務實例
+func NewEmailVerificationService(
+	userRepo repository.UserRepository,
+	mailer Mailer,
+	codes VerificationCodeStore,
+	baseURL string,
+) *EmailVerificationService {
+	return &EmailVerificationService{
+		userRepo: userRepo,
+		mailer:   mailer,
+		codes:    codes,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// SendVerification 為指定用戶產生驗證碼並寄出驗證信
+func (s *EmailVerificationService) SendVerification(ctx context.Context, userID uint) error {
+	if s.codes == nil || s.mailer == nil {
+		return ErrVerificationUnavailable
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("用戶不存在: %w", err)
+	}
+
+	if user.EmailVerified {
+		return nil
+	}
+
+	// 限制發送頻率
+	count, err := s.codes.IncrementRateLimit(s.resendKey(user.ID), verificationResendWindow)
+	if err != nil {
+		return fmt.Errorf("檢查發送頻率失敗: %w", err)
+	}
+	if count > 1 {
+		return ErrVerificationResendTooSoon
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		return fmt.Errorf("生成驗證碼失敗: %w", err)
+	}
+
+	// 只儲存驗證碼雜湊，並重置錯誤嘗試次數
+	if err := s.codes.SetVerificationCode(s.codeKey(user.ID), hashToken(code), verificationCodeTTL); err != nil {
+		return fmt.Errorf("儲存驗證碼失敗: %w", err)
+	}
+	_ = s.codes.SetRateLimit(s.attemptKey(user.ID), 0, verificationCodeTTL)
+
+	msg := &EmailMessage{
+		To:      user.Email,
+		Subject: "請驗證您的電子郵件",
+		Body:    s.buildVerificationBody(user.Email, code),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("發送驗證信失敗: %w", err)
+	}
+
+	return nil
+}
+
+// ResendVerification 依電子郵件重新發送驗證信
+// 帳號不存在或已驗證時靜默成功，避免洩漏帳號是否存在
+func (s *EmailVerificationService) ResendVerification(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil || user.EmailVerified {
+		return nil
+	}
+
+	// 發送過於頻繁同樣靜默處理，回應不因帳號狀態而不同
+	if err := s.SendVerification(ctx, user.ID); err != nil && !errors.Is(err, ErrVerificationResendTooSoon) {
+		return err
+	}
+	return nil
+}
+
+// ConfirmEmail 以驗證碼確認電子郵件
+func (s *EmailVerificationService) ConfirmEmail(ctx context.Context, email, code string) error {
+	if s.codes == nil {
+		return ErrVerificationUnavailable
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		return ErrInvalidVerificationCode
+	}
+
+	if user.EmailVerified {
+		return nil
+	}
+
+	stored, err := s.codes.GetVerificationCode(s.codeKey(user.ID))
+	if err != nil {
+		return ErrInvalidVerificationCode
+	}
+
+	// 限制錯誤嘗試次數，超過後作廢驗證碼
+	attempts, err := s.codes.IncrementRateLimit(s.attemptKey(user.ID), verificationCodeTTL)
+	if err != nil {
+		return fmt.Errorf("檢查嘗試次數失敗: %w", err)
+	}
+	if attempts > maxVerificationAttempts {
+		_ = s.codes.DeleteVerificationCode(s.codeKey(user.ID))
+		return ErrTooManyVerifyAttempts
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(hashToken(strings.TrimSpace(code)))) != 1 {
+		return ErrInvalidVerificationCode
+	}
+
+	if err := s.userRepo.SetEmailVerified(ctx, user.ID); err != nil {
+		return fmt.Errorf("更新驗證狀態失敗: %w", err)
+	}
+
+	_ = s.codes.DeleteVerificationCode(s.codeKey(user.ID))
+	log.Printf("用戶 %d 電子郵件驗證完成", user.ID)
+	return nil
+}
+
+// IsEmailVerified 檢查用戶電子郵件是否已驗證
+func (s *EmailVerificationService) IsEmailVerified(ctx context.Context, userID uint) (bool, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("用戶不存在: %w", err)
+	}
+	return user.EmailVerified, nil
+}
+
+// 私有輔助方法
+
+func (s *EmailVerificationService) codeKey(userID uint) string {
+	return fmt.Sprintf("email:%d", userID)
+}
+
+func (s *EmailVerificationService) attemptKey(userID uint) string {
+	return fmt.Sprintf("email_attempts:%d", userID)
+}
+
+func (s *EmailVerificationService) resendKey(userID uint) string {
+	return fmt.Sprintf("email_resend:%d", userID)
+}
+
+// buildVerificationBody 組裝驗證信內容
+func (s *EmailVerificationService) buildVerificationBody(email, code string) string {
+	var b strings.Builder
+	b.WriteString("您好，\n\n")
+	b.WriteString(fmt.Sprintf("您的電子郵件驗證碼為：%s\n", code))
+	b.WriteString(fmt.Sprintf("驗證碼將於 %d 分鐘後失效。\n", int(verificationCodeTTL.Minutes())))
+
+	if s.baseURL != "" {
+		query := url.Values{}
+		query.Set("email", email)
+		query.Set("code", code)
+		b.WriteString(fmt.Sprintf("\n或直接點擊以下連結完成驗證：\n%s/api/auth/verify-email?%s\n", s.baseURL, query.Encode()))
+	}
+
+	b.WriteString("\n如果您沒有註冊帳號，請忽略此郵件。\n")
+	return b.String()
+}
+
+// generateVerificationCode 生成 6 位數字驗證碼
+func generateVerificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
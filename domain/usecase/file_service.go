@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 檔案儲存相關錯誤
+var (
+	ErrFileNotFound     = errors.New("檔案不存在")
+	ErrInvalidFileKey   = errors.New("無效的檔案路徑")
+	ErrInvalidFileLink  = errors.New("檔案連結無效或已過期")
+	ErrFileAccessDenied = errors.New("無權限存取此檔案")
+)
+
+const (
+	// DefaultFileURLTTL 預設檔案下載連結有效時間
+	DefaultFileURLTTL = 15 * time.Minute
+
+	// legacyUploadPrefix 改用 BlobStore 前上傳檔案的路徑前綴，當時由 /uploads 靜態路由公開
+	legacyUploadPrefix = "/uploads/"
+)
+
+// BlobStore 檔案儲存介面
+// 由 infrastructure/storage 的本機目錄與 S3 相容實作提供；key 為以斜線分隔的相對路徑，
+// Put 必須在寫入完成後才讓檔案可見，Delete 對不存在的檔案視為成功，Open 找不到檔案時返回 ErrFileNotFound
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// BlobURLSigner 可自行簽發下載連結的檔案儲存，例如 S3 預簽章網址
+// 未實作時由 FileService 簽發經伺服器轉送的連結
+type BlobURLSigner interface {
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// FileAccessChecker 受限檔案的存取權限檢查，例如私人相簿照片
+// 受限檔案的下載連結綁定查看者，下載時重新檢查權限，撤銷授權後尚未過期的連結也會失效
+type FileAccessChecker interface {
+	IsRestricted(key string) bool
+	CanAccess(ctx context.Context, key string, viewerID uint) (bool, error)
+}
+
+// FileService 用戶上傳檔案服務
+// 負責寫入與刪除檔案，並簽發有期限的下載連結；檔案不經靜態路由公開
+type FileService struct {
+	store      BlobStore
+	signingKey []byte
+	baseURL    string
+	urlTTL     time.Duration
+	access     []FileAccessChecker // 可選，未設定時所有檔案只驗證連結簽名
+}
+
+// NewFileService 創建檔案服務實例
+// 下載連結的簽名金鑰由 secret 衍生，與其他用途的金鑰分開；ttl 不大於 0 時使用預設值
+func NewFileService(store BlobStore, secret, baseURL string, ttl time.Duration) *FileService {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("file-download"))
+
+	if ttl <= 0 {
+		ttl = DefaultFileURLTTL
+	}
+
+	return &FileService{
+		store:      store,
+		signingKey: mac.Sum(nil),
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		urlTTL:     ttl,
+	}
+}
+
+// AddAccessChecker 加入受限檔案的存取權限檢查，各檢查負責不同的路徑前綴
+func (s *FileService) AddAccessChecker(access FileAccessChecker) {
+	s.access = append(s.access, access)
+}
+
+// documentExtensions 聊天附件與驗證文件允許的檔案格式與對應副檔名
+var documentExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// detectDocumentType 以檔案開頭的魔術位元組判斷格式，不信任客戶端提供的檔名與 Content-Type
+// 只接受圖片與 PDF，返回 MIME 類型與副檔名
+func detectDocumentType(data []byte) (string, string, bool) {
+	mimeType := http.DetectContentType(data)
+	extension, ok := documentExtensions[mimeType]
+	return mimeType, extension, ok
+}
+
+// FileKey 將資料庫中的檔案路徑轉為儲存 key
+// 外部網址返回 false；舊版 /uploads/ 開頭的路徑去除前綴後即為 key
+func FileKey(path string) (string, bool) {
+	if path == "" || strings.Contains(path, "://") {
+		return "", false
+	}
+	return strings.TrimPrefix(path, legacyUploadPrefix), true
+}
+
+// Put 寫入檔案
+func (s *FileService) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	return s.store.Put(ctx, key, data, contentType)
+}
+
+// RemoveFile 刪除檔案，外部網址直接略過
+// 實作 FileRemover，供帳號清除使用
+func (s *FileService) RemoveFile(path string) error {
+	key, ok := FileKey(path)
+	if !ok {
+		return nil
+	}
+	return s.store.Delete(context.Background(), key)
+}
+
+// SignedURL 簽發檔案下載連結，外部網址原樣返回
+// 簽發失敗時記錄日誌並返回空字串，不影響其他資料的回應
+func (s *FileService) SignedURL(ctx context.Context, path string) string {
+	return s.SignedURLFor(ctx, path, 0)
+}
+
+// SignedURLFor 為指定查看者簽發檔案下載連結
+// 受限檔案的連結一律經伺服器轉送並綁定查看者，未指定查看者時返回空字串
+func (s *FileService) SignedURLFor(ctx context.Context, path string, viewerID uint) string {
+	key, ok := FileKey(path)
+	if !ok {
+		return path
+	}
+
+	if s.isRestricted(key) {
+		if viewerID == 0 {
+			return ""
+		}
+		expires := time.Now().Add(s.urlTTL).Unix()
+		return fmt.Sprintf("%s/api/files/%s?expires=%d&viewer=%d&signature=%s",
+			s.baseURL, escapeFileKey(key), expires, viewerID, s.sign(key, expires, viewerID))
+	}
+
+	if signer, ok := s.store.(BlobURLSigner); ok {
+		signed, err := signer.SignedURL(ctx, key, s.urlTTL)
+		if err != nil {
+			log.Printf("簽發檔案下載連結失敗 (%s): %v", key, err)
+			return ""
+		}
+		return signed
+	}
+
+	expires := time.Now().Add(s.urlTTL).Unix()
+	return fmt.Sprintf("%s/api/files/%s?expires=%d&signature=%s",
+		s.baseURL, escapeFileKey(key), expires, s.sign(key, expires, 0))
+}
+
+// OpenSigned 驗證下載連結簽名與期限，返回檔案內容
+// viewerID 為連結綁定的查看者，一般檔案為 0；受限檔案會重新檢查查看者目前是否仍有權限
+func (s *FileService) OpenSigned(ctx context.Context, key string, expires int64, viewerID uint, signature string) (io.ReadCloser, error) {
+	if time.Now().Unix() > expires {
+		return nil, ErrInvalidFileLink
+	}
+	if !hmac.Equal([]byte(s.sign(key, expires, viewerID)), []byte(signature)) {
+		return nil, ErrInvalidFileLink
+	}
+
+	if access := s.accessCheckerFor(key); access != nil {
+		if viewerID == 0 {
+			return nil, ErrFileAccessDenied
+		}
+		allowed, err := access.CanAccess(ctx, key, viewerID)
+		if err != nil {
+			return nil, fmt.Errorf("檢查檔案存取權限失敗: %w", err)
+		}
+		if !allowed {
+			return nil, ErrFileAccessDenied
+		}
+	}
+	return s.store.Open(ctx, key)
+}
+
+// isRestricted 檢查檔案是否需要查看者權限
+func (s *FileService) isRestricted(key string) bool {
+	return s.accessCheckerFor(key) != nil
+}
+
+// accessCheckerFor 返回負責此檔案的存取權限檢查，一般檔案返回 nil
+func (s *FileService) accessCheckerFor(key string) FileAccessChecker {
+	for _, access := range s.access {
+		if access.IsRestricted(key) {
+			return access
+		}
+	}
+	return nil
+}
+
+// sign 計算下載連結簽名，綁定查看者的連結將查看者一併納入簽名
+func (s *FileService) sign(key string, expires int64, viewerID uint) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	if viewerID == 0 {
+		fmt.Fprintf(mac, "%s:%d", key, expires)
+	} else {
+		fmt.Fprintf(mac, "%s:%d:%d", key, expires, viewerID)
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// escapeFileKey 逐段跳脫 key，保留路徑分隔符號
+func escapeFileKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,396 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+)
+
+// 興趣標籤相關錯誤
+var (
+	ErrInterestNotFound      = errors.New("興趣標籤不存在")
+	ErrInterestInactive      = errors.New("興趣標籤已停用")
+	ErrInterestNameTaken     = errors.New("興趣名稱已存在")
+	ErrTooManyInterests      = errors.New("興趣數量超過上限")
+	ErrInvalidInterestImport = errors.New("CSV 內容有誤，未匯入任何興趣")
+)
+
+// 興趣搜尋筆數
+const (
+	defaultInterestSearchLimit = 10
+	maxInterestSearchLimit     = 50
+)
+
+// interestCSVHeader 興趣 CSV 欄位，在地化名稱以 "語系=名稱" 表示，多筆值以 | 分隔
+var interestCSVHeader = []string{"name", "category", "description", "is_active", "translations", "synonyms"}
+
+// InterestService 興趣標籤目錄業務邏輯服務
+// 負責目錄維護、CSV 匯入匯出，以及依語系顯示與自動完成搜尋
+type InterestService struct {
+	interestRepo repository.InterestRepository
+}
+
+// NewInterestService 創建新的興趣標籤服務實例
+func NewInterestService(interestRepo repository.InterestRepository) *InterestService {
+	return &InterestService{
+		interestRepo: interestRepo,
+	}
+}
+
+// LocalizedInterest 指定語系的興趣標籤
+type LocalizedInterest struct {
+	ID           uint                    `json:"id"`
+	Name         string                  `json:"name"`
+	Category     entity.InterestCategory `json:"category"`
+	CategoryName string                  `json:"category_name"`
+	UsageCount   int                     `json:"usage_count"`
+}
+
+// InterestRequest 新增或修改興趣標籤請求
+type InterestRequest struct {
+	Name         string            `json:"name"`
+	Category     string            `json:"category"` // 類別代碼或顯示名稱
+	Description  string            `json:"description"`
+	IsActive     *bool             `json:"is_active,omitempty"` // 未傳入時新增為啟用、修改時不變更
+	Translations map[string]string `json:"translations"`        // 語系 -> 名稱
+	Synonyms     []string          `json:"synonyms"`
+}
+
+// InterestImportError CSV 匯入時單列的錯誤
+type InterestImportError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// InterestImportResult CSV 匯入結果
+type InterestImportResult struct {
+	Created int                   `json:"created"`
+	Updated int                   `json:"updated"`
+	Errors  []InterestImportError `json:"errors,omitempty"`
+}
+
+// ListInterests 獲取啟用中的興趣，名稱依指定語系顯示
+func (s *InterestService) ListInterests(ctx context.Context, locale string) ([]*LocalizedInterest, error) {
+	interests, err := s.interestRepo.GetAll(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	return localizeInterests(interests, locale), nil
+}
+
+// SearchInterests 以字首搜尋啟用中的興趣，比對名稱、在地化名稱與同義詞
+func (s *InterestService) SearchInterests(ctx context.Context, query, locale string, limit int) ([]*LocalizedInterest, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []*LocalizedInterest{}, nil
+	}
+	if limit <= 0 || limit > maxInterestSearchLimit {
+		limit = defaultInterestSearchLimit
+	}
+
+	interests, err := s.interestRepo.Search(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return localizeInterests(interests, locale), nil
+}
+
+// ListAllInterests 獲取所有興趣與在地化名稱、同義詞，包含已停用的興趣
+func (s *InterestService) ListAllInterests(ctx context.Context) ([]*entity.Interest, error) {
+	return s.interestRepo.GetAll(ctx, false)
+}
+
+// CreateInterest 新增興趣標籤
+func (s *InterestService) CreateInterest(ctx context.Context, req *InterestRequest) (*entity.Interest, error) {
+	interest := &entity.Interest{IsActive: true}
+	applyInterestRequest(interest, req)
+
+	if err := s.validateInterest(ctx, interest); err != nil {
+		return nil, err
+	}
+
+	if err := s.interestRepo.Create(ctx, interest); err != nil {
+		return nil, err
+	}
+	return interest, nil
+}
+
+// UpdateInterest 修改興趣標籤，在地化名稱與同義詞整份取代
+// 停用興趣不影響已選擇的用戶，只是不再提供選擇與搜尋
+func (s *InterestService) UpdateInterest(ctx context.Context, id uint, req *InterestRequest) (*entity.Interest, error) {
+	interest, err := s.interestRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if interest == nil {
+		return nil, ErrInterestNotFound
+	}
+
+	applyInterestRequest(interest, req)
+	if err := s.validateInterest(ctx, interest); err != nil {
+		return nil, err
+	}
+
+	if err := s.interestRepo.Update(ctx, interest); err != nil {
+		return nil, err
+	}
+	return interest, nil
+}
+
+// DeleteInterest 刪除興趣標籤，已選擇此興趣的用戶一併移除
+// 只想停止提供選擇時應改為停用
+func (s *InterestService) DeleteInterest(ctx context.Context, id uint) error {
+	interest, err := s.interestRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if interest == nil {
+		return ErrInterestNotFound
+	}
+	return s.interestRepo.Delete(ctx, id)
+}
+
+// ImportCSV 匯入興趣 CSV，依名稱新增或更新，使用人數不受影響
+// 所有列都通過驗證才會寫入；有任何錯誤時回傳 ErrInvalidInterestImport 與各列錯誤
+func (s *InterestService) ImportCSV(ctx context.Context, r io.Reader) (*InterestImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: 無法讀取標題列: %v", ErrInvalidInterestImport, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"name", "category"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: 缺少 %s 欄位", ErrInvalidInterestImport, required)
+		}
+	}
+
+	result := &InterestImportResult{}
+	var pending []*entity.Interest
+	seen := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("讀取 CSV 失敗: %w", err)
+			}
+			result.Errors = append(result.Errors, InterestImportError{Line: parseErr.StartLine, Message: parseErr.Err.Error()})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		interest, err := s.parseInterestRecord(ctx, record, columns)
+		if err == nil {
+			key := strings.ToLower(interest.Name)
+			if first, duplicated := seen[key]; duplicated {
+				err = fmt.Errorf("名稱與第 %d 列重複", first)
+			}
+			seen[key] = line
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, InterestImportError{Line: line, Message: err.Error()})
+			continue
+		}
+		pending = append(pending, interest)
+	}
+
+	if len(result.Errors) > 0 {
+		return result, ErrInvalidInterestImport
+	}
+
+	for _, interest := range pending {
+		if interest.ID == 0 {
+			if err := s.interestRepo.Create(ctx, interest); err != nil {
+				return result, err
+			}
+			result.Created++
+			continue
+		}
+		if err := s.interestRepo.Update(ctx, interest); err != nil {
+			return result, err
+		}
+		result.Updated++
+	}
+	return result, nil
+}
+
+// parseInterestRecord 將 CSV 列轉為興趣，名稱已存在時套用到既有的興趣
+func (s *InterestService) parseInterestRecord(ctx context.Context, record []string, columns map[string]int) (*entity.Interest, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	req := &InterestRequest{
+		Name:        field("name"),
+		Category:    field("category"),
+		Description: field("description"),
+		Synonyms:    splitCSVList(field("synonyms")),
+	}
+	if value := field("is_active"); value != "" {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("is_active 必須是 true 或 false")
+		}
+		req.IsActive = &active
+	}
+	if value := field("translations"); value != "" {
+		req.Translations = make(map[string]string)
+		for _, pair := range splitCSVList(value) {
+			locale, name, found := strings.Cut(pair, "=")
+			if !found {
+				return nil, fmt.Errorf("在地化名稱格式必須是 語系=名稱: %s", pair)
+			}
+			req.Translations[strings.TrimSpace(locale)] = name
+		}
+	}
+
+	interest, err := s.interestRepo.GetByName(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if interest == nil {
+		interest = &entity.Interest{IsActive: true}
+	}
+
+	applyInterestRequest(interest, req)
+	if err := interest.Validate(); err != nil {
+		return nil, err
+	}
+	return interest, nil
+}
+
+// ExportCSV 匯出所有興趣為 CSV，格式與 ImportCSV 相同
+func (s *InterestService) ExportCSV(ctx context.Context, w io.Writer) error {
+	interests, err := s.interestRepo.GetAll(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(interestCSVHeader); err != nil {
+		return fmt.Errorf("寫入 CSV 失敗: %w", err)
+	}
+	for _, interest := range interests {
+		translations := make([]string, 0, len(interest.Translations))
+		for _, translation := range interest.Translations {
+			translations = append(translations, translation.Locale+"="+translation.Name)
+		}
+		sort.Strings(translations)
+
+		synonyms := make([]string, 0, len(interest.Synonyms))
+		for _, synonym := range interest.Synonyms {
+			synonyms = append(synonyms, synonym.Term)
+		}
+
+		if err := writer.Write([]string{
+			interest.Name,
+			string(interest.Category),
+			interest.GetDescription(),
+			strconv.FormatBool(interest.IsActive),
+			strings.Join(translations, "|"),
+			strings.Join(synonyms, "|"),
+		}); err != nil {
+			return fmt.Errorf("寫入 CSV 失敗: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("寫入 CSV 失敗: %w", err)
+	}
+	return nil
+}
+
+// validateInterest 驗證興趣資料並檢查名稱未被其他興趣使用
+func (s *InterestService) validateInterest(ctx context.Context, interest *entity.Interest) error {
+	if err := interest.Validate(); err != nil {
+		return err
+	}
+
+	existing, err := s.interestRepo.GetByName(ctx, interest.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != interest.ID {
+		return ErrInterestNameTaken
+	}
+	return nil
+}
+
+// applyInterestRequest 將請求內容寫入興趣，無法辨識的類別保留原值交由驗證回報
+func applyInterestRequest(interest *entity.Interest, req *InterestRequest) {
+	interest.Name = strings.TrimSpace(req.Name)
+	if category, ok := entity.ParseInterestCategory(req.Category); ok {
+		interest.Category = category
+	} else {
+		interest.Category = entity.InterestCategory(strings.TrimSpace(req.Category))
+	}
+	interest.SetDescription(req.Description)
+	if req.IsActive != nil {
+		interest.IsActive = *req.IsActive
+	}
+
+	interest.Translations = make([]entity.InterestTranslation, 0, len(req.Translations))
+	for locale, name := range req.Translations {
+		interest.Translations = append(interest.Translations, entity.InterestTranslation{
+			Locale: entity.NormalizeLocale(locale),
+			Name:   strings.TrimSpace(name),
+		})
+	}
+	sort.Slice(interest.Translations, func(i, j int) bool {
+		return interest.Translations[i].Locale < interest.Translations[j].Locale
+	})
+
+	interest.Synonyms = make([]entity.InterestSynonym, 0, len(req.Synonyms))
+	for _, term := range req.Synonyms {
+		interest.Synonyms = append(interest.Synonyms, entity.InterestSynonym{Term: strings.TrimSpace(term)})
+	}
+}
+
+// localizeInterests 將興趣轉為指定語系的顯示資料
+func localizeInterests(interests []*entity.Interest, locale string) []*LocalizedInterest {
+	localized := make([]*LocalizedInterest, 0, len(interests))
+	for _, interest := range interests {
+		localized = append(localized, &LocalizedInterest{
+			ID:           interest.ID,
+			Name:         interest.LocalizedName(locale),
+			Category:     interest.Category,
+			CategoryName: interest.GetCategoryDisplayName(),
+			UsageCount:   interest.UsageCount,
+		})
+	}
+	return localized
+}
+
+// splitCSVList 拆分 CSV 欄位中以 | 分隔的多筆值，空欄位回傳 nil
+func splitCSVList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	parts := strings.Split(value, "|")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
@@ -0,0 +1,401 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+)
+
+// 登入限制相關錯誤
+var (
+	ErrLoginThrottled = errors.New("登入嘗試過於頻繁")
+	ErrLoginLocked    = errors.New("登入失敗次數過多，已暫時鎖定")
+)
+
+// 登入限制類型
+const (
+	loginLockDelay   = "delay"
+	loginLockLockout = "lockout"
+)
+
+// loginAttemptRetention 登入嘗試記錄保留時間
+const loginAttemptRetention = 90 * 24 * time.Hour
+
+// maxMFATokenFailures 同一個兩步驟驗證暫時權杖允許的驗證失敗次數，達到後權杖作廢需重新登入
+const maxMFATokenFailures = 5
+
+// LoginThrottleError 登入限制錯誤，附帶可重試的剩餘時間
+type LoginThrottleError struct {
+	Err        error // ErrLoginThrottled 或 ErrLoginLocked
+	RetryAfter time.Duration
+}
+
+// Error 實作 error 介面
+func (e *LoginThrottleError) Error() string {
+	seconds := int(math.Ceil(e.RetryAfter.Seconds()))
+	return fmt.Sprintf("%s，請於 %d 秒後再試", e.Err.Error(), seconds)
+}
+
+// Unwrap 讓 errors.Is 可比對 ErrLoginThrottled 與 ErrLoginLocked
+func (e *LoginThrottleError) Unwrap() error {
+	return e.Err
+}
+
+// LoginAttemptStore 登入失敗計數與鎖定儲存介面
+// 由 Redis CacheService 實作，多個實例共用計數
+type LoginAttemptStore interface {
+	IncrementLoginFailures(identifier string, window time.Duration) (int64, error)
+	ResetLoginFailures(identifier string) error
+	LockLogin(identifier, kind string, duration time.Duration) error
+	GetLoginLock(identifier string) (kind string, remaining time.Duration, err error)
+}
+
+// LoginThrottlePolicy 登入限制策略
+type LoginThrottlePolicy struct {
+	AccountFreeAttempts int           // 同一帳號連續失敗幾次後開始延遲
+	IPFreeAttempts      int           // 同一 IP 連續失敗幾次後開始延遲
+	BaseDelay           time.Duration // 第一次延遲時間，之後每次失敗加倍
+	MaxDelay            time.Duration // 延遲上限
+	AccountLockAfter    int           // 同一帳號失敗達此次數後鎖定
+	IPLockAfter         int           // 同一 IP 失敗達此次數後鎖定，需容許共用 IP 的正常用戶
+	LockoutDuration     time.Duration // 鎖定時間
+	FailureWindow       time.Duration // 失敗計數保留時間
+}
+
+// DefaultLoginThrottlePolicy 預設登入限制策略
+func DefaultLoginThrottlePolicy() LoginThrottlePolicy {
+	return LoginThrottlePolicy{
+		AccountFreeAttempts: 3,
+		IPFreeAttempts:      10,
+		BaseDelay:           2 * time.Second,
+		MaxDelay:            time.Minute,
+		AccountLockAfter:    10,
+		IPLockAfter:         50,
+		LockoutDuration:     15 * time.Minute,
+		FailureWindow:       time.Hour,
+	}
+}
+
+// LoginThrottleService 登入暴力破解防護服務
+// 依帳號與來源 IP 分別追蹤失敗次數，逐步延遲並在超過門檻後暫時鎖定
+// 計數儲存不可用時不限制登入，只記錄登入嘗試
+type LoginThrottleService struct {
+	attempts repository.LoginAttemptRepository
+	userRepo repository.UserRepository
+	policy   LoginThrottlePolicy
+	store    LoginAttemptStore // 可選，未設定時不限制登入
+	mailer   Mailer            // 可選，未設定時不發送鎖定通知
+}
+
+// NewLoginThrottleService 創建新的登入限制服務實例
+func NewLoginThrottleService(
+	attempts repository.LoginAttemptRepository,
+	userRepo repository.UserRepository,
+	policy LoginThrottlePolicy,
+) *LoginThrottleService {
+	return &LoginThrottleService{
+		attempts: attempts,
+		userRepo: userRepo,
+		policy:   policy,
+	}
+}
+
+// SetAttemptStore 設定失敗計數儲存
+func (s *LoginThrottleService) SetAttemptStore(store LoginAttemptStore) {
+	s.store = store
+}
+
+// SetMailer 設定鎖定通知郵件發送器
+func (s *LoginThrottleService) SetMailer(mailer Mailer) {
+	s.mailer = mailer
+}
+
+// Check 檢查帳號與來源 IP 目前是否允許登入
+// 帳號是否存在都以相同方式限制，避免洩漏帳號資訊
+func (s *LoginThrottleService) Check(ctx context.Context, email string, client ClientInfo) error {
+	if s.store == nil {
+		return nil
+	}
+
+	email = normalizeLoginEmail(email)
+	for _, identifier := range []string{accountThrottleKey(email), ipThrottleKey(client.IPAddress)} {
+		if identifier == "" {
+			continue
+		}
+
+		kind, remaining, err := s.store.GetLoginLock(identifier)
+		if err != nil {
+			log.Printf("檢查登入限制失敗 (%s): %v", identifier, err)
+			continue
+		}
+		if remaining <= 0 {
+			continue
+		}
+
+		throttleErr := &LoginThrottleError{Err: ErrLoginThrottled, RetryAfter: remaining}
+		reason := entity.LoginFailureThrottled
+		if kind == loginLockLockout {
+			throttleErr.Err = ErrLoginLocked
+			reason = entity.LoginFailureLocked
+		}
+		s.record(ctx, email, nil, client, false, reason)
+		return throttleErr
+	}
+	return nil
+}
+
+// RecordFailure 記錄密碼錯誤，並依失敗次數設定延遲或鎖定
+func (s *LoginThrottleService) RecordFailure(ctx context.Context, email string, client ClientInfo) {
+	email = normalizeLoginEmail(email)
+
+	var user *entity.User
+	if found, err := s.userRepo.GetByEmail(ctx, email); err == nil {
+		user = found
+	}
+
+	var userID *uint
+	if user != nil {
+		userID = &user.ID
+	}
+	s.record(ctx, email, userID, client, false, entity.LoginFailureInvalidCredentials)
+
+	if s.store == nil {
+		return
+	}
+
+	if s.applyPenalty(accountThrottleKey(email), s.policy.AccountFreeAttempts, s.policy.AccountLockAfter) && user != nil {
+		s.notifyLockout(ctx, user, client)
+	}
+	if identifier := ipThrottleKey(client.IPAddress); identifier != "" {
+		s.applyPenalty(identifier, s.policy.IPFreeAttempts, s.policy.IPLockAfter)
+	}
+}
+
+// RecordSuccess 記錄成功登入並清除帳號的失敗計數
+// 來源 IP 的計數不清除，避免攻擊者以自己的帳號重置 IP 限制
+func (s *LoginThrottleService) RecordSuccess(ctx context.Context, email string, userID uint, client ClientInfo) {
+	email = normalizeLoginEmail(email)
+	s.record(ctx, email, &userID, client, true, "")
+
+	if s.store == nil {
+		return
+	}
+	if err := s.store.ResetLoginFailures(accountThrottleKey(email)); err != nil {
+		log.Printf("清除登入失敗計數失敗: %v", err)
+	}
+}
+
+// CheckSecondFactor 檢查帳號目前是否允許嘗試第二步驗證
+func (s *LoginThrottleService) CheckSecondFactor(ctx context.Context, userID uint, client ClientInfo) error {
+	if s.store == nil {
+		return nil
+	}
+
+	kind, remaining, err := s.store.GetLoginLock(secondFactorThrottleKey(userID))
+	if err != nil {
+		log.Printf("檢查第二步驗證限制失敗 (用戶 %d): %v", userID, err)
+		return nil
+	}
+	if remaining <= 0 {
+		return nil
+	}
+
+	throttleErr := &LoginThrottleError{Err: ErrLoginThrottled, RetryAfter: remaining}
+	reason := entity.LoginFailureThrottled
+	if kind == loginLockLockout {
+		throttleErr.Err = ErrLoginLocked
+		reason = entity.LoginFailureLocked
+	}
+	s.recordForUser(ctx, userID, client, reason)
+	return throttleErr
+}
+
+// RecordSecondFactorFailure 記錄第二步驗證失敗，依帳號的失敗次數設定延遲或鎖定
+// 同時累計暫時權杖的失敗次數，達到上限時返回 true，由呼叫端作廢該權杖
+func (s *LoginThrottleService) RecordSecondFactorFailure(ctx context.Context, userID uint, tokenID string, client ClientInfo) bool {
+	user := s.recordForUser(ctx, userID, client, entity.LoginFailureInvalidSecondFactor)
+
+	if s.store == nil {
+		return false
+	}
+
+	if s.applyPenalty(secondFactorThrottleKey(userID), s.policy.AccountFreeAttempts, s.policy.AccountLockAfter) && user != nil {
+		s.notifyLockout(ctx, user, client)
+	}
+
+	if tokenID == "" {
+		return false
+	}
+	count, err := s.store.IncrementLoginFailures(mfaTokenThrottleKey(tokenID), s.policy.FailureWindow)
+	if err != nil {
+		log.Printf("增加兩步驟驗證權杖失敗計數失敗: %v", err)
+		return false
+	}
+	return count >= maxMFATokenFailures
+}
+
+// RecordSecondFactorSuccess 完成第二步驗證並建立會話後，記錄成功登入並清除帳號的密碼與第二步驗證失敗計數
+func (s *LoginThrottleService) RecordSecondFactorSuccess(ctx context.Context, email string, userID uint, client ClientInfo) {
+	s.RecordSuccess(ctx, email, userID, client)
+
+	if s.store == nil {
+		return
+	}
+	if err := s.store.ResetLoginFailures(secondFactorThrottleKey(userID)); err != nil {
+		log.Printf("清除第二步驗證失敗計數失敗: %v", err)
+	}
+}
+
+// PurgeOldAttempts 刪除超過保留期的登入嘗試記錄
+func (s *LoginThrottleService) PurgeOldAttempts(ctx context.Context, now time.Time) (int64, error) {
+	return s.attempts.DeleteBefore(ctx, now.Add(-loginAttemptRetention))
+}
+
+// StartCleanupJob 啟動定期清除登入嘗試記錄的背景工作，直到 ctx 取消
+func (s *LoginThrottleService) StartCleanupJob(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				count, err := s.PurgeOldAttempts(ctx, now)
+				if err != nil {
+					log.Printf("清除登入嘗試記錄失敗: %v", err)
+				} else if count > 0 {
+					log.Printf("已清除 %d 筆過期的登入嘗試記錄", count)
+				}
+			}
+		}
+	}()
+}
+
+// 私有輔助方法
+
+// applyPenalty 增加失敗計數並設定延遲或鎖定，剛達到鎖定門檻時返回 true
+func (s *LoginThrottleService) applyPenalty(identifier string, freeAttempts, lockAfter int) bool {
+	count, err := s.store.IncrementLoginFailures(identifier, s.policy.FailureWindow)
+	if err != nil {
+		log.Printf("增加登入失敗計數失敗 (%s): %v", identifier, err)
+		return false
+	}
+
+	if count >= int64(lockAfter) {
+		if err := s.store.LockLogin(identifier, loginLockLockout, s.policy.LockoutDuration); err != nil {
+			log.Printf("設定登入鎖定失敗 (%s): %v", identifier, err)
+			return false
+		}
+		return count == int64(lockAfter)
+	}
+
+	if count > int64(freeAttempts) {
+		delay := s.policy.MaxDelay
+		if shift := count - int64(freeAttempts) - 1; shift < 30 {
+			if doubled := s.policy.BaseDelay << uint(shift); doubled < delay {
+				delay = doubled
+			}
+		}
+		if err := s.store.LockLogin(identifier, loginLockDelay, delay); err != nil {
+			log.Printf("設定登入延遲失敗 (%s): %v", identifier, err)
+		}
+	}
+	return false
+}
+
+// record 寫入登入嘗試記錄，失敗只記錄日誌
+func (s *LoginThrottleService) record(ctx context.Context, email string, userID *uint, client ClientInfo, success bool, reason entity.LoginFailureReason) {
+	attempt := &entity.LoginAttempt{
+		UserID:        userID,
+		Email:         truncateString(email, 255),
+		IPAddress:     truncateString(client.IPAddress, 45),
+		UserAgent:     truncateString(client.UserAgent, 500),
+		Success:       success,
+		FailureReason: reason,
+	}
+	if err := s.attempts.Create(ctx, attempt); err != nil {
+		log.Printf("記錄登入嘗試失敗: %v", err)
+	}
+}
+
+// recordForUser 以用戶 ID 寫入登入嘗試記錄，返回查到的用戶
+func (s *LoginThrottleService) recordForUser(ctx context.Context, userID uint, client ClientInfo, reason entity.LoginFailureReason) *entity.User {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		s.record(ctx, "", &userID, client, false, reason)
+		return nil
+	}
+	s.record(ctx, normalizeLoginEmail(user.Email), &userID, client, false, reason)
+	return user
+}
+
+// notifyLockout 通知帳號擁有者帳號已被暫時鎖定，發送失敗只記錄日誌
+func (s *LoginThrottleService) notifyLockout(ctx context.Context, user *entity.User, client ClientInfo) {
+	if s.mailer == nil {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("您好，\n\n")
+	b.WriteString("您的帳號連續多次登入失敗，為保護帳號安全，已暫時鎖定登入。\n")
+	b.WriteString(fmt.Sprintf("鎖定將於 %d 分鐘後自動解除。\n", int(s.policy.LockoutDuration.Minutes())))
+	if client.IPAddress != "" {
+		b.WriteString(fmt.Sprintf("最後一次嘗試的來源 IP：%s\n", client.IPAddress))
+	}
+	b.WriteString("\n如果這不是您本人的操作，建議您使用忘記密碼功能重設密碼，並啟用兩步驟驗證。\n")
+
+	msg := &EmailMessage{
+		To:      user.Email,
+		Subject: "您的帳號已暫時鎖定",
+		Body:    b.String(),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("發送帳號鎖定通知失敗: %v", err)
+	}
+}
+
+// normalizeLoginEmail 統一信箱格式，避免以大小寫變化繞過帳號限制
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// accountThrottleKey 帳號限制鍵
+func accountThrottleKey(email string) string {
+	return "account:" + email
+}
+
+// secondFactorThrottleKey 帳號第二步驗證限制鍵
+func secondFactorThrottleKey(userID uint) string {
+	return fmt.Sprintf("mfa:user:%d", userID)
+}
+
+// mfaTokenThrottleKey 兩步驟驗證暫時權杖的失敗計數鍵
+func mfaTokenThrottleKey(tokenID string) string {
+	return "mfa:token:" + tokenID
+}
+
+// ipThrottleKey 來源 IP 限制鍵，沒有 IP 時返回空字串
+func ipThrottleKey(ip string) string {
+	if ip == "" {
+		return ""
+	}
+	return "ip:" + ip
+}
+
+// truncateString 將字串截斷至資料表欄位長度（以字元計算）
+func truncateString(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) > max {
+		return string(runes[:max])
+	}
+	return value
+}
@@ -38,7 +38,9 @@ type MatchingService struct {
 	algorithmRepo repository.MatchingAlgorithmRepository
 	userRepo      repository.UserRepository
 	profileRepo   repository.UserProfileRepository
-	cache         MatchingCacheInterface // 可選的快取服務
+	cache         MatchingCacheInterface                   // 可選的快取服務
+	attributeRepo repository.AttributePreferenceRepository // 可選
+	privateAlbums *PrivateAlbumService                     // 可選，取消配對時撤銷雙方的私人相簿授權
 }
 
 // NewMatchingService 創建新的配對服務實例
@@ -62,6 +64,16 @@ func (s *MatchingService) SetCache(cache MatchingCacheInterface) {
 	s.cache = cache
 }
 
+// SetAttributePreferenceRepository 設定屬性配對條件儲存庫，設定後潛在配對會套用用戶的硬性條件
+func (s *MatchingService) SetAttributePreferenceRepository(repo repository.AttributePreferenceRepository) {
+	s.attributeRepo = repo
+}
+
+// SetPrivateAlbums 設定私人相簿服務
+func (s *MatchingService) SetPrivateAlbums(privateAlbums *PrivateAlbumService) {
+	s.privateAlbums = privateAlbums
+}
+
 // SwipeRequest 滑動請求
 type SwipeRequest struct {
 	UserID       uint               `json:"user_id" validate:"required"`
@@ -79,14 +91,14 @@ type SwipeResponse struct {
 
 // PotentialMatchRequest 潛在配對請求
 type PotentialMatchRequest struct {
-	UserID                 uint           `json:"user_id" validate:"required"`
-	Limit                  int            `json:"limit"`
-	MaxDistance            *int           `json:"max_distance,omitempty"`
-	MinAge                 *int           `json:"min_age,omitempty"`
-	MaxAge                 *int           `json:"max_age,omitempty"`
-	PreferredGender        *entity.Gender `json:"preferred_gender,omitempty"`
-	RequireCommonInterests bool           `json:"require_common_interests"`
-	MinCommonInterests     *int           `json:"min_common_interests,omitempty"`
+	UserID                 uint            `json:"user_id" validate:"required"`
+	Limit                  int             `json:"limit"`
+	MaxDistance            *int            `json:"max_distance,omitempty"`
+	MinAge                 *int            `json:"min_age,omitempty"`
+	MaxAge                 *int            `json:"max_age,omitempty"`
+	PreferredGenders       []entity.Gender `json:"preferred_genders,omitempty"` // 本次查詢額外限定的性別
+	RequireCommonInterests bool            `json:"require_common_interests"`
+	MinCommonInterests     *int            `json:"min_common_interests,omitempty"`
 }
 
 // MatchListResponse 配對列表回應
@@ -122,7 +134,7 @@ func (s *MatchingService) ProcessSwipe(ctx context.Context, req *SwipeRequest) (
 		}, nil
 	}
 
-	if !targetUser.IsActive || !targetUser.IsVerified {
+	if !targetUser.IsActive || !targetUser.IsVerified || targetUser.RegistrationPending {
 		return &SwipeResponse{
 			Success: false,
 			IsMatch: false,
@@ -201,7 +213,7 @@ func (s *MatchingService) GetPotentialMatches(ctx context.Context, req *Potentia
 		return nil, fmt.Errorf("用戶不存在: %w", err)
 	}
 
-	if !user.IsActive || !user.IsVerified {
+	if !user.IsActive || !user.IsVerified || user.RegistrationPending {
 		return nil, errors.New("用戶未啟用或未驗證")
 	}
 
@@ -214,6 +226,19 @@ func (s *MatchingService) GetPotentialMatches(ctx context.Context, req *Potentia
 	// 建立查詢參數
 	params := s.buildMatchingParams(profile, req)
 
+	// 屬性硬性條件
+	if s.attributeRepo != nil {
+		preferences, err := s.attributeRepo.GetByUserID(ctx, req.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("獲取屬性配對條件失敗: %w", err)
+		}
+		for _, preference := range preferences {
+			if preference.Dealbreaker {
+				params.Dealbreakers = append(params.Dealbreakers, preference)
+			}
+		}
+	}
+
 	// 獲取潛在配對
 	var potentialMatches []*entity.User
 	if s.algorithmRepo != nil {
@@ -322,6 +347,13 @@ func (s *MatchingService) UnmatchUser(ctx context.Context, userID, targetUserID
 		return err
 	}
 
+	// 撤銷雙方的私人相簿授權，失敗不影響取消配對（查看時仍會確認配對狀態）
+	if s.privateAlbums != nil {
+		if err := s.privateAlbums.RevokeBetween(ctx, userID, targetUserID); err != nil {
+			log.Printf("撤銷私人相簿授權失敗 (用戶 %d, %d): %v", userID, targetUserID, err)
+		}
+	}
+
 	// 清除相關快取
 	if s.cache != nil {
 		// 清除配對列表快取
@@ -524,9 +556,9 @@ func (s *MatchingService) buildMatchingParams(profile *entity.UserProfile, req *
 		params.MaxAge = &profile.AgeRangeMax
 	}
 
-	// 性別偏好
-	if req.PreferredGender != nil {
-		params.PreferredGender = req.PreferredGender
+	// 性別偏好：檔案中儲存的偏好由儲存庫雙向檢查，這裡只傳遞本次查詢額外限定的性別
+	if len(req.PreferredGenders) > 0 {
+		params.PreferredGenders = req.PreferredGenders
 	}
 
 	// 共同興趣要求
@@ -0,0 +1,386 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+)
+
+// 外部登入相關錯誤
+var (
+	ErrOIDCProviderNotFound = errors.New("不支援的登入提供者")
+	ErrOIDCInvalidState     = errors.New("登入狀態無效或已過期，請重新登入")
+	ErrOIDCEmailRequired    = errors.New("外部帳號未提供 Email")
+	ErrOIDCEmailInUse       = errors.New("此 Email 已註冊，請先以原方式登入後再連結外部帳號")
+	ErrOIDCIdentityLinked   = errors.New("此外部帳號已連結其他用戶")
+	ErrIdentityNotFound     = errors.New("尚未連結此外部帳號")
+	ErrLastLoginMethod      = errors.New("無法解除唯一的登入方式，請先設定密碼")
+)
+
+// oidcStateTTL 授權流程的有效時間
+const oidcStateTTL = 10 * time.Minute
+
+// pendingBirthDate 外部登入建立帳號時的出生日期佔位值，補填前帳號不符合使用條件
+var pendingBirthDate = time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// OIDCProvider 外部身分提供者介面
+// 由 OIDC 基礎設施實作，負責授權網址、授權碼交換與 ID Token 驗證
+type OIDCProvider interface {
+	Name() string
+	DisplayName() string
+	AuthorizationURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier string) (*OIDCClaims, error)
+}
+
+// OIDCClaims 已驗證 ID Token 中的用戶資訊
+type OIDCClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Nonce         string
+}
+
+// OIDCStateStore 授權流程狀態儲存介面
+// 由 Redis CacheService 實作，狀態只能使用一次
+type OIDCStateStore interface {
+	SetVerificationCode(identifier, code string, expiration time.Duration) error
+	ConsumeVerificationCode(identifier string) (string, error)
+}
+
+// OIDCProviderInfo 可用的外部登入提供者
+type OIDCProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// OIDCAuthorization 授權流程開始時返回給用戶端的資訊
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+}
+
+// oidcAuthState 授權流程狀態，PKCE 驗證碼只保存在伺服器端
+type oidcAuthState struct {
+	Provider     string `json:"provider"`
+	CodeVerifier string `json:"code_verifier"`
+	Nonce        string `json:"nonce"`
+	LinkUserID   uint   `json:"link_user_id,omitempty"` // 非零表示連結既有帳號
+}
+
+// OIDCService 外部身分登入業務邏輯服務
+// 以授權碼流程搭配 PKCE 登入，並將外部帳號連結至用戶
+type OIDCService struct {
+	userRepo     repository.UserRepository
+	identityRepo repository.UserIdentityRepository
+	states       OIDCStateStore
+	providers    map[string]OIDCProvider
+	order        []string
+}
+
+// NewOIDCService 創建新的外部身分登入服務實例
+func NewOIDCService(
+	userRepo repository.UserRepository,
+	identityRepo repository.UserIdentityRepository,
+	states OIDCStateStore,
+	providers ...OIDCProvider,
+) *OIDCService {
+	s := &OIDCService{
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		states:       states,
+		providers:    make(map[string]OIDCProvider, len(providers)),
+	}
+	for _, provider := range providers {
+		s.providers[provider.Name()] = provider
+		s.order = append(s.order, provider.Name())
+	}
+	return s
+}
+
+// Providers 列出可用的外部登入提供者
+func (s *OIDCService) Providers() []OIDCProviderInfo {
+	infos := make([]OIDCProviderInfo, 0, len(s.order))
+	for _, name := range s.order {
+		infos = append(infos, OIDCProviderInfo{Name: name, DisplayName: s.providers[name].DisplayName()})
+	}
+	return infos
+}
+
+// BeginLogin 開始外部登入流程
+func (s *OIDCService) BeginLogin(ctx context.Context, providerName string) (*OIDCAuthorization, error) {
+	return s.begin(ctx, providerName, 0)
+}
+
+// BeginLink 開始將外部帳號連結至已登入用戶的流程
+func (s *OIDCService) BeginLink(ctx context.Context, userID uint, providerName string) (*OIDCAuthorization, error) {
+	return s.begin(ctx, providerName, userID)
+}
+
+// CompleteLogin 以授權碼完成外部登入
+// 已連結的外部帳號登入對應用戶；未連結且信箱未註冊時建立待補填出生日期的新帳號
+// 返回的 created 表示是否新建帳號
+func (s *OIDCService) CompleteLogin(ctx context.Context, providerName, code, state string) (user *entity.User, created bool, err error) {
+	claims, authState, err := s.complete(ctx, providerName, code, state)
+	if err != nil {
+		return nil, false, err
+	}
+	if authState.LinkUserID != 0 {
+		return nil, false, ErrOIDCInvalidState
+	}
+
+	identity, err := s.identityRepo.GetByProviderSubject(ctx, providerName, claims.Subject)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if identity != nil {
+		user, err = s.userRepo.GetByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, false, fmt.Errorf("獲取用戶失敗: %w", err)
+		}
+		if err := checkExternalLoginAllowed(user); err != nil {
+			return nil, false, err
+		}
+
+		now := time.Now()
+		identity.LastLoginAt = &now
+		if err := s.identityRepo.Update(ctx, identity); err != nil {
+			return nil, false, err
+		}
+		return user, false, nil
+	}
+
+	user, err = s.register(ctx, providerName, claims)
+	if err != nil {
+		return nil, false, err
+	}
+	return user, true, nil
+}
+
+// CompleteLink 以授權碼完成外部帳號連結
+// 授權流程必須由同一位用戶開始
+func (s *OIDCService) CompleteLink(ctx context.Context, userID uint, providerName, code, state string) (*entity.UserIdentity, error) {
+	claims, authState, err := s.complete(ctx, providerName, code, state)
+	if err != nil {
+		return nil, err
+	}
+	if authState.LinkUserID == 0 || authState.LinkUserID != userID {
+		return nil, ErrOIDCInvalidState
+	}
+
+	existing, err := s.identityRepo.GetByProviderSubject(ctx, providerName, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.UserID != userID {
+			return nil, ErrOIDCIdentityLinked
+		}
+		return existing, nil
+	}
+
+	identity := &entity.UserIdentity{
+		UserID:   userID,
+		Provider: providerName,
+		Subject:  claims.Subject,
+		Email:    strings.ToLower(claims.Email),
+	}
+	if err := s.identityRepo.Create(ctx, identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// ListIdentities 列出用戶已連結的外部帳號
+func (s *OIDCService) ListIdentities(ctx context.Context, userID uint) ([]*entity.UserIdentity, error) {
+	return s.identityRepo.ListByUserID(ctx, userID)
+}
+
+// Unlink 解除外部帳號連結
+// 沒有密碼且只剩一個外部帳號時不允許解除，避免用戶無法登入
+func (s *OIDCService) Unlink(ctx context.Context, userID uint, providerName string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("用戶不存在: %w", err)
+	}
+
+	identities, err := s.identityRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	linked := false
+	for _, identity := range identities {
+		if identity.Provider == providerName {
+			linked = true
+			break
+		}
+	}
+	if !linked {
+		return ErrIdentityNotFound
+	}
+	if user.PasswordHash == "" && len(identities) <= 1 {
+		return ErrLastLoginMethod
+	}
+
+	deleted, err := s.identityRepo.Delete(ctx, userID, providerName)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrIdentityNotFound
+	}
+	return nil
+}
+
+// 私有輔助方法
+
+// begin 產生 state、nonce 與 PKCE 驗證碼並返回授權網址
+func (s *OIDCService) begin(ctx context.Context, providerName string, linkUserID uint) (*OIDCAuthorization, error) {
+	provider, exists := s.providers[providerName]
+	if !exists {
+		return nil, ErrOIDCProviderNotFound
+	}
+	if s.states == nil {
+		return nil, ErrSessionStoreUnavailable
+	}
+
+	state, err := generateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := generateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := generateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(&oidcAuthState{
+		Provider:     providerName,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		LinkUserID:   linkUserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("序列化登入狀態失敗: %w", err)
+	}
+	if err := s.states.SetVerificationCode(oidcStateKey(state), string(data), oidcStateTTL); err != nil {
+		return nil, fmt.Errorf("保存登入狀態失敗: %w", err)
+	}
+
+	authURL, err := provider.AuthorizationURL(ctx, state, nonce, pkceChallenge(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("產生授權網址失敗: %w", err)
+	}
+	return &OIDCAuthorization{AuthorizationURL: authURL, State: state}, nil
+}
+
+// complete 取出並作廢 state，交換授權碼並驗證 nonce
+func (s *OIDCService) complete(ctx context.Context, providerName, code, state string) (*OIDCClaims, *oidcAuthState, error) {
+	provider, exists := s.providers[providerName]
+	if !exists {
+		return nil, nil, ErrOIDCProviderNotFound
+	}
+	if s.states == nil {
+		return nil, nil, ErrSessionStoreUnavailable
+	}
+	if state == "" || code == "" {
+		return nil, nil, ErrOIDCInvalidState
+	}
+
+	data, err := s.states.ConsumeVerificationCode(oidcStateKey(state))
+	if err != nil || data == "" {
+		return nil, nil, ErrOIDCInvalidState
+	}
+
+	var authState oidcAuthState
+	if err := json.Unmarshal([]byte(data), &authState); err != nil {
+		return nil, nil, ErrOIDCInvalidState
+	}
+	if authState.Provider != providerName {
+		return nil, nil, ErrOIDCInvalidState
+	}
+
+	claims, err := provider.Exchange(ctx, code, authState.CodeVerifier)
+	if err != nil {
+		return nil, nil, fmt.Errorf("外部登入驗證失敗: %w", err)
+	}
+	if claims.Nonce != authState.Nonce {
+		return nil, nil, ErrOIDCInvalidState
+	}
+	if claims.Subject == "" {
+		return nil, nil, errors.New("外部登入驗證失敗: 缺少用戶識別碼")
+	}
+	return claims, &authState, nil
+}
+
+// register 以外部帳號建立新用戶並連結
+// 信箱已註冊時不自動連結，避免未經驗證的外部帳號接管既有帳號
+func (s *OIDCService) register(ctx context.Context, providerName string, claims *OIDCClaims) (*entity.User, error) {
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if email == "" {
+		return nil, ErrOIDCEmailRequired
+	}
+
+	if existing, err := s.userRepo.GetByEmail(ctx, email); err == nil && existing != nil {
+		return nil, ErrOIDCEmailInUse
+	}
+
+	user := &entity.User{
+		Email:               email,
+		BirthDate:           pendingBirthDate,
+		IsActive:            true,
+		Role:                entity.RoleUser,
+		RegistrationPending: true,
+	}
+	if claims.EmailVerified {
+		user.MarkEmailVerified()
+	}
+
+	now := time.Now()
+	identity := &entity.UserIdentity{
+		Provider:    providerName,
+		Subject:     claims.Subject,
+		Email:       email,
+		LastLoginAt: &now,
+	}
+	if err := s.identityRepo.CreateWithUser(ctx, user, identity); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// checkExternalLoginAllowed 檢查帳號是否允許登入，規則與密碼登入一致
+func checkExternalLoginAllowed(user *entity.User) error {
+	if user.IsPendingDeletion() {
+		return fmt.Errorf("%w，將於 %s 清除，可在此之前取消", ErrAccountPendingDeletion, user.DeletionScheduledAt.Format("2006-01-02 15:04"))
+	}
+	if !user.IsActive {
+		return errors.New("帳戶已被停用")
+	}
+	return checkAccountStatus(user)
+}
+
+// oidcStateKey 授權流程狀態的儲存鍵
+func oidcStateKey(state string) string {
+	return "oidc_state:" + hashToken(state)
+}
+
+// pkceChallenge 依 RFC 7636 以 S256 計算 code_challenge
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
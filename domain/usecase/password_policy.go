@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 密碼規則相關錯誤
+var (
+	ErrWeakPassword     = errors.New("密碼不符合安全規則")
+	ErrBreachedPassword = errors.New("此密碼曾出現在外洩資料中，請改用其他密碼")
+)
+
+// bcryptMaxBytes bcrypt 只處理前 72 個位元組
+const bcryptMaxBytes = 72
+
+// PasswordPolicy 密碼規則
+type PasswordPolicy struct {
+	MinLength      int  // 最少字元數
+	MaxLength      int  // 最多位元組數，不可超過 bcrypt 的 72 位元組
+	MinCharClasses int  // 至少包含的字元類別數（小寫、大寫、數字、符號）
+	MinStrength    int  // 最低強度評分 0-4，0 表示不檢查
+	RejectPersonal bool // 不可包含 Email 或顯示名稱
+}
+
+// DefaultPasswordPolicy 預設密碼規則
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:      8,
+		MaxLength:      bcryptMaxBytes,
+		MinCharClasses: 2,
+		MinStrength:    3,
+		RejectPersonal: true,
+	}
+}
+
+// BreachedPasswordChecker 外洩密碼檢查介面
+// 由 infrastructure/breach 以本地雜湊清單實作，不需要網路請求
+type BreachedPasswordChecker interface {
+	IsBreached(password string) (bool, error)
+}
+
+// PasswordValidator 依密碼規則驗證密碼
+// 註冊、重設密碼、變更密碼與建立初始管理員共用同一套規則
+type PasswordValidator struct {
+	policy   PasswordPolicy
+	breached BreachedPasswordChecker // 可選
+}
+
+// NewPasswordValidator 創建密碼驗證器，未設定的規則欄位使用預設值
+func NewPasswordValidator(policy PasswordPolicy, breached BreachedPasswordChecker) *PasswordValidator {
+	defaults := DefaultPasswordPolicy()
+	if policy.MinLength <= 0 {
+		policy.MinLength = defaults.MinLength
+	}
+	if policy.MaxLength <= 0 || policy.MaxLength > bcryptMaxBytes {
+		policy.MaxLength = bcryptMaxBytes
+	}
+	if policy.MinCharClasses > 4 {
+		policy.MinCharClasses = 4
+	}
+	if policy.MinStrength > 4 {
+		policy.MinStrength = 4
+	}
+	return &PasswordValidator{policy: policy, breached: breached}
+}
+
+// NewDefaultPasswordValidator 以預設規則創建密碼驗證器，不檢查外洩清單
+func NewDefaultPasswordValidator() *PasswordValidator {
+	return NewPasswordValidator(DefaultPasswordPolicy(), nil)
+}
+
+// Validate 驗證密碼，userInputs 為 Email、顯示名稱等用戶個人資料
+func (v *PasswordValidator) Validate(password string, userInputs ...string) error {
+	if strings.TrimSpace(password) == "" {
+		return fmt.Errorf("%w: 密碼不能為空", ErrWeakPassword)
+	}
+
+	if utf8.RuneCountInString(password) < v.policy.MinLength {
+		return fmt.Errorf("%w: 密碼長度至少%d個字符", ErrWeakPassword, v.policy.MinLength)
+	}
+
+	if len(password) > v.policy.MaxLength {
+		return fmt.Errorf("%w: 密碼長度不能超過%d個字符", ErrWeakPassword, v.policy.MaxLength)
+	}
+
+	if classes := countCharClasses(password); classes < v.policy.MinCharClasses {
+		return fmt.Errorf("%w: 密碼須包含小寫字母、大寫字母、數字、符號中至少%d種", ErrWeakPassword, v.policy.MinCharClasses)
+	}
+
+	personal := personalTokens(userInputs)
+	if v.policy.RejectPersonal {
+		lowered := strings.ToLower(password)
+		for _, token := range personal {
+			if strings.Contains(lowered, token) {
+				return fmt.Errorf("%w: 密碼不能包含 Email 或顯示名稱", ErrWeakPassword)
+			}
+		}
+	}
+
+	if v.policy.MinStrength > 0 {
+		if strength := EstimatePasswordStrength(password, personal...); strength.Score < v.policy.MinStrength {
+			return fmt.Errorf("%w: 密碼太容易被猜到，請使用較長或較不常見的組合", ErrWeakPassword)
+		}
+	}
+
+	if v.breached != nil {
+		breached, err := v.breached.IsBreached(password)
+		if err != nil {
+			// 外洩清單無法讀取時不阻擋用戶，其他規則仍然有效
+			log.Printf("檢查外洩密碼清單失敗: %v", err)
+		} else if breached {
+			return ErrBreachedPassword
+		}
+	}
+
+	return nil
+}
+
+// countCharClasses 計算密碼包含的字元類別數
+func countCharClasses(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsLetter(r):
+			// 沒有大小寫之分的文字（例如中文）視為小寫字母
+			lower = true
+		default:
+			symbol = true
+		}
+	}
+
+	count := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			count++
+		}
+	}
+	return count
+}
+
+// personalTokens 由 Email 與顯示名稱拆出需要避免的片段
+// Email 取 @ 之前的帳號名稱，並依標點與空白拆成單字；過短的片段不列入
+func personalTokens(userInputs []string) []string {
+	seen := make(map[string]bool)
+	var tokens []string
+	add := func(token string) {
+		if utf8.RuneCountInString(token) < 3 || seen[token] {
+			return
+		}
+		seen[token] = true
+		tokens = append(tokens, token)
+	}
+
+	for _, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		if at := strings.Index(input, "@"); at >= 0 {
+			input = input[:at]
+		}
+		add(input)
+		for _, word := range strings.FieldsFunc(input, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			add(word)
+		}
+	}
+	return tokens
+}
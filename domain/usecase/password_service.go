@@ -0,0 +1,295 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+)
+
+// 密碼相關錯誤
+var (
+	ErrInvalidResetToken        = errors.New("密碼重設權杖無效或已過期")
+	ErrIncorrectPassword        = errors.New("原密碼錯誤")
+	ErrPasswordUnchanged        = errors.New("新密碼不能與原密碼相同")
+	ErrPasswordResetUnavailable = errors.New("密碼重設服務不可用")
+)
+
+const (
+	passwordResetTokenTTL      = 30 * time.Minute
+	passwordResetRequestWindow = time.Minute
+)
+
+// PasswordResetStore 密碼重設權杖儲存介面
+// 由 Redis CacheService 實作，Consume 必須為原子操作以確保權杖只能使用一次
+type PasswordResetStore interface {
+	SetVerificationCode(identifier, code string, expiration time.Duration) error
+	GetVerificationCode(identifier string) (string, error)
+	ConsumeVerificationCode(identifier string) (string, error)
+	DeleteVerificationCode(identifier string) error
+	IncrementRateLimit(identifier string, expiration time.Duration) (int64, error)
+}
+
+// SessionRevoker 會話撤銷介面
+// 由 AuthService 實作
+type SessionRevoker interface {
+	RevokeAllSessions(ctx context.Context, userID uint) error
+	RevokeOtherSessions(ctx context.Context, userID uint, currentSessionID string) error
+}
+
+// PasswordService 密碼管理業務邏輯服務
+// 負責忘記密碼、重設密碼與變更密碼
+type PasswordService struct {
+	userRepo  repository.UserRepository
+	mailer    Mailer
+	tokens    PasswordResetStore // 可選，未設定時無法重設密碼
+	sessions  SessionRevoker
+	passwords *PasswordValidator
+	profiles  repository.UserProfileRepository // 可選，用於檢查新密碼是否包含顯示名稱
+}
+
+// NewPasswordService 創建新的密碼管理服務實例
+func NewPasswordService(
+	userRepo repository.UserRepository,
+	mailer Mailer,
+	tokens PasswordResetStore,
+	sessions SessionRevoker,
+) *PasswordService {
+	return &PasswordService{
+		userRepo:  userRepo,
+		mailer:    mailer,
+		tokens:    tokens,
+		sessions:  sessions,
+		passwords: NewDefaultPasswordValidator(),
+	}
+}
+
+// SetPasswordValidator 設定重設與變更密碼時使用的密碼規則
+func (s *PasswordService) SetPasswordValidator(validator *PasswordValidator) {
+	s.passwords = validator
+}
+
+// SetProfileRepository 設定用戶檔案儲存庫，用於檢查新密碼是否包含顯示名稱
+func (s *PasswordService) SetProfileRepository(profiles repository.UserProfileRepository) {
+	s.profiles = profiles
+}
+
+// RequestPasswordReset 發送密碼重設信
+// 帳號不存在、已停用或請求過於頻繁時靜默成功，避免洩漏帳號是否存在
+func (s *PasswordService) RequestPasswordReset(ctx context.Context, email string) error {
+	if s.tokens == nil || s.mailer == nil {
+		return ErrPasswordResetUnavailable
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil || !user.IsActive {
+		return nil
+	}
+
+	count, err := s.tokens.IncrementRateLimit(s.requestKey(user.ID), passwordResetRequestWindow)
+	if err != nil {
+		return fmt.Errorf("檢查發送頻率失敗: %w", err)
+	}
+	if count > 1 {
+		return nil
+	}
+
+	token, err := generateSecureToken(32)
+	if err != nil {
+		return fmt.Errorf("生成重設權杖失敗: %w", err)
+	}
+
+	// 同一用戶只保留最新的重設權杖
+	s.invalidatePendingReset(user.ID)
+
+	tokenHash := hashToken(token)
+	if err := s.tokens.SetVerificationCode(s.tokenKey(tokenHash), strconv.FormatUint(uint64(user.ID), 10), passwordResetTokenTTL); err != nil {
+		return fmt.Errorf("儲存重設權杖失敗: %w", err)
+	}
+	if err := s.tokens.SetVerificationCode(s.userKey(user.ID), tokenHash, passwordResetTokenTTL); err != nil {
+		return fmt.Errorf("儲存重設權杖失敗: %w", err)
+	}
+
+	msg := &EmailMessage{
+		To:      user.Email,
+		Subject: "重設您的密碼",
+		Body:    buildResetBody(token),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("發送密碼重設信失敗: %w", err)
+	}
+
+	return nil
+}
+
+// ResetPassword 以重設權杖設定新密碼，並撤銷用戶所有會話
+func (s *PasswordService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if s.tokens == nil {
+		return ErrPasswordResetUnavailable
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ErrInvalidResetToken
+	}
+
+	// 先讀取權杖並驗證新密碼，避免密碼不合格時消耗掉權杖
+	tokenKey := s.tokenKey(hashToken(token))
+	value, err := s.tokens.GetVerificationCode(tokenKey)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	userID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, uint(userID))
+	if err != nil || !user.IsActive {
+		return ErrInvalidResetToken
+	}
+
+	if err := s.validateNewPassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	// 原子地消耗權杖，同一權杖的並行請求只有一個能成功
+	if consumed, err := s.tokens.ConsumeVerificationCode(tokenKey); err != nil || consumed != value {
+		return ErrInvalidResetToken
+	}
+	_ = s.tokens.DeleteVerificationCode(s.userKey(user.ID))
+
+	if err := s.updatePassword(ctx, user.ID, newPassword); err != nil {
+		return err
+	}
+
+	// 重設密碼代表帳號可能已外洩，撤銷所有裝置上的會話
+	if err := s.sessions.RevokeAllSessions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	s.notifyPasswordChanged(ctx, user.Email)
+	log.Printf("用戶 %d 已透過重設權杖更新密碼", user.ID)
+	return nil
+}
+
+// ChangePassword 驗證原密碼後變更密碼，並撤銷目前會話以外的所有會話
+func (s *PasswordService) ChangePassword(ctx context.Context, userID uint, currentSessionID, oldPassword, newPassword string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("用戶不存在: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(oldPassword)); err != nil {
+		return ErrIncorrectPassword
+	}
+
+	if oldPassword == newPassword {
+		return ErrPasswordUnchanged
+	}
+
+	if err := s.validateNewPassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	if err := s.updatePassword(ctx, user.ID, newPassword); err != nil {
+		return err
+	}
+
+	if err := s.sessions.RevokeOtherSessions(ctx, user.ID, currentSessionID); err != nil {
+		return err
+	}
+
+	// 尚未使用的重設權杖一併作廢
+	if s.tokens != nil {
+		s.invalidatePendingReset(user.ID)
+	}
+
+	s.notifyPasswordChanged(ctx, user.Email)
+	log.Printf("用戶 %d 已變更密碼", user.ID)
+	return nil
+}
+
+// 私有輔助方法
+
+func (s *PasswordService) tokenKey(tokenHash string) string {
+	return "password_reset:" + tokenHash
+}
+
+func (s *PasswordService) userKey(userID uint) string {
+	return fmt.Sprintf("password_reset_user:%d", userID)
+}
+
+func (s *PasswordService) requestKey(userID uint) string {
+	return fmt.Sprintf("password_reset_request:%d", userID)
+}
+
+// validateNewPassword 依密碼規則驗證新密碼，不可包含用戶的 Email 或顯示名稱
+func (s *PasswordService) validateNewPassword(ctx context.Context, user *entity.User, password string) error {
+	userInputs := []string{user.Email}
+	if s.profiles != nil {
+		if profile, err := s.profiles.GetByUserID(ctx, user.ID); err == nil && profile != nil {
+			userInputs = append(userInputs, profile.DisplayName)
+		}
+	}
+	return s.passwords.Validate(password, userInputs...)
+}
+
+// updatePassword 以 bcrypt 雜湊新密碼並寫入資料庫
+func (s *PasswordService) updatePassword(ctx context.Context, userID uint, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("密碼加密失敗: %w", err)
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, userID, string(hashedPassword)); err != nil {
+		return fmt.Errorf("更新密碼失敗: %w", err)
+	}
+	return nil
+}
+
+// invalidatePendingReset 作廢用戶尚未使用的重設權杖
+func (s *PasswordService) invalidatePendingReset(userID uint) {
+	previous, err := s.tokens.GetVerificationCode(s.userKey(userID))
+	if err != nil {
+		return
+	}
+	_ = s.tokens.DeleteVerificationCode(s.tokenKey(previous))
+	_ = s.tokens.DeleteVerificationCode(s.userKey(userID))
+}
+
+// notifyPasswordChanged 通知用戶密碼已變更，發送失敗只記錄日誌
+func (s *PasswordService) notifyPasswordChanged(ctx context.Context, email string) {
+	if s.mailer == nil {
+		return
+	}
+
+	msg := &EmailMessage{
+		To:      email,
+		Subject: "您的密碼已變更",
+		Body:    "您好，\n\n您的帳號密碼剛剛已被變更，其他裝置上的登入已全部登出。\n\n如果這不是您本人的操作，請立即使用忘記密碼功能重設密碼。\n",
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("發送密碼變更通知失敗: %v", err)
+	}
+}
+
+// buildResetBody 組裝密碼重設信內容
+func buildResetBody(token string) string {
+	var b strings.Builder
+	b.WriteString("您好，\n\n")
+	b.WriteString("我們收到了重設您帳號密碼的請求。\n")
+	b.WriteString(fmt.Sprintf("您的密碼重設權杖為：%s\n", token))
+	b.WriteString(fmt.Sprintf("此權杖將於 %d 分鐘後失效，且只能使用一次。\n", int(passwordResetTokenTTL.Minutes())))
+	b.WriteString("\n如果您沒有申請重設密碼，請忽略此郵件，您的密碼不會被變更。\n")
+	return b.String()
+}
@@ -0,0 +1,411 @@
+package usecase
+
+import (
+	_ "embed"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 密碼強度估計
+// 參考 zxcvbn 的作法：找出密碼中可被猜測的片段（常見密碼、字典單字、個人資料、
+// 連續字元、重複字元、鍵盤排列、年份與日期），以動態規劃求出攻擊者最少需要的猜測次數，
+// 再依猜測次數換算 0-4 的評分。所有計算以 log10 進行以避免溢位
+
+// passwordDictionaryData 依常見程度排序的密碼與單字清單，越前面越常見
+//
+//go:embed password_dictionary.txt
+var passwordDictionaryData string
+
+// passwordDictionary 單字對應其排名（從 1 開始）
+var passwordDictionary = loadRankedDictionary(passwordDictionaryData)
+
+// 猜測次數門檻（log10），對應評分 1-4
+var strengthThresholds = [4]float64{3, 6, 8, 10}
+
+const (
+	bruteforceCardinalityLog10 = 1.0             // 無法辨識的字元每個約 10 種可能
+	minSubmatchGuessesLog10    = 1.6989700043360 // 多字元片段至少 50 次猜測
+	maxDictionaryWordLength    = 24
+)
+
+// keyboardLayouts 常見的鍵盤排列，包含橫列、直行與交錯
+var keyboardLayouts = []string{
+	"`1234567890-=",
+	"qwertyuiop[]\\",
+	"asdfghjkl;'",
+	"zxcvbnm,./",
+	"1qaz2wsx3edc4rfv5tgb6yhn7ujm8ik,9ol.0p;/",
+	"1q2w3e4r5t6y7u8i9o0p",
+	"qawsedrftgyhujikolp",
+	"azsxdcfvgbhnjmk",
+}
+
+// leetSubstitutions 常見的字元替換（l33t），每組替換表各產生一種還原結果
+var leetSubstitutions = []map[rune]rune{
+	{'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '@': 'a', '$': 's', '!': 'i', '+': 't'},
+	{'0': 'o', '1': 'l', '3': 'e', '4': 'a', '5': 's', '7': 't', '@': 'a', '$': 's', '!': 'l', '|': 'l'},
+}
+
+// PasswordStrength 密碼強度估計結果
+type PasswordStrength struct {
+	GuessesLog10 float64 // 估計需要的猜測次數（log10）
+	Score        int     // 0 極弱 - 4 很強
+}
+
+// guessMatch 密碼中可被猜測的片段 [start, end)
+type guessMatch struct {
+	start, end   int
+	guessesLog10 float64
+}
+
+// EstimatePasswordStrength 估計密碼強度，userInputs 為應視為已知的個人資料片段
+func EstimatePasswordStrength(password string, userInputs ...string) PasswordStrength {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return PasswordStrength{}
+	}
+
+	guesses := minimumGuessesLog10(runes, userDictionary(userInputs))
+	score := 0
+	for _, threshold := range strengthThresholds {
+		if guesses >= threshold {
+			score++
+		}
+	}
+	return PasswordStrength{GuessesLog10: guesses, Score: score}
+}
+
+// minimumGuessesLog10 以動態規劃找出覆蓋整個密碼所需的最少猜測次數
+// 相鄰的無法辨識字元合併為一個暴力破解片段；片段數越多，組合順序的可能性越多
+func minimumGuessesLog10(runes []rune, userDict map[string]int) float64 {
+	lowered := []rune(strings.ToLower(string(runes)))
+	if len(lowered) != len(runes) {
+		// 少數字元轉小寫後長度改變，退回逐字元處理
+		lowered = make([]rune, len(runes))
+		for i, r := range runes {
+			lowered[i] = unicode.ToLower(r)
+		}
+	}
+
+	var matches []guessMatch
+	matches = append(matches, dictionaryMatches(runes, lowered, userDict)...)
+	matches = append(matches, sequenceMatches(lowered)...)
+	matches = append(matches, repeatMatches(runes, userDict)...)
+	matches = append(matches, keyboardMatches(lowered)...)
+	matches = append(matches, dateMatches(lowered)...)
+
+	byStart := make([][]guessMatch, len(runes))
+	for _, m := range matches {
+		byStart[m.start] = append(byStart[m.start], m)
+	}
+
+	type state struct {
+		guesses    float64
+		segments   int
+		bruteforce bool // 最後一個片段是否為暴力破解
+		reached    bool
+	}
+	total := func(s state) float64 {
+		return s.guesses + log10Factorial(s.segments)
+	}
+	better := func(candidate, current state) bool {
+		return !current.reached || total(candidate) < total(current)
+	}
+
+	best := make([]state, len(runes)+1)
+	best[0] = state{reached: true}
+	for i := 0; i < len(runes); i++ {
+		if !best[i].reached {
+			continue
+		}
+		from := best[i]
+
+		brute := state{guesses: from.guesses + bruteforceCardinalityLog10, segments: from.segments, bruteforce: true, reached: true}
+		if !from.bruteforce {
+			brute.segments++
+		}
+		if better(brute, best[i+1]) {
+			best[i+1] = brute
+		}
+
+		for _, m := range byStart[i] {
+			next := state{guesses: from.guesses + m.guessesLog10, segments: from.segments + 1, reached: true}
+			if better(next, best[m.end]) {
+				best[m.end] = next
+			}
+		}
+	}
+	return total(best[len(runes)])
+}
+
+// dictionaryMatches 找出常見密碼、字典單字與個人資料，包含大小寫、l33t 替換與反轉變化
+func dictionaryMatches(runes, lowered []rune, userDict map[string]int) []guessMatch {
+	var matches []guessMatch
+	for i := 0; i < len(lowered); i++ {
+		for j := i + 3; j <= len(lowered) && j-i <= maxDictionaryWordLength; j++ {
+			word := string(lowered[i:j])
+			factor := uppercaseVariationsLog10(runes[i:j])
+
+			candidates := map[string]float64{word: 0}
+			for _, table := range leetSubstitutions {
+				if unleeted := replaceRunes(lowered[i:j], table); unleeted != word {
+					candidates[unleeted] = math.Log10(2)
+				}
+			}
+			candidates[reverseString(word)] = math.Log10(2)
+
+			bestGuesses := math.Inf(1)
+			for candidate, extra := range candidates {
+				rank := lookupRank(candidate, userDict)
+				if rank == 0 {
+					continue
+				}
+				if guesses := math.Log10(float64(rank)) + factor + extra; guesses < bestGuesses {
+					bestGuesses = guesses
+				}
+			}
+			if !math.IsInf(bestGuesses, 1) {
+				matches = append(matches, guessMatch{start: i, end: j, guessesLog10: math.Max(bestGuesses, minSubmatchGuessesLog10)})
+			}
+		}
+	}
+	return matches
+}
+
+// sequenceMatches 找出 abc、987 等等差為 1 的連續字元
+func sequenceMatches(lowered []rune) []guessMatch {
+	var matches []guessMatch
+	for i := 0; i+2 < len(lowered); {
+		delta := lowered[i+1] - lowered[i]
+		if delta != 1 && delta != -1 {
+			i++
+			continue
+		}
+		j := i + 2
+		for j < len(lowered) && lowered[j]-lowered[j-1] == delta {
+			j++
+		}
+		if j-i >= 3 {
+			var base float64
+			switch first := lowered[i]; {
+			case strings.ContainsRune("az019", first):
+				base = 4
+			case unicode.IsDigit(first):
+				base = 10
+			default:
+				base = 26
+			}
+			if delta < 0 {
+				base *= 2
+			}
+			guesses := math.Log10(base * float64(j-i))
+			matches = append(matches, guessMatch{start: i, end: j, guessesLog10: math.Max(guesses, minSubmatchGuessesLog10)})
+			i = j - 1
+			continue
+		}
+		i++
+	}
+	return matches
+}
+
+// repeatMatches 找出 aaa、abcabc 等重複片段，猜測次數為單一單元的猜測次數乘上重複次數
+func repeatMatches(runes []rune, userDict map[string]int) []guessMatch {
+	var matches []guessMatch
+	for i := 0; i < len(runes); i++ {
+		for unit := 1; i+unit*2 <= len(runes); unit++ {
+			count := 1
+			for i+(count+1)*unit <= len(runes) &&
+				string(runes[i+count*unit:i+(count+1)*unit]) == string(runes[i:i+unit]) {
+				count++
+			}
+			if count < 2 || (unit == 1 && count < 3) {
+				continue
+			}
+
+			var unitGuesses float64
+			if unit == 1 {
+				unitGuesses = math.Log10(characterCardinality(runes[i]))
+			} else {
+				unitGuesses = minimumGuessesLog10(runes[i:i+unit], userDict)
+			}
+			guesses := unitGuesses + math.Log10(float64(count))
+			matches = append(matches, guessMatch{start: i, end: i + count*unit, guessesLog10: math.Max(guesses, minSubmatchGuessesLog10)})
+		}
+	}
+	return matches
+}
+
+// keyboardMatches 找出 qwerty、1qaz2wsx 等沿鍵盤排列的片段
+func keyboardMatches(lowered []rune) []guessMatch {
+	var matches []guessMatch
+	for i := 0; i < len(lowered); i++ {
+		j := i + 1
+		for j < len(lowered) && onKeyboard(string(lowered[i:j+1])) {
+			j++
+		}
+		if j-i >= 4 {
+			guesses := math.Log10(10 * float64(j-i))
+			matches = append(matches, guessMatch{start: i, end: j, guessesLog10: math.Max(guesses, minSubmatchGuessesLog10)})
+		}
+	}
+	return matches
+}
+
+// dateMatches 找出年份（1900-2039）與 8 位數日期
+func dateMatches(lowered []rune) []guessMatch {
+	const years = 140
+	var matches []guessMatch
+	for i := 0; i+4 <= len(lowered); i++ {
+		if year, ok := parseDigits(lowered[i : i+4]); ok && year >= 1900 && year < 1900+years {
+			matches = append(matches, guessMatch{start: i, end: i + 4, guessesLog10: math.Log10(years)})
+		}
+		if i+8 <= len(lowered) && isDate(lowered[i:i+8]) {
+			matches = append(matches, guessMatch{start: i, end: i + 8, guessesLog10: math.Log10(years * 366 * 3)})
+		}
+	}
+	return matches
+}
+
+// isDate 判斷 8 位數字是否為 YYYYMMDD、DDMMYYYY 或 MMDDYYYY 格式的日期
+func isDate(digits []rune) bool {
+	value, ok := parseDigits(digits)
+	if !ok {
+		return false
+	}
+	validDay := func(month, day int) bool { return month >= 1 && month <= 12 && day >= 1 && day <= 31 }
+	validYear := func(year int) bool { return year >= 1900 && year < 2040 }
+
+	if year, month, day := value/10000, value/100%100, value%100; validYear(year) && validDay(month, day) {
+		return true
+	}
+	first, second, year := value/1000000, value/10000%100, value%10000
+	return validYear(year) && (validDay(second, first) || validDay(first, second))
+}
+
+// uppercaseVariationsLog10 大小寫變化帶來的額外猜測次數
+// 常見的首字大寫、全大寫或末字大寫只加倍，其餘依大寫字母位置的組合數計算
+func uppercaseVariationsLog10(word []rune) float64 {
+	var upper, lower int
+	for _, r := range word {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+	if upper == 0 {
+		return 0
+	}
+	if lower == 0 || (upper == 1 && (unicode.IsUpper(word[0]) || unicode.IsUpper(word[len(word)-1]))) {
+		return math.Log10(2)
+	}
+
+	variations := 0.0
+	for k := 1; k <= upper && k <= lower; k++ {
+		variations += binomial(upper+lower, k)
+	}
+	return math.Log10(variations)
+}
+
+// characterCardinality 單一字元所屬類別的可能字元數
+func characterCardinality(r rune) float64 {
+	switch {
+	case unicode.IsDigit(r):
+		return 10
+	case unicode.IsLetter(r):
+		return 26
+	default:
+		return 33
+	}
+}
+
+// onKeyboard 判斷片段是否沿任一鍵盤排列（正向或反向）連續
+func onKeyboard(fragment string) bool {
+	reversed := reverseString(fragment)
+	for _, layout := range keyboardLayouts {
+		if strings.Contains(layout, fragment) || strings.Contains(layout, reversed) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupRank 查詢單字排名，個人資料優先，0 表示不在字典中
+func lookupRank(word string, userDict map[string]int) int {
+	if rank, ok := userDict[word]; ok {
+		return rank
+	}
+	return passwordDictionary[word]
+}
+
+// userDictionary 將個人資料片段建成字典，排名依提供順序
+func userDictionary(userInputs []string) map[string]int {
+	dict := make(map[string]int, len(userInputs))
+	for _, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		if _, exists := dict[input]; input != "" && !exists {
+			dict[input] = len(dict) + 1
+		}
+	}
+	return dict
+}
+
+// loadRankedDictionary 解析每行一個單字的清單
+func loadRankedDictionary(data string) map[string]int {
+	words := strings.Fields(data)
+	dict := make(map[string]int, len(words))
+	for _, word := range words {
+		if _, exists := dict[word]; !exists {
+			dict[word] = len(dict) + 1
+		}
+	}
+	return dict
+}
+
+func replaceRunes(runes []rune, table map[rune]rune) string {
+	replaced := make([]rune, len(runes))
+	for i, r := range runes {
+		if sub, ok := table[r]; ok {
+			r = sub
+		}
+		replaced[i] = r
+	}
+	return string(replaced)
+}
+
+func reverseString(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func parseDigits(runes []rune) (int, bool) {
+	for _, r := range runes {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+	}
+	value, err := strconv.Atoi(string(runes))
+	return value, err == nil
+}
+
+func binomial(n, k int) float64 {
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+	return result
+}
+
+func log10Factorial(n int) float64 {
+	result := 0.0
+	for i := 2; i <= n; i++ {
+		result += math.Log10(float64(i))
+	}
+	return result
+}
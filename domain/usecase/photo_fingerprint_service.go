@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+)
+
+// 照片指紋相關錯誤
+var (
+	ErrBlockedImageNotFound = errors.New("禁用圖片不存在")
+	ErrPhotoHashUnavailable = errors.New("照片沒有感知雜湊，無法加入禁用清單")
+)
+
+const (
+	// DefaultDuplicateDistance 預設視為相同圖片的最大漢明距離（64 位元 dHash）
+	DefaultDuplicateDistance = 10
+
+	// duplicateCandidateLimit 每次比對最多取回的相似照片數
+	duplicateCandidateLimit = 5
+)
+
+// DuplicateFinding 重複照片偵測結果
+type DuplicateFinding struct {
+	Confidence     float64 `json:"confidence"`
+	Distance       int     `json:"distance"`
+	MatchedPhotoID *uint   `json:"matched_photo_id,omitempty"` // 其他用戶的相似照片
+	MatchedUserID  *uint   `json:"matched_user_id,omitempty"`
+	BlockedImageID *uint   `json:"blocked_image_id,omitempty"` // 相符的禁用圖片
+}
+
+// PhotoFingerprintService 照片指紋業務邏輯服務
+// 以感知雜湊比對新上傳的照片與其他用戶的照片及禁用圖片清單，
+// 疑似盜用的照片標記為 fake_profile 並排入審核佇列優先處理
+type PhotoFingerprintService struct {
+	photoRepo      repository.PhotoRepository
+	blockedRepo    repository.BlockedImageRepository
+	userRepo       repository.UserRepository
+	moderationRepo repository.ModerationRepository
+	maxDistance    int
+}
+
+// NewPhotoFingerprintService 創建新的照片指紋服務實例
+func NewPhotoFingerprintService(
+	photoRepo repository.PhotoRepository,
+	blockedRepo repository.BlockedImageRepository,
+	userRepo repository.UserRepository,
+	moderationRepo repository.ModerationRepository,
+) *PhotoFingerprintService {
+	return &PhotoFingerprintService{
+		photoRepo:      photoRepo,
+		blockedRepo:    blockedRepo,
+		userRepo:       userRepo,
+		moderationRepo: moderationRepo,
+		maxDistance:    DefaultDuplicateDistance,
+	}
+}
+
+// SetMaxDistance 設定視為相同圖片的最大漢明距離，未設定或超出範圍時使用預設值
+func (s *PhotoFingerprintService) SetMaxDistance(distance int) {
+	if distance <= 0 || distance >= 32 {
+		distance = DefaultDuplicateDistance
+	}
+	s.maxDistance = distance
+}
+
+// BlockImageRequest 加入禁用清單請求
+type BlockImageRequest struct {
+	PhotoID     uint   `json:"photo_id"`
+	ModeratorID uint   `json:"-"` // 由認證資訊設定
+	Reason      string `json:"reason"`
+}
+
+// BlockedImageList 禁用圖片清單
+type BlockedImageList struct {
+	Images []*entity.BlockedImage `json:"images"`
+	Total  int64                  `json:"total"`
+	Limit  int                    `json:"limit"`
+	Offset int                    `json:"offset"`
+}
+
+// Inspect 比對照片與其他用戶的照片及禁用圖片清單
+// 找到相似圖片時標記照片並寫入自動審核日誌，沒有雜湊或沒有相似圖片時返回 nil
+func (s *PhotoFingerprintService) Inspect(ctx context.Context, photo *entity.Photo) (*DuplicateFinding, error) {
+	if photo.PerceptualHash == nil {
+		return nil, nil
+	}
+	hash := *photo.PerceptualHash
+
+	var finding *DuplicateFinding
+
+	blocked, err := s.blockedRepo.FindSimilar(ctx, hash, s.maxDistance)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocked) > 0 {
+		distance := entity.HammingDistance(hash, blocked[0].PerceptualHash)
+		finding = &DuplicateFinding{
+			Confidence:     s.confidence(distance),
+			Distance:       distance,
+			BlockedImageID: &blocked[0].ID,
+		}
+	}
+
+	similar, err := s.photoRepo.FindSimilar(ctx, hash, s.maxDistance, photo.UserID, duplicateCandidateLimit)
+	if err != nil {
+		return nil, err
+	}
+	if len(similar) > 0 && similar[0].PerceptualHash != nil {
+		distance := entity.HammingDistance(hash, *similar[0].PerceptualHash)
+		if finding == nil || distance < finding.Distance {
+			finding = &DuplicateFinding{
+				Confidence:     s.confidence(distance),
+				Distance:       distance,
+				MatchedPhotoID: &similar[0].ID,
+				MatchedUserID:  &similar[0].UserID,
+			}
+		}
+	}
+
+	if finding == nil {
+		return nil, nil
+	}
+
+	reason := string(entity.ReportCategoryFakeProfile)
+	if err := s.photoRepo.Flag(ctx, photo.ID, reason, finding.Confidence); err != nil {
+		return nil, err
+	}
+	photo.FlagReason = reason
+	photo.FlagScore = &finding.Confidence
+
+	s.writeLog(ctx, photo, finding)
+	return finding, nil
+}
+
+// ListBlockedImages 分頁獲取禁用圖片清單
+func (s *PhotoFingerprintService) ListBlockedImages(ctx context.Context, limit, offset int) (*BlockedImageList, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	images, total, err := s.blockedRepo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockedImageList{
+		Images: images,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// BlockImage 將照片的感知雜湊加入禁用清單，之後上傳的相似照片會被標記
+func (s *PhotoFingerprintService) BlockImage(ctx context.Context, req *BlockImageRequest) (*entity.BlockedImage, error) {
+	if err := s.checkModerator(ctx, req.ModeratorID); err != nil {
+		return nil, err
+	}
+
+	photo, err := s.photoRepo.GetByID(ctx, req.PhotoID)
+	if err != nil {
+		return nil, err
+	}
+	if photo == nil {
+		return nil, ErrPhotoNotFound
+	}
+	if photo.PerceptualHash == nil {
+		return nil, ErrPhotoHashUnavailable
+	}
+
+	image := &entity.BlockedImage{
+		PerceptualHash: *photo.PerceptualHash,
+		Reason:         req.Reason,
+		SourcePhotoID:  &photo.ID,
+		CreatedBy:      req.ModeratorID,
+	}
+	if err := image.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.blockedRepo.Create(ctx, image); err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+// UnblockImage 從禁用清單移除圖片
+func (s *PhotoFingerprintService) UnblockImage(ctx context.Context, moderatorID, imageID uint) error {
+	if err := s.checkModerator(ctx, moderatorID); err != nil {
+		return err
+	}
+
+	image, err := s.blockedRepo.GetByID(ctx, imageID)
+	if err != nil {
+		return err
+	}
+	if image == nil {
+		return ErrBlockedImageNotFound
+	}
+	return s.blockedRepo.Delete(ctx, imageID)
+}
+
+// confidence 依漢明距離換算信心度，距離 0 為 1，距離上限約為 0.5
+func (s *PhotoFingerprintService) confidence(distance int) float64 {
+	score := 1 - float64(distance)/float64(2*(s.maxDistance+1))
+	return math.Round(score*100) / 100
+}
+
+// checkModerator 檢查審核者權限，以資料庫中的角色為準
+func (s *PhotoFingerprintService) checkModerator(ctx context.Context, moderatorID uint) error {
+	moderator, err := s.userRepo.GetByID(ctx, moderatorID)
+	if err != nil || moderator == nil {
+		return errors.New("審核者不存在")
+	}
+	if !moderator.IsActive || !moderator.HasPermission(entity.PermissionContentReview) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// writeLog 寫入自動偵測的審核日誌，失敗只記錄日誌不影響上傳結果
+func (s *PhotoFingerprintService) writeLog(ctx context.Context, photo *entity.Photo, finding *DuplicateFinding) {
+	if s.moderationRepo == nil {
+		return
+	}
+
+	var notes string
+	if finding.MatchedPhotoID != nil {
+		notes = fmt.Sprintf("與用戶 %d 的照片 #%d 相似，漢明距離 %d",
+			*finding.MatchedUserID, *finding.MatchedPhotoID, finding.Distance)
+	} else {
+		notes = fmt.Sprintf("與禁用圖片 #%d 相似，漢明距離 %d", *finding.BlockedImageID, finding.Distance)
+	}
+
+	moderationLog := &repository.ModerationLog{
+		ContentType: photoContentType,
+		ContentID:   photo.ID,
+		UserID:      photo.UserID,
+		Action:      string(repository.ModerationActionFlagged),
+		Reason:      string(entity.ReportCategoryFakeProfile),
+		IsAutomatic: true,
+		Confidence:  &finding.Confidence,
+		Notes:       notes,
+	}
+
+	if err := s.moderationRepo.CreateModerationLog(ctx, moderationLog); err != nil {
+		log.Printf("寫入照片偵測日誌失敗 (照片 %d): %v", photo.ID, err)
+	}
+}
@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+)
+
+// 照片審核相關錯誤
+var (
+	ErrPhotoNotFound   = errors.New("照片不存在")
+	ErrPhotoNotPending = errors.New("照片不在待審核狀態")
+	ErrPhotoClaimed    = errors.New("照片已由其他審核員認領")
+)
+
+const (
+	// photoContentType 審核日誌中照片的內容類型
+	photoContentType = "photo"
+
+	// PhotoClaimTTL 審核員認領照片的有效時間，逾時未審核的照片回到佇列
+	PhotoClaimTTL = 15 * time.Minute
+)
+
+// PhotoModerationService 照片審核業務邏輯服務
+// 負責待審核照片佇列、審核員認領、審核結果與拒絕通知
+type PhotoModerationService struct {
+	photoRepo      repository.PhotoRepository
+	userRepo       repository.UserRepository
+	moderationRepo repository.ModerationRepository
+	files          *FileService      // 可選，為佇列中的照片簽發下載連結
+	notifier       WebSocketNotifier // 可選，照片被拒絕時即時通知用戶
+	mailer         Mailer            // 可選，照片被拒絕時寄信通知用戶
+}
+
+// NewPhotoModerationService 創建新的照片審核服務實例
+func NewPhotoModerationService(
+	photoRepo repository.PhotoRepository,
+	userRepo repository.UserRepository,
+	moderationRepo repository.ModerationRepository,
+) *PhotoModerationService {
+	return &PhotoModerationService{
+		photoRepo:      photoRepo,
+		userRepo:       userRepo,
+		moderationRepo: moderationRepo,
+	}
+}
+
+// SetFileService 設定檔案服務
+func (s *PhotoModerationService) SetFileService(files *FileService) {
+	s.files = files
+}
+
+// SetNotifier 設定即時通知器
+func (s *PhotoModerationService) SetNotifier(notifier WebSocketNotifier) {
+	s.notifier = notifier
+}
+
+// SetMailer 設定郵件發送器
+func (s *PhotoModerationService) SetMailer(mailer Mailer) {
+	s.mailer = mailer
+}
+
+// PhotoQueueRequest 照片審核佇列查詢請求
+type PhotoQueueRequest struct {
+	ModeratorID    uint              `json:"-"` // 由認證資訊設定
+	Type           *entity.PhotoType `json:"type,omitempty"`
+	UserID         *uint             `json:"user_id,omitempty"`
+	OnlyMine       bool              `json:"only_mine"`       // 只列出自己認領中的照片
+	OnlyFlagged    bool              `json:"only_flagged"`    // 只列出自動偵測標記的照片
+	IncludeClaimed bool              `json:"include_claimed"` // 包含其他審核員認領中的照片
+	Limit          int               `json:"limit"`
+	Offset         int               `json:"offset"`
+}
+
+// PhotoQueue 照片審核佇列
+type PhotoQueue struct {
+	Photos []*entity.Photo `json:"photos"`
+	Total  int64           `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
+
+// ReviewPhotoRequest 審核照片請求
+type ReviewPhotoRequest struct {
+	PhotoID    uint   `json:"-"`
+	ReviewerID uint   `json:"-"` // 由認證資訊設定，不接受客戶端指定
+	Approve    bool   `json:"approve"`
+	Notes      string `json:"notes"`
+}
+
+// GetQueue 獲取待審核照片佇列，自動偵測標記的照片依信心度優先，其餘依上傳時間由舊到新排列
+func (s *PhotoModerationService) GetQueue(ctx context.Context, req *PhotoQueueRequest) (*PhotoQueue, error) {
+	if req.Type != nil && !req.Type.IsValid() {
+		return nil, errors.New("type 必須是 profile、gallery 或 private")
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset := req.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	photos, total, err := s.photoRepo.GetReviewQueue(ctx, repository.PhotoQueueParams{
+		ModeratorID:    req.ModeratorID,
+		Type:           req.Type,
+		UserID:         req.UserID,
+		OnlyMine:       req.OnlyMine,
+		OnlyFlagged:    req.OnlyFlagged,
+		IncludeClaimed: req.IncludeClaimed,
+		StaleBefore:    time.Now().Add(-PhotoClaimTTL),
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	signPhotoURLs(ctx, s.files, req.ModeratorID, photos...)
+
+	return &PhotoQueue{
+		Photos: photos,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// ClaimPhoto 認領待審核照片，認領期間其他審核員無法審核
+// 同一審核員重複認領會延長認領時間
+func (s *PhotoModerationService) ClaimPhoto(ctx context.Context, photoID, moderatorID uint) (*entity.Photo, error) {
+	reviewer, err := s.getReviewer(ctx, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	photo, err := s.getPhoto(ctx, photoID)
+	if err != nil {
+		return nil, err
+	}
+	if photo.UserID == reviewer.ID {
+		return nil, ErrPermissionDenied
+	}
+	if !photo.IsPending() {
+		return nil, ErrPhotoNotPending
+	}
+
+	now := time.Now()
+	claimed, err := s.photoRepo.Claim(ctx, photo.ID, reviewer.ID, now, now.Add(-PhotoClaimTTL))
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrPhotoClaimed
+	}
+
+	photo.ClaimedBy = &reviewer.ID
+	photo.ClaimedAt = &now
+	signPhotoURLs(ctx, s.files, reviewer.ID, photo)
+	return photo, nil
+}
+
+// ReleasePhoto 釋出自己對照片的認領
+func (s *PhotoModerationService) ReleasePhoto(ctx context.Context, photoID, moderatorID uint) error {
+	reviewer, err := s.getReviewer(ctx, moderatorID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.getPhoto(ctx, photoID); err != nil {
+		return err
+	}
+	return s.photoRepo.ReleaseClaim(ctx, photoID, reviewer.ID)
+}
+
+// ReviewPhoto 審核照片，拒絕時必須提供原因並通知用戶
+// 其他審核員認領中的照片不可審核；已審核的照片可重新審核以處理申訴或後續檢舉
+func (s *PhotoModerationService) ReviewPhoto(ctx context.Context, req *ReviewPhotoRequest) (*entity.Photo, error) {
+	reviewer, err := s.getReviewer(ctx, req.ReviewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	photo, err := s.getPhoto(ctx, req.PhotoID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 不可審核自己的內容
+	if photo.UserID == reviewer.ID {
+		return nil, ErrPermissionDenied
+	}
+	if photo.IsClaimedByOther(reviewer.ID, time.Now().Add(-PhotoClaimTTL)) {
+		return nil, ErrPhotoClaimed
+	}
+
+	action := repository.ModerationActionApproved
+	if req.Approve {
+		err = photo.Approve(reviewer.ID, req.Notes)
+	} else {
+		action = repository.ModerationActionRejected
+		err = photo.Reject(reviewer.ID, req.Notes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.photoRepo.UpdateReview(ctx, photo); err != nil {
+		return nil, err
+	}
+
+	// 主要照片必須已通過審核：通過的照片可遞補空缺，被拒絕的主要照片由下一張照片遞補
+	if mainID, err := ensureMainPhoto(ctx, s.photoRepo, photo.UserID); err != nil {
+		log.Printf("更新主要照片失敗 (用戶 %d): %v", photo.UserID, err)
+	} else {
+		photo.IsMain = mainID == photo.ID
+	}
+
+	reason := ""
+	if photo.ReviewNotes != nil {
+		reason = *photo.ReviewNotes
+	}
+	s.writeLog(ctx, photo, reviewer.ID, string(action), reason)
+
+	if photo.IsRejected() {
+		s.notifyRejection(ctx, photo, reason)
+	}
+
+	signPhotoURLs(ctx, s.files, reviewer.ID, photo)
+	return photo, nil
+}
+
+// getReviewer 獲取審核者並檢查權限，以資料庫中的角色為準
+func (s *PhotoModerationService) getReviewer(ctx context.Context, reviewerID uint) (*entity.User, error) {
+	reviewer, err := s.userRepo.GetByID(ctx, reviewerID)
+	if err != nil || reviewer == nil {
+		return nil, errors.New("審核者不存在")
+	}
+	if !reviewer.IsActive || !reviewer.HasPermission(entity.PermissionContentReview) {
+		return nil, ErrPermissionDenied
+	}
+	return reviewer, nil
+}
+
+// getPhoto 獲取照片，不存在時返回 ErrPhotoNotFound
+func (s *PhotoModerationService) getPhoto(ctx context.Context, photoID uint) (*entity.Photo, error) {
+	photo, err := s.photoRepo.GetByID(ctx, photoID)
+	if err != nil {
+		return nil, err
+	}
+	if photo == nil {
+		return nil, ErrPhotoNotFound
+	}
+	return photo, nil
+}
+
+// writeLog 寫入照片審核日誌，失敗只記錄日誌不影響操作結果
+func (s *PhotoModerationService) writeLog(ctx context.Context, photo *entity.Photo, moderatorID uint, action, reason string) {
+	if s.moderationRepo == nil {
+		return
+	}
+
+	moderationLog := &repository.ModerationLog{
+		ContentType: photoContentType,
+		ContentID:   photo.ID,
+		UserID:      photo.UserID,
+		ModeratorID: &moderatorID,
+		Action:      action,
+		Reason:      reason,
+		Notes:       photo.FilePath,
+	}
+
+	if err := s.moderationRepo.CreateModerationLog(ctx, moderationLog); err != nil {
+		log.Printf("寫入照片審核日誌失敗 (照片 %d): %v", photo.ID, err)
+	}
+}
+
+// notifyRejection 通知用戶照片未通過審核，通知失敗只記錄日誌
+func (s *PhotoModerationService) notifyRejection(ctx context.Context, photo *entity.Photo, reason string) {
+	if s.notifier != nil {
+		notification := map[string]interface{}{
+			"type":     "photo_rejected",
+			"photo_id": photo.ID,
+			"reason":   reason,
+		}
+		if err := s.notifier.SendToUser(photo.UserID, notification); err != nil {
+			log.Printf("發送照片審核通知失敗 (用戶 %d): %v", photo.UserID, err)
+		}
+	}
+
+	if s.mailer == nil {
+		return
+	}
+	user, err := s.userRepo.GetByID(ctx, photo.UserID)
+	if err != nil || user == nil || user.Email == "" {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("您好，\n\n")
+	b.WriteString("您上傳的一張照片未通過審核，已不會顯示在您的個人檔案中。\n")
+	b.WriteString(fmt.Sprintf("原因：%s\n", reason))
+	b.WriteString("\n請確認照片符合社群規範後重新上傳。如有疑問，歡迎聯繫客服。\n")
+
+	msg := &EmailMessage{
+		To:      user.Email,
+		Subject: "您的照片未通過審核",
+		Body:    b.String(),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("發送照片審核通知信失敗 (用戶 %d): %v", photo.UserID, err)
+	}
+}
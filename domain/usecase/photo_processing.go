@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"golang_dev_docker/domain/entity"
+)
+
+// 照片上傳相關錯誤
+var (
+	ErrPhotoUploadUnavailable = errors.New("照片上傳功能未啟用")
+	ErrPhotoTooLarge          = errors.New("照片檔案過大")
+	ErrPhotoEmpty             = errors.New("照片檔案為空")
+	ErrUnsupportedImageType   = errors.New("不支援的圖片格式，僅接受 JPEG、PNG、GIF 與 WebP")
+	ErrInvalidImage           = errors.New("無法解析圖片內容")
+	ErrPhotoLimitReached      = errors.New("照片數量已達上限")
+)
+
+// 照片管理相關錯誤
+var (
+	ErrPhotoForbidden    = errors.New("無權限操作此照片")
+	ErrPhotoCannotBeMain = errors.New("只有已通過審核的個人檔案照片可以設為主要照片")
+	ErrInvalidPhotoOrder = errors.New("照片排序必須包含所有照片且不可重複")
+	ErrPhotoTypeChange   = errors.New("私人相簿照片不能與公開照片互換類型")
+)
+
+const (
+	// DefaultMaxPhotoSize 預設單張照片上傳大小上限
+	DefaultMaxPhotoSize int64 = 10 << 20 // 10MB
+
+	// DefaultMaxPhotos 預設每位用戶可保存的照片數量上限
+	DefaultMaxPhotos = 6
+)
+
+// PhotoLimits 每位用戶可保存的照片數量上限（含私人相簿照片）
+type PhotoLimits struct {
+	Default int            `yaml:"default"` // 未訂閱或方案未列出時的上限，不大於 0 時使用 DefaultMaxPhotos
+	Tiers   map[string]int `yaml:"tiers"`   // 訂閱方案 -> 上限
+}
+
+// Limit 獲取訂閱方案的照片數量上限
+func (l PhotoLimits) Limit(tier string) int {
+	if limit, ok := l.Tiers[tier]; ok && limit > 0 {
+		return limit
+	}
+	if l.Default > 0 {
+		return l.Default
+	}
+	return DefaultMaxPhotos
+}
+
+// SubscriptionTierResolver 查詢用戶目前的訂閱方案
+// 由訂閱系統實作；未設定時所有用戶套用預設上限
+type SubscriptionTierResolver interface {
+	SubscriptionTier(ctx context.Context, userID uint) (string, error)
+}
+
+// ProcessedImage 處理後的圖片
+// 原圖已重新編碼，不含 EXIF、GPS 等中繼資料
+type ProcessedImage struct {
+	Data           []byte
+	MimeType       string // 重新編碼後的格式
+	Extension      string // 含點的副檔名，例如 ".jpg"
+	Width          int
+	Height         int
+	PerceptualHash uint64                          // 套用方向後計算的 dHash
+	Thumbnails     map[entity.ThumbnailSize][]byte // 與原圖相同格式
+}
+
+// ImageProcessor 圖片處理介面
+// 由 infrastructure/imaging 實作，mimeType 為依檔案內容判斷的格式
+type ImageProcessor interface {
+	Process(data []byte, mimeType string) (*ProcessedImage, error)
+}
+
+// PhotoUpload 照片上傳內容
+type PhotoUpload struct {
+	Data    []byte
+	Type    entity.PhotoType // 未指定時為個人檔案照片
+	Caption string
+}
+
+// UpdatePhotoRequest 更新照片說明與類型請求，未提供的欄位不變更
+type UpdatePhotoRequest struct {
+	Caption *string           `json:"caption"`
+	Type    *entity.PhotoType `json:"type"`
+}
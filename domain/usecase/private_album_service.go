@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+)
+
+// 私人相簿相關錯誤
+var (
+	ErrPrivateAlbumNotMatched = errors.New("只能授權已配對的用戶查看私人相簿")
+)
+
+// privatePhotoKeyPrefix 私人相簿照片的儲存路徑前綴，格式為 private/<擁有者ID>/<檔名>
+// 下載連結依此前綴判斷是否需要檢查查看者權限
+const privatePhotoKeyPrefix = "private/"
+
+// PrivateAlbumService 私人相簿業務邏輯服務
+// 私人相簿照片只有擁有者授權的配對對象可以查看；取消配對或封鎖時撤銷雙方的授權，
+// 查看時也會確認雙方仍在配對中
+type PrivateAlbumService struct {
+	grantRepo repository.PrivateAlbumRepository
+	matchRepo repository.MatchRepository
+	userRepo  repository.UserRepository
+}
+
+// NewPrivateAlbumService 創建新的私人相簿服務實例
+func NewPrivateAlbumService(
+	grantRepo repository.PrivateAlbumRepository,
+	matchRepo repository.MatchRepository,
+	userRepo repository.UserRepository,
+) *PrivateAlbumService {
+	return &PrivateAlbumService{
+		grantRepo: grantRepo,
+		matchRepo: matchRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// GrantAccess 授權配對對象查看私人相簿，重複授權視為成功
+func (s *PrivateAlbumService) GrantAccess(ctx context.Context, ownerID, viewerID uint) (*entity.PrivateAlbumGrant, error) {
+	grant := &entity.PrivateAlbumGrant{
+		OwnerID:  ownerID,
+		ViewerID: viewerID,
+	}
+	if err := grant.Validate(); err != nil {
+		return nil, err
+	}
+
+	if !s.isMatched(ctx, ownerID, viewerID) {
+		return nil, ErrPrivateAlbumNotMatched
+	}
+
+	if err := s.grantRepo.Grant(ctx, grant); err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// RevokeAccess 撤銷用戶查看私人相簿的授權
+func (s *PrivateAlbumService) RevokeAccess(ctx context.Context, ownerID, viewerID uint) error {
+	return s.grantRepo.Revoke(ctx, ownerID, viewerID)
+}
+
+// RevokeBetween 撤銷兩位用戶之間雙向的授權，供取消配對與封鎖時使用
+func (s *PrivateAlbumService) RevokeBetween(ctx context.Context, user1ID, user2ID uint) error {
+	return s.grantRepo.RevokeBetween(ctx, user1ID, user2ID)
+}
+
+// ListGrants 獲取擁有者授權查看私人相簿的用戶
+func (s *PrivateAlbumService) ListGrants(ctx context.Context, ownerID uint) ([]*entity.PrivateAlbumGrant, error) {
+	return s.grantRepo.GetByOwnerID(ctx, ownerID)
+}
+
+// CanView 檢查用戶是否可以查看擁有者的私人相簿
+// 擁有者本人可以查看；其他用戶需有授權且雙方仍在配對中
+func (s *PrivateAlbumService) CanView(ctx context.Context, ownerID, viewerID uint) (bool, error) {
+	if viewerID == 0 {
+		return false, nil
+	}
+	if ownerID == viewerID {
+		return true, nil
+	}
+
+	granted, err := s.grantRepo.HasAccess(ctx, ownerID, viewerID)
+	if err != nil {
+		return false, err
+	}
+	if !granted {
+		return false, nil
+	}
+	return s.isMatched(ctx, ownerID, viewerID), nil
+}
+
+// IsRestricted 檢查檔案是否為私人相簿照片
+// 實作 FileAccessChecker
+func (s *PrivateAlbumService) IsRestricted(key string) bool {
+	return strings.HasPrefix(key, privatePhotoKeyPrefix)
+}
+
+// CanAccess 檢查用戶是否可以下載私人相簿照片，具內容審核權限的人員可下載以審核照片
+// 實作 FileAccessChecker
+func (s *PrivateAlbumService) CanAccess(ctx context.Context, key string, viewerID uint) (bool, error) {
+	ownerID, ok := privatePhotoOwner(key)
+	if !ok {
+		return false, nil
+	}
+
+	allowed, err := s.CanView(ctx, ownerID, viewerID)
+	if err != nil || allowed {
+		return allowed, err
+	}
+
+	viewer, err := s.userRepo.GetByID(ctx, viewerID)
+	if err != nil || viewer == nil {
+		return false, nil
+	}
+	return viewer.IsActive && viewer.HasPermission(entity.PermissionContentReview), nil
+}
+
+// isMatched 檢查兩位用戶目前是否配對中，查詢失敗時視為未配對
+func (s *PrivateAlbumService) isMatched(ctx context.Context, user1ID, user2ID uint) bool {
+	match, err := s.matchRepo.GetMatch(ctx, user1ID, user2ID)
+	return err == nil && match != nil && match.Status == entity.MatchStatusMatched
+}
+
+// privatePhotoKey 私人相簿照片的儲存路徑，不含副檔名
+func privatePhotoKey(ownerID uint, token string) string {
+	return fmt.Sprintf("%s%d/%s", privatePhotoKeyPrefix, ownerID, token)
+}
+
+// privatePhotoOwner 從私人相簿照片的儲存路徑取出擁有者 ID
+func privatePhotoOwner(key string) (uint, bool) {
+	rest := strings.TrimPrefix(key, privatePhotoKeyPrefix)
+	owner, _, found := strings.Cut(rest, "/")
+	if !found || rest == key {
+		return 0, false
+	}
+	ownerID, err := strconv.ParseUint(owner, 10, 32)
+	if err != nil || ownerID == 0 {
+		return 0, false
+	}
+	return uint(ownerID), true
+}
@@ -0,0 +1,312 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+)
+
+// 檔案問答相關錯誤
+var (
+	ErrPromptNotFound       = errors.New("題目不存在")
+	ErrPromptInactive       = errors.New("題目已停用")
+	ErrPromptAnswerNotFound = errors.New("回答不存在")
+)
+
+// promptAnswerContentType 審核日誌中檔案問答回答的內容類型
+const promptAnswerContentType = "prompt_answer"
+
+// PromptService 檔案問答業務邏輯服務
+// 負責題目目錄維護、用戶回答與回答的內容審核
+type PromptService struct {
+	promptRepo     repository.PromptRepository
+	answerRepo     repository.ProfilePromptRepository
+	userRepo       repository.UserRepository
+	moderationRepo repository.ModerationRepository
+}
+
+// NewPromptService 創建新的檔案問答服務實例
+func NewPromptService(
+	promptRepo repository.PromptRepository,
+	answerRepo repository.ProfilePromptRepository,
+	userRepo repository.UserRepository,
+	moderationRepo repository.ModerationRepository,
+) *PromptService {
+	return &PromptService{
+		promptRepo:     promptRepo,
+		answerRepo:     answerRepo,
+		userRepo:       userRepo,
+		moderationRepo: moderationRepo,
+	}
+}
+
+// LocalizedPrompt 指定語系的題目
+type LocalizedPrompt struct {
+	ID       uint   `json:"id"`
+	Key      string `json:"key"`
+	Category string `json:"category"`
+	Text     string `json:"text"`
+}
+
+// PromptRequest 新增或修改題目請求
+type PromptRequest struct {
+	Key          string            `json:"key"`
+	Category     string            `json:"category"`
+	IsActive     *bool             `json:"is_active,omitempty"` // 未傳入時新增為啟用、修改時不變更
+	DisplayOrder int               `json:"display_order"`
+	Translations map[string]string `json:"translations"` // 語系 -> 題目文字
+}
+
+// ProfilePromptAnswer 用戶回答
+type ProfilePromptAnswer struct {
+	PromptID uint   `json:"prompt_id"`
+	Answer   string `json:"answer"`
+}
+
+// ReviewPromptAnswerRequest 審核回答請求
+type ReviewPromptAnswerRequest struct {
+	AnswerID   uint   `json:"-"`
+	ReviewerID uint   `json:"-"` // 由認證資訊設定，不接受客戶端指定
+	Approve    bool   `json:"approve"`
+	Notes      string `json:"notes"`
+}
+
+// ListPrompts 獲取啟用中的題目，文字依指定語系顯示
+func (s *PromptService) ListPrompts(ctx context.Context, locale string) ([]*LocalizedPrompt, error) {
+	prompts, err := s.promptRepo.GetAll(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	localized := make([]*LocalizedPrompt, 0, len(prompts))
+	for _, prompt := range prompts {
+		localized = append(localized, &LocalizedPrompt{
+			ID:       prompt.ID,
+			Key:      prompt.Key,
+			Category: prompt.Category,
+			Text:     prompt.Text(locale),
+		})
+	}
+	return localized, nil
+}
+
+// ListAllPrompts 獲取所有題目與語系文字，包含已停用的題目
+func (s *PromptService) ListAllPrompts(ctx context.Context) ([]*entity.Prompt, error) {
+	return s.promptRepo.GetAll(ctx, false)
+}
+
+// CreatePrompt 新增題目
+func (s *PromptService) CreatePrompt(ctx context.Context, req *PromptRequest) (*entity.Prompt, error) {
+	prompt := &entity.Prompt{IsActive: true}
+	applyPromptRequest(prompt, req)
+
+	if err := prompt.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.promptRepo.Create(ctx, prompt); err != nil {
+		return nil, err
+	}
+	return prompt, nil
+}
+
+// UpdatePrompt 修改題目，語系文字整份取代
+// 停用題目不影響既有回答，只是不再提供選擇
+func (s *PromptService) UpdatePrompt(ctx context.Context, id uint, req *PromptRequest) (*entity.Prompt, error) {
+	prompt, err := s.promptRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if prompt == nil {
+		return nil, ErrPromptNotFound
+	}
+
+	applyPromptRequest(prompt, req)
+	if err := prompt.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.promptRepo.Update(ctx, prompt); err != nil {
+		return nil, err
+	}
+	return prompt, nil
+}
+
+// applyPromptRequest 將請求內容寫入題目
+func applyPromptRequest(prompt *entity.Prompt, req *PromptRequest) {
+	prompt.Key = strings.TrimSpace(req.Key)
+	prompt.Category = strings.TrimSpace(req.Category)
+	prompt.DisplayOrder = req.DisplayOrder
+	if req.IsActive != nil {
+		prompt.IsActive = *req.IsActive
+	}
+
+	prompt.Translations = make([]entity.PromptTranslation, 0, len(req.Translations))
+	for locale, text := range req.Translations {
+		prompt.Translations = append(prompt.Translations, entity.PromptTranslation{
+			Locale: entity.NormalizeLocale(locale),
+			Text:   strings.TrimSpace(text),
+		})
+	}
+}
+
+// GetProfilePrompts 獲取用戶的回答
+func (s *PromptService) GetProfilePrompts(ctx context.Context, userID uint) ([]*entity.ProfilePrompt, error) {
+	return s.answerRepo.GetByUserID(ctx, userID)
+}
+
+// UpdateProfilePrompts 以新的回答取代用戶所有回答，顯示順序依傳入順序
+// 回答內容未變更的題目保留原審核結果，新增或修改的回答需重新審核
+func (s *PromptService) UpdateProfilePrompts(ctx context.Context, userID uint, answers []ProfilePromptAnswer) ([]*entity.ProfilePrompt, error) {
+	if len(answers) > entity.MaxProfilePrompts {
+		return nil, fmt.Errorf("最多只能回答 %d 個題目", entity.MaxProfilePrompts)
+	}
+
+	existing, err := s.answerRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	existingByPrompt := make(map[uint]*entity.ProfilePrompt, len(existing))
+	for _, prompt := range existing {
+		existingByPrompt[prompt.PromptID] = prompt
+	}
+
+	prompts := make([]*entity.ProfilePrompt, 0, len(answers))
+	var changed []*entity.ProfilePrompt
+	for i, answer := range answers {
+		text := strings.TrimSpace(answer.Answer)
+
+		current, answered := existingByPrompt[answer.PromptID]
+		if !answered {
+			// 只有新選的題目需要是啟用中的題目，已停用題目的既有回答可以保留
+			if err := s.ensurePromptActive(ctx, answer.PromptID); err != nil {
+				return nil, err
+			}
+			current = &entity.ProfilePrompt{UserID: userID, PromptID: answer.PromptID}
+		}
+
+		if !answered || current.Answer != text {
+			current.Answer = text
+			current.Status = entity.PromptAnswerStatusPending
+			current.ReviewerID = nil
+			current.ReviewNotes = nil
+			current.ReviewedAt = nil
+			changed = append(changed, current)
+		}
+		current.DisplayOrder = i
+		prompts = append(prompts, current)
+	}
+
+	if err := entity.ValidateProfilePrompts(prompts); err != nil {
+		return nil, err
+	}
+
+	if err := s.answerRepo.ReplaceForUser(ctx, userID, prompts); err != nil {
+		return nil, fmt.Errorf("更新檔案問答失敗: %w", err)
+	}
+
+	// 新增或修改的回答送入內容審核
+	for _, prompt := range changed {
+		s.writeLog(ctx, prompt, nil, string(repository.ModerationActionFlagged), "用戶提交檔案問答回答", prompt.Answer)
+	}
+
+	return s.answerRepo.GetByUserID(ctx, userID)
+}
+
+// ensurePromptActive 檢查題目存在且啟用中
+func (s *PromptService) ensurePromptActive(ctx context.Context, promptID uint) error {
+	prompt, err := s.promptRepo.GetByID(ctx, promptID)
+	if err != nil {
+		return err
+	}
+	if prompt == nil {
+		return ErrPromptNotFound
+	}
+	if !prompt.IsActive {
+		return ErrPromptInactive
+	}
+	return nil
+}
+
+// GetPendingAnswers 獲取待審核的回答
+func (s *PromptService) GetPendingAnswers(ctx context.Context, limit int) ([]*entity.ProfilePrompt, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return s.answerRepo.GetPending(ctx, limit)
+}
+
+// ReviewAnswer 審核回答，拒絕時必須提供原因
+func (s *PromptService) ReviewAnswer(ctx context.Context, req *ReviewPromptAnswerRequest) (*entity.ProfilePrompt, error) {
+	// 檢查審核者權限，以資料庫中的角色為準
+	reviewer, err := s.userRepo.GetByID(ctx, req.ReviewerID)
+	if err != nil {
+		return nil, errors.New("審核者不存在")
+	}
+	if !reviewer.IsActive || !reviewer.HasPermission(entity.PermissionContentReview) {
+		return nil, ErrPermissionDenied
+	}
+
+	answer, err := s.answerRepo.GetByID(ctx, req.AnswerID)
+	if err != nil {
+		return nil, err
+	}
+	if answer == nil {
+		return nil, ErrPromptAnswerNotFound
+	}
+
+	// 不可審核自己的內容
+	if answer.UserID == reviewer.ID {
+		return nil, ErrPermissionDenied
+	}
+
+	action := repository.ModerationActionApproved
+	if req.Approve {
+		err = answer.Approve(reviewer.ID, req.Notes)
+	} else {
+		action = repository.ModerationActionRejected
+		err = answer.Reject(reviewer.ID, req.Notes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.answerRepo.UpdateReview(ctx, answer); err != nil {
+		return nil, err
+	}
+
+	reason := ""
+	if answer.ReviewNotes != nil {
+		reason = *answer.ReviewNotes
+	}
+	s.writeLog(ctx, answer, &reviewer.ID, string(action), reason, answer.Answer)
+
+	return answer, nil
+}
+
+// writeLog 寫入回答審核日誌，失敗只記錄日誌不影響操作結果
+func (s *PromptService) writeLog(ctx context.Context, answer *entity.ProfilePrompt, moderatorID *uint, action, reason, notes string) {
+	if s.moderationRepo == nil {
+		return
+	}
+
+	moderationLog := &repository.ModerationLog{
+		ContentType: promptAnswerContentType,
+		ContentID:   answer.ID,
+		UserID:      answer.UserID,
+		ModeratorID: moderatorID,
+		Action:      action,
+		Reason:      reason,
+		IsAutomatic: moderatorID == nil,
+		Notes:       notes,
+	}
+
+	if err := s.moderationRepo.CreateModerationLog(ctx, moderationLog); err != nil {
+		log.Printf("寫入檔案問答審核日誌失敗 (回答 %d): %v", answer.ID, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 
 	"golang_dev_docker/domain/entity"
@@ -18,6 +19,8 @@ type ReportService struct {
 	moderationRepo repository.ModerationRepository
 	userRepo       repository.UserRepository
 	matchRepo      repository.MatchRepository
+	accounts       *AccountModerationService
+	privateAlbums  *PrivateAlbumService // 可選，封鎖時撤銷雙方的私人相簿授權
 }
 
 // NewReportService 創建新的檢舉服務實例
@@ -27,6 +30,7 @@ func NewReportService(
 	moderationRepo repository.ModerationRepository,
 	userRepo repository.UserRepository,
 	matchRepo repository.MatchRepository,
+	accounts *AccountModerationService,
 ) *ReportService {
 	return &ReportService{
 		reportRepo:     reportRepo,
@@ -34,9 +38,15 @@ func NewReportService(
 		moderationRepo: moderationRepo,
 		userRepo:       userRepo,
 		matchRepo:      matchRepo,
+		accounts:       accounts,
 	}
 }
 
+// SetPrivateAlbums 設定私人相簿服務
+func (s *ReportService) SetPrivateAlbums(privateAlbums *PrivateAlbumService) {
+	s.privateAlbums = privateAlbums
+}
+
 // SubmitReportRequest 提交檢舉請求
 type SubmitReportRequest struct {
 	ReporterID     uint                  `json:"reporter_id" validate:"required"`
@@ -63,7 +73,7 @@ type ReportResponse struct {
 // ReviewReportRequest 審核檢舉請求
 type ReviewReportRequest struct {
 	ReportID    uint                `json:"report_id" validate:"required"`
-	ReviewerID  uint                `json:"reviewer_id" validate:"required"`
+	ReviewerID  uint                `json:"-"` // 由認證資訊設定，不接受客戶端指定
 	Status      entity.ReportStatus `json:"status" validate:"required"`
 	ReviewNotes string              `json:"review_notes"`
 	Action      *ModerationAction   `json:"action,omitempty"`
@@ -237,6 +247,13 @@ func (s *ReportService) BlockUser(ctx context.Context, req *BlockUserRequest) er
 		s.matchRepo.UpdateMatchStatus(ctx, match.ID, entity.MatchStatusUnmatched)
 	}
 
+	// 不論是否配對中都撤銷雙方的私人相簿授權
+	if s.privateAlbums != nil {
+		if err := s.privateAlbums.RevokeBetween(ctx, req.UserID, req.BlockedUserID); err != nil {
+			log.Printf("撤銷私人相簿授權失敗 (用戶 %d, %d): %v", req.UserID, req.BlockedUserID, err)
+		}
+	}
+
 	return nil
 }
 
@@ -297,7 +314,7 @@ func (s *ReportService) ReviewReport(ctx context.Context, req *ReviewReportReque
 		return fmt.Errorf("審核請求驗證失敗: %w", err)
 	}
 
-	// 檢查審核者權限（簡化處理，實際應用中需要更複雜的權限檢查）
+	// 檢查審核者權限，以資料庫中的角色為準
 	reviewer, err := s.userRepo.GetByID(ctx, req.ReviewerID)
 	if err != nil {
 		return errors.New("審核者不存在")
@@ -307,6 +324,10 @@ func (s *ReportService) ReviewReport(ctx context.Context, req *ReviewReportReque
 		return errors.New("審核者帳戶未啟用")
 	}
 
+	if !reviewer.HasPermission(entity.PermissionReportReview) {
+		return ErrPermissionDenied
+	}
+
 	// 獲取檢舉記錄
 	report, err := s.reportRepo.GetByID(ctx, req.ReportID)
 	if err != nil {
@@ -318,19 +339,19 @@ func (s *ReportService) ReviewReport(ctx context.Context, req *ReviewReportReque
 		return errors.New("該檢舉已被處理，無法再次審核")
 	}
 
-	// 更新檢舉狀態
-	if err := s.reportRepo.SetReportStatus(ctx, req.ReportID, req.Status, &req.ReviewerID, req.ReviewNotes); err != nil {
-		return fmt.Errorf("更新檢舉狀態失敗: %w", err)
-	}
-
-	// 如果審核通過且有指定行動，執行相應的行動
+	// 如果審核通過且有指定行動，先執行相應的行動
+	// 行動失敗（例如權限不足）時不更新檢舉狀態，以便重新審核
 	if req.Status == entity.ReportStatusApproved && req.Action != nil {
 		if err := s.executeModerationAction(ctx, report.ReportedID, req.Action, req.ReviewerID); err != nil {
-			// 記錄錯誤但不回滾審核結果
-			// 實際應用中可能需要更複雜的錯誤處理
+			return fmt.Errorf("執行審核行動失敗: %w", err)
 		}
 	}
 
+	// 更新檢舉狀態
+	if err := s.reportRepo.SetReportStatus(ctx, req.ReportID, req.Status, &req.ReviewerID, req.ReviewNotes); err != nil {
+		return fmt.Errorf("更新檢舉狀態失敗: %w", err)
+	}
+
 	// 創建審核日誌
 	moderationLog := &repository.ModerationLog{
 		ContentType: "report",
@@ -478,15 +499,15 @@ func (s *ReportService) executeModerationAction(ctx context.Context, userID uint
 		return s.sendWarningToUser(ctx, userID, action.Description)
 
 	case "suspend":
-		// 暫停用戶帳戶
+		// 暫停用戶帳戶，期限到後由排程自動恢復
 		if action.Duration != nil {
-			return s.suspendUser(ctx, userID, *action.Duration, action.Description)
+			return s.accounts.SuspendUser(ctx, userID, &moderatorID, *action.Duration, action.Description)
 		}
 		return errors.New("暫停行動必須指定時間")
 
 	case "ban":
 		// 永久封禁用戶
-		return s.banUser(ctx, userID, action.Description)
+		return s.accounts.BanUser(ctx, userID, &moderatorID, action.Description)
 
 	default:
 		return fmt.Errorf("不支援的審核行動類型: %s", action.Type)
@@ -509,43 +530,3 @@ func (s *ReportService) sendWarningToUser(ctx context.Context, userID uint, mess
 
 	return s.moderationRepo.CreateModerationLog(ctx, moderationLog)
 }
-
-// suspendUser 暫停用戶
-func (s *ReportService) suspendUser(ctx context.Context, userID uint, days int, reason string) error {
-	// 簡化實現：設置用戶為非啟用狀態
-	// 實際應用中需要更複雜的暫停機制
-
-	if err := s.userRepo.SetActive(ctx, userID, false); err != nil {
-		return err
-	}
-
-	moderationLog := &repository.ModerationLog{
-		ContentType: "user",
-		ContentID:   userID,
-		UserID:      userID,
-		Action:      "suspended",
-		IsAutomatic: false,
-		Notes:       fmt.Sprintf("暫停 %d 天: %s", days, reason),
-	}
-
-	return s.moderationRepo.CreateModerationLog(ctx, moderationLog)
-}
-
-// banUser 封禁用戶
-func (s *ReportService) banUser(ctx context.Context, userID uint, reason string) error {
-	// 永久停用用戶帳戶
-	if err := s.userRepo.SetActive(ctx, userID, false); err != nil {
-		return err
-	}
-
-	moderationLog := &repository.ModerationLog{
-		ContentType: "user",
-		ContentID:   userID,
-		UserID:      userID,
-		Action:      "banned",
-		IsAutomatic: false,
-		Notes:       fmt.Sprintf("永久封禁: %s", reason),
-	}
-
-	return s.moderationRepo.CreateModerationLog(ctx, moderationLog)
-}
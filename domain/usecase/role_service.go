@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+)
+
+// 角色權限相關錯誤
+var (
+	ErrPermissionDenied    = errors.New("權限不足")
+	ErrInvalidRole         = errors.New("無效的角色")
+	ErrCannotChangeOwnRole = errors.New("不能變更自己的角色")
+	ErrBootstrapNotOwned   = errors.New("初始管理員信箱已被其他帳號使用，且該帳號未驗證信箱或密碼與設定不符")
+)
+
+// RoleService 角色權限業務邏輯服務
+// 負責角色指派與初始管理員建立
+type RoleService struct {
+	userRepo  repository.UserRepository
+	sessions  SessionRevoker
+	passwords *PasswordValidator
+}
+
+// NewRoleService 創建新的角色權限服務實例
+func NewRoleService(userRepo repository.UserRepository, sessions SessionRevoker) *RoleService {
+	return &RoleService{
+		userRepo:  userRepo,
+		sessions:  sessions,
+		passwords: NewDefaultPasswordValidator(),
+	}
+}
+
+// SetPasswordValidator 設定建立初始管理員時使用的密碼規則
+func (s *RoleService) SetPasswordValidator(validator *PasswordValidator) {
+	s.passwords = validator
+}
+
+// AssignRole 指派用戶角色
+// 操作者只能管理角色等級低於自己的用戶，且只能指派低於自己等級的角色
+func (s *RoleService) AssignRole(ctx context.Context, actorID, targetUserID uint, role entity.UserRole) error {
+	if !role.IsValid() {
+		return ErrInvalidRole
+	}
+
+	if actorID == targetUserID {
+		return ErrCannotChangeOwnRole
+	}
+
+	actor, err := s.userRepo.GetByID(ctx, actorID)
+	if err != nil {
+		return fmt.Errorf("操作者不存在: %w", err)
+	}
+
+	if !actor.IsActive || !actor.HasPermission(entity.PermissionRoleAssign) {
+		return ErrPermissionDenied
+	}
+
+	target, err := s.userRepo.GetByID(ctx, targetUserID)
+	if err != nil {
+		return fmt.Errorf("用戶不存在: %w", err)
+	}
+
+	actorLevel := actor.GetRole().Level()
+	if target.GetRole().Level() >= actorLevel || role.Level() >= actorLevel {
+		return ErrPermissionDenied
+	}
+
+	if target.GetRole() == role {
+		return nil
+	}
+
+	if err := s.userRepo.UpdateRole(ctx, target.ID, role); err != nil {
+		return fmt.Errorf("更新用戶角色失敗: %w", err)
+	}
+
+	// 角色記錄在存取權杖中，撤銷會話讓用戶以新角色重新登入
+	if err := s.sessions.RevokeAllSessions(ctx, target.ID); err != nil {
+		return err
+	}
+
+	log.Printf("用戶 %d 將用戶 %d 的角色由 %s 變更為 %s", actor.ID, target.ID, target.GetRole(), role)
+	return nil
+}
+
+// BootstrapAdmin 建立初始超級管理員
+// 只在系統中尚無任何管理員時生效；帳號已存在時必須已驗證信箱且密碼與設定相符才提升權限，
+// 避免他人搶先以該信箱註冊後取得管理員權限；帳號不存在時以指定密碼建立新帳號
+func (s *RoleService) BootstrapAdmin(ctx context.Context, email, password string) (bool, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return false, nil
+	}
+
+	count, err := s.userRepo.CountByRoles(ctx, entity.RoleAdmin, entity.RoleSuperAdmin)
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	if user, err := s.userRepo.GetByEmail(ctx, email); err == nil && user != nil {
+		if !user.IsActive || !user.EmailVerified || password == "" ||
+			bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+			return false, ErrBootstrapNotOwned
+		}
+		if err := s.userRepo.UpdateRole(ctx, user.ID, entity.RoleSuperAdmin); err != nil {
+			return false, fmt.Errorf("提升管理員權限失敗: %w", err)
+		}
+		if err := s.sessions.RevokeAllSessions(ctx, user.ID); err != nil {
+			return false, err
+		}
+		log.Printf("已將用戶 %d 提升為超級管理員", user.ID)
+		return true, nil
+	}
+
+	if err := s.passwords.Validate(password, email); err != nil {
+		return false, fmt.Errorf("初始管理員密碼無效: %w", err)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return false, fmt.Errorf("密碼加密失敗: %w", err)
+	}
+
+	// 管理員帳號不建立用戶檔案，不會出現在配對中；出生日期僅為滿足資料完整性
+	now := time.Now()
+	admin := &entity.User{
+		Email:           email,
+		PasswordHash:    string(hashedPassword),
+		BirthDate:       time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		IsVerified:      true,
+		IsActive:        true,
+		EmailVerified:   true,
+		EmailVerifiedAt: &now,
+		Role:            entity.RoleSuperAdmin,
+	}
+	if err := s.userRepo.Create(ctx, admin); err != nil {
+		return false, fmt.Errorf("建立初始管理員失敗: %w", err)
+	}
+
+	log.Printf("已建立初始超級管理員 %s (用戶 %d)", admin.Email, admin.ID)
+	return true, nil
+}
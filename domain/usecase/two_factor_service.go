@@ -0,0 +1,349 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+)
+
+// 兩步驟驗證相關錯誤
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("兩步驟驗證已啟用")
+	ErrTwoFactorNotEnabled     = errors.New("尚未啟用兩步驟驗證")
+	ErrTwoFactorNotStarted     = errors.New("請先開始綁定驗證器")
+	ErrTwoFactorRequired       = errors.New("此帳號角色必須啟用兩步驟驗證")
+	ErrInvalidTwoFactorCode    = errors.New("驗證碼錯誤或已使用")
+)
+
+const recoveryCodeCount = 10
+
+// TOTPEnrollment 綁定驗證器所需的資訊
+type TOTPEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth URI，由用戶端轉為 QR Code
+}
+
+// TwoFactorStatus 用戶的兩步驟驗證狀態
+type TwoFactorStatus struct {
+	Enabled                bool  `json:"enabled"`
+	Required               bool  `json:"required"`
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorService 兩步驟驗證業務邏輯服務
+// 負責 TOTP 綁定、驗證碼與恢復碼驗證，以及管理人員的強制啟用政策
+type TwoFactorService struct {
+	userRepo        repository.UserRepository
+	twoFactorRepo   repository.TwoFactorRepository
+	issuer          string // 顯示於驗證器 App 的服務名稱
+	requireForStaff bool   // 審核員以上角色是否必須啟用
+}
+
+// NewTwoFactorService 創建新的兩步驟驗證服務實例
+func NewTwoFactorService(
+	userRepo repository.UserRepository,
+	twoFactorRepo repository.TwoFactorRepository,
+	issuer string,
+) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+		issuer:        issuer,
+	}
+}
+
+// SetRequireForStaff 設定審核員與管理員是否必須啟用兩步驟驗證
+func (s *TwoFactorService) SetRequireForStaff(required bool) {
+	s.requireForStaff = required
+}
+
+// IsRequired 檢查指定角色是否必須啟用兩步驟驗證
+func (s *TwoFactorService) IsRequired(role entity.UserRole) bool {
+	return s.requireForStaff && role.AtLeast(entity.RoleModerator)
+}
+
+// GetStatus 獲取用戶的兩步驟驗證狀態
+func (s *TwoFactorService) GetStatus(ctx context.Context, userID uint) (*TwoFactorStatus, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("用戶不存在: %w", err)
+	}
+
+	credential, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &TwoFactorStatus{Required: s.IsRequired(user.GetRole())}
+	if credential != nil && credential.Enabled {
+		status.Enabled = true
+		status.RecoveryCodesRemaining, err = s.twoFactorRepo.CountUnusedRecoveryCodes(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// BeginEnrollment 產生新的 TOTP 金鑰，需以 ConfirmEnrollment 確認後才會啟用
+// 重複呼叫會以新金鑰取代尚未確認的金鑰
+func (s *TwoFactorService) BeginEnrollment(ctx context.Context, userID uint) (*TOTPEnrollment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("用戶不存在: %w", err)
+	}
+
+	credential, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if credential != nil && credential.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("生成 TOTP 金鑰失敗: %w", err)
+	}
+
+	if credential == nil {
+		credential = &entity.TwoFactorCredential{UserID: userID}
+	}
+	credential.Secret = secret
+	credential.Enabled = false
+	credential.LastUsedStep = 0
+	credential.EnabledAt = nil
+	if err := s.twoFactorRepo.Save(ctx, credential); err != nil {
+		return nil, err
+	}
+
+	return &TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totpProvisioningURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment 以驗證器產生的驗證碼確認綁定並啟用兩步驟驗證
+// 返回的恢復碼只會顯示這一次
+func (s *TwoFactorService) ConfirmEnrollment(ctx context.Context, userID uint, code string) ([]string, error) {
+	credential, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if credential == nil {
+		return nil, ErrTwoFactorNotStarted
+	}
+	if credential.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	return s.enable(ctx, credential, code)
+}
+
+// Disable 停用兩步驟驗證，需提供有效的驗證碼或恢復碼
+func (s *TwoFactorService) Disable(ctx context.Context, userID uint, code string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("用戶不存在: %w", err)
+	}
+	if s.IsRequired(user.GetRole()) {
+		return ErrTwoFactorRequired
+	}
+
+	credential, err := s.enabledCredential(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.verifyCode(ctx, credential, code); err != nil {
+		return err
+	}
+
+	return s.twoFactorRepo.Delete(ctx, userID)
+}
+
+// RegenerateRecoveryCodes 重新產生恢復碼，舊的恢復碼全部失效
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error) {
+	credential, err := s.enabledCredential(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyCode(ctx, credential, code); err != nil {
+		return nil, err
+	}
+
+	return s.issueRecoveryCodes(ctx, userID)
+}
+
+// RequiresChallenge 判斷密碼驗證通過後是否需要第二步驗證
+// 已啟用的用戶需輸入驗證碼；必須啟用但尚未綁定的用戶需先完成綁定
+func (s *TwoFactorService) RequiresChallenge(ctx context.Context, userID uint, role entity.UserRole) (challenge bool, enrollmentRequired bool, err error) {
+	credential, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return false, false, err
+	}
+
+	if credential != nil && credential.Enabled {
+		return true, false, nil
+	}
+	if s.IsRequired(role) {
+		return true, true, nil
+	}
+	return false, false, nil
+}
+
+// VerifyLogin 驗證登入第二步的驗證碼或恢復碼，返回通過驗證的用戶
+// 必須啟用但尚未綁定的用戶，以驗證碼完成綁定並返回新產生的恢復碼
+func (s *TwoFactorService) VerifyLogin(ctx context.Context, userID uint, code string) (*entity.User, []string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("用戶不存在: %w", err)
+	}
+
+	// 第一步與第二步之間帳號可能已被停用或停權
+	if !user.IsActive {
+		return nil, nil, errors.New("帳戶已被停用")
+	}
+	if err := checkAccountStatus(user); err != nil {
+		return nil, nil, err
+	}
+
+	credential, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if credential != nil && credential.Enabled {
+		if err := s.verifyCode(ctx, credential, code); err != nil {
+			return nil, nil, err
+		}
+		return user, nil, nil
+	}
+
+	if !s.IsRequired(user.GetRole()) {
+		return nil, nil, ErrTwoFactorNotEnabled
+	}
+	if credential == nil {
+		return nil, nil, ErrTwoFactorNotStarted
+	}
+
+	recoveryCodes, err := s.enable(ctx, credential, code)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, recoveryCodes, nil
+}
+
+// 私有輔助方法
+
+// enabledCredential 獲取已啟用的 TOTP 設定
+func (s *TwoFactorService) enabledCredential(ctx context.Context, userID uint) (*entity.TwoFactorCredential, error) {
+	credential, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if credential == nil || !credential.Enabled {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	return credential, nil
+}
+
+// enable 驗證 TOTP 驗證碼後啟用設定並產生恢復碼
+// 綁定時只接受驗證碼，確認用戶的驗證器已正確設定
+func (s *TwoFactorService) enable(ctx context.Context, credential *entity.TwoFactorCredential, code string) ([]string, error) {
+	if err := s.verifyTOTP(ctx, credential, code); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	credential.Enabled = true
+	credential.EnabledAt = &now
+	if err := s.twoFactorRepo.Save(ctx, credential); err != nil {
+		return nil, err
+	}
+
+	return s.issueRecoveryCodes(ctx, credential.UserID)
+}
+
+// verifyCode 驗證 6 位數 TOTP 驗證碼，或消耗一組恢復碼
+func (s *TwoFactorService) verifyCode(ctx context.Context, credential *entity.TwoFactorCredential, code string) error {
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		return s.verifyTOTP(ctx, credential, code)
+	}
+
+	used, err := s.twoFactorRepo.UseRecoveryCode(ctx, credential.UserID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// verifyTOTP 驗證 TOTP 驗證碼，同一時間步的驗證碼只能使用一次
+func (s *TwoFactorService) verifyTOTP(ctx context.Context, credential *entity.TwoFactorCredential, code string) error {
+	step, ok := matchTOTPStep(credential.Secret, strings.TrimSpace(code), time.Now())
+	if !ok || step <= credential.LastUsedStep {
+		return ErrInvalidTwoFactorCode
+	}
+
+	marked, err := s.twoFactorRepo.MarkStepUsed(ctx, credential.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return ErrInvalidTwoFactorCode
+	}
+
+	credential.LastUsedStep = step
+	return nil
+}
+
+// issueRecoveryCodes 產生新的恢復碼，只儲存雜湊並返回明文
+func (s *TwoFactorService) issueRecoveryCodes(ctx context.Context, userID uint) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("生成恢復碼失敗: %w", err)
+		}
+		raw := hex.EncodeToString(buf)
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashToken(raw))
+	}
+
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// isTOTPCode 檢查輸入是否為 6 位數字驗證碼
+func isTOTPCode(code string) bool {
+	if len(code) != totpDigits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeRecoveryCode 移除恢復碼中的分隔符與空白並轉為小寫
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"net/http"
 	"strings"
 	"time"
 
@@ -13,6 +15,18 @@ import (
 	"golang_dev_docker/domain/repository"
 )
 
+// ErrRegistrationCompleted 帳號已完成註冊，不需補填資料
+var ErrRegistrationCompleted = errors.New("帳號已完成註冊")
+
+// ErrAttributePreferencesUnavailable 未設定屬性配對條件儲存庫
+var ErrAttributePreferencesUnavailable = errors.New("屬性配對條件功能未啟用")
+
+// MatchingCacheInvalidator 配對快取清除介面
+// 由 Redis MatchingCacheService 實作，檔案或配對條件變更後清除用戶的潛在配對快取
+type MatchingCacheInvalidator interface {
+	InvalidateUserCache(userID uint) error
+}
+
 // UserService 用戶業務邏輯服務
 // 負責用戶註冊、認證、個人檔案管理等核心業務邏輯
 type UserService struct {
@@ -21,6 +35,17 @@ type UserService struct {
 	photoRepo           repository.PhotoRepository
 	interestRepo        repository.InterestRepository
 	ageVerificationRepo repository.AgeVerificationRepository
+	passwords           *PasswordValidator
+	attributeRepo       repository.AttributePreferenceRepository // 可選
+	promptRepo          repository.ProfilePromptRepository       // 可選
+	imageProcessor      ImageProcessor                           // 可選，未設定時無法上傳照片
+	files               *FileService                             // 可選，未設定時無法上傳照片
+	fingerprints        *PhotoFingerprintService                 // 可選，偵測重複使用他人照片
+	privateAlbums       *PrivateAlbumService                     // 可選，未設定時私人相簿照片只有本人可見
+	tiers               SubscriptionTierResolver                 // 可選，未設定時所有用戶套用預設照片數量上限
+	matchingCache       MatchingCacheInvalidator                 // 可選，未設定時不清除配對快取
+	maxPhotoSize        int64
+	photoLimits         PhotoLimits
 }
 
 // NewUserService 創建新的用戶服務實例
@@ -37,9 +62,74 @@ func NewUserService(
 		photoRepo:           photoRepo,
 		interestRepo:        interestRepo,
 		ageVerificationRepo: ageVerificationRepo,
+		passwords:           NewDefaultPasswordValidator(),
+		maxPhotoSize:        DefaultMaxPhotoSize,
+	}
+}
+
+// SetPasswordValidator 設定註冊時使用的密碼規則
+func (s *UserService) SetPasswordValidator(validator *PasswordValidator) {
+	s.passwords = validator
+}
+
+// SetAttributePreferenceRepository 設定屬性配對條件儲存庫
+func (s *UserService) SetAttributePreferenceRepository(repo repository.AttributePreferenceRepository) {
+	s.attributeRepo = repo
+}
+
+// SetProfilePromptRepository 設定檔案問答儲存庫，設定後檔案回應會包含問答
+func (s *UserService) SetProfilePromptRepository(repo repository.ProfilePromptRepository) {
+	s.promptRepo = repo
+}
+
+// SetPhotoProcessing 設定照片處理器與檔案服務，maxSize 不大於 0 時使用預設上限
+func (s *UserService) SetPhotoProcessing(processor ImageProcessor, files *FileService, maxSize int64) {
+	s.imageProcessor = processor
+	s.files = files
+	if maxSize > 0 {
+		s.maxPhotoSize = maxSize
 	}
 }
 
+// SetPhotoFingerprints 設定照片指紋服務，上傳的照片會與其他用戶的照片及禁用清單比對
+func (s *UserService) SetPhotoFingerprints(fingerprints *PhotoFingerprintService) {
+	s.fingerprints = fingerprints
+}
+
+// SetPrivateAlbums 設定私人相簿服務，獲得授權的配對對象可查看私人相簿照片
+func (s *UserService) SetPrivateAlbums(privateAlbums *PrivateAlbumService) {
+	s.privateAlbums = privateAlbums
+}
+
+// SetMatchingCache 設定配對快取，檔案或配對條件變更後清除
+func (s *UserService) SetMatchingCache(cache MatchingCacheInvalidator) {
+	s.matchingCache = cache
+}
+
+// SetPhotoLimits 設定各訂閱方案的照片數量上限，tiers 為 nil 時所有用戶套用預設上限
+func (s *UserService) SetPhotoLimits(limits PhotoLimits, tiers SubscriptionTierResolver) {
+	s.photoLimits = limits
+	s.tiers = tiers
+}
+
+// PhotoLimit 獲取用戶可保存的照片數量上限，查詢訂閱方案失敗時套用預設上限
+func (s *UserService) PhotoLimit(ctx context.Context, userID uint) int {
+	if s.tiers == nil {
+		return s.photoLimits.Limit("")
+	}
+	tier, err := s.tiers.SubscriptionTier(ctx, userID)
+	if err != nil {
+		log.Printf("查詢訂閱方案失敗 (用戶 %d): %v", userID, err)
+		return s.photoLimits.Limit("")
+	}
+	return s.photoLimits.Limit(tier)
+}
+
+// MaxPhotoSize 獲取單張照片上傳大小上限
+func (s *UserService) MaxPhotoSize() int64 {
+	return s.maxPhotoSize
+}
+
 // RegisterRequest 用戶註冊請求
 type RegisterRequest struct {
 	Email       string    `json:"email" validate:"required,email"`
@@ -50,6 +140,14 @@ type RegisterRequest struct {
 	Biography   string    `json:"biography" validate:"max=500"`
 }
 
+// CompleteRegistrationRequest 外部登入帳號補填註冊資料請求
+type CompleteRegistrationRequest struct {
+	BirthDate   time.Time `json:"birth_date" validate:"required"`
+	DisplayName string    `json:"display_name" validate:"required,min=2,max=50"`
+	Gender      string    `json:"gender" validate:"required"`
+	Biography   string    `json:"biography" validate:"max=500"`
+}
+
 // LoginRequest 用戶登入請求
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
@@ -58,13 +156,38 @@ type LoginRequest struct {
 
 // UserResponse 用戶回應資料
 type UserResponse struct {
-	ID         uint                `json:"id"`
-	Email      string              `json:"email"`
-	IsVerified bool                `json:"is_verified"`
-	IsActive   bool                `json:"is_active"`
-	CreatedAt  time.Time           `json:"created_at"`
-	Age        int                 `json:"age"`
-	Profile    *entity.UserProfile `json:"profile,omitempty"`
+	ID            uint                `json:"id"`
+	Email         string              `json:"email"`
+	IsVerified    bool                `json:"is_verified"`
+	EmailVerified bool                `json:"email_verified"`
+	Role          entity.UserRole     `json:"role"`
+	IsActive      bool                `json:"is_active"`
+	CreatedAt     time.Time           `json:"created_at"`
+	Age           int                 `json:"age"`
+	Profile       *entity.UserProfile `json:"profile,omitempty"`
+
+	// 外部登入建立的帳號需補填出生日期，完成前不顯示年齡
+	RegistrationPending bool `json:"registration_pending,omitempty"`
+}
+
+// newUserResponse 由用戶實體建立回應資料
+func newUserResponse(user *entity.User, profile *entity.UserProfile) *UserResponse {
+	response := &UserResponse{
+		ID:                  user.ID,
+		Email:               user.Email,
+		IsVerified:          user.IsVerified,
+		EmailVerified:       user.EmailVerified,
+		Role:                user.GetRole(),
+		IsActive:            user.IsActive,
+		CreatedAt:           user.CreatedAt,
+		Age:                 user.GetAge(),
+		Profile:             profile,
+		RegistrationPending: user.RegistrationPending,
+	}
+	if user.RegistrationPending {
+		response.Age = 0
+	}
+	return response
 }
 
 // Register 用戶註冊
@@ -96,8 +219,10 @@ func (s *UserService) Register(ctx context.Context, req *RegisterRequest) (*User
 		return nil, fmt.Errorf("密碼加密失敗: %w", err)
 	}
 	user.PasswordHash = string(hashedPassword)
-	user.IsVerified = false // 需要年齡驗證
+	user.IsVerified = false    // 需要年齡驗證
+	user.EmailVerified = false // 需要電子郵件驗證
 	user.IsActive = true
+	user.Role = entity.RoleUser // 註冊一律為一般用戶，不接受客戶端指定
 
 	// 創建用戶
 	if err := s.userRepo.Create(ctx, user); err != nil {
@@ -132,15 +257,62 @@ func (s *UserService) Register(ctx context.Context, req *RegisterRequest) (*User
 		// 可以稍後通過其他方式創建驗證記錄
 	}
 
-	return &UserResponse{
-		ID:         user.ID,
-		Email:      user.Email,
-		IsVerified: user.IsVerified,
-		IsActive:   user.IsActive,
-		CreatedAt:  user.CreatedAt,
-		Age:        user.GetAge(),
-		Profile:    profile,
-	}, nil
+	return newUserResponse(user, profile), nil
+}
+
+// CompleteRegistration 外部登入建立的帳號補填出生日期與基本檔案
+// 完成前帳號不符合配對等使用條件
+func (s *UserService) CompleteRegistration(ctx context.Context, userID uint, req *CompleteRegistrationRequest) (*UserResponse, error) {
+	if err := s.validateCompleteRegistrationRequest(req); err != nil {
+		return nil, fmt.Errorf("註冊資料驗證失敗: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("用戶不存在: %w", err)
+	}
+	if !user.RegistrationPending {
+		return nil, ErrRegistrationCompleted
+	}
+
+	// 檢查年齡（必須18+）
+	candidate := &entity.User{BirthDate: req.BirthDate}
+	if !candidate.IsAdult() {
+		return nil, errors.New("用戶必須年滿18歲才能註冊")
+	}
+
+	// 先建立檔案再解除待補填狀態，中途失敗時可重新送出
+	profile, err := s.userProfileRepo.GetByUserID(ctx, user.ID)
+	if err != nil || profile == nil {
+		profile = &entity.UserProfile{UserID: user.ID}
+	}
+	profile.DisplayName = req.DisplayName
+	profile.Bio = req.Biography
+	profile.Gender = entity.Gender(req.Gender)
+	if profile.ID == 0 {
+		err = s.userProfileRepo.Create(ctx, profile)
+	} else {
+		err = s.userProfileRepo.Update(ctx, profile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("保存用戶檔案失敗: %w", err)
+	}
+
+	user.CompleteRegistration(req.BirthDate)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("更新用戶失敗: %w", err)
+	}
+
+	ageVerification := &entity.AgeVerification{
+		UserID: user.ID,
+		Method: entity.VerificationMethodOther,
+		Status: entity.VerificationStatusPending,
+	}
+	if err := s.ageVerificationRepo.Create(ctx, ageVerification); err != nil {
+		// 與註冊相同，驗證記錄可稍後建立，不阻塞流程
+	}
+
+	return newUserResponse(user, profile), nil
 }
 
 // Login 用戶登入
@@ -154,17 +326,26 @@ func (s *UserService) Login(ctx context.Context, req *LoginRequest) (*UserRespon
 	// 根據 Email 查找用戶
 	user, err := s.userRepo.GetByEmail(ctx, strings.ToLower(req.Email))
 	if err != nil {
-		return nil, errors.New("Email 或密碼錯誤")
+		return nil, ErrInvalidCredentials
 	}
 
-	// 檢查用戶狀態
-	if !user.IsActive {
+	// 檢查用戶狀態（排定刪除的帳號在密碼驗證後回報）
+	if !user.IsActive && !user.IsPendingDeletion() {
 		return nil, errors.New("帳戶已被停用")
 	}
 
 	// 驗證密碼
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		return nil, errors.New("Email 或密碼錯誤")
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.IsPendingDeletion() {
+		return nil, fmt.Errorf("%w，將於 %s 清除，可在此之前取消", ErrAccountPendingDeletion, user.DeletionScheduledAt.Format("2006-01-02 15:04"))
+	}
+
+	// 密碼正確後才回報停權狀態，避免洩漏帳號資訊
+	if err := checkAccountStatus(user); err != nil {
+		return nil, err
 	}
 
 	// 獲取用戶檔案
@@ -174,15 +355,7 @@ func (s *UserService) Login(ctx context.Context, req *LoginRequest) (*UserRespon
 		profile = nil
 	}
 
-	return &UserResponse{
-		ID:         user.ID,
-		Email:      user.Email,
-		IsVerified: user.IsVerified,
-		IsActive:   user.IsActive,
-		CreatedAt:  user.CreatedAt,
-		Age:        user.GetAge(),
-		Profile:    profile,
-	}, nil
+	return newUserResponse(user, profile), nil
 }
 
 // GetProfile 獲取用戶完整檔案資訊
@@ -199,28 +372,32 @@ func (s *UserService) GetProfile(ctx context.Context, userID uint) (*UserRespons
 		return nil, fmt.Errorf("獲取用戶檔案失敗: %w", err)
 	}
 
-	return &UserResponse{
-		ID:         user.ID,
-		Email:      user.Email,
-		IsVerified: user.IsVerified,
-		IsActive:   user.IsActive,
-		CreatedAt:  user.CreatedAt,
-		Age:        user.GetAge(),
-		Profile:    profile,
-	}, nil
+	// 載入檔案問答
+	if s.promptRepo != nil && profile != nil {
+		prompts, err := s.promptRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("獲取檔案問答失敗: %w", err)
+		}
+		profile.Prompts = prompts
+	}
+
+	return newUserResponse(user, profile), nil
 }
 
 // UpdateProfile 更新用戶檔案
 type UpdateProfileRequest struct {
-	DisplayName      *string        `json:"display_name,omitempty"`
-	Biography        *string        `json:"biography,omitempty"`
-	LocationLat      *float64       `json:"location_lat,omitempty"`
-	LocationLng      *float64       `json:"location_lng,omitempty"`
-	MaxDistance      *int           `json:"max_distance,omitempty"`
-	AgeRangeMin      *int           `json:"age_range_min,omitempty"`
-	AgeRangeMax      *int           `json:"age_range_max,omitempty"`
-	InterestedGender *entity.Gender `json:"interested_gender,omitempty"`
-	InterestIDs      []uint         `json:"interest_ids,omitempty"`
+	DisplayName  *string         `json:"display_name,omitempty"`
+	Biography    *string         `json:"biography,omitempty"`
+	LocationLat  *float64        `json:"location_lat,omitempty"`
+	LocationLng  *float64        `json:"location_lng,omitempty"`
+	MaxDistance  *int            `json:"max_distance,omitempty"`
+	AgeRangeMin  *int            `json:"age_range_min,omitempty"`
+	AgeRangeMax  *int            `json:"age_range_max,omitempty"`
+	InterestedIn []entity.Gender `json:"interested_in,omitempty"` // nil 表示不變更，空陣列表示不限性別
+	InterestIDs  []uint          `json:"interest_ids,omitempty"`
+
+	// 結構化屬性與可見性，傳入時整份取代
+	Attributes *entity.ProfileAttributes `json:"attributes,omitempty"`
 }
 
 // UpdateProfile 更新用戶檔案
@@ -231,6 +408,14 @@ func (s *UserService) UpdateProfile(ctx context.Context, userID uint, req *Updat
 		return nil, fmt.Errorf("獲取用戶檔案失敗: %w", err)
 	}
 
+	// 先檢查興趣標籤，避免檔案已更新後才因興趣無效而失敗
+	var interestIDs []uint
+	if req.InterestIDs != nil {
+		if interestIDs, err = s.checkInterestSelection(ctx, userID, req.InterestIDs); err != nil {
+			return nil, err
+		}
+	}
+
 	// 更新檔案資料
 	if req.DisplayName != nil {
 		if strings.TrimSpace(*req.DisplayName) == "" {
@@ -243,10 +428,17 @@ func (s *UserService) UpdateProfile(ctx context.Context, userID uint, req *Updat
 		profile.Bio = *req.Biography
 	}
 
-	// Note: InterestedGender not found in UserProfile entity - removing this feature
-	// if req.InterestedGender != nil {
-	//	profile.InterestedGender = *req.InterestedGender
-	// }
+	if req.InterestedIn != nil {
+		if err := profile.UpdateInterestedIn(req.InterestedIn); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Attributes != nil {
+		if err := profile.UpdateAttributes(*req.Attributes); err != nil {
+			return nil, err
+		}
+	}
 
 	// 更新檔案
 	if err := s.userProfileRepo.Update(ctx, profile); err != nil {
@@ -283,89 +475,448 @@ func (s *UserService) UpdateProfile(ctx context.Context, userID uint, req *Updat
 
 	// 更新興趣標籤
 	if req.InterestIDs != nil {
-		if err := s.interestRepo.SetUserInterests(ctx, userID, req.InterestIDs); err != nil {
+		if err := s.interestRepo.SetUserInterests(ctx, userID, interestIDs); err != nil {
 			return nil, fmt.Errorf("更新興趣標籤失敗: %w", err)
 		}
 	}
 
+	// 性別、年齡、距離與位置都影響潛在配對
+	s.invalidateMatchingCache(userID)
+
 	// 返回更新後的檔案
 	return s.GetProfile(ctx, userID)
 }
 
-// GetUserPhotos 獲取用戶照片
+// AttributePreferencesRequest 屬性配對條件請求，整份取代現有條件
+type AttributePreferencesRequest struct {
+	Preferences []*entity.AttributePreference `json:"preferences"`
+}
+
+// GetAttributePreferences 獲取用戶的屬性配對條件
+func (s *UserService) GetAttributePreferences(ctx context.Context, userID uint) ([]*entity.AttributePreference, error) {
+	if s.attributeRepo == nil {
+		return nil, ErrAttributePreferencesUnavailable
+	}
+	return s.attributeRepo.GetByUserID(ctx, userID)
+}
+
+// UpdateAttributePreferences 更新用戶的屬性配對條件
+// 每個屬性只能設定一個條件，Dealbreaker 為 true 時作為潛在配對的硬性篩選
+func (s *UserService) UpdateAttributePreferences(ctx context.Context, userID uint, req *AttributePreferencesRequest) ([]*entity.AttributePreference, error) {
+	if s.attributeRepo == nil {
+		return nil, ErrAttributePreferencesUnavailable
+	}
+
+	seen := make(map[entity.ProfileAttribute]bool, len(req.Preferences))
+	preferences := make([]*entity.AttributePreference, 0, len(req.Preferences))
+	for _, preference := range req.Preferences {
+		if preference == nil {
+			continue
+		}
+		if seen[preference.Attribute] {
+			return nil, fmt.Errorf("%s 條件重複", preference.Attribute)
+		}
+		seen[preference.Attribute] = true
+
+		if preference.Attribute == entity.AttributeLanguages {
+			preference.Values = entity.StringList(normalizeLanguageCodes(preference.Values))
+		}
+		if err := preference.Validate(); err != nil {
+			return nil, err
+		}
+
+		preferences = append(preferences, &entity.AttributePreference{
+			UserID:      userID,
+			Attribute:   preference.Attribute,
+			Values:      preference.Values,
+			MinHeight:   preference.MinHeight,
+			MaxHeight:   preference.MaxHeight,
+			Dealbreaker: preference.Dealbreaker,
+		})
+	}
+
+	if err := s.attributeRepo.ReplaceForUser(ctx, userID, preferences); err != nil {
+		return nil, fmt.Errorf("更新屬性配對條件失敗: %w", err)
+	}
+	s.invalidateMatchingCache(userID)
+
+	return s.attributeRepo.GetByUserID(ctx, userID)
+}
+
+// normalizeLanguageCodes 語言代碼統一為小寫
+func normalizeLanguageCodes(codes []string) []string {
+	normalized := make([]string, 0, len(codes))
+	for _, code := range codes {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(code)))
+	}
+	return normalized
+}
+
+// GetUserPhotos 獲取用戶照片，附上有期限的下載連結
 func (s *UserService) GetUserPhotos(ctx context.Context, userID uint) ([]*entity.Photo, error) {
-	return s.photoRepo.GetByUserID(ctx, userID)
+	photos, err := s.photoRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	signPhotoURLs(ctx, s.files, userID, photos...)
+	return photos, nil
+}
+
+// GetPublicPhotos 獲取公開的照片，只包含已通過審核且不在私人相簿的照片
+func (s *UserService) GetPublicPhotos(ctx context.Context, userID uint) ([]*entity.Photo, error) {
+	return s.GetVisiblePhotos(ctx, userID, 0)
+}
+
+// GetVisiblePhotos 獲取查看者可見的照片，只包含已通過審核的照片
+// 私人相簿照片只在查看者獲得授權時返回，下載連結綁定查看者
+func (s *UserService) GetVisiblePhotos(ctx context.Context, ownerID, viewerID uint) ([]*entity.Photo, error) {
+	photos, err := s.photoRepo.GetByUserID(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var canViewPrivate, checked bool
+	visible := make([]*entity.Photo, 0, len(photos))
+	for _, photo := range photos {
+		if !photo.IsApproved() {
+			continue
+		}
+		if photo.IsPrivatePhoto() {
+			if !checked {
+				if canViewPrivate, err = s.canViewPrivate(ctx, ownerID, viewerID); err != nil {
+					return nil, fmt.Errorf("檢查私人相簿權限失敗: %w", err)
+				}
+				checked = true
+			}
+			if !canViewPrivate {
+				continue
+			}
+		}
+		visible = append(visible, photo)
+	}
+	signPhotoURLs(ctx, s.files, viewerID, visible...)
+	return visible, nil
+}
+
+// canViewPrivate 檢查查看者是否可以查看私人相簿，未設定私人相簿服務時只有本人可以查看
+func (s *UserService) canViewPrivate(ctx context.Context, ownerID, viewerID uint) (bool, error) {
+	if s.privateAlbums == nil {
+		return viewerID != 0 && ownerID == viewerID, nil
+	}
+	return s.privateAlbums.CanView(ctx, ownerID, viewerID)
 }
 
-// AddPhoto 添加用戶照片
-func (s *UserService) AddPhoto(ctx context.Context, userID uint, imageURL, description string) (*entity.Photo, error) {
-	// 檢查用戶照片數量限制（例如最多6張）
+// UploadPhoto 上傳用戶照片
+// 依檔案內容判斷格式，重新編碼移除 EXIF（含 GPS 位置）並產生各尺寸縮圖後寫入儲存區
+func (s *UserService) UploadPhoto(ctx context.Context, userID uint, upload *PhotoUpload) (*entity.Photo, error) {
+	if s.imageProcessor == nil || s.files == nil {
+		return nil, ErrPhotoUploadUnavailable
+	}
+
+	if len(upload.Data) == 0 {
+		return nil, ErrPhotoEmpty
+	}
+	if int64(len(upload.Data)) > s.maxPhotoSize {
+		return nil, ErrPhotoTooLarge
+	}
+
+	photoType := upload.Type
+	if photoType == "" {
+		photoType = entity.PhotoTypeProfile
+	}
+	if !photoType.IsValid() {
+		return nil, errors.New("type 必須是 profile、gallery 或 private")
+	}
+
+	// 以檔案開頭的魔術位元組判斷格式，不信任客戶端提供的檔名與 Content-Type
+	mimeType := http.DetectContentType(upload.Data)
+	if !entity.IsValidImageMimeType(mimeType) {
+		return nil, ErrUnsupportedImageType
+	}
+
+	// 檢查用戶照片數量限制
 	photos, err := s.photoRepo.GetByUserID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("獲取用戶照片失敗: %w", err)
 	}
 
-	if len(photos) >= 6 {
-		return nil, errors.New("照片數量已達上限(6張)")
+	if limit := s.PhotoLimit(ctx, userID); len(photos) >= limit {
+		return nil, fmt.Errorf("%w(%d張)", ErrPhotoLimitReached, limit)
 	}
 
-	// 創建照片記錄
-	photo := &entity.Photo{
-		UserID:       userID,
-		Type:         entity.PhotoTypeProfile,
-		FileName:     description,               // Using description as filename for now
-		FilePath:     imageURL,                  // Using imageURL as file path
-		FileSize:     0,                         // Would need to be calculated
-		MimeType:     "image/jpeg",              // Default, should be determined from actual file
-		DisplayOrder: len(photos) + 1,           // 新照片排在最後
-		Status:       entity.PhotoStatusPending, // 需要審核
+	processed, err := s.imageProcessor.Process(upload.Data, mimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	photo, err := s.storePhotoFiles(ctx, userID, photoType, processed)
+	if err != nil {
+		return nil, err
 	}
+	photo.Type = photoType
+	photo.DisplayOrder = len(photos) + 1     // 新照片排在最後
+	photo.Status = entity.PhotoStatusPending // 需要審核，通過審核後才可能成為主要照片
 
-	// 如果是第一張照片，設為主要照片
-	if len(photos) == 0 {
-		photo.IsMain = true
+	if err := photo.SetCaption(upload.Caption); err != nil {
+		s.removePhotoFiles(photo)
+		return nil, err
+	}
+	if err := photo.Validate(); err != nil {
+		s.removePhotoFiles(photo)
+		return nil, err
 	}
 
 	if err := s.photoRepo.Create(ctx, photo); err != nil {
+		s.removePhotoFiles(photo)
 		return nil, fmt.Errorf("添加照片失敗: %w", err)
 	}
 
+	// 比對其他用戶的照片與禁用清單，疑似盜用時標記待審核，偵測失敗不影響上傳
+	if s.fingerprints != nil {
+		if _, err := s.fingerprints.Inspect(ctx, photo); err != nil {
+			log.Printf("照片重複偵測失敗 (照片 %d): %v", photo.ID, err)
+		}
+	}
+
+	signPhotoURLs(ctx, s.files, userID, photo)
 	return photo, nil
 }
 
-// SetPrimaryPhoto 設定主要照片
-func (s *UserService) SetPrimaryPhoto(ctx context.Context, userID, photoID uint) error {
-	// 驗證照片所有權
-	photo, err := s.photoRepo.GetByID(ctx, photoID)
+// storePhotoFiles 以隨機檔名寫入原圖與縮圖，返回尚未保存的照片記錄
+// 私人相簿照片存放於獨立路徑，下載時檢查查看者權限；任一檔案寫入失敗時刪除已寫入的檔案
+func (s *UserService) storePhotoFiles(ctx context.Context, userID uint, photoType entity.PhotoType, processed *ProcessedImage) (*entity.Photo, error) {
+	token, err := generateSecureToken(16)
 	if err != nil {
-		return fmt.Errorf("照片不存在: %w", err)
+		return nil, fmt.Errorf("生成照片檔名失敗: %w", err)
+	}
+	base := fmt.Sprintf("photos/%d/%s", userID, token)
+	if photoType == entity.PhotoTypePrivate {
+		base = privatePhotoKey(userID, token)
 	}
 
-	if photo.UserID != userID {
-		return errors.New("無權限操作此照片")
+	hash := processed.PerceptualHash
+	photo := &entity.Photo{
+		UserID:         userID,
+		FileName:       token + processed.Extension,
+		FilePath:       base + processed.Extension,
+		FileSize:       int64(len(processed.Data)),
+		MimeType:       processed.MimeType,
+		Width:          processed.Width,
+		Height:         processed.Height,
+		Thumbnails:     make(entity.PhotoThumbnails, len(processed.Thumbnails)),
+		PerceptualHash: &hash,
+	}
+	if err := s.files.Put(ctx, photo.FilePath, processed.Data, processed.MimeType); err != nil {
+		return nil, fmt.Errorf("儲存照片檔案失敗: %w", err)
+	}
+
+	for _, size := range entity.ThumbnailSizes {
+		data, ok := processed.Thumbnails[size.Name]
+		if !ok {
+			continue
+		}
+		key := base + "_" + string(size.Name) + processed.Extension
+		if err := s.files.Put(ctx, key, data, processed.MimeType); err != nil {
+			s.removePhotoFiles(photo)
+			return nil, fmt.Errorf("儲存照片縮圖失敗: %w", err)
+		}
+		photo.Thumbnails[size.Name] = key
+	}
+
+	return photo, nil
+}
+
+// removePhotoFiles 刪除照片原圖與縮圖，失敗只記錄日誌
+func (s *UserService) removePhotoFiles(photo *entity.Photo) {
+	if s.files == nil {
+		return
+	}
+	for _, path := range photo.FilePaths() {
+		if err := s.files.RemoveFile(path); err != nil {
+			log.Printf("刪除照片檔案失敗 (%s): %v", path, err)
+		}
+	}
+}
+
+// signPhotoURLs 為查看者簽發照片與縮圖的下載連結
+func signPhotoURLs(ctx context.Context, files *FileService, viewerID uint, photos ...*entity.Photo) {
+	if files == nil {
+		return
+	}
+	for _, photo := range photos {
+		photo.URL = files.SignedURLFor(ctx, photo.FilePath, viewerID)
+		if len(photo.Thumbnails) == 0 {
+			continue
+		}
+		photo.ThumbnailURLs = make(map[entity.ThumbnailSize]string, len(photo.Thumbnails))
+		for size, path := range photo.Thumbnails {
+			photo.ThumbnailURLs[size] = files.SignedURLFor(ctx, path, viewerID)
+		}
+	}
+}
+
+// SetPrimaryPhoto 設定主要照片，只有已通過審核的個人檔案照片可以設為主要照片
+func (s *UserService) SetPrimaryPhoto(ctx context.Context, userID, photoID uint) error {
+	photo, err := s.getOwnPhoto(ctx, userID, photoID)
+	if err != nil {
+		return err
+	}
+	if !photo.CanBeMain() {
+		return ErrPhotoCannotBeMain
 	}
 
 	return s.photoRepo.SetPrimary(ctx, userID, photoID)
 }
 
-// DeletePhoto 刪除照片
+// UpdatePhoto 更新照片說明與類型
+// 類型只能在個人檔案照片與相簿照片之間切換；主要照片改為相簿照片時由下一張符合條件的照片遞補
+func (s *UserService) UpdatePhoto(ctx context.Context, userID, photoID uint, req *UpdatePhotoRequest) (*entity.Photo, error) {
+	photo, err := s.getOwnPhoto(ctx, userID, photoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Caption != nil {
+		if err := photo.SetCaption(*req.Caption); err != nil {
+			return nil, err
+		}
+	}
+
+	typeChanged := false
+	if req.Type != nil && *req.Type != photo.Type {
+		if !req.Type.IsValid() {
+			return nil, errors.New("type 必須是 profile、gallery 或 private")
+		}
+		// 私人相簿照片存放於獨立路徑，不能與公開照片互換
+		if photo.IsPrivatePhoto() || *req.Type == entity.PhotoTypePrivate {
+			return nil, ErrPhotoTypeChange
+		}
+		photo.Type = *req.Type
+		typeChanged = true
+	}
+	if photo.IsMain && !photo.CanBeMain() {
+		photo.UnsetAsMain()
+	}
+
+	if err := s.photoRepo.Update(ctx, photo); err != nil {
+		return nil, fmt.Errorf("更新照片失敗: %w", err)
+	}
+
+	if typeChanged {
+		mainID, err := ensureMainPhoto(ctx, s.photoRepo, userID)
+		if err != nil {
+			log.Printf("遞補主要照片失敗 (用戶 %d): %v", userID, err)
+		} else {
+			photo.IsMain = mainID == photo.ID
+		}
+	}
+
+	signPhotoURLs(ctx, s.files, userID, photo)
+	return photo, nil
+}
+
+// ReorderPhotos 依照片 ID 順序重新排列照片，必須包含用戶所有照片且不可重複
+func (s *UserService) ReorderPhotos(ctx context.Context, userID uint, photoIDs []uint) ([]*entity.Photo, error) {
+	photos, err := s.photoRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("獲取用戶照片失敗: %w", err)
+	}
+	if len(photoIDs) != len(photos) {
+		return nil, ErrInvalidPhotoOrder
+	}
+
+	owned := make(map[uint]bool, len(photos))
+	for _, photo := range photos {
+		owned[photo.ID] = true
+	}
+
+	orders := make([]struct {
+		PhotoID uint
+		Order   int
+	}, 0, len(photoIDs))
+	for i, photoID := range photoIDs {
+		if !owned[photoID] {
+			return nil, ErrInvalidPhotoOrder
+		}
+		delete(owned, photoID) // 重複的 ID 第二次出現時會找不到
+		orders = append(orders, struct {
+			PhotoID uint
+			Order   int
+		}{PhotoID: photoID, Order: i + 1})
+	}
+
+	if err := s.photoRepo.UpdateOrder(ctx, userID, orders); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserPhotos(ctx, userID)
+}
+
+// DeletePhoto 刪除照片，刪除主要照片時由下一張已通過審核的個人檔案照片遞補
 func (s *UserService) DeletePhoto(ctx context.Context, userID, photoID uint) error {
-	// 驗證照片所有權
-	photo, err := s.photoRepo.GetByID(ctx, photoID)
+	photo, err := s.getOwnPhoto(ctx, userID, photoID)
 	if err != nil {
-		return fmt.Errorf("照片不存在: %w", err)
+		return err
 	}
 
-	if photo.UserID != userID {
-		return errors.New("無權限操作此照片")
+	if err := s.photoRepo.Delete(ctx, photoID); err != nil {
+		return err
+	}
+
+	s.removePhotoFiles(photo)
+
+	if photo.IsMain {
+		if _, err := ensureMainPhoto(ctx, s.photoRepo, userID); err != nil {
+			log.Printf("遞補主要照片失敗 (用戶 %d): %v", userID, err)
+		}
 	}
+	return nil
+}
 
-	return s.photoRepo.Delete(ctx, photoID)
+// getOwnPhoto 獲取用戶自己的照片，不存在時返回 ErrPhotoNotFound，不是本人的照片返回 ErrPhotoForbidden
+func (s *UserService) getOwnPhoto(ctx context.Context, userID, photoID uint) (*entity.Photo, error) {
+	photo, err := s.photoRepo.GetByID(ctx, photoID)
+	if err != nil {
+		return nil, err
+	}
+	if photo == nil {
+		return nil, ErrPhotoNotFound
+	}
+	if photo.UserID != userID {
+		return nil, ErrPhotoForbidden
+	}
+	return photo, nil
 }
 
-// GetAvailableInterests 獲取所有可用的興趣標籤
-func (s *UserService) GetAvailableInterests(ctx context.Context) ([]*entity.Interest, error) {
-	return s.interestRepo.GetAll(ctx)
+// ensureMainPhoto 確保用戶的主要照片符合條件，返回目前主要照片的 ID（沒有時為 0）
+// 沒有主要照片或主要照片已不符合條件時，依顯示順序選出第一張已通過審核的個人檔案照片；
+// 沒有符合條件的照片時取消原本的主要照片設定
+func ensureMainPhoto(ctx context.Context, photoRepo repository.PhotoRepository, userID uint) (uint, error) {
+	photos, err := photoRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var current, candidate *entity.Photo
+	for _, photo := range photos {
+		if photo.IsMain && current == nil {
+			current = photo
+		}
+		if photo.CanBeMain() && candidate == nil {
+			candidate = photo
+		}
+	}
+
+	switch {
+	case current != nil && current.CanBeMain():
+		return current.ID, nil
+	case candidate != nil:
+		return candidate.ID, photoRepo.SetPrimary(ctx, userID, candidate.ID)
+	case current != nil:
+		current.UnsetAsMain()
+		return 0, photoRepo.Update(ctx, current)
+	}
+	return 0, nil
 }
 
 // GetUserInterests 獲取用戶的興趣標籤
@@ -373,29 +924,71 @@ func (s *UserService) GetUserInterests(ctx context.Context, userID uint) ([]*ent
 	return s.interestRepo.GetByUserID(ctx, userID)
 }
 
-// SubmitAgeVerification 提交年齡驗證
-func (s *UserService) SubmitAgeVerification(ctx context.Context, userID uint, method entity.VerificationMethod, documentNumber string, documentImagePath string) error {
-	// 檢查是否已有驗證記錄
-	verification, err := s.ageVerificationRepo.GetByUserID(ctx, userID)
+// SetUserInterests 以新的興趣取代用戶所有興趣，使用人數由儲存庫在同一交易中更新
+func (s *UserService) SetUserInterests(ctx context.Context, userID uint, interestIDs []uint) ([]*entity.Interest, error) {
+	interestIDs, err := s.checkInterestSelection(ctx, userID, interestIDs)
 	if err != nil {
-		// 沒有記錄，創建新的
-		verification = &entity.AgeVerification{
-			UserID:            userID,
-			Method:            method,
-			DocumentNumber:    documentNumber,
-			DocumentImagePath: documentImagePath,
-			Status:            entity.VerificationStatusPending,
+		return nil, err
+	}
+
+	if err := s.interestRepo.SetUserInterests(ctx, userID, interestIDs); err != nil {
+		return nil, fmt.Errorf("更新興趣標籤失敗: %w", err)
+	}
+	return s.interestRepo.GetByUserID(ctx, userID)
+}
+
+// checkInterestSelection 去除重複的興趣並檢查數量上限
+// 只有新選的興趣需要是啟用中的興趣，已停用興趣的既有選擇可以保留
+func (s *UserService) checkInterestSelection(ctx context.Context, userID uint, interestIDs []uint) ([]uint, error) {
+	unique := make([]uint, 0, len(interestIDs))
+	seen := make(map[uint]bool, len(interestIDs))
+	for _, id := range interestIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
 		}
-		return s.ageVerificationRepo.Create(ctx, verification)
+	}
+	if len(unique) > entity.MaxUserInterests {
+		return nil, fmt.Errorf("%w(最多 %d 個)", ErrTooManyInterests, entity.MaxUserInterests)
 	}
 
-	// 更新現有記錄
-	verification.Method = method
-	verification.DocumentNumber = documentNumber
-	verification.DocumentImagePath = documentImagePath
-	verification.Status = entity.VerificationStatusPending
+	current, err := s.interestRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[uint]bool, len(current))
+	for _, interest := range current {
+		selected[interest.ID] = true
+	}
+
+	var added []uint
+	for _, id := range unique {
+		if !selected[id] {
+			added = append(added, id)
+		}
+	}
+	if len(added) == 0 {
+		return unique, nil
+	}
 
-	return s.ageVerificationRepo.Update(ctx, verification)
+	interests, err := s.interestRepo.GetByIDs(ctx, added)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uint]*entity.Interest, len(interests))
+	for _, interest := range interests {
+		found[interest.ID] = interest
+	}
+	for _, id := range added {
+		interest, ok := found[id]
+		if !ok {
+			return nil, fmt.Errorf("%w (ID: %d)", ErrInterestNotFound, id)
+		}
+		if !interest.IsActiveInterest() {
+			return nil, fmt.Errorf("%w: %s", ErrInterestInactive, interest.Name)
+		}
+	}
+	return unique, nil
 }
 
 // GetAgeVerificationStatus 獲取年齡驗證狀態
@@ -405,6 +998,31 @@ func (s *UserService) GetAgeVerificationStatus(ctx context.Context, userID uint)
 
 // 私有輔助方法
 
+// validateCompleteRegistrationRequest 驗證補填註冊資料請求
+func (s *UserService) validateCompleteRegistrationRequest(req *CompleteRegistrationRequest) error {
+	if strings.TrimSpace(req.DisplayName) == "" {
+		return errors.New("顯示名稱不能為空")
+	}
+
+	if len(req.DisplayName) < 2 || len(req.DisplayName) > 50 {
+		return errors.New("顯示名稱長度必須在2-50個字符之間")
+	}
+
+	if !entity.Gender(req.Gender).IsValid() {
+		return errors.New("性別值不正確")
+	}
+
+	if req.BirthDate.IsZero() {
+		return errors.New("出生日期不能為空")
+	}
+
+	if len(req.Biography) > 500 {
+		return errors.New("個人簡介不能超過500個字符")
+	}
+
+	return nil
+}
+
 // validateRegisterRequest 驗證註冊請求
 func (s *UserService) validateRegisterRequest(req *RegisterRequest) error {
 	if strings.TrimSpace(req.Email) == "" {
@@ -415,12 +1033,8 @@ func (s *UserService) validateRegisterRequest(req *RegisterRequest) error {
 		return errors.New("Email 格式不正確")
 	}
 
-	if strings.TrimSpace(req.Password) == "" {
-		return errors.New("密碼不能為空")
-	}
-
-	if len(req.Password) < 8 {
-		return errors.New("密碼長度至少8個字符")
+	if err := s.passwords.Validate(req.Password, req.Email, req.DisplayName); err != nil {
+		return err
 	}
 
 	if strings.TrimSpace(req.DisplayName) == "" {
@@ -464,3 +1078,13 @@ func (s *UserService) validateLoginRequest(req *LoginRequest) error {
 
 	return nil
 }
+
+// invalidateMatchingCache 清除用戶的配對快取，失敗只記錄日誌，快取到期後自然更新
+func (s *UserService) invalidateMatchingCache(userID uint) {
+	if s.matchingCache == nil {
+		return
+	}
+	if err := s.matchingCache.InvalidateUserCache(userID); err != nil {
+		log.Printf("清除用戶 %d 配對快取失敗: %v", userID, err)
+	}
+}
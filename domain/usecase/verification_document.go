@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"golang_dev_docker/domain/entity"
+)
+
+// 年齡驗證文件相關錯誤
+var (
+	ErrVerificationUploadUnavailable = errors.New("驗證文件上傳功能未啟用")
+	ErrInvalidVerification           = errors.New("年齡驗證資料無效")
+	ErrVerificationDocumentEmpty     = errors.New("驗證文件為空")
+	ErrVerificationDocumentTooLarge  = errors.New("驗證文件過大")
+	ErrUnsupportedVerificationType   = errors.New("不支援的驗證文件格式，僅接受 JPEG、PNG、GIF、WebP 圖片與 PDF")
+)
+
+const (
+	// MaxVerificationDocumentSize 年齡驗證文件大小上限
+	MaxVerificationDocumentSize int64 = 10 << 20
+
+	// verificationDocumentKeyPrefix 年齡驗證文件的儲存路徑前綴，格式為 verifications/<用戶ID>/<檔名>
+	// 路徑由伺服器產生，下載時只允許本人與具內容審核權限的人員
+	verificationDocumentKeyPrefix = "verifications/"
+)
+
+// SubmitAgeVerification 提交年齡驗證
+// 依檔案內容判斷格式，文件寫入伺服器產生的路徑後才保存驗證記錄，重新提交時刪除先前的文件
+func (s *UserService) SubmitAgeVerification(ctx context.Context, userID uint, method entity.VerificationMethod, documentNumber string, document []byte) (*entity.AgeVerification, error) {
+	if s.files == nil {
+		return nil, ErrVerificationUploadUnavailable
+	}
+	if len(document) == 0 {
+		return nil, ErrVerificationDocumentEmpty
+	}
+	if int64(len(document)) > MaxVerificationDocumentSize {
+		return nil, ErrVerificationDocumentTooLarge
+	}
+
+	mimeType, extension, ok := detectDocumentType(document)
+	if !ok {
+		return nil, ErrUnsupportedVerificationType
+	}
+
+	// 檢查是否已有驗證記錄
+	verification, err := s.ageVerificationRepo.GetByUserID(ctx, userID)
+	if err != nil || verification == nil {
+		verification = &entity.AgeVerification{UserID: userID}
+	}
+	previousPath := verification.DocumentImagePath
+
+	token, err := generateSecureToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("生成驗證文件檔名失敗: %w", err)
+	}
+	verification.Method = method
+	verification.DocumentNumber = strings.TrimSpace(documentNumber)
+	verification.DocumentImagePath = fmt.Sprintf("%s%d/%s%s", verificationDocumentKeyPrefix, userID, token, extension)
+	verification.Status = entity.VerificationStatusPending
+	if err := verification.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVerification, err)
+	}
+
+	if err := s.files.Put(ctx, verification.DocumentImagePath, document, mimeType); err != nil {
+		return nil, fmt.Errorf("儲存驗證文件失敗: %w", err)
+	}
+
+	if verification.ID == 0 {
+		err = s.ageVerificationRepo.Create(ctx, verification)
+	} else {
+		err = s.ageVerificationRepo.Update(ctx, verification)
+	}
+	if err != nil {
+		s.removeVerificationDocument(verification.DocumentImagePath)
+		return nil, fmt.Errorf("保存年齡驗證失敗: %w", err)
+	}
+
+	if strings.HasPrefix(previousPath, verificationDocumentKeyPrefix) {
+		s.removeVerificationDocument(previousPath)
+	}
+	return verification, nil
+}
+
+// IsRestricted 檢查檔案是否為年齡驗證文件
+// 實作 FileAccessChecker
+func (s *UserService) IsRestricted(key string) bool {
+	return strings.HasPrefix(key, verificationDocumentKeyPrefix)
+}
+
+// CanAccess 檢查用戶是否可以下載年齡驗證文件，只有本人與具內容審核權限的人員可以下載
+// 實作 FileAccessChecker
+func (s *UserService) CanAccess(ctx context.Context, key string, viewerID uint) (bool, error) {
+	rest := strings.TrimPrefix(key, verificationDocumentKeyPrefix)
+	owner, _, found := strings.Cut(rest, "/")
+	if !found || rest == key {
+		return false, nil
+	}
+	ownerID, err := strconv.ParseUint(owner, 10, 32)
+	if err != nil || ownerID == 0 {
+		return false, nil
+	}
+	if uint(ownerID) == viewerID {
+		return true, nil
+	}
+
+	viewer, err := s.userRepo.GetByID(ctx, viewerID)
+	if err != nil || viewer == nil {
+		return false, nil
+	}
+	return viewer.IsActive && viewer.HasPermission(entity.PermissionContentReview), nil
+}
+
+// removeVerificationDocument 刪除驗證文件，失敗時只記錄日誌
+func (s *UserService) removeVerificationDocument(path string) {
+	if err := s.files.RemoveFile(path); err != nil {
+		log.Printf("刪除驗證文件失敗 (%s): %v", path, err)
+	}
+}
@@ -0,0 +1,149 @@
+package breach
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 外洩密碼檢查
+// 採用與 Have I Been Pwned 相同的 k-anonymity 分組方式：以密碼 SHA-1 雜湊的前 5 碼
+// 取得同組的雜湊後綴，再於本地比對完整雜湊。所有清單都在本機，檢查時不需要網路請求
+
+// prefixLength 雜湊前綴長度
+const prefixLength = 5
+
+// bundledHashes 隨程式內建的常見外洩密碼清單，每行一個大寫 SHA-1 雜湊
+//
+//go:embed data/sha1.txt
+var bundledHashes string
+
+// RangeSource 依雜湊前綴返回同組雜湊後綴的清單來源
+type RangeSource interface {
+	Range(prefix string) ([]string, error)
+}
+
+// Checker 外洩密碼檢查器，依序查詢所有清單來源
+type Checker struct {
+	sources []RangeSource
+}
+
+// NewChecker 建立外洩密碼檢查器
+func NewChecker(sources ...RangeSource) *Checker {
+	return &Checker{sources: sources}
+}
+
+// NewDefaultChecker 建立使用內建清單的檢查器，dir 不為空時一併查詢該目錄下的完整清單
+func NewDefaultChecker(dir string) (*Checker, error) {
+	sources := []RangeSource{NewBundledSource()}
+	if dir != "" {
+		source, err := NewDirectorySource(dir)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+	return NewChecker(sources...), nil
+}
+
+// IsBreached 判斷密碼是否出現在任一外洩清單中
+func (c *Checker) IsBreached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+	for _, source := range c.sources {
+		suffixes, err := source.Range(prefix)
+		if err != nil {
+			return false, err
+		}
+		for _, candidate := range suffixes {
+			if candidate == suffix {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// BundledSource 內建清單，啟動時依前綴分組載入記憶體
+type BundledSource struct {
+	ranges map[string][]string
+}
+
+// NewBundledSource 建立內建清單來源
+func NewBundledSource() *BundledSource {
+	return &BundledSource{ranges: groupHashes(bundledHashes)}
+}
+
+// Range 返回前綴對應的雜湊後綴
+func (s *BundledSource) Range(prefix string) ([]string, error) {
+	return s.ranges[strings.ToUpper(prefix)], nil
+}
+
+// groupHashes 將完整雜湊清單依前綴分組
+func groupHashes(data string) map[string][]string {
+	ranges := make(map[string][]string)
+	for _, line := range strings.Fields(data) {
+		if len(line) != sha1.Size*2 {
+			continue
+		}
+		line = strings.ToUpper(line)
+		ranges[line[:prefixLength]] = append(ranges[line[:prefixLength]], line[prefixLength:])
+	}
+	return ranges
+}
+
+// DirectorySource 讀取目錄中依前綴分檔的完整清單
+// 檔案格式與 HIBP 官方下載工具輸出相同：<PREFIX>.txt，每行為「後綴:出現次數」
+type DirectorySource struct {
+	dir string
+}
+
+// NewDirectorySource 建立目錄清單來源
+func NewDirectorySource(dir string) (*DirectorySource, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("外洩密碼清單目錄無法讀取: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("外洩密碼清單路徑不是目錄: %s", dir)
+	}
+	return &DirectorySource{dir: dir}, nil
+}
+
+// Range 讀取前綴對應的檔案，檔案不存在視為該組沒有外洩記錄
+func (s *DirectorySource) Range(prefix string) ([]string, error) {
+	prefix = strings.ToUpper(prefix)
+	if len(prefix) != prefixLength || strings.Trim(prefix, "0123456789ABCDEF") != "" {
+		return nil, fmt.Errorf("無效的雜湊前綴: %s", prefix)
+	}
+
+	file, err := os.Open(filepath.Join(s.dir, prefix+".txt"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("讀取外洩密碼清單失敗: %w", err)
+	}
+	defer file.Close()
+
+	var suffixes []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		suffix, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if suffix != "" {
+			suffixes = append(suffixes, strings.ToUpper(suffix))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("讀取外洩密碼清單失敗: %w", err)
+	}
+	return suffixes, nil
+}
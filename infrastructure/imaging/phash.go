@@ -0,0 +1,33 @@
+package imaging
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// dHash 感知雜湊
+// 將圖片縮成 9x8 灰階後逐列比較相鄰像素的亮度，產生 64 位元雜湊。
+// 重新壓縮、縮放與輕微調色後雜湊幾乎不變，以漢明距離比較兩張圖片是否相同
+const (
+	dHashWidth  = 9
+	dHashHeight = 8
+)
+
+// DifferenceHash 計算圖片的 dHash，透明區域視為白色
+func DifferenceHash(img image.Image) uint64 {
+	// CatmullRom 縮小時會依比例擴大取樣範圍，等同區域平均，不會只取到少數像素
+	gray := image.NewGray(image.Rect(0, 0, dHashWidth, dHashHeight))
+	draw.CatmullRom.Scale(gray, gray.Bounds(), flatten(img), img.Bounds(), draw.Src, nil)
+
+	var hash uint64
+	for y := 0; y < dHashHeight; y++ {
+		for x := 0; x < dHashWidth-1; x++ {
+			hash <<= 1
+			if gray.GrayAt(x, y).Y < gray.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
@@ -0,0 +1,192 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // 註冊 GIF 解碼器
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // 註冊 WebP 解碼器
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+)
+
+// 圖片處理
+// 所有圖片都會解碼後重新編碼，Go 的編碼器不寫入任何中繼資料，因此 EXIF（含 GPS 位置）、
+// XMP 與 ICC 等區段不會保留。JPEG 的 EXIF 方向在移除前先套用到像素上。
+// JPEG 與 WebP 輸出為 JPEG（標準函式庫沒有 WebP 編碼器），PNG 與 GIF 輸出為 PNG 以保留透明度，
+// GIF 動畫只保留第一格
+
+// Config 圖片處理設定
+type Config struct {
+	MaxPixels    int `yaml:"max_pixels"`    // 解碼前檢查的像素上限，防止解壓縮炸彈
+	MaxDimension int `yaml:"max_dimension"` // 原圖最長邊，超過時等比例縮小
+	JPEGQuality  int `yaml:"jpeg_quality"`
+}
+
+// DefaultConfig 預設圖片處理設定
+func DefaultConfig() Config {
+	return Config{
+		MaxPixels:    50_000_000,
+		MaxDimension: 2048,
+		JPEGQuality:  85,
+	}
+}
+
+// Processor 圖片處理器
+// 實作 usecase.ImageProcessor
+type Processor struct {
+	config Config
+}
+
+// NewProcessor 創建圖片處理器，未設定的項目使用預設值
+func NewProcessor(config Config) *Processor {
+	defaults := DefaultConfig()
+	if config.MaxPixels <= 0 {
+		config.MaxPixels = defaults.MaxPixels
+	}
+	if config.MaxDimension <= 0 {
+		config.MaxDimension = defaults.MaxDimension
+	}
+	if config.JPEGQuality <= 0 || config.JPEGQuality > 100 {
+		config.JPEGQuality = defaults.JPEGQuality
+	}
+	return &Processor{config: config}
+}
+
+// formatMimeTypes 解碼器格式名稱對應的 MIME 類型
+var formatMimeTypes = map[string]string{
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"gif":  "image/gif",
+	"webp": "image/webp",
+}
+
+// Process 解碼圖片、去除中繼資料並產生縮圖
+func (p *Processor) Process(data []byte, mimeType string) (*usecase.ProcessedImage, error) {
+	// 先讀取尺寸，避免解碼過大的圖片耗盡記憶體
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", usecase.ErrInvalidImage, err)
+	}
+	if formatMimeTypes[format] != normalizeMimeType(mimeType) {
+		return nil, fmt.Errorf("%w: 檔案內容為 %s", usecase.ErrUnsupportedImageType, format)
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, fmt.Errorf("%w: 圖片尺寸無效", usecase.ErrInvalidImage)
+	}
+	if config.Width*config.Height > p.config.MaxPixels {
+		return nil, fmt.Errorf("%w: 圖片尺寸 %dx%d 超過上限", usecase.ErrInvalidImage, config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", usecase.ErrInvalidImage, err)
+	}
+
+	// 等比例縮放不影響方向，先縮小再旋轉可減少旋轉的像素數
+	img = fit(img, p.config.MaxDimension)
+	if format == "jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	encode, outputType, extension := p.encoder(format)
+	encoded, err := encode(img)
+	if err != nil {
+		return nil, fmt.Errorf("編碼圖片失敗: %w", err)
+	}
+
+	processed := &usecase.ProcessedImage{
+		Data:           encoded,
+		MimeType:       outputType,
+		Extension:      extension,
+		Width:          img.Bounds().Dx(),
+		Height:         img.Bounds().Dy(),
+		PerceptualHash: DifferenceHash(img),
+		Thumbnails:     make(map[entity.ThumbnailSize][]byte, len(entity.ThumbnailSizes)),
+	}
+
+	for _, size := range entity.ThumbnailSizes {
+		thumbnail, err := encode(fit(img, size.MaxLength))
+		if err != nil {
+			return nil, fmt.Errorf("產生 %s 縮圖失敗: %w", size.Name, err)
+		}
+		processed.Thumbnails[size.Name] = thumbnail
+	}
+
+	return processed, nil
+}
+
+// encoder 依來源格式選擇輸出編碼器
+func (p *Processor) encoder(format string) (func(image.Image) ([]byte, error), string, string) {
+	switch format {
+	case "png", "gif":
+		return encodePNG, "image/png", ".png"
+	default:
+		return func(img image.Image) ([]byte, error) {
+			return encodeJPEG(img, p.config.JPEGQuality)
+		}, "image/jpeg", ".jpg"
+	}
+}
+
+// normalizeMimeType 將 image/jpg 統一為 image/jpeg
+func normalizeMimeType(mimeType string) string {
+	if mimeType == "image/jpg" {
+		return "image/jpeg"
+	}
+	return mimeType
+}
+
+// fit 將圖片等比例縮小到最長邊不超過 maxLength，較小的圖片不放大
+func fit(img image.Image, maxLength int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxLength && height <= maxLength {
+		return img
+	}
+
+	if width >= height {
+		height = max(1, height*maxLength/width)
+		width = maxLength
+	} else {
+		width = max(1, width*maxLength/height)
+		height = maxLength
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// flatten 將透明區域以白色填滿
+func flatten(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	flattened := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(flattened, flattened.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flattened, flattened.Bounds(), img, bounds.Min, draw.Over)
+	return flattened
+}
+
+// encodeJPEG 以 JPEG 編碼，透明區域以白色填滿
+func encodeJPEG(img image.Image, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flatten(img), &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodePNG 以 PNG 編碼
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
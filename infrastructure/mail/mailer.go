@@ -0,0 +1,135 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"mime"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang_dev_docker/domain/usecase"
+)
+
+// MailConfig 郵件服務配置
+type MailConfig struct {
+	Driver      string // smtp, log
+	SMTPHost    string
+	SMTPPort    int
+	Username    string
+	Password    string
+	FromAddress string
+	OutputDir   string // log 驅動時將郵件寫入的目錄，留空則只寫入日誌
+}
+
+// NewMailer 依配置建立郵件發送器
+func NewMailer(config *MailConfig) (usecase.Mailer, error) {
+	switch config.Driver {
+	case "smtp":
+		if config.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP 主機未配置")
+		}
+		return NewSMTPMailer(config), nil
+	case "", "log":
+		return NewLogMailer(config.OutputDir), nil
+	default:
+		return nil, fmt.Errorf("不支援的郵件驅動: %s", config.Driver)
+	}
+}
+
+// SMTPMailer SMTP 郵件發送器
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer 建立 SMTP 郵件發送器
+func NewSMTPMailer(config *MailConfig) *SMTPMailer {
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.SMTPHost)
+	}
+
+	return &SMTPMailer{
+		addr: fmt.Sprintf("%s:%d", config.SMTPHost, config.SMTPPort),
+		auth: auth,
+		from: config.FromAddress,
+	}
+}
+
+// Send 透過 SMTP 發送郵件
+func (m *SMTPMailer) Send(ctx context.Context, msg *usecase.EmailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, buildMessage(m.from, msg)); err != nil {
+		return fmt.Errorf("SMTP 發送郵件失敗: %w", err)
+	}
+	return nil
+}
+
+// LogMailer 本地開發用郵件發送器
+// 不實際寄出郵件，而是寫入日誌，並可選擇寫入檔案方便查看
+type LogMailer struct {
+	outputDir string
+}
+
+// NewLogMailer 建立本地郵件發送器
+func NewLogMailer(outputDir string) *LogMailer {
+	return &LogMailer{outputDir: outputDir}
+}
+
+// Send 將郵件寫入日誌或檔案
+func (m *LogMailer) Send(ctx context.Context, msg *usecase.EmailMessage) error {
+	log.Printf("[mail] 收件人: %s 主旨: %s\n%s", msg.To, msg.Subject, msg.Body)
+
+	if m.outputDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(m.outputDir, 0o755); err != nil {
+		return fmt.Errorf("建立郵件輸出目錄失敗: %w", err)
+	}
+
+	fileName := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405.000000000"), sanitizeFileName(msg.To))
+	path := filepath.Join(m.outputDir, fileName)
+	if err := os.WriteFile(path, buildMessage("noreply@localhost", msg), 0o600); err != nil {
+		return fmt.Errorf("寫入郵件檔案失敗: %w", err)
+	}
+	return nil
+}
+
+// buildMessage 組裝 RFC 5322 格式的純文字郵件
+func buildMessage(from string, msg *usecase.EmailMessage) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + stripCRLF(from) + "\r\n")
+	b.WriteString("To: " + stripCRLF(msg.To) + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("UTF-8", stripCRLF(msg.Subject)) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+	return []byte(b.String())
+}
+
+// stripCRLF 移除換行字元，避免郵件標頭注入
+func stripCRLF(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+// sanitizeFileName 將收件人地址轉為安全的檔名
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
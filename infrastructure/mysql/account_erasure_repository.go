@@ -0,0 +1,167 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// erasedBirthDate 匿名化後的出生日期佔位值（欄位不可為空）
+var erasedBirthDate = time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// erasedText 清除後取代檢舉與審核日誌自由文字的佔位值
+const erasedText = "[已清除]"
+
+// MySQLAccountErasureRepository MySQL 帳號資料清除儲存庫實作
+type MySQLAccountErasureRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountErasureRepository 創建新的 MySQL 帳號資料清除儲存庫
+func NewAccountErasureRepository(db *gorm.DB) repository.AccountErasureRepository {
+	return &MySQLAccountErasureRepository{db: db}
+}
+
+// EraseUser 清除用戶個人資料
+// 檢舉與審核日誌不刪除，保留 ID、類別與處理動作並透過匿名化的用戶列關聯，自由文字則以佔位值取代
+func (r *MySQLAccountErasureRepository) EraseUser(ctx context.Context, userID uint, erasedAt time.Time) (*repository.ErasureResult, error) {
+	result := &repository.ErasureResult{}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 先收集需要刪除的檔案路徑與配對
+		var photos []*entity.Photo
+		if err := tx.Select("file_path", "thumbnails").Where("user_id = ?", userID).Find(&photos).Error; err != nil {
+			return fmt.Errorf("查詢用戶照片失敗: %w", err)
+		}
+		for _, photo := range photos {
+			result.FilePaths = append(result.FilePaths, photo.FilePaths()...)
+		}
+
+		var documentPaths []string
+		if err := tx.Model(&entity.AgeVerification{}).Where("user_id = ?", userID).Pluck("document_image_path", &documentPaths).Error; err != nil {
+			return fmt.Errorf("查詢年齡驗證文件失敗: %w", err)
+		}
+		result.FilePaths = append(result.FilePaths, documentPaths...)
+
+		if err := tx.Model(&entity.Match{}).Where("user1_id = ? OR user2_id = ?", userID, userID).Pluck("id", &result.MatchIDs).Error; err != nil {
+			return fmt.Errorf("查詢用戶配對失敗: %w", err)
+		}
+
+		// 訊息以寄件者、收件者或所屬配對判斷，涵蓋對方在配對中傳送的訊息
+		messageScope := "sender_id = ? OR receiver_id = ?"
+		messageArgs := []interface{}{userID, userID}
+		if len(result.MatchIDs) > 0 {
+			messageScope += " OR match_id IN ?"
+			messageArgs = append(messageArgs, result.MatchIDs)
+		}
+
+		var attachmentPaths []string
+		if err := tx.Model(&entity.ChatMessage{}).
+			Where("("+messageScope+") AND file_path IS NOT NULL AND file_path <> ''", messageArgs...).
+			Pluck("file_path", &attachmentPaths).Error; err != nil {
+			return fmt.Errorf("查詢聊天附件失敗: %w", err)
+		}
+		result.FilePaths = append(result.FilePaths, attachmentPaths...)
+
+		// 刪除聊天訊息與配對
+		if err := tx.Where(messageScope, messageArgs...).Delete(&entity.ChatMessage{}).Error; err != nil {
+			return fmt.Errorf("刪除聊天訊息失敗: %w", err)
+		}
+		if err := tx.Where("user1_id = ? OR user2_id = ?", userID, userID).Delete(&entity.Match{}).Error; err != nil {
+			return fmt.Errorf("刪除配對記錄失敗: %w", err)
+		}
+
+		// 刪除檔案、照片、興趣與驗證資料
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.Photo{}).Error; err != nil {
+			return fmt.Errorf("刪除用戶照片失敗: %w", err)
+		}
+		if err := tx.Where("owner_id = ? OR viewer_id = ?", userID, userID).Delete(&entity.PrivateAlbumGrant{}).Error; err != nil {
+			return fmt.Errorf("刪除私人相簿授權失敗: %w", err)
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.UserProfile{}).Error; err != nil {
+			return fmt.Errorf("刪除用戶檔案失敗: %w", err)
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.AttributePreference{}).Error; err != nil {
+			return fmt.Errorf("刪除屬性配對條件失敗: %w", err)
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.ProfilePrompt{}).Error; err != nil {
+			return fmt.Errorf("刪除檔案問答失敗: %w", err)
+		}
+		if err := tx.Exec("UPDATE interests SET usage_count = usage_count - 1 WHERE usage_count > 0 AND id IN (SELECT interest_id FROM user_interests WHERE user_id = ?)", userID).Error; err != nil {
+			return fmt.Errorf("更新興趣使用人數失敗: %w", err)
+		}
+		if err := tx.Exec("DELETE FROM user_interests WHERE user_id = ?", userID).Error; err != nil {
+			return fmt.Errorf("刪除興趣關聯失敗: %w", err)
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.AgeVerification{}).Error; err != nil {
+			return fmt.Errorf("刪除年齡驗證記錄失敗: %w", err)
+		}
+		if err := tx.Where("blocker_id = ? OR blocked_id = ?", userID, userID).Delete(&entity.Block{}).Error; err != nil {
+			return fmt.Errorf("刪除封鎖記錄失敗: %w", err)
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error; err != nil {
+			return fmt.Errorf("刪除恢復碼失敗: %w", err)
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.TwoFactorCredential{}).Error; err != nil {
+			return fmt.Errorf("刪除兩步驟驗證設定失敗: %w", err)
+		}
+		if err := tx.Exec("DELETE FROM websocket_connections WHERE user_id = ?", userID).Error; err != nil {
+			return fmt.Errorf("刪除連線記錄失敗: %w", err)
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.UserIdentity{}).Error; err != nil {
+			return fmt.Errorf("刪除外部身分連結失敗: %w", err)
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.LoginAttempt{}).Error; err != nil {
+			return fmt.Errorf("刪除登入記錄失敗: %w", err)
+		}
+
+		if err := tx.Model(&entity.DataExport{}).Where("user_id = ? AND file_name <> ''", userID).Pluck("file_name", &result.Archives).Error; err != nil {
+			return fmt.Errorf("查詢資料匯出檔案失敗: %w", err)
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.DataExport{}).Error; err != nil {
+			return fmt.Errorf("刪除資料匯出記錄失敗: %w", err)
+		}
+
+		// 檢舉說明（含證據）與審核備註可能記載個人資料，清除文字但保留記錄供統計與稽核
+		if err := tx.Model(&entity.Report{}).
+			Where("reporter_id = ? OR reported_id = ?", userID, userID).
+			Updates(map[string]interface{}{"description": erasedText, "review_notes": nil}).Error; err != nil {
+			return fmt.Errorf("清除檢舉內容失敗: %w", err)
+		}
+		if err := tx.Table("moderation_logs").
+			Where("user_id = ?", userID).
+			Updates(map[string]interface{}{"reason": erasedText, "notes": erasedText}).Error; err != nil {
+			return fmt.Errorf("清除審核日誌內容失敗: %w", err)
+		}
+
+		// 匿名化用戶列，保留 ID 與停權狀態供檢舉與審核日誌關聯
+		updates := map[string]interface{}{
+			"email":                 fmt.Sprintf("deleted-%d@erased.invalid", userID),
+			"password_hash":         "",
+			"birth_date":            erasedBirthDate,
+			"is_active":             false,
+			"is_verified":           false,
+			"email_verified":        false,
+			"email_verified_at":     nil,
+			"role":                  entity.RoleUser,
+			"deletion_scheduled_at": nil,
+			"erased_at":             erasedAt,
+		}
+		if err := tx.Model(&entity.User{}).Where("id = ?", userID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("匿名化用戶資料失敗: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("清除用戶 %d 資料失敗: %w", userID, err)
+	}
+
+	return result, nil
+}
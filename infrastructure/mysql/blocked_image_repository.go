@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// MySQLBlockedImageRepository MySQL 禁用圖片清單儲存庫實作
+type MySQLBlockedImageRepository struct {
+	db *gorm.DB
+}
+
+// NewBlockedImageRepository 創建新的 MySQL 禁用圖片清單儲存庫
+func NewBlockedImageRepository(db *gorm.DB) repository.BlockedImageRepository {
+	return &MySQLBlockedImageRepository{db: db}
+}
+
+// Create 加入禁用圖片
+func (r *MySQLBlockedImageRepository) Create(ctx context.Context, image *entity.BlockedImage) error {
+	image.SyncHashBands()
+	if err := r.db.WithContext(ctx).Create(image).Error; err != nil {
+		return fmt.Errorf("加入禁用圖片失敗: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根據 ID 獲取禁用圖片
+func (r *MySQLBlockedImageRepository) GetByID(ctx context.Context, id uint) (*entity.BlockedImage, error) {
+	var image entity.BlockedImage
+	if err := r.db.WithContext(ctx).First(&image, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢禁用圖片失敗: %w", err)
+	}
+	return &image, nil
+}
+
+// List 分頁獲取禁用圖片
+func (r *MySQLBlockedImageRepository) List(ctx context.Context, limit, offset int) ([]*entity.BlockedImage, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&entity.BlockedImage{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("計算禁用圖片數量失敗: %w", err)
+	}
+
+	query := r.db.WithContext(ctx).Order("created_at DESC").Order("id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var images []*entity.BlockedImage
+	if err := query.Find(&images).Error; err != nil {
+		return nil, 0, fmt.Errorf("獲取禁用圖片失敗: %w", err)
+	}
+	return images, total, nil
+}
+
+// Delete 移除禁用圖片
+func (r *MySQLBlockedImageRepository) Delete(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Delete(&entity.BlockedImage{}, id).Error; err != nil {
+		return fmt.Errorf("移除禁用圖片失敗: %w", err)
+	}
+	return nil
+}
+
+// FindSimilar 找出感知雜湊相近的禁用圖片
+func (r *MySQLBlockedImageRepository) FindSimilar(ctx context.Context, hash uint64, maxDistance int) ([]*entity.BlockedImage, error) {
+	var images []*entity.BlockedImage
+	if err := whereSimilarHash(r.db.WithContext(ctx), hash, maxDistance).
+		Order(gorm.Expr("BIT_COUNT(perceptual_hash ^ ?)", hash)).
+		Find(&images).Error; err != nil {
+		return nil, fmt.Errorf("查詢相似禁用圖片失敗: %w", err)
+	}
+	return images, nil
+}
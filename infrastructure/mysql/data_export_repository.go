@@ -0,0 +1,187 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// MySQLDataExportRepository MySQL 個人資料匯出記錄儲存庫實作
+type MySQLDataExportRepository struct {
+	db *gorm.DB
+}
+
+// NewDataExportRepository 創建新的 MySQL 個人資料匯出記錄儲存庫
+func NewDataExportRepository(db *gorm.DB) repository.DataExportRepository {
+	return &MySQLDataExportRepository{db: db}
+}
+
+// Create 創建匯出記錄
+func (r *MySQLDataExportRepository) Create(ctx context.Context, export *entity.DataExport) error {
+	if err := r.db.WithContext(ctx).Create(export).Error; err != nil {
+		return fmt.Errorf("創建匯出記錄失敗: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根據 ID 獲取匯出記錄，不存在時返回 nil
+func (r *MySQLDataExportRepository) GetByID(ctx context.Context, id uint) (*entity.DataExport, error) {
+	var export entity.DataExport
+	if err := r.db.WithContext(ctx).First(&export, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢匯出記錄失敗: %w", err)
+	}
+	return &export, nil
+}
+
+// GetLatestByUserID 獲取用戶最近一次匯出記錄，不存在時返回 nil
+func (r *MySQLDataExportRepository) GetLatestByUserID(ctx context.Context, userID uint) (*entity.DataExport, error) {
+	var export entity.DataExport
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC, id DESC").First(&export).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢匯出記錄失敗: %w", err)
+	}
+	return &export, nil
+}
+
+// claimableExport 等待中或處理中斷的匯出條件，未記錄開始時間的處理中記錄同樣視為中斷
+const claimableExport = "(status = ? OR (status = ? AND (processing_started_at IS NULL OR processing_started_at <= ?)))"
+
+// GetPending 獲取等待處理與處理中斷的匯出記錄
+func (r *MySQLDataExportRepository) GetPending(ctx context.Context, staleBefore time.Time, limit int) ([]*entity.DataExport, error) {
+	var exports []*entity.DataExport
+	if err := r.db.WithContext(ctx).
+		Where(claimableExport, entity.DataExportStatusPending, entity.DataExportStatusProcessing, staleBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&exports).Error; err != nil {
+		return nil, fmt.Errorf("獲取待處理匯出失敗: %w", err)
+	}
+	return exports, nil
+}
+
+// ClaimPending 以條件更新認領等待中或處理中斷的匯出，並發執行時只有一個會成功
+func (r *MySQLDataExportRepository) ClaimPending(ctx context.Context, id uint, now, staleBefore time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.DataExport{}).
+		Where("id = ? AND "+claimableExport, id, entity.DataExportStatusPending, entity.DataExportStatusProcessing, staleBefore).
+		Updates(map[string]interface{}{
+			"status":                entity.DataExportStatusProcessing,
+			"processing_started_at": now,
+			"attempts":              gorm.Expr("attempts + 1"),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("認領匯出工作失敗: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Update 更新匯出記錄
+func (r *MySQLDataExportRepository) Update(ctx context.Context, export *entity.DataExport) error {
+	if err := r.db.WithContext(ctx).Save(export).Error; err != nil {
+		return fmt.Errorf("更新匯出記錄失敗: %w", err)
+	}
+	return nil
+}
+
+// GetExpired 獲取下載期限已過且封存檔尚未刪除的匯出記錄
+func (r *MySQLDataExportRepository) GetExpired(ctx context.Context, now time.Time, limit int) ([]*entity.DataExport, error) {
+	var exports []*entity.DataExport
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at <= ?", entity.DataExportStatusCompleted, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&exports).Error; err != nil {
+		return nil, fmt.Errorf("獲取過期匯出失敗: %w", err)
+	}
+	return exports, nil
+}
+
+// MySQLPersonalDataRepository MySQL 個人資料讀取儲存庫實作
+type MySQLPersonalDataRepository struct {
+	db *gorm.DB
+}
+
+// NewPersonalDataRepository 創建新的 MySQL 個人資料讀取儲存庫
+func NewPersonalDataRepository(db *gorm.DB) repository.PersonalDataRepository {
+	return &MySQLPersonalDataRepository{db: db}
+}
+
+// CollectUserData 收集用戶個人資料
+// 在同一交易中查詢，確保各資料表內容一致
+func (r *MySQLPersonalDataRepository) CollectUserData(ctx context.Context, userID uint) (*repository.PersonalData, error) {
+	data := &repository.PersonalData{}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return fmt.Errorf("查詢用戶失敗: %w", err)
+		}
+		data.User = &user
+
+		var profile entity.UserProfile
+		if err := tx.Where("user_id = ?", userID).First(&profile).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("查詢用戶檔案失敗: %w", err)
+			}
+		} else {
+			data.Profile = &profile
+		}
+
+		if err := tx.Where("user_id = ?", userID).Order("id").Find(&data.AttributePreferences).Error; err != nil {
+			return fmt.Errorf("查詢屬性配對條件失敗: %w", err)
+		}
+
+		if err := tx.Preload("Prompt.Translations").Where("user_id = ?", userID).Order("display_order").Find(&data.Prompts).Error; err != nil {
+			return fmt.Errorf("查詢檔案問答失敗: %w", err)
+		}
+
+		if err := tx.Where("user_id = ?", userID).Order("display_order").Find(&data.Photos).Error; err != nil {
+			return fmt.Errorf("查詢用戶照片失敗: %w", err)
+		}
+
+		if err := tx.Table("interests").
+			Select("interests.*").
+			Joins("INNER JOIN user_interests ON interests.id = user_interests.interest_id").
+			Where("user_interests.user_id = ?", userID).
+			Find(&data.Interests).Error; err != nil {
+			return fmt.Errorf("查詢用戶興趣失敗: %w", err)
+		}
+
+		if err := tx.Where("user1_id = ? OR user2_id = ?", userID, userID).Order("created_at").Find(&data.Matches).Error; err != nil {
+			return fmt.Errorf("查詢配對記錄失敗: %w", err)
+		}
+
+		if err := tx.Where("sender_id = ? OR receiver_id = ?", userID, userID).Order("created_at").Find(&data.Messages).Error; err != nil {
+			return fmt.Errorf("查詢聊天訊息失敗: %w", err)
+		}
+
+		if err := tx.Where("owner_id = ?", userID).Order("created_at").Find(&data.Grants).Error; err != nil {
+			return fmt.Errorf("查詢私人相簿授權失敗: %w", err)
+		}
+
+		if err := tx.Where("blocker_id = ?", userID).Order("created_at").Find(&data.Blocks).Error; err != nil {
+			return fmt.Errorf("查詢封鎖記錄失敗: %w", err)
+		}
+
+		if err := tx.Where("reporter_id = ?", userID).Order("created_at").Find(&data.Reports).Error; err != nil {
+			return fmt.Errorf("查詢檢舉記錄失敗: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("收集用戶 %d 個人資料失敗: %w", userID, err)
+	}
+
+	return data, nil
+}
@@ -13,8 +13,16 @@ func AutoMigrateEntities(db *gorm.DB) error {
 		// 用戶相關實體
 		&entity.User{},
 		&entity.UserProfile{},
+		&entity.AttributePreference{},
+		&entity.Prompt{},
+		&entity.PromptTranslation{},
+		&entity.ProfilePrompt{},
 		&entity.Photo{},
+		&entity.BlockedImage{},
+		&entity.PrivateAlbumGrant{},
 		&entity.Interest{},
+		&entity.InterestTranslation{},
+		&entity.InterestSynonym{},
 		&entity.AgeVerification{},
 
 		// 配對相關實體
@@ -26,14 +34,33 @@ func AutoMigrateEntities(db *gorm.DB) error {
 		// 檢舉和封鎖實體
 		&entity.Report{},
 		&entity.Block{},
+
+		// 兩步驟驗證實體
+		&entity.TwoFactorCredential{},
+		&entity.RecoveryCode{},
+
+		// 個人資料匯出實體
+		&entity.DataExport{},
+
+		// 登入嘗試記錄實體
+		&entity.LoginAttempt{},
+
+		// 外部身分連結實體
+		&entity.UserIdentity{},
 	}
 
+	backfills := pendingBackfills(db)
+
 	for _, entity := range entities {
 		if err := db.AutoMigrate(entity); err != nil {
 			return err
 		}
 	}
 
+	if err := runBackfills(db, backfills); err != nil {
+		return err
+	}
+
 	// 創建關聯表
 	if err := createAssociationTables(db); err != nil {
 		return err
@@ -182,6 +209,11 @@ func createIndexes(db *gorm.DB) error {
 // DropAllTables 刪除所有表（用於測試或重置）
 func DropAllTables(db *gorm.DB) error {
 	tables := []string{
+		"user_identities",
+		"login_attempts",
+		"data_exports",
+		"user_recovery_codes",
+		"user_two_factor_credentials",
 		"moderation_logs",
 		"websocket_connections",
 		"user_interests",
@@ -190,8 +222,16 @@ func DropAllTables(db *gorm.DB) error {
 		"chat_messages",
 		"matches",
 		"age_verifications",
+		"private_album_grants",
+		"blocked_images",
 		"photos",
+		"interest_synonyms",
+		"interest_translations",
 		"interests",
+		"profile_prompts",
+		"prompt_translations",
+		"prompts",
+		"user_attribute_preferences",
 		"user_profiles",
 		"users",
 	}
@@ -283,6 +323,8 @@ func ValidateDatabase(db *gorm.DB) error {
 		"users",
 		"user_profiles",
 		"photos",
+		"interest_synonyms",
+		"interest_translations",
 		"interests",
 		"age_verifications",
 		"matches",
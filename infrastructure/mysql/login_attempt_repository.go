@@ -0,0 +1,39 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// MySQLLoginAttemptRepository MySQL 登入嘗試記錄儲存庫實作
+type MySQLLoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository 創建新的 MySQL 登入嘗試記錄儲存庫
+func NewLoginAttemptRepository(db *gorm.DB) repository.LoginAttemptRepository {
+	return &MySQLLoginAttemptRepository{db: db}
+}
+
+// Create 記錄一次登入嘗試
+func (r *MySQLLoginAttemptRepository) Create(ctx context.Context, attempt *entity.LoginAttempt) error {
+	if err := r.db.WithContext(ctx).Create(attempt).Error; err != nil {
+		return fmt.Errorf("記錄登入嘗試失敗: %w", err)
+	}
+	return nil
+}
+
+// DeleteBefore 刪除指定時間之前的記錄
+func (r *MySQLLoginAttemptRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&entity.LoginAttempt{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("清除登入嘗試記錄失敗: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
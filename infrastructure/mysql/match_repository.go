@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	"golang_dev_docker/domain/entity"
@@ -200,12 +201,34 @@ func NewMatchingAlgorithmRepository(db *gorm.DB) repository.MatchingAlgorithmRep
 }
 
 // GetPotentialMatches 獲取潛在配對對象
+// 配對偏好雙向檢查：對方必須符合查詢者的性別、年齡與距離偏好，查詢者也必須符合對方檔案中的偏好
 func (r *MySQLMatchingAlgorithmRepository) GetPotentialMatches(ctx context.Context, userID uint, params repository.PotentialMatchParams) ([]*entity.User, error) {
 	query := r.db.WithContext(ctx).
 		Table("users").
 		Select("DISTINCT users.*").
 		Joins("INNER JOIN user_profiles ON users.id = user_profiles.user_id").
-		Where("users.id != ? AND users.is_active = ? AND users.is_verified = ?", userID, true, true)
+		Joins("INNER JOIN users AS seeker ON seeker.id = ?", userID).
+		Joins("INNER JOIN user_profiles AS seeker_profile ON seeker_profile.user_id = seeker.id").
+		Where("users.id != ? AND users.is_active = ? AND users.is_verified = ? AND users.registration_pending = ? AND users.account_status = ?", userID, true, true, false, entity.AccountStatusActive)
+
+	// 性別偏好（雙向），空字串表示不限
+	query = query.
+		Where("(seeker_profile.interested_in = '' OR FIND_IN_SET(user_profiles.gender, seeker_profile.interested_in) > 0)").
+		Where("(user_profiles.interested_in = '' OR FIND_IN_SET(seeker_profile.gender, user_profiles.interested_in) > 0)")
+
+	// 查詢者的年齡必須在對方的年齡範圍內
+	query = query.Where("YEAR(NOW()) - YEAR(seeker.birth_date) BETWEEN user_profiles.age_range_min AND user_profiles.age_range_max")
+
+	// 查詢者有位置時，距離也必須在對方設定的範圍內
+	query = query.Where(`
+		(
+			seeker_profile.location_lat IS NULL OR seeker_profile.location_lng IS NULL OR
+			ST_Distance_Sphere(
+				POINT(user_profiles.location_lng, user_profiles.location_lat),
+				POINT(seeker_profile.location_lng, seeker_profile.location_lat)
+			) <= user_profiles.max_distance * 1000
+		)
+	`)
 
 	// 排除已滑動過的用戶
 	if params.ExcludeSwipedUsers {
@@ -256,9 +279,14 @@ func (r *MySQLMatchingAlgorithmRepository) GetPotentialMatches(ctx context.Conte
 		query = query.Where("YEAR(NOW()) - YEAR(users.birth_date) <= ?", *params.MaxAge)
 	}
 
-	// 性別偏好
-	if params.PreferredGender != nil {
-		query = query.Where("user_profiles.gender = ?", *params.PreferredGender)
+	// 本次查詢額外限定的性別
+	if len(params.PreferredGenders) > 0 {
+		query = query.Where("user_profiles.gender IN ?", params.PreferredGenders)
+	}
+
+	// 屬性硬性條件
+	for _, dealbreaker := range params.Dealbreakers {
+		query = applyDealbreaker(query, dealbreaker)
 	}
 
 	// 共同興趣篩選
@@ -295,6 +323,52 @@ func (r *MySQLMatchingAlgorithmRepository) GetPotentialMatches(ctx context.Conte
 	return users, nil
 }
 
+// dealbreakerColumns 可作為硬性條件的屬性與對應欄位
+var dealbreakerColumns = map[entity.ProfileAttribute]string{
+	entity.AttributeHeight:           "user_profiles.height",
+	entity.AttributeEducation:        "user_profiles.education",
+	entity.AttributeRelationshipGoal: "user_profiles.relationship_goal",
+	entity.AttributeChildren:         "user_profiles.children",
+	entity.AttributeSmoking:          "user_profiles.smoking",
+	entity.AttributeDrinking:         "user_profiles.drinking",
+	entity.AttributeReligion:         "user_profiles.religion",
+	entity.AttributeLanguages:        "user_profiles.languages",
+}
+
+// applyDealbreaker 將屬性硬性條件加入查詢，規則與 entity.AttributePreference.Accepts 一致
+// 對方將該屬性設為隱藏或未填寫時不會出現在結果中
+func applyDealbreaker(query *gorm.DB, dealbreaker *entity.AttributePreference) *gorm.DB {
+	column, ok := dealbreakerColumns[dealbreaker.Attribute]
+	if !ok {
+		return query
+	}
+
+	query = query.Where("FIND_IN_SET(?, user_profiles.hidden_attributes) = 0", string(dealbreaker.Attribute))
+
+	switch dealbreaker.Attribute {
+	case entity.AttributeHeight:
+		query = query.Where(column + " > 0")
+		if dealbreaker.MinHeight > 0 {
+			query = query.Where(column+" >= ?", dealbreaker.MinHeight)
+		}
+		if dealbreaker.MaxHeight > 0 {
+			query = query.Where(column+" <= ?", dealbreaker.MaxHeight)
+		}
+	case entity.AttributeLanguages:
+		// 對方會說任一指定語言即可
+		conditions := make([]string, 0, len(dealbreaker.Values))
+		args := make([]interface{}, 0, len(dealbreaker.Values))
+		for _, language := range dealbreaker.Values {
+			conditions = append(conditions, "FIND_IN_SET(?, "+column+") > 0")
+			args = append(args, language)
+		}
+		query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	default:
+		query = query.Where(column+" IN ?", []string(dealbreaker.Values))
+	}
+	return query
+}
+
 // GetUsersNearby 獲取附近的用戶
 func (r *MySQLMatchingAlgorithmRepository) GetUsersNearby(ctx context.Context, userID uint, lat, lng float64, maxDistanceKm int, limit int) ([]*entity.User, error) {
 	var users []*entity.User
@@ -303,7 +377,7 @@ func (r *MySQLMatchingAlgorithmRepository) GetUsersNearby(ctx context.Context, u
 		Table("users").
 		Select("users.*, ST_Distance_Sphere(POINT(user_profiles.location_lng, user_profiles.location_lat), POINT(?, ?)) as distance", lng, lat).
 		Joins("INNER JOIN user_profiles ON users.id = user_profiles.user_id").
-		Where("users.id != ? AND users.is_active = ? AND users.is_verified = ?", userID, true, true).
+		Where("users.id != ? AND users.is_active = ? AND users.is_verified = ? AND users.registration_pending = ? AND users.account_status = ?", userID, true, true, false, entity.AccountStatusActive).
 		Where("user_profiles.location_lat IS NOT NULL AND user_profiles.location_lng IS NOT NULL").
 		Where("ST_Distance_Sphere(POINT(user_profiles.location_lng, user_profiles.location_lat), POINT(?, ?)) <= ?", lng, lat, maxDistanceKm*1000).
 		Order("distance").
@@ -321,7 +395,7 @@ func (r *MySQLMatchingAlgorithmRepository) GetUsersByAgeRange(ctx context.Contex
 	var users []*entity.User
 
 	if err := r.db.WithContext(ctx).
-		Where("id != ? AND is_active = ? AND is_verified = ?", userID, true, true).
+		Where("id != ? AND is_active = ? AND is_verified = ? AND account_status = ?", userID, true, true, entity.AccountStatusActive).
 		Where("YEAR(NOW()) - YEAR(birth_date) BETWEEN ? AND ?", minAge, maxAge).
 		Limit(limit).
 		Find(&users).Error; err != nil {
@@ -342,7 +416,7 @@ func (r *MySQLMatchingAlgorithmRepository) GetUsersByCommonInterests(ctx context
 			INNER JOIN user_interests ui2 ON users.id = ui2.user_id
 			INNER JOIN user_interests ui1 ON ui1.interest_id = ui2.interest_id AND ui1.user_id = ?
 		`, userID).
-		Where("users.id != ? AND users.is_active = ? AND users.is_verified = ?", userID, true, true).
+		Where("users.id != ? AND users.is_active = ? AND users.is_verified = ? AND users.registration_pending = ? AND users.account_status = ?", userID, true, true, false, entity.AccountStatusActive).
 		Group("users.id").
 		Order("common_interests DESC").
 		Limit(limit).
@@ -404,9 +478,51 @@ func (r *MySQLMatchingAlgorithmRepository) CalculateCompatibilityScore(ctx conte
 	interestScore := math.Min(1.0, float64(commonInterests)/5.0) // 最多5個共同興趣
 	score += interestScore * 0.3
 
+	// 屬性加分條件：任一方設有非硬性條件時佔 20%，取雙方符合比例的平均
+	attributeScore, ok, err := r.attributePreferenceScore(ctx, user1ID, user2ID, &profile1, &profile2)
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		score = score*0.8 + attributeScore*0.2
+	}
+
 	return math.Min(1.0, score), nil
 }
 
+// attributePreferenceScore 計算雙方對彼此屬性加分條件的平均符合比例
+// 雙方都沒有非硬性條件時返回 false
+func (r *MySQLMatchingAlgorithmRepository) attributePreferenceScore(ctx context.Context, user1ID, user2ID uint, profile1, profile2 *entity.UserProfile) (float64, bool, error) {
+	var preferences []*entity.AttributePreference
+	if err := r.db.WithContext(ctx).Where("user_id IN ? AND dealbreaker = ?", []uint{user1ID, user2ID}, false).Find(&preferences).Error; err != nil {
+		return 0, false, fmt.Errorf("獲取屬性配對條件失敗: %w", err)
+	}
+
+	var preferences1, preferences2 []*entity.AttributePreference
+	for _, preference := range preferences {
+		if preference.UserID == user1ID {
+			preferences1 = append(preferences1, preference)
+		} else {
+			preferences2 = append(preferences2, preference)
+		}
+	}
+
+	var total float64
+	var count int
+	if score, ok := entity.AttributePreferenceScore(preferences1, &profile2.Attributes); ok {
+		total += score
+		count++
+	}
+	if score, ok := entity.AttributePreferenceScore(preferences2, &profile1.Attributes); ok {
+		total += score
+		count++
+	}
+	if count == 0 {
+		return 0, false, nil
+	}
+	return total / float64(count), true, nil
+}
+
 // GetMatchingStats 獲取配對統計數據
 func (r *MySQLMatchingAlgorithmRepository) GetMatchingStats(ctx context.Context, userID uint) (*repository.MatchingStats, error) {
 	stats := &repository.MatchingStats{}
@@ -5,6 +5,7 @@ import (
 	"log"
 
 	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
 
 	"gorm.io/gorm"
 )
@@ -27,15 +28,31 @@ func (m *Migration) AutoMigrate() error {
 	models := []interface{}{
 		&entity.User{},
 		&entity.UserProfile{},
+		&entity.AttributePreference{},
+		&entity.Prompt{},
+		&entity.PromptTranslation{},
+		&entity.ProfilePrompt{},
 		&entity.Photo{},
+		&entity.BlockedImage{},
+		&entity.PrivateAlbumGrant{},
 		&entity.Interest{},
+		&entity.InterestTranslation{},
+		&entity.InterestSynonym{},
 		&entity.AgeVerification{},
 		&entity.Match{},
 		&entity.ChatMessage{},
 		&entity.Report{},
 		&entity.Block{},
+		&entity.TwoFactorCredential{},
+		&entity.RecoveryCode{},
+		&entity.DataExport{},
+		&entity.LoginAttempt{},
+		&entity.UserIdentity{},
 	}
 
+	// 須在遷移前判斷，遷移後欄位已存在
+	backfills := pendingBackfills(m.db)
+
 	// 執行自動遷移
 	for _, model := range models {
 		if err := m.db.AutoMigrate(model); err != nil {
@@ -44,6 +61,16 @@ func (m *Migration) AutoMigrate() error {
 		log.Printf("模型 %T 遷移成功", model)
 	}
 
+	if err := runBackfills(m.db, backfills); err != nil {
+		return err
+	}
+
+	// 審核日誌沒有對應的實體，直接以儲存庫模型遷移
+	if err := m.db.Table("moderation_logs").AutoMigrate(&repository.ModerationLog{}); err != nil {
+		return fmt.Errorf("遷移審核日誌表失敗: %w", err)
+	}
+	log.Println("審核日誌表遷移成功")
+
 	// 創建必要的索引
 	if err := m.createIndexes(); err != nil {
 		return fmt.Errorf("創建索引失敗: %w", err)
@@ -53,6 +80,85 @@ func (m *Migration) AutoMigrate() error {
 	return nil
 }
 
+// columnBackfill 新增欄位時為既有資料回填內容
+// 只在欄位由遷移新增時執行一次
+type columnBackfill struct {
+	model interface{}
+	field string
+	desc  string
+	apply func(db *gorm.DB) (int64, error)
+}
+
+// columnBackfills 需要回填的欄位
+var columnBackfills = []columnBackfill{
+	{
+		// 加入電子郵件驗證前已存在的啟用用戶視為已驗證，避免因預設未驗證而被擋在受保護功能之外
+		model: &entity.User{},
+		field: "EmailVerified",
+		desc:  "既有用戶電子郵件驗證狀態",
+		apply: func(db *gorm.DB) (int64, error) {
+			result := db.Model(&entity.User{}).
+				Where("is_active = ?", true).
+				UpdateColumns(map[string]interface{}{
+					"email_verified":    true,
+					"email_verified_at": gorm.Expr("created_at"),
+				})
+			return result.RowsAffected, result.Error
+		},
+	},
+	{
+		model: &entity.Photo{},
+		field: "HashBand0",
+		desc:  "照片感知雜湊區段",
+		apply: func(db *gorm.DB) (int64, error) {
+			result := db.Model(&entity.Photo{}).Where("perceptual_hash IS NOT NULL").UpdateColumns(hashBandExprs())
+			return result.RowsAffected, result.Error
+		},
+	},
+	{
+		model: &entity.BlockedImage{},
+		field: "HashBand0",
+		desc:  "禁用圖片感知雜湊區段",
+		apply: func(db *gorm.DB) (int64, error) {
+			result := db.Session(&gorm.Session{AllowGlobalUpdate: true}).Model(&entity.BlockedImage{}).UpdateColumns(hashBandExprs())
+			return result.RowsAffected, result.Error
+		},
+	},
+}
+
+// hashBandExprs 由感知雜湊計算各區段欄位的更新內容
+func hashBandExprs() map[string]interface{} {
+	exprs := make(map[string]interface{}, len(hashBandColumns))
+	for i, column := range hashBandColumns {
+		exprs[column] = gorm.Expr("(perceptual_hash >> ?) & 65535", 16*i)
+	}
+	return exprs
+}
+
+// pendingBackfills 返回資料表已存在但欄位尚未新增的回填，須在遷移前判斷
+func pendingBackfills(db *gorm.DB) []columnBackfill {
+	migrator := db.Migrator()
+	var pending []columnBackfill
+	for _, backfill := range columnBackfills {
+		if migrator.HasTable(backfill.model) && !migrator.HasColumn(backfill.model, backfill.field) {
+			pending = append(pending, backfill)
+		}
+	}
+	return pending
+}
+
+// runBackfills 執行遷移前判斷需要的回填
+func runBackfills(db *gorm.DB, backfills []columnBackfill) error {
+	for _, backfill := range backfills {
+		rows, err := backfill.apply(db)
+		if err != nil {
+			return fmt.Errorf("回填%s失敗: %w", backfill.desc, err)
+		}
+		log.Printf("已回填%s: %d 筆", backfill.desc, rows)
+	}
+	return nil
+}
+
 // createIndexes 創建必要的索引
 func (m *Migration) createIndexes() error {
 	log.Println("創建資料庫索引...")
@@ -133,9 +239,10 @@ func (m *Migration) DropAllTables() error {
 
 	// 獲取所有表名
 	tables := []string{
-		"blocks", "reports", "chat_messages", "matches",
-		"age_verifications", "user_interests", "interests",
-		"photos", "user_profiles", "users",
+		"user_identities", "login_attempts", "data_exports", "user_recovery_codes", "user_two_factor_credentials",
+		"moderation_logs", "blocks", "reports", "chat_messages", "matches",
+		"age_verifications", "user_interests", "interest_synonyms", "interest_translations", "interests",
+		"private_album_grants", "blocked_images", "photos", "profile_prompts", "prompt_translations", "prompts", "user_attribute_preferences", "user_profiles", "users",
 	}
 
 	// 刪除表
@@ -169,6 +276,7 @@ func (m *Migration) CheckTablesExist() (map[string]bool, error) {
 		"chat_messages":     false,
 		"reports":           false,
 		"blocks":            false,
+		"moderation_logs":   false,
 	}
 
 	for table := range tables {
@@ -0,0 +1,46 @@
+package mysql
+
+import (
+	"math"
+	"math/bits"
+	"strings"
+
+	"golang_dev_docker/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+// hashBandColumns 感知雜湊區段欄位，順序與 entity.HashBands 相同
+var hashBandColumns = [entity.HashBandCount]string{"hash_band0", "hash_band1", "hash_band2", "hash_band3"}
+
+// maxBandRadius 區段預先篩選允許的最大區段距離
+// 距離 2 時每個區段約 137 個候選值，再大時候選值過多，改為直接比對全表
+const maxBandRadius = 2
+
+// whereSimilarHash 加入感知雜湊距離在 maxDistance 以內的查詢條件
+// 距離在 maxDistance 以內的雜湊至少有一個區段距離不超過 maxDistance / 4，
+// 因此先以各區段索引篩選該距離內的所有區段值，再以 BIT_COUNT 計算實際距離
+func whereSimilarHash(query *gorm.DB, hash uint64, maxDistance int) *gorm.DB {
+	radius := maxDistance / entity.HashBandCount
+	if maxDistance >= 0 && radius <= maxBandRadius {
+		conditions := make([]string, 0, entity.HashBandCount)
+		args := make([]interface{}, 0, entity.HashBandCount)
+		for i, band := range entity.HashBands(hash) {
+			conditions = append(conditions, hashBandColumns[i]+" IN ?")
+			args = append(args, bandNeighbors(band, radius))
+		}
+		query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+	return query.Where("BIT_COUNT(perceptual_hash ^ ?) <= ?", hash, maxDistance)
+}
+
+// bandNeighbors 返回與區段值距離在 radius 以內的所有區段值
+func bandNeighbors(band uint16, radius int) []uint16 {
+	var values []uint16
+	for value := 0; value <= math.MaxUint16; value++ {
+		if bits.OnesCount16(uint16(value)^band) <= radius {
+			values = append(values, uint16(value))
+		}
+	}
+	return values
+}
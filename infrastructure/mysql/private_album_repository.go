@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MySQLPrivateAlbumRepository MySQL 私人相簿授權儲存庫實作
+type MySQLPrivateAlbumRepository struct {
+	db *gorm.DB
+}
+
+// NewPrivateAlbumRepository 創建新的 MySQL 私人相簿授權儲存庫
+func NewPrivateAlbumRepository(db *gorm.DB) repository.PrivateAlbumRepository {
+	return &MySQLPrivateAlbumRepository{db: db}
+}
+
+// Grant 建立授權，已存在時不重複建立
+func (r *MySQLPrivateAlbumRepository) Grant(ctx context.Context, grant *entity.PrivateAlbumGrant) error {
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(grant).Error; err != nil {
+		return fmt.Errorf("建立私人相簿授權失敗: %w", err)
+	}
+	return nil
+}
+
+// Revoke 撤銷擁有者對特定用戶的授權
+func (r *MySQLPrivateAlbumRepository) Revoke(ctx context.Context, ownerID, viewerID uint) error {
+	if err := r.db.WithContext(ctx).
+		Where("owner_id = ? AND viewer_id = ?", ownerID, viewerID).
+		Delete(&entity.PrivateAlbumGrant{}).Error; err != nil {
+		return fmt.Errorf("撤銷私人相簿授權失敗: %w", err)
+	}
+	return nil
+}
+
+// RevokeBetween 撤銷兩位用戶之間雙向的授權
+func (r *MySQLPrivateAlbumRepository) RevokeBetween(ctx context.Context, user1ID, user2ID uint) error {
+	if err := r.db.WithContext(ctx).
+		Where("(owner_id = ? AND viewer_id = ?) OR (owner_id = ? AND viewer_id = ?)",
+			user1ID, user2ID, user2ID, user1ID).
+		Delete(&entity.PrivateAlbumGrant{}).Error; err != nil {
+		return fmt.Errorf("撤銷私人相簿授權失敗: %w", err)
+	}
+	return nil
+}
+
+// HasAccess 檢查用戶是否獲得擁有者授權
+func (r *MySQLPrivateAlbumRepository) HasAccess(ctx context.Context, ownerID, viewerID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entity.PrivateAlbumGrant{}).
+		Where("owner_id = ? AND viewer_id = ?", ownerID, viewerID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("查詢私人相簿授權失敗: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetByOwnerID 獲取擁有者的所有授權
+func (r *MySQLPrivateAlbumRepository) GetByOwnerID(ctx context.Context, ownerID uint) ([]*entity.PrivateAlbumGrant, error) {
+	var grants []*entity.PrivateAlbumGrant
+	if err := r.db.WithContext(ctx).
+		Where("owner_id = ?", ownerID).
+		Order("created_at DESC").Order("id DESC").
+		Find(&grants).Error; err != nil {
+		return nil, fmt.Errorf("獲取私人相簿授權失敗: %w", err)
+	}
+	return grants, nil
+}
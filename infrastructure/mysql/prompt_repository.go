@@ -0,0 +1,171 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// MySQLPromptRepository MySQL 檔案問答題目儲存庫實作
+type MySQLPromptRepository struct {
+	db *gorm.DB
+}
+
+// NewPromptRepository 創建新的 MySQL 檔案問答題目儲存庫
+func NewPromptRepository(db *gorm.DB) repository.PromptRepository {
+	return &MySQLPromptRepository{db: db}
+}
+
+// GetAll 獲取題目與所有語系文字
+func (r *MySQLPromptRepository) GetAll(ctx context.Context, activeOnly bool) ([]*entity.Prompt, error) {
+	query := r.db.WithContext(ctx).Preload("Translations").Order("display_order, id")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var prompts []*entity.Prompt
+	if err := query.Find(&prompts).Error; err != nil {
+		return nil, fmt.Errorf("獲取檔案問答題目失敗: %w", err)
+	}
+	return prompts, nil
+}
+
+// GetByID 根據 ID 獲取題目與所有語系文字
+func (r *MySQLPromptRepository) GetByID(ctx context.Context, id uint) (*entity.Prompt, error) {
+	var prompt entity.Prompt
+	if err := r.db.WithContext(ctx).Preload("Translations").First(&prompt, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("獲取檔案問答題目失敗: %w", err)
+	}
+	return &prompt, nil
+}
+
+// Create 創建題目與語系文字
+func (r *MySQLPromptRepository) Create(ctx context.Context, prompt *entity.Prompt) error {
+	if err := r.db.WithContext(ctx).Create(prompt).Error; err != nil {
+		return fmt.Errorf("創建檔案問答題目失敗: %w", err)
+	}
+	return nil
+}
+
+// Update 更新題目並以新的語系文字取代原有文字
+func (r *MySQLPromptRepository) Update(ctx context.Context, prompt *entity.Prompt) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(prompt).Select("prompt_key", "category", "is_active", "display_order", "updated_at").Updates(prompt).Error; err != nil {
+			return fmt.Errorf("更新檔案問答題目失敗: %w", err)
+		}
+
+		if err := tx.Where("prompt_id = ?", prompt.ID).Delete(&entity.PromptTranslation{}).Error; err != nil {
+			return fmt.Errorf("刪除舊題目文字失敗: %w", err)
+		}
+
+		for i := range prompt.Translations {
+			prompt.Translations[i].ID = 0
+			prompt.Translations[i].PromptID = prompt.ID
+		}
+		if len(prompt.Translations) == 0 {
+			return nil
+		}
+		if err := tx.Create(&prompt.Translations).Error; err != nil {
+			return fmt.Errorf("建立題目文字失敗: %w", err)
+		}
+		return nil
+	})
+}
+
+// MySQLProfilePromptRepository MySQL 用戶檔案問答儲存庫實作
+type MySQLProfilePromptRepository struct {
+	db *gorm.DB
+}
+
+// NewProfilePromptRepository 創建新的 MySQL 用戶檔案問答儲存庫
+func NewProfilePromptRepository(db *gorm.DB) repository.ProfilePromptRepository {
+	return &MySQLProfilePromptRepository{db: db}
+}
+
+// GetByUserID 獲取用戶的所有回答，依顯示順序排列並載入題目
+func (r *MySQLProfilePromptRepository) GetByUserID(ctx context.Context, userID uint) ([]*entity.ProfilePrompt, error) {
+	var prompts []*entity.ProfilePrompt
+	if err := r.db.WithContext(ctx).
+		Preload("Prompt.Translations").
+		Where("user_id = ?", userID).
+		Order("display_order, id").
+		Find(&prompts).Error; err != nil {
+		return nil, fmt.Errorf("獲取用戶檔案問答失敗: %w", err)
+	}
+	return prompts, nil
+}
+
+// GetByID 根據 ID 獲取回答
+func (r *MySQLProfilePromptRepository) GetByID(ctx context.Context, id uint) (*entity.ProfilePrompt, error) {
+	var prompt entity.ProfilePrompt
+	if err := r.db.WithContext(ctx).Preload("Prompt.Translations").First(&prompt, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("獲取檔案問答回答失敗: %w", err)
+	}
+	return &prompt, nil
+}
+
+// ReplaceForUser 以新的回答取代用戶所有回答
+func (r *MySQLProfilePromptRepository) ReplaceForUser(ctx context.Context, userID uint, prompts []*entity.ProfilePrompt) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		keep := make([]uint, 0, len(prompts))
+		for _, prompt := range prompts {
+			if prompt.ID != 0 {
+				keep = append(keep, prompt.ID)
+			}
+		}
+
+		remove := tx.Where("user_id = ?", userID)
+		if len(keep) > 0 {
+			remove = remove.Where("id NOT IN ?", keep)
+		}
+		if err := remove.Delete(&entity.ProfilePrompt{}).Error; err != nil {
+			return fmt.Errorf("刪除舊檔案問答失敗: %w", err)
+		}
+
+		for _, prompt := range prompts {
+			prompt.UserID = userID
+			if err := tx.Omit("Prompt").Save(prompt).Error; err != nil {
+				return fmt.Errorf("保存檔案問答失敗: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetPending 獲取待審核的回答，依提交時間排列
+func (r *MySQLProfilePromptRepository) GetPending(ctx context.Context, limit int) ([]*entity.ProfilePrompt, error) {
+	query := r.db.WithContext(ctx).
+		Preload("Prompt.Translations").
+		Where("status = ?", entity.PromptAnswerStatusPending).
+		Order("updated_at ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var prompts []*entity.ProfilePrompt
+	if err := query.Find(&prompts).Error; err != nil {
+		return nil, fmt.Errorf("獲取待審核檔案問答失敗: %w", err)
+	}
+	return prompts, nil
+}
+
+// UpdateReview 更新回答的審核結果
+func (r *MySQLProfilePromptRepository) UpdateReview(ctx context.Context, prompt *entity.ProfilePrompt) error {
+	if err := r.db.WithContext(ctx).Model(prompt).
+		Select("status", "reviewer_id", "review_notes", "reviewed_at", "updated_at").
+		Updates(prompt).Error; err != nil {
+		return fmt.Errorf("更新檔案問答審核結果失敗: %w", err)
+	}
+	return nil
+}
@@ -29,6 +29,10 @@ func (s *Seeder) SeedAll() error {
 		return err
 	}
 
+	if err := s.seedPrompts(); err != nil {
+		return err
+	}
+
 	if err := s.seedDemoUsers(); err != nil {
 		return err
 	}
@@ -41,29 +45,40 @@ func (s *Seeder) SeedAll() error {
 func (s *Seeder) seedInterests() error {
 	log.Println("植入興趣資料...")
 
+	en := func(name string) []entity.InterestTranslation {
+		return []entity.InterestTranslation{{Locale: "en", Name: name}}
+	}
+	synonyms := func(terms ...string) []entity.InterestSynonym {
+		result := make([]entity.InterestSynonym, 0, len(terms))
+		for _, term := range terms {
+			result = append(result, entity.InterestSynonym{Term: term})
+		}
+		return result
+	}
+
 	interests := []entity.Interest{
-		{Name: "音樂", Category: "娛樂", IsActive: true},
-		{Name: "電影", Category: "娛樂", IsActive: true},
-		{Name: "閱讀", Category: "文化", IsActive: true},
-		{Name: "旅行", Category: "生活", IsActive: true},
-		{Name: "運動", Category: "健康", IsActive: true},
-		{Name: "健身", Category: "健康", IsActive: true},
-		{Name: "瑜伽", Category: "健康", IsActive: true},
-		{Name: "烹飪", Category: "生活", IsActive: true},
-		{Name: "攝影", Category: "藝術", IsActive: true},
-		{Name: "繪畫", Category: "藝術", IsActive: true},
-		{Name: "舞蹈", Category: "藝術", IsActive: true},
-		{Name: "游泳", Category: "運動", IsActive: true},
-		{Name: "跑步", Category: "運動", IsActive: true},
-		{Name: "登山", Category: "運動", IsActive: true},
-		{Name: "騎車", Category: "運動", IsActive: true},
-		{Name: "咖啡", Category: "飲食", IsActive: true},
-		{Name: "紅酒", Category: "飲食", IsActive: true},
-		{Name: "茶道", Category: "文化", IsActive: true},
-		{Name: "動漫", Category: "娛樂", IsActive: true},
-		{Name: "遊戲", Category: "娛樂", IsActive: true},
-		{Name: "寵物", Category: "生活", IsActive: true},
-		{Name: "園藝", Category: "生活", IsActive: true},
+		{Name: "音樂", Category: entity.InterestCategoryMusic, IsActive: true, Translations: en("Music"), Synonyms: synonyms("樂團", "演唱會")},
+		{Name: "電影", Category: entity.InterestCategoryMovies, IsActive: true, Translations: en("Movies"), Synonyms: synonyms("影集")},
+		{Name: "閱讀", Category: entity.InterestCategoryReading, IsActive: true, Translations: en("Reading"), Synonyms: synonyms("讀書")},
+		{Name: "旅行", Category: entity.InterestCategoryTravel, IsActive: true, Translations: en("Travel"), Synonyms: synonyms("旅遊")},
+		{Name: "運動", Category: entity.InterestCategorySports, IsActive: true, Translations: en("Sports")},
+		{Name: "健身", Category: entity.InterestCategoryFitness, IsActive: true, Translations: en("Fitness"), Synonyms: synonyms("重訓")},
+		{Name: "瑜伽", Category: entity.InterestCategoryFitness, IsActive: true, Translations: en("Yoga")},
+		{Name: "烹飪", Category: entity.InterestCategoryFood, IsActive: true, Translations: en("Cooking"), Synonyms: synonyms("料理", "下廚")},
+		{Name: "攝影", Category: entity.InterestCategoryArt, IsActive: true, Translations: en("Photography"), Synonyms: synonyms("拍照")},
+		{Name: "繪畫", Category: entity.InterestCategoryArt, IsActive: true, Translations: en("Painting")},
+		{Name: "舞蹈", Category: entity.InterestCategoryArt, IsActive: true, Translations: en("Dance"), Synonyms: synonyms("跳舞")},
+		{Name: "游泳", Category: entity.InterestCategorySports, IsActive: true, Translations: en("Swimming")},
+		{Name: "跑步", Category: entity.InterestCategorySports, IsActive: true, Translations: en("Running"), Synonyms: synonyms("慢跑", "馬拉松")},
+		{Name: "登山", Category: entity.InterestCategoryNature, IsActive: true, Translations: en("Hiking"), Synonyms: synonyms("健行", "爬山")},
+		{Name: "騎車", Category: entity.InterestCategorySports, IsActive: true, Translations: en("Cycling"), Synonyms: synonyms("單車", "自行車")},
+		{Name: "咖啡", Category: entity.InterestCategoryFood, IsActive: true, Translations: en("Coffee")},
+		{Name: "紅酒", Category: entity.InterestCategoryFood, IsActive: true, Translations: en("Wine"), Synonyms: synonyms("葡萄酒")},
+		{Name: "茶道", Category: entity.InterestCategoryHobbies, IsActive: true, Translations: en("Tea"), Synonyms: synonyms("品茶")},
+		{Name: "動漫", Category: entity.InterestCategoryHobbies, IsActive: true, Translations: en("Anime"), Synonyms: synonyms("動畫", "漫畫")},
+		{Name: "遊戲", Category: entity.InterestCategoryGaming, IsActive: true, Translations: en("Gaming"), Synonyms: synonyms("電玩", "桌遊")},
+		{Name: "寵物", Category: entity.InterestCategoryHobbies, IsActive: true, Translations: en("Pets"), Synonyms: synonyms("貓", "狗")},
+		{Name: "園藝", Category: entity.InterestCategoryNature, IsActive: true, Translations: en("Gardening"), Synonyms: synonyms("植物")},
 	}
 
 	for _, interest := range interests {
@@ -75,6 +90,15 @@ func (s *Seeder) seedInterests() error {
 				return err
 			}
 			log.Printf("興趣 '%s' 已植入", interest.Name)
+			continue
+		}
+
+		// 修正舊版種子資料寫入的無效類別（例如 "娛樂"、"健康"）
+		if result.Error == nil && !existing.Category.IsValid() {
+			if err := s.db.Model(&existing).Update("category", interest.Category).Error; err != nil {
+				return err
+			}
+			log.Printf("興趣 '%s' 的類別已修正為 %s", interest.Name, interest.Category)
 		}
 	}
 
@@ -82,6 +106,55 @@ func (s *Seeder) seedInterests() error {
 	return nil
 }
 
+// seedPrompts 植入檔案問答題目
+func (s *Seeder) seedPrompts() error {
+	log.Println("植入檔案問答題目...")
+
+	prompts := []entity.Prompt{
+		{Key: "ideal_weekend", Category: "生活", DisplayOrder: 1, Translations: []entity.PromptTranslation{
+			{Locale: "zh-tw", Text: "我理想的週末是"},
+			{Locale: "en", Text: "My ideal weekend is"},
+		}},
+		{Key: "simple_pleasures", Category: "生活", DisplayOrder: 2, Translations: []entity.PromptTranslation{
+			{Locale: "zh-tw", Text: "讓我開心的小事"},
+			{Locale: "en", Text: "My simple pleasures"},
+		}},
+		{Key: "looking_for", Category: "關係", DisplayOrder: 3, Translations: []entity.PromptTranslation{
+			{Locale: "zh-tw", Text: "我在找一個會"},
+			{Locale: "en", Text: "I'm looking for someone who"},
+		}},
+		{Key: "best_travel_story", Category: "旅行", DisplayOrder: 4, Translations: []entity.PromptTranslation{
+			{Locale: "zh-tw", Text: "我最難忘的旅行"},
+			{Locale: "en", Text: "My most memorable trip"},
+		}},
+		{Key: "green_flag", Category: "關係", DisplayOrder: 5, Translations: []entity.PromptTranslation{
+			{Locale: "zh-tw", Text: "會讓我心動的加分項"},
+			{Locale: "en", Text: "A green flag I look for"},
+		}},
+		{Key: "unpopular_opinion", Category: "趣味", DisplayOrder: 6, Translations: []entity.PromptTranslation{
+			{Locale: "zh-tw", Text: "我的冷門觀點"},
+			{Locale: "en", Text: "My unpopular opinion"},
+		}},
+	}
+
+	for _, prompt := range prompts {
+		prompt.IsActive = true
+
+		// 檢查是否已存在
+		var existing entity.Prompt
+		result := s.db.Where("prompt_key = ?", prompt.Key).First(&existing)
+		if result.Error == gorm.ErrRecordNotFound {
+			if err := s.db.Create(&prompt).Error; err != nil {
+				return err
+			}
+			log.Printf("題目 '%s' 已植入", prompt.Key)
+		}
+	}
+
+	log.Println("檔案問答題目植入完成")
+	return nil
+}
+
 // seedDemoUsers 植入示範用戶
 func (s *Seeder) seedDemoUsers() error {
 	log.Println("植入示範用戶...")
@@ -106,11 +179,12 @@ func (s *Seeder) seedDemoUsers() error {
 	}{
 		{
 			User: entity.User{
-				Email:        "alice@example.com",
-				PasswordHash: string(passwordHash),
-				BirthDate:    time.Date(1995, 6, 15, 0, 0, 0, 0, time.UTC),
-				IsVerified:   true,
-				IsActive:     true,
+				Email:         "alice@example.com",
+				PasswordHash:  string(passwordHash),
+				BirthDate:     time.Date(1995, 6, 15, 0, 0, 0, 0, time.UTC),
+				IsVerified:    true,
+				EmailVerified: true,
+				IsActive:      true,
 			},
 			Profile: entity.UserProfile{
 				DisplayName: "Alice",
@@ -126,11 +200,12 @@ func (s *Seeder) seedDemoUsers() error {
 		},
 		{
 			User: entity.User{
-				Email:        "bob@example.com",
-				PasswordHash: string(passwordHash),
-				BirthDate:    time.Date(1992, 3, 22, 0, 0, 0, 0, time.UTC),
-				IsVerified:   true,
-				IsActive:     true,
+				Email:         "bob@example.com",
+				PasswordHash:  string(passwordHash),
+				BirthDate:     time.Date(1992, 3, 22, 0, 0, 0, 0, time.UTC),
+				IsVerified:    true,
+				EmailVerified: true,
+				IsActive:      true,
 			},
 			Profile: entity.UserProfile{
 				DisplayName: "Bob",
@@ -146,11 +221,12 @@ func (s *Seeder) seedDemoUsers() error {
 		},
 		{
 			User: entity.User{
-				Email:        "charlie@example.com",
-				PasswordHash: string(passwordHash),
-				BirthDate:    time.Date(1998, 9, 8, 0, 0, 0, 0, time.UTC),
-				IsVerified:   true,
-				IsActive:     true,
+				Email:         "charlie@example.com",
+				PasswordHash:  string(passwordHash),
+				BirthDate:     time.Date(1998, 9, 8, 0, 0, 0, 0, time.UTC),
+				IsVerified:    true,
+				EmailVerified: true,
+				IsActive:      true,
 			},
 			Profile: entity.UserProfile{
 				DisplayName: "Charlie",
@@ -192,8 +268,8 @@ func (s *Seeder) ClearAll() error {
 	// 按相反順序刪除（避免外鍵約束問題）
 	tables := []string{
 		"blocks", "reports", "chat_messages", "matches",
-		"age_verifications", "user_interests", "photos",
-		"user_profiles", "users", "interests",
+		"age_verifications", "user_interests", "photos", "profile_prompts",
+		"user_profiles", "users", "interest_synonyms", "interest_translations", "interests", "prompt_translations", "prompts",
 	}
 
 	for _, table := range tables {
@@ -0,0 +1,111 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// MySQLTwoFactorRepository MySQL 兩步驟驗證儲存庫實作
+type MySQLTwoFactorRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository 創建新的 MySQL 兩步驟驗證儲存庫
+func NewTwoFactorRepository(db *gorm.DB) repository.TwoFactorRepository {
+	return &MySQLTwoFactorRepository{db: db}
+}
+
+// GetByUserID 獲取用戶的 TOTP 設定，尚未設定時返回 nil
+func (r *MySQLTwoFactorRepository) GetByUserID(ctx context.Context, userID uint) (*entity.TwoFactorCredential, error) {
+	var credential entity.TwoFactorCredential
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&credential).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢兩步驟驗證設定失敗: %w", err)
+	}
+	return &credential, nil
+}
+
+// Save 建立或更新 TOTP 設定
+func (r *MySQLTwoFactorRepository) Save(ctx context.Context, credential *entity.TwoFactorCredential) error {
+	if err := r.db.WithContext(ctx).Save(credential).Error; err != nil {
+		return fmt.Errorf("保存兩步驟驗證設定失敗: %w", err)
+	}
+	return nil
+}
+
+// Delete 刪除用戶的 TOTP 設定與所有恢復碼
+func (r *MySQLTwoFactorRepository) Delete(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error; err != nil {
+			return fmt.Errorf("刪除恢復碼失敗: %w", err)
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.TwoFactorCredential{}).Error; err != nil {
+			return fmt.Errorf("刪除兩步驟驗證設定失敗: %w", err)
+		}
+		return nil
+	})
+}
+
+// MarkStepUsed 以條件更新記錄已使用的時間步，並發請求中只有一個會成功
+func (r *MySQLTwoFactorRepository) MarkStepUsed(ctx context.Context, userID uint, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.TwoFactorCredential{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, fmt.Errorf("更新驗證碼使用記錄失敗: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReplaceRecoveryCodes 以新的恢復碼雜湊取代用戶所有恢復碼
+func (r *MySQLTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error; err != nil {
+			return fmt.Errorf("刪除舊恢復碼失敗: %w", err)
+		}
+
+		codes := make([]entity.RecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, entity.RecoveryCode{UserID: userID, CodeHash: hash})
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+
+		if err := tx.Create(&codes).Error; err != nil {
+			return fmt.Errorf("建立恢復碼失敗: %w", err)
+		}
+		return nil
+	})
+}
+
+// UseRecoveryCode 以條件更新標記恢復碼為已使用，確保每個恢復碼只能使用一次
+func (r *MySQLTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("使用恢復碼失敗: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// CountUnusedRecoveryCodes 統計剩餘可用的恢復碼數量
+func (r *MySQLTwoFactorRepository) CountUnusedRecoveryCodes(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("統計恢復碼失敗: %w", err)
+	}
+	return count, nil
+}
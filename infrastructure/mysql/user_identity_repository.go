@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// MySQLUserIdentityRepository MySQL 外部身分連結儲存庫實作
+type MySQLUserIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewUserIdentityRepository 創建新的 MySQL 外部身分連結儲存庫
+func NewUserIdentityRepository(db *gorm.DB) repository.UserIdentityRepository {
+	return &MySQLUserIdentityRepository{db: db}
+}
+
+// Create 建立外部身分連結
+func (r *MySQLUserIdentityRepository) Create(ctx context.Context, identity *entity.UserIdentity) error {
+	if err := r.db.WithContext(ctx).Create(identity).Error; err != nil {
+		return fmt.Errorf("建立外部身分連結失敗: %w", err)
+	}
+	return nil
+}
+
+// CreateWithUser 在同一交易中建立用戶與其外部身分連結
+func (r *MySQLUserIdentityRepository) CreateWithUser(ctx context.Context, user *entity.User, identity *entity.UserIdentity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return fmt.Errorf("創建用戶失敗: %w", err)
+		}
+		identity.UserID = user.ID
+		if err := tx.Create(identity).Error; err != nil {
+			return fmt.Errorf("建立外部身分連結失敗: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetByProviderSubject 根據提供者與 subject 獲取連結，不存在時返回 nil
+func (r *MySQLUserIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	var identity entity.UserIdentity
+	if err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢外部身分連結失敗: %w", err)
+	}
+	return &identity, nil
+}
+
+// ListByUserID 獲取用戶所有外部身分連結
+func (r *MySQLUserIdentityRepository) ListByUserID(ctx context.Context, userID uint) ([]*entity.UserIdentity, error) {
+	var identities []*entity.UserIdentity
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at").Find(&identities).Error; err != nil {
+		return nil, fmt.Errorf("查詢外部身分連結失敗: %w", err)
+	}
+	return identities, nil
+}
+
+// Update 更新外部身分連結
+func (r *MySQLUserIdentityRepository) Update(ctx context.Context, identity *entity.UserIdentity) error {
+	if err := r.db.WithContext(ctx).Save(identity).Error; err != nil {
+		return fmt.Errorf("更新外部身分連結失敗: %w", err)
+	}
+	return nil
+}
+
+// Delete 解除用戶與指定提供者的連結
+func (r *MySQLUserIdentityRepository) Delete(ctx context.Context, userID uint, provider string) (bool, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND provider = ?", userID, provider).Delete(&entity.UserIdentity{})
+	if result.Error != nil {
+		return false, fmt.Errorf("解除外部身分連結失敗: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
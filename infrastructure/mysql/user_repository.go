@@ -3,6 +3,8 @@ package mysql
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"golang_dev_docker/domain/entity"
 	"golang_dev_docker/domain/repository"
@@ -76,6 +78,26 @@ func (r *MySQLUserRepository) SetVerified(ctx context.Context, id uint, verified
 	return nil
 }
 
+// SetEmailVerified 標記用戶電子郵件已驗證
+func (r *MySQLUserRepository) SetEmailVerified(ctx context.Context, id uint) error {
+	updates := map[string]interface{}{
+		"email_verified":    true,
+		"email_verified_at": time.Now(),
+	}
+	if err := r.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return fmt.Errorf("設定電子郵件驗證狀態失敗: %w", err)
+	}
+	return nil
+}
+
+// UpdatePassword 更新用戶密碼雜湊
+func (r *MySQLUserRepository) UpdatePassword(ctx context.Context, id uint, passwordHash string) error {
+	if err := r.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).Update("password_hash", passwordHash).Error; err != nil {
+		return fmt.Errorf("更新密碼失敗: %w", err)
+	}
+	return nil
+}
+
 // SetActive 設定用戶啟用狀態
 func (r *MySQLUserRepository) SetActive(ctx context.Context, id uint, active bool) error {
 	if err := r.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).Update("is_active", active).Error; err != nil {
@@ -84,6 +106,77 @@ func (r *MySQLUserRepository) SetActive(ctx context.Context, id uint, active boo
 	return nil
 }
 
+// UpdateRole 更新用戶角色
+func (r *MySQLUserRepository) UpdateRole(ctx context.Context, id uint, role entity.UserRole) error {
+	if err := r.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).Update("role", role).Error; err != nil {
+		return fmt.Errorf("更新用戶角色失敗: %w", err)
+	}
+	return nil
+}
+
+// UpdateAccountStatus 更新用戶帳號停權狀態
+func (r *MySQLUserRepository) UpdateAccountStatus(ctx context.Context, user *entity.User) error {
+	updates := map[string]interface{}{
+		"account_status":    user.AccountStatus,
+		"suspended_until":   user.SuspendedUntil,
+		"status_reason":     user.StatusReason,
+		"status_actor_id":   user.StatusActorID,
+		"status_changed_at": user.StatusChangedAt,
+	}
+	if err := r.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", user.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("更新帳號狀態失敗: %w", err)
+	}
+	return nil
+}
+
+// GetExpiredSuspensions 獲取暫停期限已過的用戶
+func (r *MySQLUserRepository) GetExpiredSuspensions(ctx context.Context, now time.Time, limit int) ([]*entity.User, error) {
+	var users []*entity.User
+	if err := r.db.WithContext(ctx).
+		Where("account_status = ? AND suspended_until IS NOT NULL AND suspended_until <= ?", entity.AccountStatusSuspended, now).
+		Order("suspended_until ASC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("獲取到期暫停用戶失敗: %w", err)
+	}
+	return users, nil
+}
+
+// UpdateDeletionSchedule 更新帳號刪除排程
+func (r *MySQLUserRepository) UpdateDeletionSchedule(ctx context.Context, user *entity.User) error {
+	updates := map[string]interface{}{
+		"is_active":             user.IsActive,
+		"deletion_requested_at": user.DeletionRequestedAt,
+		"deletion_scheduled_at": user.DeletionScheduledAt,
+	}
+	if err := r.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", user.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("更新帳號刪除排程失敗: %w", err)
+	}
+	return nil
+}
+
+// GetDueDeletions 獲取刪除寬限期已過的用戶
+func (r *MySQLUserRepository) GetDueDeletions(ctx context.Context, now time.Time, afterID uint, limit int) ([]*entity.User, error) {
+	var users []*entity.User
+	if err := r.db.WithContext(ctx).
+		Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ? AND erased_at IS NULL AND id > ?", now, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("獲取到期刪除用戶失敗: %w", err)
+	}
+	return users, nil
+}
+
+// CountByRoles 統計擁有指定角色的用戶數量
+func (r *MySQLUserRepository) CountByRoles(ctx context.Context, roles ...entity.UserRole) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entity.User{}).Where("role IN ?", roles).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("統計用戶角色失敗: %w", err)
+	}
+	return count, nil
+}
+
 // MySQLUserProfileRepository MySQL 用戶檔案儲存庫實作
 type MySQLUserProfileRepository struct {
 	db *gorm.DB
@@ -157,6 +250,46 @@ func (r *MySQLUserProfileRepository) UpdateMatchingPreferences(ctx context.Conte
 	return nil
 }
 
+// MySQLAttributePreferenceRepository MySQL 屬性配對條件儲存庫實作
+type MySQLAttributePreferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewAttributePreferenceRepository 創建新的 MySQL 屬性配對條件儲存庫
+func NewAttributePreferenceRepository(db *gorm.DB) repository.AttributePreferenceRepository {
+	return &MySQLAttributePreferenceRepository{db: db}
+}
+
+// GetByUserID 獲取用戶的所有屬性配對條件
+func (r *MySQLAttributePreferenceRepository) GetByUserID(ctx context.Context, userID uint) ([]*entity.AttributePreference, error) {
+	var preferences []*entity.AttributePreference
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&preferences).Error; err != nil {
+		return nil, fmt.Errorf("獲取屬性配對條件失敗: %w", err)
+	}
+	return preferences, nil
+}
+
+// ReplaceForUser 以新的條件取代用戶所有屬性配對條件
+func (r *MySQLAttributePreferenceRepository) ReplaceForUser(ctx context.Context, userID uint, preferences []*entity.AttributePreference) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.AttributePreference{}).Error; err != nil {
+			return fmt.Errorf("刪除舊屬性配對條件失敗: %w", err)
+		}
+		if len(preferences) == 0 {
+			return nil
+		}
+
+		for _, preference := range preferences {
+			preference.ID = 0
+			preference.UserID = userID
+		}
+		if err := tx.Create(&preferences).Error; err != nil {
+			return fmt.Errorf("建立屬性配對條件失敗: %w", err)
+		}
+		return nil
+	})
+}
+
 // MySQLPhotoRepository MySQL 照片儲存庫實作
 type MySQLPhotoRepository struct {
 	db *gorm.DB
@@ -169,6 +302,7 @@ func NewPhotoRepository(db *gorm.DB) repository.PhotoRepository {
 
 // Create 添加用戶照片
 func (r *MySQLPhotoRepository) Create(ctx context.Context, photo *entity.Photo) error {
+	photo.SyncHashBands()
 	if err := r.db.WithContext(ctx).Create(photo).Error; err != nil {
 		return fmt.Errorf("添加照片失敗: %w", err)
 	}
@@ -189,7 +323,7 @@ func (r *MySQLPhotoRepository) GetByID(ctx context.Context, id uint) (*entity.Ph
 	var photo entity.Photo
 	if err := r.db.WithContext(ctx).First(&photo, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("照片不存在: %w", err)
+			return nil, nil
 		}
 		return nil, fmt.Errorf("查詢照片失敗: %w", err)
 	}
@@ -198,6 +332,7 @@ func (r *MySQLPhotoRepository) GetByID(ctx context.Context, id uint) (*entity.Ph
 
 // Update 更新照片資訊
 func (r *MySQLPhotoRepository) Update(ctx context.Context, photo *entity.Photo) error {
+	photo.SyncHashBands()
 	if err := r.db.WithContext(ctx).Save(photo).Error; err != nil {
 		return fmt.Errorf("更新照片失敗: %w", err)
 	}
@@ -217,20 +352,24 @@ func (r *MySQLPhotoRepository) SetPrimary(ctx context.Context, userID, photoID u
 	// 開始事務
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 先將該用戶的所有照片設為非主要
-		if err := tx.Model(&entity.Photo{}).Where("user_id = ?", userID).Update("is_primary", false).Error; err != nil {
+		if err := tx.Model(&entity.Photo{}).Where("user_id = ? AND is_main = ?", userID, true).Update("is_main", false).Error; err != nil {
 			return fmt.Errorf("重置主要照片失敗: %w", err)
 		}
 
 		// 設定指定照片為主要
-		if err := tx.Model(&entity.Photo{}).Where("id = ? AND user_id = ?", photoID, userID).Update("is_primary", true).Error; err != nil {
-			return fmt.Errorf("設定主要照片失敗: %w", err)
+		result := tx.Model(&entity.Photo{}).Where("id = ? AND user_id = ?", photoID, userID).Update("is_main", true)
+		if result.Error != nil {
+			return fmt.Errorf("設定主要照片失敗: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("設定主要照片失敗: 照片 %d 不存在", photoID)
 		}
 
 		return nil
 	})
 }
 
-// UpdateOrder 更新照片排序
+// UpdateOrder 更新照片排序，所有照片在同一事務中更新
 func (r *MySQLPhotoRepository) UpdateOrder(ctx context.Context, userID uint, photoOrders []struct {
 	PhotoID uint
 	Order   int
@@ -247,6 +386,115 @@ func (r *MySQLPhotoRepository) UpdateOrder(ctx context.Context, userID uint, pho
 	})
 }
 
+// GetReviewQueue 獲取待審核照片佇列
+func (r *MySQLPhotoRepository) GetReviewQueue(ctx context.Context, params repository.PhotoQueueParams) ([]*entity.Photo, int64, error) {
+	query := r.db.WithContext(ctx).Model(&entity.Photo{}).Where("status = ?", entity.PhotoStatusPending)
+
+	if params.Type != nil {
+		query = query.Where("type = ?", *params.Type)
+	}
+	if params.UserID != nil {
+		query = query.Where("user_id = ?", *params.UserID)
+	}
+	if params.OnlyFlagged {
+		query = query.Where("flag_score IS NOT NULL")
+	}
+	switch {
+	case params.OnlyMine:
+		query = query.Where("claimed_by = ? AND claimed_at >= ?", params.ModeratorID, params.StaleBefore)
+	case !params.IncludeClaimed:
+		query = query.Where("(claimed_by IS NULL OR claimed_by = ? OR claimed_at < ?)", params.ModeratorID, params.StaleBefore)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("計算待審核照片數量失敗: %w", err)
+	}
+
+	// 自動偵測標記的照片依信心度優先，其餘依上傳時間
+	query = query.Order("flag_score IS NULL").Order("flag_score DESC").Order("created_at ASC").Order("id ASC")
+	if params.Limit > 0 {
+		query = query.Limit(params.Limit)
+	}
+	if params.Offset > 0 {
+		query = query.Offset(params.Offset)
+	}
+
+	var photos []*entity.Photo
+	if err := query.Find(&photos).Error; err != nil {
+		return nil, 0, fmt.Errorf("獲取待審核照片失敗: %w", err)
+	}
+	return photos, total, nil
+}
+
+// Claim 認領待審核照片，以單一條件更新避免兩位審核員同時認領成功
+func (r *MySQLPhotoRepository) Claim(ctx context.Context, photoID, moderatorID uint, claimedAt, staleBefore time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.Photo{}).
+		Where("id = ? AND status = ?", photoID, entity.PhotoStatusPending).
+		Where("(claimed_by IS NULL OR claimed_by = ? OR claimed_at < ?)", moderatorID, staleBefore).
+		Updates(map[string]interface{}{
+			"claimed_by": moderatorID,
+			"claimed_at": claimedAt,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("認領照片失敗: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseClaim 釋出審核員對照片的認領
+func (r *MySQLPhotoRepository) ReleaseClaim(ctx context.Context, photoID, moderatorID uint) error {
+	if err := r.db.WithContext(ctx).Model(&entity.Photo{}).
+		Where("id = ? AND claimed_by = ?", photoID, moderatorID).
+		Updates(map[string]interface{}{
+			"claimed_by": nil,
+			"claimed_at": nil,
+		}).Error; err != nil {
+		return fmt.Errorf("釋出照片認領失敗: %w", err)
+	}
+	return nil
+}
+
+// UpdateReview 更新照片的審核結果並清除認領
+func (r *MySQLPhotoRepository) UpdateReview(ctx context.Context, photo *entity.Photo) error {
+	if err := r.db.WithContext(ctx).Model(photo).
+		Select("status", "reviewer_id", "review_notes", "reviewed_at", "claimed_by", "claimed_at", "updated_at").
+		Updates(photo).Error; err != nil {
+		return fmt.Errorf("更新照片審核結果失敗: %w", err)
+	}
+	return nil
+}
+
+// FindSimilar 找出其他用戶感知雜湊相近的照片
+// 先以區段索引篩選候選，再以 BIT_COUNT 在資料庫端計算距離，只返回距離以內的照片
+func (r *MySQLPhotoRepository) FindSimilar(ctx context.Context, hash uint64, maxDistance int, excludeUserID uint, limit int) ([]*entity.Photo, error) {
+	query := whereSimilarHash(r.db.WithContext(ctx), hash, maxDistance).
+		Where("perceptual_hash IS NOT NULL AND user_id <> ?", excludeUserID).
+		Order(gorm.Expr("BIT_COUNT(perceptual_hash ^ ?)", hash))
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var photos []*entity.Photo
+	if err := query.Find(&photos).Error; err != nil {
+		return nil, fmt.Errorf("查詢相似照片失敗: %w", err)
+	}
+	return photos, nil
+}
+
+// Flag 記錄照片的自動偵測標記
+func (r *MySQLPhotoRepository) Flag(ctx context.Context, photoID uint, reason string, score float64) error {
+	if err := r.db.WithContext(ctx).Model(&entity.Photo{}).
+		Where("id = ?", photoID).
+		Updates(map[string]interface{}{
+			"flag_reason": reason,
+			"flag_score":  score,
+		}).Error; err != nil {
+		return fmt.Errorf("標記照片失敗: %w", err)
+	}
+	return nil
+}
+
 // MySQLInterestRepository MySQL 興趣標籤儲存庫實作
 type MySQLInterestRepository struct {
 	db *gorm.DB
@@ -257,20 +505,78 @@ func NewInterestRepository(db *gorm.DB) repository.InterestRepository {
 	return &MySQLInterestRepository{db: db}
 }
 
-// GetAll 獲取所有可用興趣標籤
-func (r *MySQLInterestRepository) GetAll(ctx context.Context) ([]*entity.Interest, error) {
+// GetAll 獲取興趣標籤與在地化名稱、同義詞
+func (r *MySQLInterestRepository) GetAll(ctx context.Context, activeOnly bool) ([]*entity.Interest, error) {
+	query := r.withDetails(ctx).Order("category, name")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var interests []*entity.Interest
+	if err := query.Find(&interests).Error; err != nil {
+		return nil, fmt.Errorf("獲取興趣標籤失敗: %w", err)
+	}
+	return interests, nil
+}
+
+// GetByID 根據 ID 獲取興趣標籤
+func (r *MySQLInterestRepository) GetByID(ctx context.Context, id uint) (*entity.Interest, error) {
+	var interest entity.Interest
+	if err := r.withDetails(ctx).First(&interest, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("獲取興趣標籤失敗: %w", err)
+	}
+	return &interest, nil
+}
+
+// GetByIDs 根據 ID 批次獲取興趣標籤
+func (r *MySQLInterestRepository) GetByIDs(ctx context.Context, ids []uint) ([]*entity.Interest, error) {
 	var interests []*entity.Interest
-	if err := r.db.WithContext(ctx).Find(&interests).Error; err != nil {
+	if len(ids) == 0 {
+		return interests, nil
+	}
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&interests).Error; err != nil {
 		return nil, fmt.Errorf("獲取興趣標籤失敗: %w", err)
 	}
 	return interests, nil
 }
 
+// GetByName 根據標準名稱獲取興趣標籤
+func (r *MySQLInterestRepository) GetByName(ctx context.Context, name string) (*entity.Interest, error) {
+	var interest entity.Interest
+	if err := r.withDetails(ctx).Where("name = ?", name).First(&interest).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("獲取興趣標籤失敗: %w", err)
+	}
+	return &interest, nil
+}
+
+// Search 以字首搜尋啟用中的興趣，比對名稱、在地化名稱與同義詞
+func (r *MySQLInterestRepository) Search(ctx context.Context, prefix string, limit int) ([]*entity.Interest, error) {
+	pattern := escapeLike(prefix) + "%"
+
+	var interests []*entity.Interest
+	if err := r.withDetails(ctx).
+		Where("is_active = ?", true).
+		Where("(name LIKE ? OR id IN (?) OR id IN (?))", pattern,
+			r.db.Model(&entity.InterestTranslation{}).Select("interest_id").Where("name LIKE ?", pattern),
+			r.db.Model(&entity.InterestSynonym{}).Select("interest_id").Where("term LIKE ?", pattern)).
+		Order("usage_count DESC, name").
+		Limit(limit).
+		Find(&interests).Error; err != nil {
+		return nil, fmt.Errorf("搜尋興趣標籤失敗: %w", err)
+	}
+	return interests, nil
+}
+
 // GetByUserID 獲取用戶的興趣標籤
 func (r *MySQLInterestRepository) GetByUserID(ctx context.Context, userID uint) ([]*entity.Interest, error) {
 	var interests []*entity.Interest
-	// 假設有一個 user_interests 關聯表
-	if err := r.db.WithContext(ctx).
+	if err := r.withDetails(ctx).
 		Table("interests").
 		Select("interests.*").
 		Joins("INNER JOIN user_interests ON interests.id = user_interests.interest_id").
@@ -282,8 +588,15 @@ func (r *MySQLInterestRepository) GetByUserID(ctx context.Context, userID uint)
 }
 
 // SetUserInterests 設定用戶興趣標籤
+// 使用人數以關聯表重新計算，涵蓋移除與新增的興趣，避免累加誤差
 func (r *MySQLInterestRepository) SetUserInterests(ctx context.Context, userID uint, interestIDs []uint) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 鎖定現有關聯，避免同一用戶並行更新時重複計算
+		var affected []uint
+		if err := tx.Raw("SELECT interest_id FROM user_interests WHERE user_id = ? FOR UPDATE", userID).Scan(&affected).Error; err != nil {
+			return fmt.Errorf("查詢現有興趣關聯失敗: %w", err)
+		}
+
 		// 先刪除現有關聯
 		if err := tx.Exec("DELETE FROM user_interests WHERE user_id = ?", userID).Error; err != nil {
 			return fmt.Errorf("刪除現有興趣關聯失敗: %w", err)
@@ -296,11 +609,21 @@ func (r *MySQLInterestRepository) SetUserInterests(ctx context.Context, userID u
 			}
 		}
 
+		affected = append(affected, interestIDs...)
+		if len(affected) == 0 {
+			return nil
+		}
+		if err := tx.Exec(`UPDATE interests SET usage_count = (
+			SELECT COUNT(*) FROM user_interests WHERE user_interests.interest_id = interests.id
+		) WHERE id IN ?`, affected).Error; err != nil {
+			return fmt.Errorf("更新興趣使用人數失敗: %w", err)
+		}
+
 		return nil
 	})
 }
 
-// Create 創建新興趣標籤
+// Create 創建新興趣標籤與在地化名稱、同義詞
 func (r *MySQLInterestRepository) Create(ctx context.Context, interest *entity.Interest) error {
 	if err := r.db.WithContext(ctx).Create(interest).Error; err != nil {
 		return fmt.Errorf("創建興趣標籤失敗: %w", err)
@@ -308,20 +631,70 @@ func (r *MySQLInterestRepository) Create(ctx context.Context, interest *entity.I
 	return nil
 }
 
-// Update 更新興趣標籤
+// Update 更新興趣標籤並以新的在地化名稱與同義詞取代原有資料
 func (r *MySQLInterestRepository) Update(ctx context.Context, interest *entity.Interest) error {
-	if err := r.db.WithContext(ctx).Save(interest).Error; err != nil {
-		return fmt.Errorf("更新興趣標籤失敗: %w", err)
-	}
-	return nil
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(interest).Select("name", "category", "description", "is_active", "updated_at").Updates(interest).Error; err != nil {
+			return fmt.Errorf("更新興趣標籤失敗: %w", err)
+		}
+
+		if err := tx.Where("interest_id = ?", interest.ID).Delete(&entity.InterestTranslation{}).Error; err != nil {
+			return fmt.Errorf("刪除舊在地化名稱失敗: %w", err)
+		}
+		if err := tx.Where("interest_id = ?", interest.ID).Delete(&entity.InterestSynonym{}).Error; err != nil {
+			return fmt.Errorf("刪除舊同義詞失敗: %w", err)
+		}
+
+		for i := range interest.Translations {
+			interest.Translations[i].ID = 0
+			interest.Translations[i].InterestID = interest.ID
+		}
+		if len(interest.Translations) > 0 {
+			if err := tx.Create(&interest.Translations).Error; err != nil {
+				return fmt.Errorf("建立在地化名稱失敗: %w", err)
+			}
+		}
+
+		for i := range interest.Synonyms {
+			interest.Synonyms[i].ID = 0
+			interest.Synonyms[i].InterestID = interest.ID
+		}
+		if len(interest.Synonyms) > 0 {
+			if err := tx.Create(&interest.Synonyms).Error; err != nil {
+				return fmt.Errorf("建立同義詞失敗: %w", err)
+			}
+		}
+		return nil
+	})
 }
 
-// Delete 刪除興趣標籤
+// Delete 刪除興趣標籤，一併移除在地化名稱、同義詞與用戶的興趣關聯
 func (r *MySQLInterestRepository) Delete(ctx context.Context, id uint) error {
-	if err := r.db.WithContext(ctx).Delete(&entity.Interest{}, id).Error; err != nil {
-		return fmt.Errorf("刪除興趣標籤失敗: %w", err)
-	}
-	return nil
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM user_interests WHERE interest_id = ?", id).Error; err != nil {
+			return fmt.Errorf("刪除興趣關聯失敗: %w", err)
+		}
+		if err := tx.Where("interest_id = ?", id).Delete(&entity.InterestTranslation{}).Error; err != nil {
+			return fmt.Errorf("刪除在地化名稱失敗: %w", err)
+		}
+		if err := tx.Where("interest_id = ?", id).Delete(&entity.InterestSynonym{}).Error; err != nil {
+			return fmt.Errorf("刪除同義詞失敗: %w", err)
+		}
+		if err := tx.Delete(&entity.Interest{}, id).Error; err != nil {
+			return fmt.Errorf("刪除興趣標籤失敗: %w", err)
+		}
+		return nil
+	})
+}
+
+// withDetails 載入在地化名稱與同義詞
+func (r *MySQLInterestRepository) withDetails(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Preload("Translations").Preload("Synonyms")
+}
+
+// escapeLike 跳脫 LIKE 模式中的萬用字元
+func escapeLike(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(value)
 }
 
 // MySQLAgeVerificationRepository MySQL 年齡驗證儲存庫實作
@@ -0,0 +1,340 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang_dev_docker/domain/usecase"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ProviderConfig 外部身分提供者配置
+type ProviderConfig struct {
+	Name         string // 路由使用的識別名稱，例如 google
+	DisplayName  string // 顯示名稱
+	Issuer       string // 發行者網址，用於探索端點與驗證 ID Token
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string   // 用戶端接收授權碼的網址，須與提供者註冊的一致
+	Scopes       []string // 留空則使用 openid email profile
+}
+
+// discoveryDocument OpenID 探索文件中使用到的欄位
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider 通用 OIDC 提供者，實作授權碼流程與 ID Token 驗證
+type Provider struct {
+	config     ProviderConfig
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	discovery *discoveryDocument
+	keys      map[string]crypto.PublicKey
+}
+
+// NewProvider 建立 OIDC 提供者，httpClient 為 nil 時使用預設逾時設定
+func NewProvider(config ProviderConfig, httpClient *http.Client) (*Provider, error) {
+	if config.Name == "" || config.Issuer == "" || config.ClientID == "" {
+		return nil, fmt.Errorf("OIDC 提供者配置不完整")
+	}
+	if config.DisplayName == "" {
+		config.DisplayName = config.Name
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{config: config, httpClient: httpClient}, nil
+}
+
+// Name 提供者識別名稱
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// DisplayName 提供者顯示名稱
+func (p *Provider) DisplayName() string {
+	return p.config.DisplayName
+}
+
+// AuthorizationURL 產生導向提供者的授權網址
+func (p *Provider) AuthorizationURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(p.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(doc.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return doc.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange 以授權碼與 PKCE 驗證碼換取並驗證 ID Token
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*usecase.OIDCClaims, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("建立授權碼交換請求失敗: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("授權碼交換失敗: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("讀取授權碼交換回應失敗: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("授權碼交換失敗: HTTP %d", resp.StatusCode)
+	}
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("解析授權碼交換回應失敗: %w", err)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("提供者未返回 ID Token")
+	}
+
+	return p.verifyIDToken(ctx, doc, token.IDToken)
+}
+
+// idTokenClaims ID Token 中使用到的聲明
+type idTokenClaims struct {
+	Email         string          `json:"email"`
+	EmailVerified json.RawMessage `json:"email_verified"`
+	Name          string          `json:"name"`
+	Nonce         string          `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// verifyIDToken 驗證 ID Token 的簽章、發行者、受眾與有效期
+func (p *Provider) verifyIDToken(ctx context.Context, doc *discoveryDocument, raw string) (*usecase.OIDCClaims, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.publicKey(ctx, doc, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(doc.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30*time.Second),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ID Token 驗證失敗: %w", err)
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("ID Token 缺少 sub")
+	}
+
+	return &usecase.OIDCClaims{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: parseEmailVerified(claims.EmailVerified),
+		Name:          claims.Name,
+		Nonce:         claims.Nonce,
+	}, nil
+}
+
+// parseEmailVerified 部分提供者以字串表示 email_verified
+func parseEmailVerified(raw json.RawMessage) bool {
+	var verified bool
+	if err := json.Unmarshal(raw, &verified); err == nil {
+		return verified
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return strings.EqualFold(text, "true")
+	}
+	return false
+}
+
+// discover 取得並快取探索文件
+func (p *Provider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	endpoint := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	doc := &discoveryDocument{}
+	if err := p.getJSON(ctx, endpoint, doc); err != nil {
+		return nil, fmt.Errorf("取得 OIDC 探索文件失敗: %w", err)
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != strings.TrimSuffix(p.config.Issuer, "/") {
+		return nil, fmt.Errorf("OIDC 探索文件發行者不符: %s", doc.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("OIDC 探索文件缺少必要端點")
+	}
+
+	p.discovery = doc
+	return doc, nil
+}
+
+// jsonWebKey JWKS 中的單一公鑰
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey 依 kid 取得簽章公鑰，遇到未知 kid 時重新下載 JWKS 以支援金鑰輪替
+func (p *Provider) publicKey(ctx context.Context, doc *discoveryDocument, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, exists := p.keys[kid]; exists {
+		return key, nil
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.getJSON(ctx, doc.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("取得 JWKS 失敗: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	p.keys = keys
+
+	key, exists := keys[kid]
+	if !exists {
+		return nil, fmt.Errorf("找不到簽章金鑰: %s", kid)
+	}
+	return key, nil
+}
+
+// publicKey 將 JWK 轉換為公鑰
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("不支援的曲線: %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("公鑰不在曲線上")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("不支援的金鑰類型: %s", k.Kty)
+	}
+}
+
+// getJSON 以 GET 取得 JSON 資源
+func (p *Provider) getJSON(ctx context.Context, endpoint string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out)
+}
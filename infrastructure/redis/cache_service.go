@@ -1,8 +1,11 @@
 package redis
 
 import (
+	"errors"
 	"fmt"
 	"time"
+
+	goredis "github.com/redis/go-redis/v9"
 )
 
 // CacheService Redis 快取服務
@@ -266,6 +269,59 @@ func (c *CacheService) GetRateLimit(identifier string) (int64, error) {
 	return count, nil
 }
 
+// 登入失敗追蹤
+
+// IncrementLoginFailures 增加登入失敗計數，計數在第一次失敗後 window 時間內有效
+func (c *CacheService) IncrementLoginFailures(identifier string, window time.Duration) (int64, error) {
+	key := c.key("login", "failures", identifier)
+	count, err := c.client.Increment(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if count == 1 {
+		if err := c.client.Expire(key, window); err != nil {
+			return count, err
+		}
+	}
+
+	return count, nil
+}
+
+// ResetLoginFailures 清除登入失敗計數
+func (c *CacheService) ResetLoginFailures(identifier string) error {
+	key := c.key("login", "failures", identifier)
+	_, err := c.client.Delete(key)
+	return err
+}
+
+// LockLogin 在指定時間內禁止登入，kind 區分延遲與鎖定
+func (c *CacheService) LockLogin(identifier, kind string, duration time.Duration) error {
+	key := c.key("login", "lock", identifier)
+	return c.client.Set(key, kind, duration)
+}
+
+// GetLoginLock 獲取登入限制類型與剩餘時間，沒有限制時返回空字串
+func (c *CacheService) GetLoginLock(identifier string) (string, time.Duration, error) {
+	key := c.key("login", "lock", identifier)
+	kind, err := c.client.Get(key)
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return "", 0, nil
+		}
+		return "", 0, err
+	}
+
+	ttl, err := c.client.TTL(key)
+	if err != nil {
+		return "", 0, err
+	}
+	if ttl <= 0 {
+		return "", 0, nil
+	}
+	return kind, ttl, nil
+}
+
 // JWT Token 黑名單
 
 // AddTokenToBlacklist 添加 token 到黑名單
@@ -295,6 +351,12 @@ func (c *CacheService) GetVerificationCode(identifier string) (string, error) {
 	return c.client.Get(key)
 }
 
+// ConsumeVerificationCode 獲取並刪除驗證碼，確保只能使用一次
+func (c *CacheService) ConsumeVerificationCode(identifier string) (string, error) {
+	key := c.key("verification", identifier)
+	return c.client.GetDel(key)
+}
+
 // DeleteVerificationCode 刪除驗證碼
 func (c *CacheService) DeleteVerificationCode(identifier string) error {
 	key := c.key("verification", identifier)
@@ -92,6 +92,16 @@ func (r *RedisClient) Set(key string, value interface{}, expiration time.Duratio
 	return r.client.Set(r.ctx, key, value, expiration).Err()
 }
 
+// GetDel 獲取值並同時刪除鍵（原子操作）
+func (r *RedisClient) GetDel(key string) (string, error) {
+	return r.client.GetDel(r.ctx, key).Result()
+}
+
+// SetNX 僅在鍵不存在時設置值，返回是否設置成功
+func (r *RedisClient) SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	return r.client.SetNX(r.ctx, key, value, expiration).Result()
+}
+
 // Get 獲取值
 func (r *RedisClient) Get(key string) (string, error) {
 	return r.client.Get(r.ctx, key).Result()
@@ -24,12 +24,22 @@ func NewSessionCacheService(client *RedisClient) *SessionCacheService {
 type SessionData struct {
 	UserID      uint      `json:"user_id"`
 	Email       string    `json:"email"`
+	Role        string    `json:"role"`
 	DisplayName string    `json:"display_name"`
 	IsVerified  bool      `json:"is_verified"`
 	LoginTime   time.Time `json:"login_time"`
 	LastActive  time.Time `json:"last_active"`
 	IPAddress   string    `json:"ip_address"`
 	UserAgent   string    `json:"user_agent"`
+	TokenID     string    `json:"token_id"` // 目前綁定的存取權杖 jti
+}
+
+// RefreshTokenData 刷新權杖資料結構
+// 以權杖雜湊值為鍵儲存，同一會話內輪換的刷新權杖屬於同一個權杖家族
+type RefreshTokenData struct {
+	UserID    uint      `json:"user_id"`
+	SessionID string    `json:"session_id"`
+	IssuedAt  time.Time `json:"issued_at"`
 }
 
 // StoreSession 儲存用戶會話
@@ -96,20 +106,106 @@ func (s *SessionCacheService) GetActiveUserSessions(userID uint) ([]string, erro
 }
 
 // DeleteAllUserSessions 刪除用戶的所有會話
+// 同時刪除用戶關聯鍵與主會話鍵，確保已撤銷的會話無法再通過驗證
 func (s *SessionCacheService) DeleteAllUserSessions(userID uint) error {
-	sessions, err := s.GetActiveUserSessions(userID)
+	userSessionKeys, err := s.GetActiveUserSessions(userID)
+	if err != nil {
+		return err
+	}
+
+	if len(userSessionKeys) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(userSessionKeys)*2)
+	for _, userSessionKey := range userSessionKeys {
+		keys = append(keys, userSessionKey)
+		if sessionID, err := s.client.Get(userSessionKey); err == nil {
+			keys = append(keys, s.sessionKey(sessionID))
+		}
+	}
+
+	_, err = s.client.Delete(keys...)
+	return err
+}
+
+// DeleteUserSession 刪除指定用戶的單一會話
+func (s *SessionCacheService) DeleteUserSession(userID uint, sessionID string) error {
+	_, err := s.client.Delete(s.sessionKey(sessionID), s.userSessionKey(userID, sessionID))
+	return err
+}
+
+// DeleteOtherUserSessions 刪除用戶除指定會話以外的所有會話
+func (s *SessionCacheService) DeleteOtherUserSessions(userID uint, keepSessionID string) error {
+	userSessionKeys, err := s.GetActiveUserSessions(userID)
 	if err != nil {
 		return err
 	}
 
-	if len(sessions) == 0 {
+	keys := make([]string, 0, len(userSessionKeys)*2)
+	for _, userSessionKey := range userSessionKeys {
+		if userSessionKey == s.userSessionKey(userID, keepSessionID) {
+			continue
+		}
+		keys = append(keys, userSessionKey)
+		if sessionID, err := s.client.Get(userSessionKey); err == nil {
+			keys = append(keys, s.sessionKey(sessionID))
+		}
+	}
+
+	if len(keys) == 0 {
 		return nil
 	}
 
-	_, err = s.client.Delete(sessions...)
+	_, err = s.client.Delete(keys...)
 	return err
 }
 
+// GetUserSessions 獲取用戶所有有效會話，以會話 ID 為鍵
+// 主會話已過期的關聯鍵會一併清除
+func (s *SessionCacheService) GetUserSessions(userID uint) (map[string]*SessionData, error) {
+	userSessionKeys, err := s.GetActiveUserSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make(map[string]*SessionData, len(userSessionKeys))
+	for _, userSessionKey := range userSessionKeys {
+		sessionID, err := s.client.Get(userSessionKey)
+		if err != nil {
+			continue
+		}
+
+		data, err := s.GetSession(sessionID)
+		if err != nil {
+			s.client.Delete(userSessionKey)
+			continue
+		}
+		sessions[sessionID] = data
+	}
+
+	return sessions, nil
+}
+
+// RefreshUserSession 刷新帶用戶 ID 關聯的會話過期時間並更新最後活躍時間
+// tokenID 非空時同時更新會話目前綁定的存取權杖
+func (s *SessionCacheService) RefreshUserSession(userID uint, sessionID, tokenID string) error {
+	data, err := s.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	data.LastActive = time.Now()
+	if tokenID != "" {
+		data.TokenID = tokenID
+	}
+	if err := s.StoreSession(sessionID, data); err != nil {
+		return err
+	}
+
+	return s.client.Expire(s.userSessionKey(userID, sessionID), s.ttl)
+}
+
 // SetSessionTTL 設定會話過期時間
 func (s *SessionCacheService) SetSessionTTL(ttl time.Duration) {
 	s.ttl = ttl
@@ -186,6 +282,14 @@ func (s *SessionCacheService) userSessionPattern(userID uint) string {
 	return fmt.Sprintf("user:%d:session:*", userID)
 }
 
+func (s *SessionCacheService) refreshTokenKey(tokenHash string) string {
+	return fmt.Sprintf("refresh_token:%s", tokenHash)
+}
+
+func (s *SessionCacheService) refreshTokenUsedKey(tokenHash string) string {
+	return fmt.Sprintf("refresh_token:%s:used", tokenHash)
+}
+
 // StoreUserSession 儲存帶用戶 ID 關聯的會話
 func (s *SessionCacheService) StoreUserSession(userID uint, sessionID string, data *SessionData) error {
 	// 儲存主會話
@@ -213,3 +317,42 @@ func (s *SessionCacheService) ValidateSession(sessionID string) (*SessionData, b
 
 	return data, true, nil
 }
+
+// StoreRefreshToken 儲存刷新權杖
+func (s *SessionCacheService) StoreRefreshToken(tokenHash string, data *RefreshTokenData, ttl time.Duration) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("序列化刷新權杖資料失敗: %w", err)
+	}
+
+	return s.client.Set(s.refreshTokenKey(tokenHash), string(jsonData), ttl)
+}
+
+// ConsumeRefreshToken 消耗刷新權杖
+// 以 SETNX 原子地標記權杖已使用；第二個返回值為 false 表示權杖先前已被使用過
+func (s *SessionCacheService) ConsumeRefreshToken(tokenHash string) (*RefreshTokenData, bool, error) {
+	key := s.refreshTokenKey(tokenHash)
+
+	result, err := s.client.Get(key)
+	if err != nil {
+		return nil, false, fmt.Errorf("獲取刷新權杖失敗: %w", err)
+	}
+
+	var data RefreshTokenData
+	if err := json.Unmarshal([]byte(result), &data); err != nil {
+		return nil, false, fmt.Errorf("反序列化刷新權杖資料失敗: %w", err)
+	}
+
+	// 使用標記沿用權杖剩餘的存活時間，以便在權杖過期前都能偵測重複使用
+	ttl, err := s.client.TTL(key)
+	if err != nil || ttl <= 0 {
+		ttl = s.ttl
+	}
+
+	firstUse, err := s.client.SetNX(s.refreshTokenUsedKey(tokenHash), "1", ttl)
+	if err != nil {
+		return nil, false, fmt.Errorf("標記刷新權杖失敗: %w", err)
+	}
+
+	return &data, firstUse, nil
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"golang_dev_docker/domain/usecase"
+)
+
+// LocalBlobStore 本機目錄檔案儲存
+// 實作 usecase.BlobStore；目錄不經靜態路由公開，只能透過 FileService 簽發的連結下載
+type LocalBlobStore struct {
+	root string
+}
+
+// NewLocalBlobStore 創建本機目錄檔案儲存
+func NewLocalBlobStore(root string) *LocalBlobStore {
+	return &LocalBlobStore{root: root}
+}
+
+// Put 寫入檔案
+// 先寫入同目錄的暫存檔再更名，更名在同一檔案系統內是原子操作，讀取端不會看到寫到一半的檔案
+func (s *LocalBlobStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("建立檔案目錄失敗: %w", err)
+	}
+
+	file, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("建立暫存檔失敗: %w", err)
+	}
+	temp := file.Name()
+
+	_, writeErr := file.Write(data)
+	if writeErr == nil {
+		writeErr = file.Sync()
+	}
+	if closeErr := file.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr == nil {
+		writeErr = os.Chmod(temp, 0o644)
+	}
+	if writeErr == nil {
+		writeErr = os.Rename(temp, target)
+	}
+	if writeErr != nil {
+		os.Remove(temp)
+		return fmt.Errorf("寫入檔案失敗: %w", writeErr)
+	}
+	return nil
+}
+
+// Open 開啟檔案
+func (s *LocalBlobStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(target)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, usecase.ErrFileNotFound
+		}
+		return nil, fmt.Errorf("開啟檔案失敗: %w", err)
+	}
+	return file, nil
+}
+
+// Delete 刪除檔案，檔案不存在時視為成功
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("刪除檔案失敗: %w", err)
+	}
+	return nil
+}
+
+// path 解析 key 對應的檔案路徑，防止路徑穿越存取目錄以外的檔案
+func (s *LocalBlobStore) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
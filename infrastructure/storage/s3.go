@@ -0,0 +1,298 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang_dev_docker/domain/usecase"
+)
+
+const (
+	s3Algorithm      = "AWS4-HMAC-SHA256"
+	s3Service        = "s3"
+	s3DateFormat     = "20060102T150405Z"
+	s3MaxPresignTTL  = 7 * 24 * time.Hour // S3 預簽章網址的最長有效期
+	s3UnsignedBody   = "UNSIGNED-PAYLOAD"
+	s3ErrorBodyLimit = 1024
+)
+
+// S3Config S3 相容物件儲存配置
+type S3Config struct {
+	Endpoint        string `yaml:"endpoint"` // 例如 https://s3.ap-northeast-1.amazonaws.com 或 http://minio:9000
+	Region          string `yaml:"region"`
+	Bucket          string `yaml:"bucket"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	UsePathStyle    bool   `yaml:"use_path_style"` // MinIO 等以路徑指定 bucket，AWS 使用子網域
+}
+
+// S3BlobStore S3 相容物件儲存
+// 實作 usecase.BlobStore 與 usecase.BlobURLSigner，以 AWS Signature Version 4 簽署請求，
+// 下載連結為 S3 預簽章網址，檔案不經過應用伺服器
+type S3BlobStore struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+}
+
+// NewS3BlobStore 創建 S3 相容物件儲存
+func NewS3BlobStore(config S3Config) (*S3BlobStore, error) {
+	if config.Endpoint == "" || config.Bucket == "" || config.Region == "" {
+		return nil, fmt.Errorf("S3 儲存需要設定 endpoint、region 與 bucket")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("S3 儲存需要設定存取金鑰")
+	}
+
+	endpoint, err := url.Parse(strings.TrimRight(config.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("無效的 S3 endpoint: %s", config.Endpoint)
+	}
+
+	return &S3BlobStore{
+		config:   config,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Put 上傳物件，S3 的 PUT 完成後物件才會出現
+func (s *S3BlobStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("上傳物件失敗: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return s3Error("上傳物件失敗", resp)
+	}
+	return nil
+}
+
+// Open 下載物件
+func (s *S3BlobStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("下載物件失敗: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, usecase.ErrFileNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, s3Error("下載物件失敗", resp)
+	}
+	return resp.Body, nil
+}
+
+// Delete 刪除物件，物件不存在時視為成功
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("刪除物件失敗: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return s3Error("刪除物件失敗", resp)
+	}
+	return nil
+}
+
+// SignedURL 簽發物件的預簽章下載網址
+func (s *S3BlobStore) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.Presign(http.MethodGet, key, ttl, time.Now())
+}
+
+// Presign 簽發指定時間起算的預簽章網址，ttl 上限為 7 天
+func (s *S3BlobStore) Presign(method, key string, ttl time.Duration, now time.Time) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	if ttl <= 0 || ttl > s3MaxPresignTTL {
+		return "", fmt.Errorf("預簽章網址有效時間必須介於 1 秒到 7 天")
+	}
+
+	target := s.objectURL(key)
+	amzDate := now.UTC().Format(s3DateFormat)
+	scope := s.scope(amzDate)
+
+	query := map[string]string{
+		"X-Amz-Algorithm":     s3Algorithm,
+		"X-Amz-Credential":    s.config.AccessKeyID + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(ttl / time.Second)),
+		"X-Amz-SignedHeaders": "host",
+	}
+	canonicalQuery := canonicalQueryString(query)
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		target.EscapedPath(),
+		canonicalQuery,
+		"host:" + target.Host + "\n",
+		"host",
+		s3UnsignedBody,
+	}, "\n")
+
+	signature := s.signature(amzDate, scope, canonicalRequest)
+	return target.String() + "?" + canonicalQuery + "&X-Amz-Signature=" + signature, nil
+}
+
+// newRequest 建立以標頭簽署的請求
+func (s *S3BlobStore) newRequest(ctx context.Context, method, key string, body []byte) (*http.Request, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	target := s.objectURL(key)
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("建立 S3 請求失敗: %w", err)
+	}
+	req.ContentLength = int64(len(body))
+
+	payloadHash := sha256Hex(body)
+	amzDate := time.Now().UTC().Format(s3DateFormat)
+	scope := s.scope(amzDate)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		method,
+		target.EscapedPath(),
+		"",
+		"host:" + target.Host + "\n" +
+			"x-amz-content-sha256:" + payloadHash + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.config.AccessKeyID, scope, signedHeaders, s.signature(amzDate, scope, canonicalRequest)))
+	return req, nil
+}
+
+// objectURL 組合物件網址，路徑依 SigV4 規則逐段跳脫
+func (s *S3BlobStore) objectURL(key string) *url.URL {
+	host := s.endpoint.Host
+	escapedPath := "/" + s3Escape(key, false)
+	if s.config.UsePathStyle {
+		escapedPath = "/" + s3Escape(s.config.Bucket, true) + escapedPath
+	} else {
+		host = s.config.Bucket + "." + host
+	}
+
+	unescaped, _ := url.PathUnescape(escapedPath)
+	return &url.URL{
+		Scheme:  s.endpoint.Scheme,
+		Host:    host,
+		Path:    unescaped,
+		RawPath: escapedPath,
+	}
+}
+
+// scope 簽章適用範圍：日期/區域/服務/aws4_request
+func (s *S3BlobStore) scope(amzDate string) string {
+	return amzDate[:8] + "/" + s.config.Region + "/" + s3Service + "/aws4_request"
+}
+
+// signature 計算 SigV4 簽章
+func (s *S3BlobStore) signature(amzDate, scope, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), amzDate[:8])
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalQueryString 依參數名稱排序並跳脫查詢參數
+func canonicalQueryString(query map[string]string) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = s3Escape(name, true) + "=" + s3Escape(query[name], true)
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Escape 依 SigV4 規則跳脫字串，只保留英數字與 -._~，encodeSlash 為 false 時保留斜線
+func s3Escape(value string, encodeSlash bool) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '.', c == '_', c == '~':
+			builder.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			builder.WriteByte(c)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", c)
+		}
+	}
+	return builder.String()
+}
+
+// s3Error 讀取 S3 錯誤回應
+func s3Error(action string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, s3ErrorBodyLimit))
+	return fmt.Errorf("%s: HTTP %d %s", action, resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
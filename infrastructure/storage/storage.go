@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"golang_dev_docker/domain/usecase"
+)
+
+// 用戶上傳檔案儲存
+// 本機目錄只適合單一伺服器；多個副本共用檔案時使用 S3 相容的物件儲存（AWS S3、MinIO 等）
+
+// Config 檔案儲存配置
+type Config struct {
+	Driver   string   `yaml:"driver"`    // local 或 s3，預設為 local
+	LocalDir string   `yaml:"local_dir"` // 本機儲存目錄
+	S3       S3Config `yaml:"s3"`
+}
+
+// New 依配置建立檔案儲存
+func New(config Config) (usecase.BlobStore, error) {
+	switch config.Driver {
+	case "", "local":
+		if config.LocalDir == "" {
+			return nil, fmt.Errorf("本機檔案儲存需要設定目錄")
+		}
+		return NewLocalBlobStore(config.LocalDir), nil
+	case "s3":
+		return NewS3BlobStore(config.S3)
+	default:
+		return nil, fmt.Errorf("不支援的檔案儲存: %s", config.Driver)
+	}
+}
+
+// validateKey 檢查 key 為不含上層目錄的相對路徑
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key ||
+		key == "." || key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("%w: %s", usecase.ErrInvalidFileKey, key)
+	}
+	return nil
+}
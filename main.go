@@ -1,19 +1,32 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
 	"log"
 	"os"
 	"time"
 
 	"golang_dev_docker/config"
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/infrastructure/imaging"
+	"golang_dev_docker/infrastructure/mail"
 	"golang_dev_docker/infrastructure/mysql"
+	"golang_dev_docker/infrastructure/oidc"
 	"golang_dev_docker/infrastructure/redis"
+	"golang_dev_docker/infrastructure/storage"
 	"golang_dev_docker/server"
+	"golang_dev_docker/server/middleware"
 
 	_ "github.com/go-sql-driver/mysql"
 )
 
 func main() {
+	// 命令列參數：初始管理員（優先於配置檔）
+	adminEmail := flag.String("bootstrap-admin", "", "建立初始超級管理員的電子郵件（密碼由 ADMIN_BOOTSTRAP_PASSWORD 環境變數提供）")
+	flag.Parse()
+
 	// 載入配置
 	cfg, err := config.LoadConfig("")
 	if err != nil {
@@ -25,6 +38,38 @@ func main() {
 		cfg.Database.Host, cfg.Database.Port, cfg.Database.User, cfg.Database.DBName)
 	log.Printf("DSN: %s", cfg.Database.GetDSN())
 
+	// 連結簽章密鑰支援以環境變數設定（例如 "${JWT_SECRET_KEY}"），未配置時每次啟動隨機產生
+	jwtSecret := os.ExpandEnv(cfg.JWT.Secret)
+	if jwtSecret == "" {
+		log.Println("警告: 未配置 JWT 密鑰，使用隨機值，重新啟動後先前的下載連結將失效")
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			log.Fatalf("產生隨機密鑰失敗: %v", err)
+		}
+		jwtSecret = hex.EncodeToString(buf)
+	}
+
+	// JWT 簽章金鑰支援以檔案或環境變數（PEM 內容）提供
+	var jwtKeys *middleware.KeySet
+	if len(cfg.JWT.Keys) > 0 {
+		signingKeyID, keys := cfg.JWT.SigningKeys()
+		keyConfigs := make([]middleware.KeyConfig, 0, len(keys))
+		for _, key := range keys {
+			keyConfigs = append(keyConfigs, middleware.KeyConfig{
+				ID:             key.ID,
+				PrivateKey:     key.PrivateKey,
+				PrivateKeyFile: key.PrivateKeyFile,
+				PublicKey:      key.PublicKey,
+				PublicKeyFile:  key.PublicKeyFile,
+			})
+		}
+		jwtKeys, err = middleware.LoadKeySet(keyConfigs, signingKeyID)
+		if err != nil {
+			log.Fatalf("載入 JWT 簽章金鑰失敗: %v", err)
+		}
+		log.Printf("JWT 簽章金鑰載入完成，簽發金鑰: %s", jwtKeys.SigningKeyID())
+	}
+
 	// 建立伺服器實例
 	serverConfig := &server.ServerConfig{
 		Port:                    cfg.Server.Port,
@@ -36,8 +81,79 @@ func main() {
 		GracefulShutdownTimeout: 30 * time.Second,
 		StaticPath:              "./static",
 		UploadPath:              "./uploads",
-		JWTSecret:               "dating-app-secret-key", // TODO: 從環境變數或配置讀取
+		JWTSecret:               jwtSecret,
+		JWTKeys:                 jwtKeys,
+		AccessTokenTTL:          time.Duration(cfg.JWT.AccessExpiryMinutes) * time.Minute,
+		RefreshTokenTTL:         time.Duration(cfg.JWT.RefreshExpiryDays) * 24 * time.Hour,
+		BaseURL:                 os.ExpandEnv(cfg.Server.BaseURL),
+		RequireStaffTwoFactor:   cfg.Admin.RequireTwoFactor,
+		DeletionGracePeriod:     time.Duration(cfg.Privacy.DeletionGraceDays) * 24 * time.Hour,
+		MaxPhotoSize:            int64(cfg.Upload.MaxFileSizeMB) << 20,
+		Imaging: imaging.Config{
+			MaxDimension: cfg.Upload.MaxImageDimension,
+			JPEGQuality:  cfg.Upload.JPEGQuality,
+		},
+		PhotoDuplicateDistance: cfg.Upload.DuplicateMaxDistance,
+		PhotoLimits: usecase.PhotoLimits{
+			Default: cfg.Upload.MaxPhotosPerUser,
+			Tiers:   cfg.Upload.MaxPhotosByTier,
+		},
+		Storage: storage.Config{
+			Driver:   cfg.Storage.Driver,
+			LocalDir: cfg.Storage.LocalDir,
+			S3: storage.S3Config{
+				Endpoint:        os.ExpandEnv(cfg.Storage.S3Endpoint),
+				Region:          cfg.Storage.S3Region,
+				Bucket:          os.ExpandEnv(cfg.Storage.S3Bucket),
+				AccessKeyID:     os.ExpandEnv(cfg.Storage.S3AccessKeyID),
+				SecretAccessKey: os.ExpandEnv(cfg.Storage.S3SecretKey),
+				UsePathStyle:    cfg.Storage.S3UsePathStyle,
+			},
+		},
+		FileURLTTL: time.Duration(cfg.Storage.URLExpiryMinutes) * time.Minute,
+		Mail: mail.MailConfig{
+			Driver:      cfg.Mail.Driver,
+			SMTPHost:    os.ExpandEnv(cfg.Mail.SMTPHost),
+			SMTPPort:    cfg.Mail.SMTPPort,
+			Username:    os.ExpandEnv(cfg.Mail.Username),
+			Password:    os.ExpandEnv(cfg.Mail.Password),
+			FromAddress: cfg.Mail.FromAddress,
+			OutputDir:   cfg.Mail.OutputDir,
+		},
+	}
+
+	// OIDC 用戶端密鑰支援以環境變數設定
+	for _, provider := range cfg.OIDC.Providers {
+		serverConfig.OIDCProviders = append(serverConfig.OIDCProviders, oidc.ProviderConfig{
+			Name:         provider.Name,
+			DisplayName:  provider.DisplayName,
+			Issuer:       provider.Issuer,
+			ClientID:     os.ExpandEnv(provider.ClientID),
+			ClientSecret: os.ExpandEnv(provider.ClientSecret),
+			RedirectURL:  os.ExpandEnv(provider.RedirectURL),
+			Scopes:       provider.Scopes,
+		})
+	}
+
+	// 密碼規則，未設定的欄位沿用預設值
+	passwordPolicy := usecase.DefaultPasswordPolicy()
+	if cfg.Password.MinLength > 0 {
+		passwordPolicy.MinLength = cfg.Password.MinLength
+	}
+	if cfg.Password.MaxLength > 0 {
+		passwordPolicy.MaxLength = cfg.Password.MaxLength
+	}
+	if cfg.Password.MinCharClasses != nil {
+		passwordPolicy.MinCharClasses = *cfg.Password.MinCharClasses
+	}
+	if cfg.Password.MinStrength != nil {
+		passwordPolicy.MinStrength = *cfg.Password.MinStrength
 	}
+	if cfg.Password.RejectPersonal != nil {
+		passwordPolicy.RejectPersonal = *cfg.Password.RejectPersonal
+	}
+	serverConfig.PasswordPolicy = passwordPolicy
+	serverConfig.BreachedPasswordDir = os.ExpandEnv(cfg.Password.BreachedDir)
 
 	srv := server.NewServer(serverConfig)
 
@@ -90,6 +206,19 @@ func main() {
 		log.Fatalf("初始化業務服務失敗: %v", err)
 	}
 
+	// 建立初始管理員
+	bootstrapEmail := os.ExpandEnv(cfg.Admin.BootstrapEmail)
+	bootstrapPassword := os.ExpandEnv(cfg.Admin.BootstrapPassword)
+	if *adminEmail != "" {
+		bootstrapEmail = *adminEmail
+		bootstrapPassword = os.Getenv("ADMIN_BOOTSTRAP_PASSWORD")
+	}
+	if bootstrapEmail != "" {
+		if err := srv.BootstrapAdmin(bootstrapEmail, bootstrapPassword); err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
+
 	// 初始化中間件
 	env := os.Getenv("APP_ENV")
 	if env == "" {
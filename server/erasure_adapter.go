@@ -0,0 +1,28 @@
+package server
+
+import (
+	"golang_dev_docker/infrastructure/redis"
+)
+
+// UserDataCacheAdapter 用戶資料快取適配器
+// 實作 usecase.UserDataCache，同時清除一般快取與配對快取
+type UserDataCacheAdapter struct {
+	cache    *redis.CacheService
+	matching *redis.MatchingCacheService // 可選
+}
+
+// ClearUserCache 清除用戶相關快取
+func (a *UserDataCacheAdapter) ClearUserCache(userID uint) error {
+	if err := a.cache.ClearUserCache(userID); err != nil {
+		return err
+	}
+	if a.matching != nil {
+		return a.matching.InvalidateUserCache(userID)
+	}
+	return nil
+}
+
+// ClearChatCache 清除配對的聊天快取
+func (a *UserDataCacheAdapter) ClearChatCache(chatID uint) error {
+	return a.cache.ClearChatCache(chatID)
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"golang_dev_docker/domain/usecase"
+)
+
+// archiveKeyPrefix 匯出封存檔在檔案儲存中的鍵前綴
+const archiveKeyPrefix = "exports/"
+
+// BlobArchiveStore 以檔案儲存保存封存檔
+// 實作 usecase.ArchiveStore，所有實例共用同一個儲存，下載與刪除不依賴產生封存檔的節點；
+// 封存檔放在 exports/ 前綴下，不簽發檔案下載連結，只能透過匯出的簽名連結下載
+type BlobArchiveStore struct {
+	store usecase.BlobStore
+}
+
+// NewBlobArchiveStore 創建以檔案儲存為後端的封存檔儲存
+func NewBlobArchiveStore(store usecase.BlobStore) *BlobArchiveStore {
+	return &BlobArchiveStore{store: store}
+}
+
+// SaveArchive 寫入封存檔
+func (s *BlobArchiveStore) SaveArchive(name string, data []byte) error {
+	key, err := archiveKey(name)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Put(context.Background(), key, data, "application/zip"); err != nil {
+		return fmt.Errorf("寫入匯出檔案失敗: %w", err)
+	}
+	return nil
+}
+
+// OpenArchive 開啟封存檔
+func (s *BlobArchiveStore) OpenArchive(name string) (io.ReadCloser, error) {
+	key, err := archiveKey(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.Open(context.Background(), key)
+}
+
+// RemoveArchive 刪除封存檔，檔案不存在時視為成功
+func (s *BlobArchiveStore) RemoveArchive(name string) error {
+	key, err := archiveKey(name)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Delete(context.Background(), key); err != nil {
+		return fmt.Errorf("刪除匯出檔案失敗: %w", err)
+	}
+	return nil
+}
+
+// archiveKey 解析封存檔的儲存鍵，只接受不含目錄的檔名
+func archiveKey(name string) (string, error) {
+	if name == "" || name != path.Base(name) || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("無效的匯出檔名: %s", name)
+	}
+	return archiveKeyPrefix + name, nil
+}
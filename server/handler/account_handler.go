@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"golang_dev_docker/domain/usecase"
+)
+
+// AccountHandler 帳號刪除處理器
+type AccountHandler struct {
+	deletionService *usecase.AccountDeletionService
+}
+
+// NewAccountHandler 創建帳號刪除處理器
+func NewAccountHandler(deletionService *usecase.AccountDeletionService) *AccountHandler {
+	return &AccountHandler{
+		deletionService: deletionService,
+	}
+}
+
+// DeleteAccountRequest 申請刪除帳號請求結構
+// 未設定密碼的帳號改以 Email 確認信申請
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// CancelDeletionRequest 取消刪除帳號請求結構
+type CancelDeletionRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// DeletionTokenRequest 以確認信權杖刪除或取消刪除帳號請求結構
+type DeletionTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// CancellationEmailRequest 寄送取消刪除確認信請求結構
+type CancellationEmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// RequestDeletion 申請刪除帳號
+// DELETE /api/users/account
+func (h *AccountHandler) RequestDeletion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	scheduledAt, err := h.deletionService.RequestDeletion(c.Request.Context(), userID, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrIncorrectPassword):
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "密碼錯誤",
+			})
+		case errors.Is(err, usecase.ErrDeletionConfirmationRequired):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+				"code":  "EMAIL_CONFIRMATION_REQUIRED",
+			})
+		case errors.Is(err, usecase.ErrAccountPendingDeletion):
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "申請刪除帳號失敗",
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":               "帳號已停用並排定刪除，在預定時間前可取消刪除",
+		"deletion_scheduled_at": scheduledAt,
+	})
+}
+
+// CancelDeletion 在寬限期內取消刪除帳號
+// POST /api/auth/account/cancel-deletion
+func (h *AccountHandler) CancelDeletion(c *gin.Context) {
+	var req CancelDeletionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.deletionService.CancelDeletion(c.Request.Context(), req.Email, req.Password); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidCredentials):
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": err.Error(),
+			})
+		case errors.Is(err, usecase.ErrDeletionNotScheduled):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "取消刪除帳號失敗",
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已取消刪除，帳號已重新啟用，請重新登入",
+	})
+}
+
+// SendDeletionConfirmation 寄送刪除帳號確認信（未設定密碼的帳號）
+// POST /api/users/account/deletion-confirmation
+func (h *AccountHandler) SendDeletionConfirmation(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.deletionService.SendDeletionConfirmation(c.Request.Context(), userID); err != nil {
+		h.respondConfirmationError(c, err, "寄送確認信失敗")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "確認信已寄出，請依信中權杖確認刪除",
+	})
+}
+
+// ConfirmDeletion 以確認信權杖刪除帳號
+// POST /api/auth/account/confirm-deletion
+func (h *AccountHandler) ConfirmDeletion(c *gin.Context) {
+	var req DeletionTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	scheduledAt, err := h.deletionService.ConfirmDeletion(c.Request.Context(), req.Token)
+	if err != nil {
+		h.respondConfirmationError(c, err, "申請刪除帳號失敗")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":               "帳號已停用並排定刪除，在預定時間前可取消刪除",
+		"deletion_scheduled_at": scheduledAt,
+	})
+}
+
+// SendCancellationConfirmation 寄送取消刪除確認信（未設定密碼的帳號）
+// POST /api/auth/account/cancel-deletion/email
+func (h *AccountHandler) SendCancellationConfirmation(c *gin.Context) {
+	var req CancellationEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.deletionService.SendCancellationConfirmation(c.Request.Context(), req.Email); err != nil {
+		h.respondConfirmationError(c, err, "寄送確認信失敗")
+		return
+	}
+
+	// 不論帳號是否存在都返回相同訊息
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "若帳號已排定刪除且未設定密碼，確認信已寄出",
+	})
+}
+
+// ConfirmCancellation 以確認信權杖取消刪除帳號
+// POST /api/auth/account/cancel-deletion/confirm
+func (h *AccountHandler) ConfirmCancellation(c *gin.Context) {
+	var req DeletionTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.deletionService.ConfirmCancellation(c.Request.Context(), req.Token); err != nil {
+		h.respondConfirmationError(c, err, "取消刪除帳號失敗")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已取消刪除，帳號已重新啟用，請重新登入",
+	})
+}
+
+// respondConfirmationError 將確認信流程的錯誤轉換為 HTTP 回應
+func (h *AccountHandler) respondConfirmationError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidDeletionToken):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, usecase.ErrDeletionPasswordRequired):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, usecase.ErrAccountPendingDeletion):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, usecase.ErrDeletionNotScheduled):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, usecase.ErrDeletionConfirmationUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"message": err.Error(),
+		})
+	}
+}
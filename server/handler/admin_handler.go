@@ -0,0 +1,380 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+	"golang_dev_docker/domain/usecase"
+)
+
+// AdminHandler 管理後台處理器
+type AdminHandler struct {
+	reportService  *usecase.ReportService
+	roleService    *usecase.RoleService
+	accountService *usecase.AccountModerationService
+}
+
+// NewAdminHandler 創建管理後台處理器
+func NewAdminHandler(
+	reportService *usecase.ReportService,
+	roleService *usecase.RoleService,
+	accountService *usecase.AccountModerationService,
+) *AdminHandler {
+	return &AdminHandler{
+		reportService:  reportService,
+		roleService:    roleService,
+		accountService: accountService,
+	}
+}
+
+// ReviewReportRequest 審核檢舉請求結構
+type ReviewReportRequest struct {
+	Status      string                    `json:"status" binding:"required"`
+	ReviewNotes string                    `json:"review_notes"`
+	Action      *usecase.ModerationAction `json:"action,omitempty"`
+}
+
+// AssignRoleRequest 指派角色請求結構
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// SuspendUserRequest 暫停帳號請求結構
+type SuspendUserRequest struct {
+	Days   int    `json:"days" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+// AccountActionRequest 封禁或恢復帳號請求結構
+type AccountActionRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// GetPendingReports 獲取待審核檢舉
+// GET /admin/reports/pending
+func (h *AdminHandler) GetPendingReports(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit 參數格式錯誤",
+		})
+		return
+	}
+
+	reports, err := h.reportService.GetPendingReports(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取待審核檢舉失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reports": reports,
+		"count":   len(reports),
+	})
+}
+
+// ReviewReport 審核檢舉
+// PUT /admin/reports/:id/review
+func (h *AdminHandler) ReviewReport(c *gin.Context) {
+	reviewerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	reportID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "無效的檢舉ID",
+		})
+		return
+	}
+
+	var req ReviewReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// 審核者一律取自 token，不接受客戶端指定
+	err = h.reportService.ReviewReport(c.Request.Context(), &usecase.ReviewReportRequest{
+		ReportID:    uint(reportID),
+		ReviewerID:  reviewerID,
+		Status:      entity.ReportStatus(req.Status),
+		ReviewNotes: req.ReviewNotes,
+		Action:      req.Action,
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrPermissionDenied) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+				"code":  "PERMISSION_DENIED",
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "審核檢舉失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "檢舉審核完成",
+	})
+}
+
+// GetReportStats 獲取檢舉統計
+// GET /admin/reports/stats
+func (h *AdminHandler) GetReportStats(c *gin.Context) {
+	params := repository.ReportStatsParams{
+		GroupByCategory: true,
+		GroupByStatus:   true,
+	}
+
+	if category := c.Query("category"); category != "" {
+		reportCategory := entity.ReportCategory(category)
+		if !reportCategory.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "無效的檢舉類別",
+			})
+			return
+		}
+		params.Category = &reportCategory
+	}
+
+	if status := c.Query("status"); status != "" {
+		reportStatus := entity.ReportStatus(status)
+		if !reportStatus.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "無效的檢舉狀態",
+			})
+			return
+		}
+		params.Status = &reportStatus
+	}
+
+	stats, err := h.reportService.GetReportStats(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取檢舉統計失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stats": stats,
+	})
+}
+
+// AssignRole 指派用戶角色
+// PUT /admin/users/:id/role
+func (h *AdminHandler) AssignRole(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "無效的用戶ID",
+		})
+		return
+	}
+
+	var req AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	role := entity.UserRole(req.Role)
+	if err := h.roleService.AssignRole(c.Request.Context(), actorID, uint(targetID), role); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrPermissionDenied):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+				"code":  "PERMISSION_DENIED",
+			})
+		case errors.Is(err, usecase.ErrInvalidRole), errors.Is(err, usecase.ErrCannotChangeOwnRole):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "指派角色失敗",
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "角色已更新",
+		"user_id": targetID,
+		"role":    role,
+	})
+}
+
+// SuspendUser 暫停用戶帳號
+// PUT /admin/users/:id/suspend
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	actorID, targetID, ok := parseAccountTarget(c)
+	if !ok {
+		return
+	}
+
+	var req SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.accountService.SuspendUser(c.Request.Context(), targetID, &actorID, req.Days, req.Reason); err != nil {
+		respondAccountActionError(c, err, "暫停帳號失敗")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "帳號已暫停",
+		"user_id": targetID,
+		"days":    req.Days,
+	})
+}
+
+// BanUser 永久封禁用戶帳號
+// PUT /admin/users/:id/ban
+func (h *AdminHandler) BanUser(c *gin.Context) {
+	actorID, targetID, ok := parseAccountTarget(c)
+	if !ok {
+		return
+	}
+
+	var req AccountActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.accountService.BanUser(c.Request.Context(), targetID, &actorID, req.Reason); err != nil {
+		respondAccountActionError(c, err, "封禁帳號失敗")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "帳號已封禁",
+		"user_id": targetID,
+	})
+}
+
+// ReinstateUser 恢復被暫停或封禁的用戶帳號
+// PUT /admin/users/:id/reinstate
+func (h *AdminHandler) ReinstateUser(c *gin.Context) {
+	actorID, targetID, ok := parseAccountTarget(c)
+	if !ok {
+		return
+	}
+
+	var req AccountActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.accountService.ReinstateUser(c.Request.Context(), targetID, &actorID, req.Reason); err != nil {
+		respondAccountActionError(c, err, "恢復帳號失敗")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "帳號已恢復",
+		"user_id": targetID,
+	})
+}
+
+// parseAccountTarget 解析操作者與目標用戶 ID，失敗時直接寫入錯誤回應
+func parseAccountTarget(c *gin.Context) (uint, uint, bool) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "無效的用戶ID",
+		})
+		return 0, 0, false
+	}
+
+	return actorID, uint(targetID), true
+}
+
+// respondAccountActionError 將帳號停權操作的錯誤對應為 HTTP 回應
+func respondAccountActionError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, usecase.ErrPermissionDenied):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+			"code":  "PERMISSION_DENIED",
+		})
+	case errors.Is(err, usecase.ErrInvalidSuspension),
+		errors.Is(err, usecase.ErrModerationReason),
+		errors.Is(err, usecase.ErrAccountAlreadyOpen),
+		errors.Is(err, usecase.ErrAccountBanned):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"message": err.Error(),
+		})
+	}
+}
+
+// currentUserID 從上下文獲取目前用戶 ID，失敗時直接寫入錯誤回應
+func currentUserID(c *gin.Context) (uint, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未授權訪問",
+		})
+		return 0, false
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return 0, false
+	}
+
+	return userIDUint, true
+}
@@ -1,29 +1,38 @@
 package handler
 
 import (
+	"errors"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 
 	"golang_dev_docker/domain/usecase"
 )
 
 // AuthHandler 認證處理器
 type AuthHandler struct {
-	userService *usecase.UserService
-	jwtSecret   string
+	userService     *usecase.UserService
+	authService     *usecase.AuthService
+	emailService    *usecase.EmailVerificationService
+	passwordService *usecase.PasswordService
 }
 
-// 全域認證處理器實例
-var authHandler *AuthHandler
-
-// SetUserServiceForAuth 設置認證處理器的用戶服務依賴
-func SetUserServiceForAuth(userService *usecase.UserService, jwtSecret string) {
-	authHandler = &AuthHandler{
-		userService: userService,
-		jwtSecret:   jwtSecret,
+// NewAuthHandler 創建認證處理器
+func NewAuthHandler(
+	userService *usecase.UserService,
+	authService *usecase.AuthService,
+	emailService *usecase.EmailVerificationService,
+	passwordService *usecase.PasswordService,
+) *AuthHandler {
+	return &AuthHandler{
+		userService:     userService,
+		authService:     authService,
+		emailService:    emailService,
+		passwordService: passwordService,
 	}
 }
 
@@ -43,16 +52,54 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-// RegisterHandler 處理用戶註冊請求
-// POST /api/auth/register
-func RegisterHandler(c *gin.Context) {
-	if authHandler == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "認證服務未初始化",
-		})
-		return
-	}
+// VerifyMFARequest 登入第二步驗證請求結構
+// code 可為 6 位數驗證碼或恢復碼
+type VerifyMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// BeginMFAEnrollmentRequest 登入過程中綁定驗證器請求結構
+type BeginMFAEnrollmentRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+// VerifyEmailRequest 電子郵件驗證請求結構
+type VerifyEmailRequest struct {
+	Email string `json:"email" form:"email" binding:"required,email"`
+	Code  string `json:"code" form:"code" binding:"required,len=6,numeric"`
+}
+
+// ResendVerificationRequest 重新發送驗證信請求結構
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ForgotPasswordRequest 忘記密碼請求結構
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest 重設密碼請求結構
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+// ChangePasswordRequest 變更密碼請求結構
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+// RefreshRequest 刷新權杖請求結構
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
 
+// Register 處理用戶註冊請求
+// POST /api/auth/register
+func (h *AuthHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -82,7 +129,7 @@ func RegisterHandler(c *gin.Context) {
 	}
 
 	// 調用用戶服務註冊
-	userResponse, err := authHandler.userService.Register(c.Request.Context(), serviceReq)
+	userResponse, err := h.userService.Register(c.Request.Context(), serviceReq)
 	if err != nil {
 		// 根據錯誤類型返回適當的 HTTP 狀態碼
 		if err.Error() == "用戶必須年滿18歲才能註冊" {
@@ -99,6 +146,10 @@ func RegisterHandler(c *gin.Context) {
 			})
 			return
 		}
+		if isPasswordPolicyError(err) {
+			respondPasswordPolicyError(c, err)
+			return
+		}
 
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "註冊失敗",
@@ -107,29 +158,28 @@ func RegisterHandler(c *gin.Context) {
 		return
 	}
 
+	// 發送驗證信，失敗不影響註冊結果，用戶可稍後重新發送
+	if err := h.emailService.SendVerification(c.Request.Context(), userResponse.ID); err != nil {
+		log.Printf("發送驗證信失敗 (用戶 %d): %v", userResponse.ID, err)
+	}
+
 	// 成功回應
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "註冊成功",
+		"message": "註冊成功，請至信箱收取驗證碼",
 		"user_id": userResponse.ID,
 		"user": gin.H{
-			"id":          userResponse.ID,
-			"email":       userResponse.Email,
-			"is_verified": userResponse.IsVerified,
-			"age":         userResponse.Age,
+			"id":             userResponse.ID,
+			"email":          userResponse.Email,
+			"is_verified":    userResponse.IsVerified,
+			"email_verified": userResponse.EmailVerified,
+			"age":            userResponse.Age,
 		},
 	})
 }
 
-// LoginHandler 處理用戶登入請求
+// Login 處理用戶登入請求
 // POST /api/auth/login
-func LoginHandler(c *gin.Context) {
-	if authHandler == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "認證服務未初始化",
-		})
-		return
-	}
-
+func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -145,10 +195,43 @@ func LoginHandler(c *gin.Context) {
 		Password: req.Password,
 	}
 
-	// 調用用戶服務登入
-	userResponse, err := authHandler.userService.Login(c.Request.Context(), serviceReq)
+	// 調用認證服務登入並建立會話
+	result, err := h.authService.Login(c.Request.Context(), serviceReq, usecase.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
 	if err != nil {
-		// 登入錯誤統一返回 401
+		// 登入失敗次數過多返回 429 並告知重試時間
+		if respondLoginThrottled(c, err) {
+			return
+		}
+
+		// 停權帳號返回 403 與對應錯誤碼，讓客戶端顯示停權說明
+		switch {
+		case errors.Is(err, usecase.ErrAccountSuspended):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "登入失敗",
+				"message": err.Error(),
+				"code":    "ACCOUNT_SUSPENDED",
+			})
+			return
+		case errors.Is(err, usecase.ErrAccountBanned):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "登入失敗",
+				"message": err.Error(),
+				"code":    "ACCOUNT_BANNED",
+			})
+			return
+		case errors.Is(err, usecase.ErrAccountPendingDeletion):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "登入失敗",
+				"message": err.Error(),
+				"code":    "ACCOUNT_PENDING_DELETION",
+			})
+			return
+		}
+
+		// 其他登入錯誤統一返回 401
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error":   "登入失敗",
 			"message": err.Error(),
@@ -156,36 +239,584 @@ func LoginHandler(c *gin.Context) {
 		return
 	}
 
-	// 生成 JWT token
-	token, err := generateJWT(userResponse.ID, authHandler.jwtSecret)
+	// 需要兩步驟驗證時只返回暫時權杖
+	if result.MFAChallenge != nil {
+		message := "請輸入驗證器 App 產生的驗證碼"
+		if result.MFAChallenge.EnrollmentRequired {
+			message = "您的帳號必須啟用兩步驟驗證，請先綁定驗證器"
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message":             message,
+			"mfa_required":        true,
+			"mfa_token":           result.MFAChallenge.Token,
+			"expires_in":          result.MFAChallenge.ExpiresIn,
+			"enrollment_required": result.MFAChallenge.EnrollmentRequired,
+		})
+		return
+	}
+
+	respondLoginSuccess(c, result)
+}
+
+// VerifyMFA 完成登入第二步驗證
+// POST /api/auth/login/2fa
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req VerifyMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.authService.CompleteMFALogin(c.Request.Context(), req.MFAToken, req.Code, usecase.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Token 生成失敗",
+		// 第二步驗證失敗次數過多時與密碼登入相同返回 429
+		if respondLoginThrottled(c, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, usecase.ErrAccountSuspended):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "登入失敗",
+				"message": err.Error(),
+				"code":    "ACCOUNT_SUSPENDED",
+			})
+		case errors.Is(err, usecase.ErrAccountBanned):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "登入失敗",
+				"message": err.Error(),
+				"code":    "ACCOUNT_BANNED",
+			})
+		case errors.Is(err, usecase.ErrTwoFactorNotStarted):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"code":  "MFA_ENROLLMENT_REQUIRED",
+			})
+		default:
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "兩步驟驗證失敗",
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	respondLoginSuccess(c, result)
+}
+
+// respondLoginThrottled 登入或第二步驗證被限制時回應 429 與重試時間，已回應時返回 true
+func respondLoginThrottled(c *gin.Context, err error) bool {
+	var throttleErr *usecase.LoginThrottleError
+	if !errors.As(err, &throttleErr) {
+		return false
+	}
+
+	code := "LOGIN_THROTTLED"
+	if errors.Is(err, usecase.ErrLoginLocked) {
+		code = "LOGIN_LOCKED"
+	}
+	retryAfter := int(math.Ceil(throttleErr.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       "登入失敗",
+		"message":     err.Error(),
+		"code":        code,
+		"retry_after": retryAfter,
+	})
+	return true
+}
+
+// BeginMFAEnrollment 登入過程中綁定驗證器（用於必須啟用兩步驟驗證的帳號）
+// POST /api/auth/login/2fa/enroll
+func (h *AuthHandler) BeginMFAEnrollment(c *gin.Context) {
+	var req BeginMFAEnrollmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	enrollment, err := h.authService.BeginMFAEnrollment(c.Request.Context(), req.MFAToken)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, usecase.ErrInvalidMFAToken) {
+			status = http.StatusUnauthorized
+		}
+		c.JSON(status, gin.H{
+			"error":   "無法開始綁定驗證器",
+			"message": err.Error(),
 		})
 		return
 	}
 
-	// 成功回應
 	c.JSON(http.StatusOK, gin.H{
-		"message": "登入成功",
-		"token":   token,
+		"message":          "請以驗證器 App 掃描 QR Code，並輸入驗證碼完成登入",
+		"secret":           enrollment.Secret,
+		"provisioning_uri": enrollment.ProvisioningURI,
+	})
+}
+
+// respondLoginSuccess 返回登入成功的權杖與用戶資訊
+func respondLoginSuccess(c *gin.Context, result *usecase.LoginResult) {
+	tokens, userResponse := result.Tokens, result.User
+
+	response := gin.H{
+		"message":       "登入成功",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"token_type":    tokens.TokenType,
+		"expires_in":    tokens.ExpiresIn,
 		"user": gin.H{
-			"id":          userResponse.ID,
-			"email":       userResponse.Email,
-			"is_verified": userResponse.IsVerified,
-			"age":         userResponse.Age,
+			"id":                   userResponse.ID,
+			"email":                userResponse.Email,
+			"is_verified":          userResponse.IsVerified,
+			"email_verified":       userResponse.EmailVerified,
+			"age":                  userResponse.Age,
+			"registration_pending": userResponse.RegistrationPending,
 		},
+	}
+
+	// 登入時完成強制綁定，恢復碼只會顯示這一次
+	if len(result.RecoveryCodes) > 0 {
+		response["recovery_codes"] = result.RecoveryCodes
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// VerifyEmail 確認電子郵件驗證碼
+// POST /api/auth/verify-email
+// GET /api/auth/verify-email?email=...&code=...（驗證信連結）
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.emailService.ConfirmEmail(c.Request.Context(), req.Email, req.Code); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidVerificationCode):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"code":  "INVALID_VERIFICATION_CODE",
+			})
+		case errors.Is(err, usecase.ErrTooManyVerifyAttempts):
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error": err.Error(),
+				"code":  "TOO_MANY_ATTEMPTS",
+			})
+		case errors.Is(err, usecase.ErrVerificationUnavailable):
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "電子郵件驗證失敗",
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "電子郵件驗證成功",
+	})
+}
+
+// ResendVerification 重新發送驗證信
+// POST /api/auth/verify-email/resend
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.emailService.ResendVerification(c.Request.Context(), req.Email); err != nil {
+		if errors.Is(err, usecase.ErrVerificationUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		log.Printf("重新發送驗證信失敗: %v", err)
+	}
+
+	// 不論帳號是否存在都回應相同訊息，避免帳號探測
+	c.JSON(http.StatusOK, gin.H{
+		"message": "如果該電子郵件尚未驗證，驗證信已重新寄出",
+	})
+}
+
+// Refresh 以刷新權杖換發新的權杖組
+// POST /api/auth/refresh
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	tokens, err := h.authService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": err.Error(),
+				"code":  "REFRESH_TOKEN_REUSED",
+			})
+		case errors.Is(err, usecase.ErrInvalidRefreshToken):
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": err.Error(),
+				"code":  "INVALID_REFRESH_TOKEN",
+			})
+		case errors.Is(err, usecase.ErrSessionStoreUnavailable):
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "刷新權杖失敗",
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "權杖已更新",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"token_type":    tokens.TokenType,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+// Logout 登出目前的會話
+// POST /api/auth/logout
+func (h *AuthHandler) Logout(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未授權訪問",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	err := h.authService.Logout(
+		c.Request.Context(),
+		userIDUint,
+		c.GetString("session_id"),
+		c.GetString("token_id"),
+		c.GetTime("token_expires_at"),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "登出失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "登出成功",
+	})
+}
+
+// LogoutAll 登出所有裝置上的會話
+// POST /api/auth/logout-all
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未授權訪問",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	err := h.authService.LogoutAll(
+		c.Request.Context(),
+		userIDUint,
+		c.GetString("token_id"),
+		c.GetTime("token_expires_at"),
+	)
+	if err != nil {
+		if errors.Is(err, usecase.ErrSessionStoreUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "登出所有裝置失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已登出所有裝置",
 	})
 }
 
-// generateJWT 生成 JWT token
-func generateJWT(userID uint, secret string) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(), // 24小時過期
-		"iat":     time.Now().Unix(),
+// ListSessions 列出目前用戶所有登入中的裝置
+// GET /api/auth/sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	sessions, err := h.authService.ListSessions(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrSessionStoreUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取會話列表失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	currentSessionID := c.GetString("session_id")
+	items := make([]gin.H, 0, len(sessions))
+	for _, session := range sessions {
+		items = append(items, gin.H{
+			"id":          session.SessionID,
+			"ip_address":  session.IPAddress,
+			"user_agent":  session.UserAgent,
+			"login_time":  session.LoginTime,
+			"last_active": session.LastActive,
+			"current":     session.SessionID == currentSessionID,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": items,
+		"count":    len(items),
+	})
+}
+
+// RevokeSession 登出指定裝置的會話
+// DELETE /api/auth/sessions/:id
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.Request.Context(), userID, c.Param("id")); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrSessionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+			})
+		case errors.Is(err, usecase.ErrSessionStoreUnavailable):
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "撤銷會話失敗",
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已登出該裝置",
+	})
+}
+
+// ForgotPassword 發送密碼重設信
+// POST /api/auth/password/forgot
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.passwordService.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		if errors.Is(err, usecase.ErrPasswordResetUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		log.Printf("發送密碼重設信失敗: %v", err)
+	}
+
+	// 不論帳號是否存在都回應相同訊息，避免帳號探測
+	c.JSON(http.StatusOK, gin.H{
+		"message": "如果該電子郵件已註冊，密碼重設信已寄出",
+	})
+}
+
+// ResetPassword 以重設權杖設定新密碼
+// POST /api/auth/password/reset
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.passwordService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidResetToken):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"code":  "INVALID_RESET_TOKEN",
+			})
+		case errors.Is(err, usecase.ErrPasswordResetUnavailable):
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": err.Error(),
+			})
+		case isPasswordPolicyError(err):
+			respondPasswordPolicyError(c, err)
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "重設密碼失敗",
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "密碼已重設，請使用新密碼重新登入",
+	})
+}
+
+// ChangePassword 變更目前用戶的密碼
+// PUT /api/users/password
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未授權訪問",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	err := h.passwordService.ChangePassword(
+		c.Request.Context(),
+		userIDUint,
+		c.GetString("session_id"),
+		req.OldPassword,
+		req.NewPassword,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrIncorrectPassword):
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": err.Error(),
+				"code":  "INCORRECT_PASSWORD",
+			})
+		case errors.Is(err, usecase.ErrPasswordUnchanged):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+		case isPasswordPolicyError(err):
+			respondPasswordPolicyError(c, err)
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "變更密碼失敗",
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "密碼已變更，其他裝置已登出",
+	})
+}
+
+// isPasswordPolicyError 判斷是否為不符合密碼規則的錯誤
+func isPasswordPolicyError(err error) bool {
+	return errors.Is(err, usecase.ErrWeakPassword) || errors.Is(err, usecase.ErrBreachedPassword)
+}
+
+// respondPasswordPolicyError 回應不符合密碼規則的錯誤
+func respondPasswordPolicyError(c *gin.Context, err error) {
+	if errors.Is(err, usecase.ErrBreachedPassword) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": usecase.ErrBreachedPassword.Error(),
+			"code":  "BREACHED_PASSWORD",
+		})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":   "密碼強度不足",
+		"message": err.Error(),
+		"code":    "WEAK_PASSWORD",
+	})
 }
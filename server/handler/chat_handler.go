@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -17,12 +18,9 @@ type ChatHandler struct {
 	matchingService *usecase.MatchingService
 }
 
-// 全域聊天處理器實例
-var chatHandler *ChatHandler
-
-// SetChatService 設置聊天處理器的服務依賴
-func SetChatService(chatService *usecase.ChatService, matchingService *usecase.MatchingService) {
-	chatHandler = &ChatHandler{
+// NewChatHandler 創建聊天處理器
+func NewChatHandler(chatService *usecase.ChatService, matchingService *usecase.MatchingService) *ChatHandler {
+	return &ChatHandler{
 		chatService:     chatService,
 		matchingService: matchingService,
 	}
@@ -30,24 +28,14 @@ func SetChatService(chatService *usecase.ChatService, matchingService *usecase.M
 
 // SendMessageRequest 發送訊息請求結構
 type SendMessageRequest struct {
-	MatchID  uint    `json:"match_id" binding:"required"`
-	Content  string  `json:"content" binding:"required"`
-	Type     string  `json:"type"` // "text", "image", "file"
-	FileName *string `json:"file_name,omitempty"`
-	FileSize *int64  `json:"file_size,omitempty"`
-	FilePath *string `json:"file_path,omitempty"`
+	MatchID uint   `json:"match_id" binding:"required"`
+	Content string `json:"content" binding:"required"`
+	Type    string `json:"type"` // "text"；圖片與檔案請使用附件上傳
 }
 
-// GetChatMatchesHandler 獲取用戶的配對聊天列表
+// GetChatMatches 獲取用戶的配對聊天列表
 // GET /chat/matches
-func GetChatMatchesHandler(c *gin.Context) {
-	if chatHandler == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "聊天服務未初始化",
-		})
-		return
-	}
-
+func (h *ChatHandler) GetChatMatches(c *gin.Context) {
 	// 從 JWT token 中獲取用戶 ID
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -66,7 +54,7 @@ func GetChatMatchesHandler(c *gin.Context) {
 	}
 
 	// 獲取用戶的配對成功列表（用於聊天）
-	matchList, err := chatHandler.matchingService.GetUserMatches(c.Request.Context(), userIDUint, entity.MatchStatusMatched)
+	matchList, err := h.matchingService.GetUserMatches(c.Request.Context(), userIDUint, entity.MatchStatusMatched)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "獲取配對列表失敗",
@@ -76,7 +64,7 @@ func GetChatMatchesHandler(c *gin.Context) {
 	}
 
 	// 獲取聊天列表（包含最後訊息等資訊）
-	chatList, err := chatHandler.chatService.GetActiveChatList(c.Request.Context(), userIDUint)
+	chatList, err := h.chatService.GetActiveChatList(c.Request.Context(), userIDUint)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "獲取聊天列表失敗",
@@ -137,16 +125,9 @@ func GetChatMatchesHandler(c *gin.Context) {
 	})
 }
 
-// SendChatMessageHandler 發送聊天訊息
+// SendChatMessage 發送聊天訊息
 // POST /chat/messages
-func SendChatMessageHandler(c *gin.Context) {
-	if chatHandler == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "聊天服務未初始化",
-		})
-		return
-	}
-
+func (h *ChatHandler) SendChatMessage(c *gin.Context) {
 	// 從 JWT token 中獲取用戶 ID
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -187,7 +168,7 @@ func SendChatMessageHandler(c *gin.Context) {
 	}
 
 	// 首先需要確定接收者ID（配對中的另一方）
-	matchList, err := chatHandler.matchingService.GetUserMatches(c.Request.Context(), userIDUint, entity.MatchStatusMatched)
+	matchList, err := h.matchingService.GetUserMatches(c.Request.Context(), userIDUint, entity.MatchStatusMatched)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "獲取配對資訊失敗",
@@ -226,13 +207,10 @@ func SendChatMessageHandler(c *gin.Context) {
 		MatchID:    req.MatchID,
 		Type:       msgType,
 		Content:    req.Content,
-		FileName:   req.FileName,
-		FileSize:   req.FileSize,
-		FilePath:   req.FilePath,
 	}
 
 	// 調用聊天服務發送訊息
-	response, err := chatHandler.chatService.SendMessage(c.Request.Context(), serviceReq)
+	response, err := h.chatService.SendMessage(c.Request.Context(), serviceReq)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "發送訊息失敗",
@@ -262,16 +240,114 @@ func SendChatMessageHandler(c *gin.Context) {
 	})
 }
 
-// GetChatHistoryHandler 獲取聊天歷史
-// GET /chat/matches/:matchId/messages
-func GetChatHistoryHandler(c *gin.Context) {
-	if chatHandler == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "聊天服務未初始化",
+// SendAttachment 傳送圖片或 PDF 附件
+// 以 multipart/form-data 的 file 欄位上傳，caption 欄位為可選的訊息內容
+// POST /chat/matches/:matchId/attachments
+func (h *ChatHandler) SendAttachment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	matchID, err := strconv.ParseUint(c.Param("matchId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "配對ID格式錯誤",
+		})
+		return
+	}
+
+	// 限制請求主體大小，超過時讀取會失敗而不是把整個檔案讀進記憶體
+	maxSize := usecase.DefaultMaxAttachmentSize
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":   "附件上傳失敗",
+				"message": usecase.ErrAttachmentTooLarge.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": "請以 multipart/form-data 的 file 欄位上傳附件",
 		})
 		return
 	}
 
+	if file.Size > maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":   "附件上傳失敗",
+			"message": usecase.ErrAttachmentTooLarge.Error(),
+		})
+		return
+	}
+
+	data, err := readFormFile(file, maxSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "讀取附件失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	message, err := h.chatService.SendAttachment(c.Request.Context(), &usecase.SendAttachmentRequest{
+		SenderID: userID,
+		MatchID:  uint(matchID),
+		FileName: file.Filename,
+		Caption:  c.PostForm("caption"),
+		Data:     data,
+	})
+	if err != nil {
+		var status int
+		switch {
+		case errors.Is(err, usecase.ErrAttachmentTooLarge):
+			status = http.StatusRequestEntityTooLarge
+		case errors.Is(err, usecase.ErrUnsupportedAttachmentType):
+			status = http.StatusUnsupportedMediaType
+		case errors.Is(err, usecase.ErrChatMatchNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, usecase.ErrChatForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, usecase.ErrAttachmentUnavailable):
+			status = http.StatusServiceUnavailable
+		case errors.Is(err, usecase.ErrAttachmentEmpty), errors.Is(err, usecase.ErrAttachmentCaptionTooLong),
+			errors.Is(err, usecase.ErrChatNotMatched):
+			status = http.StatusBadRequest
+		default:
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, gin.H{
+			"error":   "附件傳送失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "附件傳送成功",
+		"data": gin.H{
+			"id":         message.ID,
+			"content":    message.Content,
+			"type":       message.Type,
+			"file_name":  message.FileName,
+			"file_size":  message.FileSize,
+			"file_url":   message.FileURL,
+			"created_at": message.CreatedAt,
+			"status":     message.Status,
+		},
+	})
+}
+
+// GetChatHistory 獲取聊天歷史
+// GET /chat/matches/:matchId/messages
+func (h *ChatHandler) GetChatHistory(c *gin.Context) {
 	// 從 JWT token 中獲取用戶 ID
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -323,7 +399,7 @@ func GetChatHistoryHandler(c *gin.Context) {
 	}
 
 	// 調用聊天服務獲取歷史
-	history, err := chatHandler.chatService.GetChatHistory(c.Request.Context(), historyReq)
+	history, err := h.chatService.GetChatHistory(c.Request.Context(), historyReq)
 	if err != nil {
 		if err.Error() == "配對不存在" || err.Error() == "無權限查看此聊天記錄" {
 			c.JSON(http.StatusForbidden, gin.H{
@@ -351,13 +427,14 @@ func GetChatHistoryHandler(c *gin.Context) {
 			"content":     msg.Content,
 			"file_name":   msg.FileName,
 			"file_path":   msg.FilePath,
+			"file_url":    msg.FileURL,
 			"status":      msg.Status,
 			"created_at":  msg.CreatedAt,
 		})
 	}
 
 	// 標記訊息為已讀
-	if err := chatHandler.chatService.MarkMessagesAsRead(c.Request.Context(), uint(matchID), userIDUint); err != nil {
+	if err := h.chatService.MarkMessagesAsRead(c.Request.Context(), uint(matchID), userIDUint); err != nil {
 		// 記錄錯誤但不影響歷史返回
 		// 可以考慮使用日誌系統記錄
 	}
@@ -370,3 +447,52 @@ func GetChatHistoryHandler(c *gin.Context) {
 		"limit":       limit,
 	})
 }
+
+// MarkMessagesAsRead 將配對中的訊息標記為已讀
+// PUT /chat/matches/:matchId/read
+func (h *ChatHandler) MarkMessagesAsRead(c *gin.Context) {
+	// 從 JWT token 中獲取用戶 ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未授權訪問",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	matchID, err := strconv.ParseUint(c.Param("matchId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "配對ID格式錯誤",
+		})
+		return
+	}
+
+	if err := h.chatService.MarkMessagesAsRead(c.Request.Context(), uint(matchID), userIDUint); err != nil {
+		if err.Error() == "無權限操作此聊天" {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "無權限操作",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "標記已讀失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "訊息已標記為已讀",
+	})
+}
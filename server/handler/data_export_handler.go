@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"golang_dev_docker/domain/usecase"
+)
+
+// DataExportHandler 個人資料匯出處理器
+type DataExportHandler struct {
+	exportService *usecase.DataExportService
+}
+
+// NewDataExportHandler 創建個人資料匯出處理器
+func NewDataExportHandler(exportService *usecase.DataExportService) *DataExportHandler {
+	return &DataExportHandler{
+		exportService: exportService,
+	}
+}
+
+// RequestExport 申請匯出個人資料
+// POST /api/users/data-export
+func (h *DataExportHandler) RequestExport(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.exportService.RequestExport(c.Request.Context(), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrExportInProgress):
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+		case errors.Is(err, usecase.ErrExportRateLimited):
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "申請資料匯出失敗",
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "已受理資料匯出申請，完成後可在此查詢下載連結",
+		"export":  export,
+	})
+}
+
+// GetExportStatus 查詢最近一次資料匯出狀態
+// GET /api/users/data-export
+func (h *DataExportHandler) GetExportStatus(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	info, err := h.exportService.GetLatestExport(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrExportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "查詢資料匯出狀態失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"export": info,
+	})
+}
+
+// Download 以簽名連結下載匯出封存檔
+// GET /api/exports/:id/download?expires=...&signature=...
+// 不需要登入，由連結簽名與期限授權
+func (h *DataExportHandler) Download(c *gin.Context) {
+	exportID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "無效的匯出 ID",
+		})
+		return
+	}
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": usecase.ErrInvalidDownloadLink.Error(),
+		})
+		return
+	}
+
+	export, reader, err := h.exportService.OpenDownload(c.Request.Context(), uint(exportID), expires, c.Query("signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidDownloadLink):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+			})
+		case errors.Is(err, usecase.ErrExportNotFound), errors.Is(err, usecase.ErrExportNotReady):
+			c.JSON(http.StatusGone, gin.H{
+				"error": usecase.ErrExportNotReady.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "下載匯出檔案失敗",
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+	defer reader.Close()
+
+	c.Header("Cache-Control", "no-store")
+	c.DataFromReader(http.StatusOK, export.FileSize, "application/zip", reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="personal-data-%s.zip"`, export.CompletedAt.Format("20060102")),
+	})
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+)
+
+// FileHandler 上傳檔案下載處理器
+type FileHandler struct {
+	fileService *usecase.FileService
+}
+
+// NewFileHandler 創建上傳檔案下載處理器
+func NewFileHandler(fileService *usecase.FileService) *FileHandler {
+	return &FileHandler{
+		fileService: fileService,
+	}
+}
+
+// Download 以簽名連結下載檔案，連結由 FileService.SignedURL 或 SignedURLFor 簽發
+// GET /api/files/*key
+func (h *FileHandler) Download(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": usecase.ErrInvalidFileLink.Error(),
+		})
+		return
+	}
+
+	// 私人相簿等受限檔案的連結綁定查看者，一般檔案沒有此參數
+	var viewerID uint64
+	if viewer := c.Query("viewer"); viewer != "" {
+		if viewerID, err = strconv.ParseUint(viewer, 10, 32); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": usecase.ErrInvalidFileLink.Error(),
+			})
+			return
+		}
+	}
+
+	reader, err := h.fileService.OpenSigned(c.Request.Context(), key, expires, uint(viewerID), c.Query("signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidFileLink), errors.Is(err, usecase.ErrInvalidFileKey):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": usecase.ErrInvalidFileLink.Error(),
+			})
+		case errors.Is(err, usecase.ErrFileAccessDenied):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+			})
+		case errors.Is(err, usecase.ErrFileNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "下載檔案失敗",
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+	defer reader.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	// 只有允許上傳的圖片格式可直接顯示，其他檔案一律下載，避免在本站網域執行上傳的內容
+	var headers map[string]string
+	if !entity.IsValidImageMimeType(contentType) {
+		headers = map[string]string{
+			"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, path.Base(key)),
+		}
+	}
+
+	// 連結到期前可由瀏覽器快取，但不可由共用快取保存
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.DataFromReader(http.StatusOK, -1, contentType, reader, headers)
+}
@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"golang_dev_docker/domain/usecase"
+)
+
+// maxInterestImportSize 興趣 CSV 匯入檔案大小上限
+const maxInterestImportSize = 1 << 20
+
+// InterestHandler 興趣標籤處理器
+type InterestHandler struct {
+	interestService *usecase.InterestService
+}
+
+// NewInterestHandler 創建興趣標籤處理器
+func NewInterestHandler(interestService *usecase.InterestService) *InterestHandler {
+	return &InterestHandler{
+		interestService: interestService,
+	}
+}
+
+// ListInterests 獲取所有可選興趣，依 locale 參數或 Accept-Language 標頭顯示名稱
+// GET /interests
+func (h *InterestHandler) ListInterests(c *gin.Context) {
+	locale := requestLocale(c)
+
+	interests, err := h.interestService.ListInterests(c.Request.Context(), locale)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取興趣列表失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"interests": interests,
+		"locale":    locale,
+	})
+}
+
+// SearchInterests 以字首搜尋興趣，比對名稱、在地化名稱與同義詞，供輸入時自動完成
+// GET /interests/search?q=跑&limit=10
+func (h *InterestHandler) SearchInterests(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit 必須是數字",
+		})
+		return
+	}
+
+	locale := requestLocale(c)
+	interests, err := h.interestService.SearchInterests(c.Request.Context(), c.Query("q"), locale, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "搜尋興趣失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"interests": interests,
+		"locale":    locale,
+	})
+}
+
+// ListAllInterests 獲取所有興趣與在地化名稱、同義詞（管理後台）
+// GET /admin/interests
+func (h *InterestHandler) ListAllInterests(c *gin.Context) {
+	interests, err := h.interestService.ListAllInterests(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取興趣列表失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"interests": interests,
+	})
+}
+
+// CreateInterest 新增興趣（管理後台）
+// POST /admin/interests
+func (h *InterestHandler) CreateInterest(c *gin.Context) {
+	var req usecase.InterestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	interest, err := h.interestService.CreateInterest(c.Request.Context(), &req)
+	if err != nil {
+		respondInterestError(c, "新增興趣失敗", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"interest": interest,
+	})
+}
+
+// UpdateInterest 修改或停用興趣（管理後台）
+// PUT /admin/interests/:id
+func (h *InterestHandler) UpdateInterest(c *gin.Context) {
+	interestID, ok := interestIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req usecase.InterestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	interest, err := h.interestService.UpdateInterest(c.Request.Context(), interestID, &req)
+	if err != nil {
+		respondInterestError(c, "修改興趣失敗", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"interest": interest,
+	})
+}
+
+// DeleteInterest 刪除興趣，已選擇的用戶一併移除（管理後台）
+// DELETE /admin/interests/:id
+func (h *InterestHandler) DeleteInterest(c *gin.Context) {
+	interestID, ok := interestIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.interestService.DeleteInterest(c.Request.Context(), interestID); err != nil {
+		respondInterestError(c, "刪除興趣失敗", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "興趣已刪除",
+	})
+}
+
+// ImportInterests 匯入興趣 CSV，接受 multipart 的 file 欄位或直接以 text/csv 傳送（管理後台）
+// POST /admin/interests/import
+func (h *InterestHandler) ImportInterests(c *gin.Context) {
+	var data []byte
+	if file, err := c.FormFile("file"); err == nil {
+		data, err = readFormFile(file, maxInterestImportSize)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "讀取上傳檔案失敗",
+				"message": err.Error(),
+			})
+			return
+		}
+	} else {
+		data, err = io.ReadAll(io.LimitReader(c.Request.Body, maxInterestImportSize+1))
+		if err != nil || len(data) > maxInterestImportSize {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "CSV 檔案過大或無法讀取",
+			})
+			return
+		}
+	}
+
+	result, err := h.interestService.ImportCSV(c.Request.Context(), bytes.NewReader(data))
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidInterestImport) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "匯入興趣失敗",
+				"message": err.Error(),
+				"result":  result,
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "匯入興趣失敗",
+			"message": err.Error(),
+			"result":  result,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "興趣已匯入",
+		"result":  result,
+	})
+}
+
+// ExportInterests 匯出所有興趣為 CSV（管理後台）
+// GET /admin/interests/export
+func (h *InterestHandler) ExportInterests(c *gin.Context) {
+	var buf bytes.Buffer
+	if err := h.interestService.ExportCSV(c.Request.Context(), &buf); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "匯出興趣失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="interests.csv"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// respondInterestError 依錯誤類型回應興趣管理錯誤
+func respondInterestError(c *gin.Context, message string, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, usecase.ErrInterestNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, usecase.ErrInterestNameTaken):
+		status = http.StatusConflict
+	}
+
+	c.JSON(status, gin.H{
+		"error":   message,
+		"message": err.Error(),
+	})
+}
+
+// interestIDParam 解析路徑中的興趣 ID
+func interestIDParam(c *gin.Context) (uint, bool) {
+	interestID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "無效的興趣ID",
+		})
+		return 0, false
+	}
+	return uint(interestID), true
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
@@ -15,12 +16,9 @@ type MatchingHandler struct {
 	matchingService *usecase.MatchingService
 }
 
-// 全域配對處理器實例
-var matchingHandler *MatchingHandler
-
-// SetMatchingService 設置配對處理器的服務依賴
-func SetMatchingService(matchingService *usecase.MatchingService) {
-	matchingHandler = &MatchingHandler{
+// NewMatchingHandler 創建配對處理器
+func NewMatchingHandler(matchingService *usecase.MatchingService) *MatchingHandler {
+	return &MatchingHandler{
 		matchingService: matchingService,
 	}
 }
@@ -31,16 +29,9 @@ type SwipeRequest struct {
 	Action       string `json:"action" binding:"required"` // "like" or "pass"
 }
 
-// GetPotentialMatchesHandler 獲取潛在配對對象
+// GetPotentialMatches 獲取潛在配對對象
 // GET /matching/potential
-func GetPotentialMatchesHandler(c *gin.Context) {
-	if matchingHandler == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "配對服務未初始化",
-		})
-		return
-	}
-
+func (h *MatchingHandler) GetPotentialMatches(c *gin.Context) {
 	// 從 JWT token 中獲取用戶 ID
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -90,10 +81,13 @@ func GetPotentialMatchesHandler(c *gin.Context) {
 		}
 	}
 
+	// 可用逗號指定多個性別，例如 preferred_gender=female,other
 	if genderStr := c.Query("preferred_gender"); genderStr != "" {
-		gender := entity.Gender(genderStr)
-		if gender.IsValid() {
-			req.PreferredGender = &gender
+		for _, value := range strings.Split(genderStr, ",") {
+			gender := entity.Gender(strings.TrimSpace(value))
+			if gender.IsValid() {
+				req.PreferredGenders = append(req.PreferredGenders, gender)
+			}
 		}
 	}
 
@@ -107,7 +101,7 @@ func GetPotentialMatchesHandler(c *gin.Context) {
 	}
 
 	// 調用配對服務獲取潛在配對
-	potentialUsers, err := matchingHandler.matchingService.GetPotentialMatches(c.Request.Context(), req)
+	potentialUsers, err := h.matchingService.GetPotentialMatches(c.Request.Context(), req)
 	if err != nil {
 		if err.Error() == "用戶未啟用或未驗證" {
 			c.JSON(http.StatusForbidden, gin.H{
@@ -142,16 +136,9 @@ func GetPotentialMatchesHandler(c *gin.Context) {
 	})
 }
 
-// SwipeHandler 處理滑動配對
+// Swipe 處理滑動配對
 // POST /matching/swipe
-func SwipeHandler(c *gin.Context) {
-	if matchingHandler == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "配對服務未初始化",
-		})
-		return
-	}
-
+func (h *MatchingHandler) Swipe(c *gin.Context) {
 	// 從 JWT token 中獲取用戶 ID
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -201,7 +188,7 @@ func SwipeHandler(c *gin.Context) {
 	}
 
 	// 調用配對服務處理滑動
-	swipeResponse, err := matchingHandler.matchingService.ProcessSwipe(c.Request.Context(), serviceReq)
+	swipeResponse, err := h.matchingService.ProcessSwipe(c.Request.Context(), serviceReq)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "滑動處理失敗",
@@ -242,16 +229,9 @@ func SwipeHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-// GetUserMatchesHandler 獲取用戶配對列表
+// GetUserMatches 獲取用戶配對列表
 // GET /matching/matches
-func GetUserMatchesHandler(c *gin.Context) {
-	if matchingHandler == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "配對服務未初始化",
-		})
-		return
-	}
-
+func (h *MatchingHandler) GetUserMatches(c *gin.Context) {
 	// 從 JWT token 中獲取用戶 ID
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -284,7 +264,7 @@ func GetUserMatchesHandler(c *gin.Context) {
 	}
 
 	// 調用配對服務獲取配對列表
-	matchList, err := matchingHandler.matchingService.GetUserMatches(c.Request.Context(), userIDUint, status)
+	matchList, err := h.matchingService.GetUserMatches(c.Request.Context(), userIDUint, status)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "獲取配對列表失敗",
@@ -312,3 +292,78 @@ func GetUserMatchesHandler(c *gin.Context) {
 		"status":      statusStr,
 	})
 }
+
+// Unmatch 取消與指定用戶的配對
+// DELETE /matching/matches/:userId
+func (h *MatchingHandler) Unmatch(c *gin.Context) {
+	// 從 JWT token 中獲取用戶 ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未授權訪問",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	targetUserID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	if err := h.matchingService.UnmatchUser(c.Request.Context(), userIDUint, uint(targetUserID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "取消配對失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已取消配對",
+	})
+}
+
+// GetMatchingStats 獲取配對統計
+// GET /matching/stats
+func (h *MatchingHandler) GetMatchingStats(c *gin.Context) {
+	// 從 JWT token 中獲取用戶 ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未授權訪問",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	stats, err := h.matchingService.GetMatchingStats(c.Request.Context(), userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取配對統計失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stats": stats,
+	})
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"golang_dev_docker/domain/usecase"
+)
+
+// OIDCHandler 外部身分登入處理器
+type OIDCHandler struct {
+	oidcService *usecase.OIDCService
+	authService *usecase.AuthService
+}
+
+// NewOIDCHandler 創建外部身分登入處理器
+func NewOIDCHandler(oidcService *usecase.OIDCService, authService *usecase.AuthService) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+		authService: authService,
+	}
+}
+
+// OIDCCallbackRequest 授權完成後回傳的授權碼與狀態
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// ListProviders 列出可用的外部登入提供者
+// GET /api/auth/oidc/providers
+func (h *OIDCHandler) ListProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"providers": h.oidcService.Providers(),
+	})
+}
+
+// BeginLogin 開始外部登入，返回授權網址
+// POST /api/auth/oidc/:provider/start
+func (h *OIDCHandler) BeginLogin(c *gin.Context) {
+	authorization, err := h.oidcService.BeginLogin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		respondOIDCError(c, "無法開始外部登入", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, authorization)
+}
+
+// CompleteLogin 以授權碼完成外部登入
+// POST /api/auth/oidc/:provider/callback
+func (h *OIDCHandler) CompleteLogin(c *gin.Context) {
+	var req OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	user, _, err := h.oidcService.CompleteLogin(c.Request.Context(), c.Param("provider"), req.Code, req.State)
+	if err != nil {
+		respondOIDCError(c, "外部登入失敗", err)
+		return
+	}
+
+	result, err := h.authService.LoginWithIdentity(c.Request.Context(), user, usecase.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		respondOIDCError(c, "外部登入失敗", err)
+		return
+	}
+
+	// 已連結帳號啟用兩步驟驗證時仍需完成第二步
+	if result.MFAChallenge != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message":             "請輸入驗證器 App 產生的驗證碼",
+			"mfa_required":        true,
+			"mfa_token":           result.MFAChallenge.Token,
+			"expires_in":          result.MFAChallenge.ExpiresIn,
+			"enrollment_required": result.MFAChallenge.EnrollmentRequired,
+		})
+		return
+	}
+
+	// 新建帳號的 registration_pending 為 true，須先補填出生日期
+	respondLoginSuccess(c, result)
+}
+
+// ListIdentities 列出已連結的外部帳號
+// GET /api/users/identities
+func (h *OIDCHandler) ListIdentities(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	identities, err := h.oidcService.ListIdentities(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取外部帳號失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"identities": identities,
+	})
+}
+
+// BeginLink 開始連結外部帳號，返回授權網址
+// POST /api/users/identities/:provider/link
+func (h *OIDCHandler) BeginLink(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	authorization, err := h.oidcService.BeginLink(c.Request.Context(), userID, c.Param("provider"))
+	if err != nil {
+		respondOIDCError(c, "無法開始連結外部帳號", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, authorization)
+}
+
+// CompleteLink 以授權碼完成外部帳號連結
+// POST /api/users/identities/:provider/link/callback
+func (h *OIDCHandler) CompleteLink(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	identity, err := h.oidcService.CompleteLink(c.Request.Context(), userID, c.Param("provider"), req.Code, req.State)
+	if err != nil {
+		respondOIDCError(c, "連結外部帳號失敗", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "外部帳號已連結",
+		"identity": identity,
+	})
+}
+
+// Unlink 解除外部帳號連結
+// DELETE /api/users/identities/:provider
+func (h *OIDCHandler) Unlink(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.oidcService.Unlink(c.Request.Context(), userID, c.Param("provider")); err != nil {
+		respondOIDCError(c, "解除外部帳號連結失敗", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "外部帳號已解除連結",
+	})
+}
+
+// respondOIDCError 將外部登入錯誤對應為 HTTP 狀態碼
+func respondOIDCError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrOIDCProviderNotFound), errors.Is(err, usecase.ErrIdentityNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   message,
+			"message": err.Error(),
+		})
+	case errors.Is(err, usecase.ErrOIDCEmailInUse):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   message,
+			"message": err.Error(),
+			"code":    "EMAIL_IN_USE",
+		})
+	case errors.Is(err, usecase.ErrOIDCIdentityLinked):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   message,
+			"message": err.Error(),
+			"code":    "IDENTITY_LINKED",
+		})
+	case errors.Is(err, usecase.ErrLastLoginMethod), errors.Is(err, usecase.ErrOIDCEmailRequired):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   message,
+			"message": err.Error(),
+		})
+	case errors.Is(err, usecase.ErrAccountSuspended):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   message,
+			"message": err.Error(),
+			"code":    "ACCOUNT_SUSPENDED",
+		})
+	case errors.Is(err, usecase.ErrAccountBanned):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   message,
+			"message": err.Error(),
+			"code":    "ACCOUNT_BANNED",
+		})
+	case errors.Is(err, usecase.ErrAccountPendingDeletion):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   message,
+			"message": err.Error(),
+			"code":    "ACCOUNT_PENDING_DELETION",
+		})
+	case errors.Is(err, usecase.ErrSessionStoreUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		// 狀態無效、授權碼或 ID Token 驗證失敗
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   message,
+			"message": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+)
+
+// PhotoModerationHandler 照片審核處理器
+type PhotoModerationHandler struct {
+	moderationService  *usecase.PhotoModerationService
+	fingerprintService *usecase.PhotoFingerprintService
+}
+
+// NewPhotoModerationHandler 創建照片審核處理器
+func NewPhotoModerationHandler(moderationService *usecase.PhotoModerationService, fingerprintService *usecase.PhotoFingerprintService) *PhotoModerationHandler {
+	return &PhotoModerationHandler{
+		moderationService:  moderationService,
+		fingerprintService: fingerprintService,
+	}
+}
+
+// ReviewPhotoRequest 審核照片請求結構
+type ReviewPhotoRequest struct {
+	Approve bool   `json:"approve"`
+	Notes   string `json:"notes"`
+}
+
+// GetQueue 獲取待審核照片佇列
+// GET /admin/photos/pending?type=&user_id=&mine=&flagged=&include_claimed=&limit=&offset=
+func (h *PhotoModerationHandler) GetQueue(c *gin.Context) {
+	moderatorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	req := &usecase.PhotoQueueRequest{
+		ModeratorID:    moderatorID,
+		OnlyMine:       c.Query("mine") == "true",
+		OnlyFlagged:    c.Query("flagged") == "true",
+		IncludeClaimed: c.Query("include_claimed") == "true",
+	}
+
+	var err error
+	if req.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "20")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit 參數格式錯誤",
+		})
+		return
+	}
+	if req.Offset, err = strconv.Atoi(c.DefaultQuery("offset", "0")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "offset 參數格式錯誤",
+		})
+		return
+	}
+	if photoType := c.Query("type"); photoType != "" {
+		t := entity.PhotoType(photoType)
+		req.Type = &t
+	}
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userID, err := strconv.ParseUint(userIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "user_id 參數格式錯誤",
+			})
+			return
+		}
+		id := uint(userID)
+		req.UserID = &id
+	}
+
+	queue, err := h.moderationService.GetQueue(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "獲取待審核照片失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+// ClaimPhoto 認領待審核照片
+// POST /admin/photos/:id/claim
+func (h *PhotoModerationHandler) ClaimPhoto(c *gin.Context) {
+	moderatorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	photoID, ok := photoIDParam(c)
+	if !ok {
+		return
+	}
+
+	photo, err := h.moderationService.ClaimPhoto(c.Request.Context(), photoID, moderatorID)
+	if err != nil {
+		respondPhotoModerationError(c, "認領照片失敗", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "照片認領成功",
+		"photo":            photo,
+		"claim_expires_at": photo.ClaimedAt.Add(usecase.PhotoClaimTTL),
+	})
+}
+
+// ReleasePhoto 釋出照片認領
+// DELETE /admin/photos/:id/claim
+func (h *PhotoModerationHandler) ReleasePhoto(c *gin.Context) {
+	moderatorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	photoID, ok := photoIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.moderationService.ReleasePhoto(c.Request.Context(), photoID, moderatorID); err != nil {
+		respondPhotoModerationError(c, "釋出照片認領失敗", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "照片認領已釋出",
+	})
+}
+
+// ReviewPhoto 審核照片
+// PUT /admin/photos/:id/review
+func (h *PhotoModerationHandler) ReviewPhoto(c *gin.Context) {
+	reviewerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	photoID, ok := photoIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req ReviewPhotoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// 審核者一律取自 token，不接受客戶端指定
+	photo, err := h.moderationService.ReviewPhoto(c.Request.Context(), &usecase.ReviewPhotoRequest{
+		PhotoID:    photoID,
+		ReviewerID: reviewerID,
+		Approve:    req.Approve,
+		Notes:      req.Notes,
+	})
+	if err != nil {
+		respondPhotoModerationError(c, "審核照片失敗", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "照片審核完成",
+		"photo":   photo,
+	})
+}
+
+// BlockImageRequest 加入禁用圖片清單請求結構
+type BlockImageRequest struct {
+	PhotoID uint   `json:"photo_id" binding:"required"`
+	Reason  string `json:"reason" binding:"required"`
+}
+
+// ListBlockedImages 獲取禁用圖片清單
+// GET /admin/blocked-images
+func (h *PhotoModerationHandler) ListBlockedImages(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit 參數格式錯誤",
+		})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "offset 參數格式錯誤",
+		})
+		return
+	}
+
+	list, err := h.fingerprintService.ListBlockedImages(c.Request.Context(), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取禁用圖片清單失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// BlockImage 將照片加入禁用圖片清單，之後其他帳號上傳相似照片時會被標記
+// POST /admin/blocked-images
+func (h *PhotoModerationHandler) BlockImage(c *gin.Context) {
+	moderatorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req BlockImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	image, err := h.fingerprintService.BlockImage(c.Request.Context(), &usecase.BlockImageRequest{
+		PhotoID:     req.PhotoID,
+		ModeratorID: moderatorID,
+		Reason:      req.Reason,
+	})
+	if err != nil {
+		respondPhotoModerationError(c, "加入禁用圖片清單失敗", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "已加入禁用圖片清單",
+		"image":   image,
+	})
+}
+
+// UnblockImage 從禁用圖片清單移除
+// DELETE /admin/blocked-images/:id
+func (h *PhotoModerationHandler) UnblockImage(c *gin.Context) {
+	moderatorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	imageID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "無效的禁用圖片ID",
+		})
+		return
+	}
+
+	if err := h.fingerprintService.UnblockImage(c.Request.Context(), moderatorID, uint(imageID)); err != nil {
+		respondPhotoModerationError(c, "移除禁用圖片失敗", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已從禁用圖片清單移除",
+	})
+}
+
+// photoIDParam 解析路徑中的照片 ID
+func photoIDParam(c *gin.Context) (uint, bool) {
+	photoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "無效的照片ID",
+		})
+		return 0, false
+	}
+	return uint(photoID), true
+}
+
+// respondPhotoModerationError 將照片審核錯誤對應到 HTTP 狀態碼
+func respondPhotoModerationError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrPermissionDenied):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, usecase.ErrPhotoNotFound), errors.Is(err, usecase.ErrBlockedImageNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, usecase.ErrPhotoClaimed), errors.Is(err, usecase.ErrPhotoNotPending):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   message,
+			"message": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"golang_dev_docker/domain/usecase"
+)
+
+// PrivateAlbumHandler 私人相簿授權處理器
+type PrivateAlbumHandler struct {
+	privateAlbumService *usecase.PrivateAlbumService
+}
+
+// NewPrivateAlbumHandler 創建私人相簿授權處理器
+func NewPrivateAlbumHandler(privateAlbumService *usecase.PrivateAlbumService) *PrivateAlbumHandler {
+	return &PrivateAlbumHandler{
+		privateAlbumService: privateAlbumService,
+	}
+}
+
+// ListGrants 獲取自己授權查看私人相簿的用戶
+// GET /users/albums/private/access
+func (h *PrivateAlbumHandler) ListGrants(c *gin.Context) {
+	ownerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	grants, err := h.privateAlbumService.ListGrants(c.Request.Context(), ownerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取私人相簿授權失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"grants": grants,
+	})
+}
+
+// GrantAccess 授權配對對象查看私人相簿
+// PUT /users/albums/private/access/:userId
+func (h *PrivateAlbumHandler) GrantAccess(c *gin.Context) {
+	ownerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	viewerID, ok := albumViewerParam(c)
+	if !ok {
+		return
+	}
+
+	grant, err := h.privateAlbumService.GrantAccess(c.Request.Context(), ownerID, viewerID)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, usecase.ErrPrivateAlbumNotMatched) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "授權查看私人相簿失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已授權查看私人相簿",
+		"grant":   grant,
+	})
+}
+
+// RevokeAccess 撤銷用戶查看私人相簿的授權
+// DELETE /users/albums/private/access/:userId
+func (h *PrivateAlbumHandler) RevokeAccess(c *gin.Context) {
+	ownerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	viewerID, ok := albumViewerParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.privateAlbumService.RevokeAccess(c.Request.Context(), ownerID, viewerID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "撤銷私人相簿授權失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已撤銷私人相簿授權",
+	})
+}
+
+// albumViewerParam 解析路徑中的被授權用戶 ID
+func albumViewerParam(c *gin.Context) (uint, bool) {
+	viewerID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return 0, false
+	}
+	return uint(viewerID), true
+}
@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+)
+
+// PromptHandler 檔案問答處理器
+type PromptHandler struct {
+	promptService *usecase.PromptService
+}
+
+// NewPromptHandler 創建檔案問答處理器
+func NewPromptHandler(promptService *usecase.PromptService) *PromptHandler {
+	return &PromptHandler{
+		promptService: promptService,
+	}
+}
+
+// UpdateProfilePromptsRequest 更新檔案問答請求結構，顯示順序依陣列順序
+type UpdateProfilePromptsRequest struct {
+	Prompts []usecase.ProfilePromptAnswer `json:"prompts"`
+}
+
+// ReviewPromptAnswerRequest 審核回答請求結構
+type ReviewPromptAnswerRequest struct {
+	Approve bool   `json:"approve"`
+	Notes   string `json:"notes"`
+}
+
+// ListPrompts 獲取可選題目，依 locale 參數或 Accept-Language 標頭顯示文字
+// GET /prompts
+func (h *PromptHandler) ListPrompts(c *gin.Context) {
+	locale := requestLocale(c)
+
+	prompts, err := h.promptService.ListPrompts(c.Request.Context(), locale)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取題目失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"prompts": prompts,
+		"locale":  locale,
+	})
+}
+
+// GetProfilePrompts 獲取自己的檔案問答
+// GET /users/prompts
+func (h *PromptHandler) GetProfilePrompts(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	prompts, err := h.promptService.GetProfilePrompts(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取檔案問答失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"prompts": prompts,
+	})
+}
+
+// UpdateProfilePrompts 更新自己的檔案問答，整份取代現有回答
+// PUT /users/prompts
+func (h *PromptHandler) UpdateProfilePrompts(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateProfilePromptsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	prompts, err := h.promptService.UpdateProfilePrompts(c.Request.Context(), userID, req.Prompts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "更新檔案問答失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "檔案問答已更新，回答通過審核後才會顯示給其他用戶",
+		"prompts": prompts,
+	})
+}
+
+// ListAllPrompts 獲取所有題目與語系文字（管理後台）
+// GET /admin/prompts
+func (h *PromptHandler) ListAllPrompts(c *gin.Context) {
+	prompts, err := h.promptService.ListAllPrompts(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取題目失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"prompts": prompts,
+	})
+}
+
+// CreatePrompt 新增題目（管理後台）
+// POST /admin/prompts
+func (h *PromptHandler) CreatePrompt(c *gin.Context) {
+	var req usecase.PromptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	prompt, err := h.promptService.CreatePrompt(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "新增題目失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"prompt": prompt,
+	})
+}
+
+// UpdatePrompt 修改或停用題目（管理後台）
+// PUT /admin/prompts/:id
+func (h *PromptHandler) UpdatePrompt(c *gin.Context) {
+	promptID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "無效的題目ID",
+		})
+		return
+	}
+
+	var req usecase.PromptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	prompt, err := h.promptService.UpdatePrompt(c.Request.Context(), uint(promptID), &req)
+	if err != nil {
+		if errors.Is(err, usecase.ErrPromptNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "修改題目失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"prompt": prompt,
+	})
+}
+
+// GetPendingAnswers 獲取待審核的回答
+// GET /admin/prompt-answers/pending
+func (h *PromptHandler) GetPendingAnswers(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit 參數格式錯誤",
+		})
+		return
+	}
+
+	answers, err := h.promptService.GetPendingAnswers(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取待審核回答失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"answers": answers,
+		"count":   len(answers),
+	})
+}
+
+// ReviewAnswer 審核回答
+// PUT /admin/prompt-answers/:id/review
+func (h *PromptHandler) ReviewAnswer(c *gin.Context) {
+	reviewerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	answerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "無效的回答ID",
+		})
+		return
+	}
+
+	var req ReviewPromptAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// 審核者一律取自 token，不接受客戶端指定
+	answer, err := h.promptService.ReviewAnswer(c.Request.Context(), &usecase.ReviewPromptAnswerRequest{
+		AnswerID:   uint(answerID),
+		ReviewerID: reviewerID,
+		Approve:    req.Approve,
+		Notes:      req.Notes,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrPermissionDenied):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+			})
+		case errors.Is(err, usecase.ErrPromptAnswerNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "審核回答失敗",
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "回答審核完成",
+		"answer":  answer,
+	})
+}
+
+// requestLocale 取得請求語系：優先使用 locale 參數，其次為 Accept-Language 的第一個語系
+func requestLocale(c *gin.Context) string {
+	if locale := c.Query("locale"); locale != "" {
+		return entity.NormalizeLocale(locale)
+	}
+
+	header := c.GetHeader("Accept-Language")
+	if header == "" {
+		return entity.DefaultPromptLocale
+	}
+	first, _, _ := strings.Cut(header, ",")
+	first, _, _ = strings.Cut(first, ";")
+	if first = entity.NormalizeLocale(first); first == "" || first == "*" {
+		return entity.DefaultPromptLocale
+	}
+	return first
+}
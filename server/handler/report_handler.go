@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+)
+
+// ReportHandler 檢舉與封鎖處理器
+type ReportHandler struct {
+	reportService *usecase.ReportService
+}
+
+// NewReportHandler 創建檢舉與封鎖處理器
+func NewReportHandler(reportService *usecase.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// SubmitReportRequest 提交檢舉請求結構
+type SubmitReportRequest struct {
+	ReportedUserID uint     `json:"reported_user_id" binding:"required"`
+	Category       string   `json:"category" binding:"required"`
+	Description    string   `json:"description" binding:"required"`
+	Evidence       []string `json:"evidence,omitempty"`
+}
+
+// BlockUserRequest 封鎖用戶請求結構
+type BlockUserRequest struct {
+	BlockedUserID uint   `json:"blocked_user_id" binding:"required"`
+	Reason        string `json:"reason"`
+}
+
+// SubmitReport 提交檢舉
+// POST /reports
+func (h *ReportHandler) SubmitReport(c *gin.Context) {
+	// 從 JWT token 中獲取用戶 ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未授權訪問",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	var req SubmitReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求格式錯誤",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// 檢舉者一律取自 token，避免冒用他人身份
+	result, err := h.reportService.SubmitReport(c.Request.Context(), &usecase.SubmitReportRequest{
+		ReporterID:     userIDUint,
+		ReportedUserID: req.ReportedUserID,
+		Category:       entity.ReportCategory(req.Category),
+		Description:    req.Description,
+		Evidence:       req.Evidence,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "提交檢舉失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if !result.Success {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "提交檢舉失敗",
+			"message": result.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": result.Message,
+		"report":  result.Report,
+	})
+}
+
+// GetMyReports 獲取自己提交的檢舉記錄
+// GET /reports
+func (h *ReportHandler) GetMyReports(c *gin.Context) {
+	// 從 JWT token 中獲取用戶 ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未授權訪問",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit 參數格式錯誤",
+		})
+		return
+	}
+
+	reports, err := h.reportService.GetUserReports(c.Request.Context(), userIDUint, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取檢舉記錄失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reports": reports,
+		"count":   len(reports),
+	})
+}
+
+// BlockUser 封鎖用戶
+// POST /blocks
+func (h *ReportHandler) BlockUser(c *gin.Context) {
+	// 從 JWT token 中獲取用戶 ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未授權訪問",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	var req BlockUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求格式錯誤",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	err := h.reportService.BlockUser(c.Request.Context(), &usecase.BlockUserRequest{
+		UserID:        userIDUint,
+		BlockedUserID: req.BlockedUserID,
+		Reason:        req.Reason,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "封鎖失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "已封鎖該用戶",
+	})
+}
+
+// UnblockUser 解除封鎖
+// DELETE /blocks/:userId
+func (h *ReportHandler) UnblockUser(c *gin.Context) {
+	// 從 JWT token 中獲取用戶 ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未授權訪問",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	blockedUserID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	if err := h.reportService.UnblockUser(c.Request.Context(), userIDUint, uint(blockedUserID)); err != nil {
+		if err.Error() == "封鎖關係不存在" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "解除封鎖失敗",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "解除封鎖失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已解除封鎖",
+	})
+}
+
+// GetBlockList 獲取自己的封鎖列表
+// GET /blocks
+func (h *ReportHandler) GetBlockList(c *gin.Context) {
+	// 從 JWT token 中獲取用戶 ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未授權訪問",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	blocks, err := h.reportService.GetUserBlockList(c.Request.Context(), userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取封鎖列表失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"blocks": blocks,
+		"count":  len(blocks),
+	})
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"golang_dev_docker/domain/usecase"
+)
+
+// TwoFactorHandler 兩步驟驗證設定處理器
+type TwoFactorHandler struct {
+	twoFactorService *usecase.TwoFactorService
+}
+
+// NewTwoFactorHandler 創建兩步驟驗證設定處理器
+func NewTwoFactorHandler(twoFactorService *usecase.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+	}
+}
+
+// TwoFactorCodeRequest 需要驗證碼的請求結構
+// code 可為 6 位數驗證碼或恢復碼
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// GetStatus 獲取兩步驟驗證狀態
+// GET /api/users/2fa
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.twoFactorService.GetStatus(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取兩步驟驗證狀態失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"two_factor": status,
+	})
+}
+
+// BeginEnrollment 開始綁定驗證器
+// POST /api/users/2fa/setup
+func (h *TwoFactorHandler) BeginEnrollment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.twoFactorService.BeginEnrollment(c.Request.Context(), userID)
+	if err != nil {
+		respondTwoFactorError(c, err, "開始綁定驗證器失敗")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "請以驗證器 App 掃描 QR Code，並輸入驗證碼完成綁定",
+		"secret":           enrollment.Secret,
+		"provisioning_uri": enrollment.ProvisioningURI,
+	})
+}
+
+// ConfirmEnrollment 確認綁定並啟用兩步驟驗證
+// POST /api/users/2fa/confirm
+func (h *TwoFactorHandler) ConfirmEnrollment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	recoveryCodes, err := h.twoFactorService.ConfirmEnrollment(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err, "啟用兩步驟驗證失敗")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "兩步驟驗證已啟用，請妥善保存恢復碼",
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// Disable 停用兩步驟驗證
+// DELETE /api/users/2fa
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.twoFactorService.Disable(c.Request.Context(), userID, req.Code); err != nil {
+		respondTwoFactorError(c, err, "停用兩步驟驗證失敗")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "兩步驟驗證已停用",
+	})
+}
+
+// RegenerateRecoveryCodes 重新產生恢復碼
+// POST /api/users/2fa/recovery-codes
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	recoveryCodes, err := h.twoFactorService.RegenerateRecoveryCodes(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err, "重新產生恢復碼失敗")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "已產生新的恢復碼，舊的恢復碼已失效",
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// respondTwoFactorError 將兩步驟驗證錯誤對應為 HTTP 回應
+func respondTwoFactorError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidTwoFactorCode):
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": err.Error(),
+			"code":  "INVALID_2FA_CODE",
+		})
+	case errors.Is(err, usecase.ErrTwoFactorRequired):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+			"code":  "2FA_REQUIRED",
+		})
+	case errors.Is(err, usecase.ErrTwoFactorAlreadyEnabled),
+		errors.Is(err, usecase.ErrTwoFactorNotEnabled),
+		errors.Is(err, usecase.ErrTwoFactorNotStarted):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"message": err.Error(),
+		})
+	}
+}
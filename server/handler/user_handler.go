@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"golang_dev_docker/domain/entity"
 	"golang_dev_docker/domain/usecase"
 )
 
@@ -14,44 +19,42 @@ type UserHandler struct {
 	userService *usecase.UserService
 }
 
-// 全域用戶處理器實例
-var userHandler *UserHandler
-
-// SetUserService 設置用戶處理器的服務依賴
-func SetUserService(userService *usecase.UserService) {
-	userHandler = &UserHandler{
+// NewUserHandler 創建用戶處理器
+func NewUserHandler(userService *usecase.UserService) *UserHandler {
+	return &UserHandler{
 		userService: userService,
 	}
 }
 
 // UpdateProfileRequest 更新檔案請求結構
 type UpdateProfileRequest struct {
-	DisplayName *string  `json:"display_name,omitempty"`
-	Biography   *string  `json:"biography,omitempty"`
-	LocationLat *float64 `json:"location_lat,omitempty"`
-	LocationLng *float64 `json:"location_lng,omitempty"`
-	MaxDistance *int     `json:"max_distance,omitempty"`
-	AgeRangeMin *int     `json:"age_range_min,omitempty"`
-	AgeRangeMax *int     `json:"age_range_max,omitempty"`
-	InterestIDs []uint   `json:"interest_ids,omitempty"`
+	DisplayName  *string         `json:"display_name,omitempty"`
+	Biography    *string         `json:"biography,omitempty"`
+	LocationLat  *float64        `json:"location_lat,omitempty"`
+	LocationLng  *float64        `json:"location_lng,omitempty"`
+	MaxDistance  *int            `json:"max_distance,omitempty"`
+	AgeRangeMin  *int            `json:"age_range_min,omitempty"`
+	AgeRangeMax  *int            `json:"age_range_max,omitempty"`
+	InterestedIn []entity.Gender `json:"interested_in,omitempty"`
+	InterestIDs  []uint          `json:"interest_ids,omitempty"`
+
+	Attributes *entity.ProfileAttributes `json:"attributes,omitempty"`
 }
 
-// PhotoUploadRequest 照片上傳請求結構
-type PhotoUploadRequest struct {
-	ImageURL    string `json:"image_url" binding:"required"`
-	Description string `json:"description"`
+// CompleteRegistrationRequest 外部登入新帳號補填註冊資料請求結構
+type CompleteRegistrationRequest struct {
+	BirthDate   string `json:"birth_date" binding:"required"`
+	DisplayName string `json:"display_name" binding:"required,min=2,max=50"`
+	Gender      string `json:"gender" binding:"required"`
+	Biography   string `json:"biography"`
 }
 
-// GetProfileHandler 獲取用戶檔案
-// GET /users/profile
-func GetProfileHandler(c *gin.Context) {
-	if userHandler == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "用戶服務未初始化",
-		})
-		return
-	}
+// multipartOverhead 照片上傳請求中表單欄位與分隔線的額外大小
+const multipartOverhead = 1 << 20
 
+// GetProfile 獲取用戶檔案
+// GET /users/profile
+func (h *UserHandler) GetProfile(c *gin.Context) {
 	// 從 JWT token 中獲取用戶 ID (假設已經通過認證中間件)
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -70,7 +73,7 @@ func GetProfileHandler(c *gin.Context) {
 	}
 
 	// 獲取用戶檔案
-	userResponse, err := userHandler.userService.GetProfile(c.Request.Context(), userIDUint)
+	userResponse, err := h.userService.GetProfile(c.Request.Context(), userIDUint)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error":   "獲取檔案失敗",
@@ -80,14 +83,14 @@ func GetProfileHandler(c *gin.Context) {
 	}
 
 	// 獲取用戶照片
-	photos, err := userHandler.userService.GetUserPhotos(c.Request.Context(), userIDUint)
+	photos, err := h.userService.GetUserPhotos(c.Request.Context(), userIDUint)
 	if err != nil {
 		// 照片獲取失敗不阻塞主要檔案返回
 		photos = nil
 	}
 
 	// 獲取用戶興趣
-	interests, err := userHandler.userService.GetUserInterests(c.Request.Context(), userIDUint)
+	interests, err := h.userService.GetUserInterests(c.Request.Context(), userIDUint)
 	if err != nil {
 		// 興趣獲取失敗不阻塞主要檔案返回
 		interests = nil
@@ -107,16 +110,9 @@ func GetProfileHandler(c *gin.Context) {
 	})
 }
 
-// UpdateProfileHandler 更新用戶檔案
+// UpdateProfile 更新用戶檔案
 // PUT /users/profile
-func UpdateProfileHandler(c *gin.Context) {
-	if userHandler == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "用戶服務未初始化",
-		})
-		return
-	}
-
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	// 從 JWT token 中獲取用戶 ID
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -145,18 +141,20 @@ func UpdateProfileHandler(c *gin.Context) {
 
 	// 構建服務層請求
 	serviceReq := &usecase.UpdateProfileRequest{
-		DisplayName: req.DisplayName,
-		Biography:   req.Biography,
-		LocationLat: req.LocationLat,
-		LocationLng: req.LocationLng,
-		MaxDistance: req.MaxDistance,
-		AgeRangeMin: req.AgeRangeMin,
-		AgeRangeMax: req.AgeRangeMax,
-		InterestIDs: req.InterestIDs,
+		DisplayName:  req.DisplayName,
+		Biography:    req.Biography,
+		LocationLat:  req.LocationLat,
+		LocationLng:  req.LocationLng,
+		MaxDistance:  req.MaxDistance,
+		AgeRangeMin:  req.AgeRangeMin,
+		AgeRangeMax:  req.AgeRangeMax,
+		InterestedIn: req.InterestedIn,
+		InterestIDs:  req.InterestIDs,
+		Attributes:   req.Attributes,
 	}
 
 	// 調用用戶服務更新檔案
-	userResponse, err := userHandler.userService.UpdateProfile(c.Request.Context(), userIDUint, serviceReq)
+	userResponse, err := h.userService.UpdateProfile(c.Request.Context(), userIDUint, serviceReq)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "更新檔案失敗",
@@ -178,16 +176,159 @@ func UpdateProfileHandler(c *gin.Context) {
 	})
 }
 
-// UploadPhotoHandler 上傳用戶照片
-// POST /users/photos
-func UploadPhotoHandler(c *gin.Context) {
-	if userHandler == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "用戶服務未初始化",
+// CompleteRegistration 補填出生日期等註冊資料，完成後帳號才符合使用條件
+// PUT /api/users/registration
+func (h *UserHandler) CompleteRegistration(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CompleteRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	birthDate, err := time.Parse("2006-01-02", req.BirthDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "出生日期格式錯誤，請使用 YYYY-MM-DD 格式",
+		})
+		return
+	}
+
+	userResponse, err := h.userService.CompleteRegistration(c.Request.Context(), userID, &usecase.CompleteRegistrationRequest{
+		BirthDate:   birthDate,
+		DisplayName: req.DisplayName,
+		Gender:      req.Gender,
+		Biography:   req.Biography,
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrRegistrationCompleted) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "補填註冊資料失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "註冊資料已完成",
+		"user":    userResponse,
+	})
+}
+
+// GetAgeVerification 獲取目前用戶的年齡驗證狀態
+// GET /users/age-verification
+func (h *UserHandler) GetAgeVerification(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	verification, err := h.userService.GetAgeVerificationStatus(c.Request.Context(), userID)
+	if err != nil || verification == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "尚未提交年齡驗證",
 		})
 		return
 	}
 
+	c.JSON(http.StatusOK, gin.H{
+		"verification": verification,
+	})
+}
+
+// SubmitAgeVerification 提交年齡驗證文件
+// 以 multipart/form-data 的 document 欄位上傳證件圖片或 PDF，並提供 method 與 document_number 欄位
+// POST /users/age-verification
+func (h *UserHandler) SubmitAgeVerification(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	maxSize := usecase.MaxVerificationDocumentSize
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+
+	file, err := c.FormFile("document")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":   "驗證文件上傳失敗",
+				"message": usecase.ErrVerificationDocumentTooLarge.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": "請以 multipart/form-data 的 document 欄位上傳驗證文件",
+		})
+		return
+	}
+
+	if file.Size > maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":   "驗證文件上傳失敗",
+			"message": usecase.ErrVerificationDocumentTooLarge.Error(),
+		})
+		return
+	}
+
+	data, err := readFormFile(file, maxSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "讀取驗證文件失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	verification, err := h.userService.SubmitAgeVerification(c.Request.Context(), userID,
+		entity.VerificationMethod(c.PostForm("method")), c.PostForm("document_number"), data)
+	if err != nil {
+		var status int
+		switch {
+		case errors.Is(err, usecase.ErrVerificationDocumentTooLarge):
+			status = http.StatusRequestEntityTooLarge
+		case errors.Is(err, usecase.ErrUnsupportedVerificationType):
+			status = http.StatusUnsupportedMediaType
+		case errors.Is(err, usecase.ErrVerificationUploadUnavailable):
+			status = http.StatusServiceUnavailable
+		case errors.Is(err, usecase.ErrVerificationDocumentEmpty), errors.Is(err, usecase.ErrInvalidVerification):
+			status = http.StatusBadRequest
+		default:
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, gin.H{
+			"error":   "提交年齡驗證失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "年齡驗證已提交，等待審核",
+		"verification": verification,
+	})
+}
+
+// UploadPhoto 上傳用戶照片
+// POST /users/photos
+func (h *UserHandler) UploadPhoto(c *gin.Context) {
 	// 從 JWT token 中獲取用戶 ID
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -205,27 +346,69 @@ func UploadPhotoHandler(c *gin.Context) {
 		return
 	}
 
-	var req PhotoUploadRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	// 限制請求主體大小，超過時讀取會失敗而不是把整個檔案讀進記憶體
+	maxSize := h.userService.MaxPhotoSize()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+
+	file, err := c.FormFile("photo")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":   "照片上傳失敗",
+				"message": usecase.ErrPhotoTooLarge.Error(),
+			})
+			return
+		}
+
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "請求資料格式錯誤",
-			"details": err.Error(),
+			"details": "請以 multipart/form-data 的 photo 欄位上傳照片",
+		})
+		return
+	}
+
+	if file.Size > maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":   "照片上傳失敗",
+			"message": usecase.ErrPhotoTooLarge.Error(),
 		})
 		return
 	}
 
-	// 調用用戶服務添加照片
-	photo, err := userHandler.userService.AddPhoto(c.Request.Context(), userIDUint, req.ImageURL, req.Description)
+	data, err := readFormFile(file, maxSize)
 	if err != nil {
-		if err.Error() == "照片數量已達上限(6張)" {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "照片上傳失敗",
-				"message": err.Error(),
-			})
-			return
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "讀取照片失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// 調用用戶服務處理並保存照片，格式由檔案內容判斷
+	photo, err := h.userService.UploadPhoto(c.Request.Context(), userIDUint, &usecase.PhotoUpload{
+		Data:    data,
+		Type:    entity.PhotoType(c.PostForm("type")),
+		Caption: c.PostForm("caption"),
+	})
+	if err != nil {
+		var status int
+		switch {
+		case errors.Is(err, usecase.ErrPhotoTooLarge):
+			status = http.StatusRequestEntityTooLarge
+		case errors.Is(err, usecase.ErrUnsupportedImageType):
+			status = http.StatusUnsupportedMediaType
+		case errors.Is(err, usecase.ErrPhotoUploadUnavailable):
+			status = http.StatusServiceUnavailable
+		case errors.Is(err, usecase.ErrPhotoEmpty),
+			errors.Is(err, usecase.ErrInvalidImage),
+			errors.Is(err, usecase.ErrPhotoLimitReached):
+			status = http.StatusBadRequest
+		default:
+			status = http.StatusInternalServerError
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(status, gin.H{
 			"error":   "照片上傳失敗",
 			"message": err.Error(),
 		})
@@ -236,21 +419,43 @@ func UploadPhotoHandler(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "照片上傳成功",
 		"photo": gin.H{
-			"id":        photo.ID,
-			"image_url": photo.FilePath,
-			"is_main":   photo.IsMain,
-			"status":    photo.Status,
+			"id":         photo.ID,
+			"image_url":  photo.URL,
+			"thumbnails": photo.ThumbnailURLs,
+			"width":      photo.Width,
+			"height":     photo.Height,
+			"type":       photo.Type,
+			"caption":    photo.Caption,
+			"is_main":    photo.IsMain,
+			"status":     photo.Status,
 		},
 	})
 }
 
-// GetUserPhotosByIDHandler 根據用戶ID獲取照片 (公開API，用於配對顯示)
+// readFormFile 讀取上傳檔案內容，超過 maxSize 時返回錯誤
+func readFormFile(header *multipart.FileHeader, maxSize int64) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, usecase.ErrPhotoTooLarge
+	}
+	return data, nil
+}
+
+// GetUserPhotosByID 根據用戶ID獲取照片 (公開API，用於配對顯示)
+// 私人相簿照片只在查看者獲得擁有者授權時返回
 // GET /users/:id/photos
-func GetUserPhotosByIDHandler(c *gin.Context) {
-	if userHandler == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "用戶服務未初始化",
-		})
+func (h *UserHandler) GetUserPhotosByID(c *gin.Context) {
+	viewerID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
@@ -264,8 +469,8 @@ func GetUserPhotosByIDHandler(c *gin.Context) {
 		return
 	}
 
-	// 獲取用戶照片
-	photos, err := userHandler.userService.GetUserPhotos(c.Request.Context(), uint(userID))
+	// 只返回已通過審核的照片
+	photos, err := h.userService.GetVisiblePhotos(c.Request.Context(), uint(userID), viewerID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error":   "獲取照片失敗",
@@ -274,17 +479,17 @@ func GetUserPhotosByIDHandler(c *gin.Context) {
 		return
 	}
 
-	// 只返回已通過審核的照片
-	var approvedPhotos []map[string]interface{}
+	approvedPhotos := make([]map[string]interface{}, 0, len(photos))
 	for _, photo := range photos {
-		if photo.Status == "approved" { // 假設這是已通過的狀態
-			approvedPhotos = append(approvedPhotos, map[string]interface{}{
-				"id":        photo.ID,
-				"image_url": photo.FilePath,
-				"is_main":   photo.IsMain,
-				"order":     photo.DisplayOrder,
-			})
-		}
+		approvedPhotos = append(approvedPhotos, map[string]interface{}{
+			"id":         photo.ID,
+			"image_url":  photo.URL,
+			"thumbnails": photo.ThumbnailURLs,
+			"type":       photo.Type,
+			"caption":    photo.Caption,
+			"is_main":    photo.IsMain,
+			"order":      photo.DisplayOrder,
+		})
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -292,16 +497,9 @@ func GetUserPhotosByIDHandler(c *gin.Context) {
 	})
 }
 
-// DeletePhotoHandler 刪除用戶照片
+// DeletePhoto 刪除用戶照片
 // DELETE /users/photos/:photoId
-func DeletePhotoHandler(c *gin.Context) {
-	if userHandler == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "用戶服務未初始化",
-		})
-		return
-	}
-
+func (h *UserHandler) DeletePhoto(c *gin.Context) {
 	// 從 JWT token 中獲取用戶 ID
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -329,10 +527,10 @@ func DeletePhotoHandler(c *gin.Context) {
 		return
 	}
 
-	// 調用用戶服務刪除照片
-	err = userHandler.userService.DeletePhoto(c.Request.Context(), userIDUint, uint(photoID))
+	// 調用用戶服務刪除照片，刪除主要照片時由下一張照片遞補
+	err = h.userService.DeletePhoto(c.Request.Context(), userIDUint, uint(photoID))
 	if err != nil {
-		if err.Error() == "無權限操作此照片" {
+		if errors.Is(err, usecase.ErrPhotoForbidden) {
 			c.JSON(http.StatusForbidden, gin.H{
 				"error":   "無權限操作",
 				"message": err.Error(),
@@ -352,3 +550,316 @@ func DeletePhotoHandler(c *gin.Context) {
 		"message": "照片刪除成功",
 	})
 }
+
+// GetUserPhotos 獲取自己的所有照片（包含審核中的照片）
+// GET /users/photos
+func (h *UserHandler) GetUserPhotos(c *gin.Context) {
+	// 從 JWT token 中獲取用戶 ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未授權訪問",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	photos, err := h.userService.GetUserPhotos(c.Request.Context(), userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取照片失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"photos": photos,
+	})
+}
+
+// SetPrimaryPhoto 設定主要照片
+// PUT /users/photos/:photoId/primary
+func (h *UserHandler) SetPrimaryPhoto(c *gin.Context) {
+	// 從 JWT token 中獲取用戶 ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未授權訪問",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	photoID, err := strconv.ParseUint(c.Param("photoId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "照片ID格式錯誤",
+		})
+		return
+	}
+
+	if err := h.userService.SetPrimaryPhoto(c.Request.Context(), userIDUint, uint(photoID)); err != nil {
+		respondPhotoError(c, "設定主要照片失敗", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "主要照片設定成功",
+	})
+}
+
+// UpdatePhotoRequest 更新照片請求結構，未提供的欄位不變更
+type UpdatePhotoRequest struct {
+	Caption *string           `json:"caption"`
+	Type    *entity.PhotoType `json:"type"`
+}
+
+// UpdatePhoto 更新照片說明或切換個人檔案照片與相簿照片
+// PATCH /users/photos/:photoId
+func (h *UserHandler) UpdatePhoto(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	photoID, err := strconv.ParseUint(c.Param("photoId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "照片ID格式錯誤",
+		})
+		return
+	}
+
+	var req UpdatePhotoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	photo, err := h.userService.UpdatePhoto(c.Request.Context(), userID, uint(photoID), &usecase.UpdatePhotoRequest{
+		Caption: req.Caption,
+		Type:    req.Type,
+	})
+	if err != nil {
+		respondPhotoError(c, "更新照片失敗", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "照片更新成功",
+		"photo":   photo,
+	})
+}
+
+// ReorderPhotosRequest 照片排序請求結構，依陣列順序排列
+type ReorderPhotosRequest struct {
+	PhotoIDs []uint `json:"photo_ids" binding:"required"`
+}
+
+// ReorderPhotos 重新排列自己的照片，必須包含所有照片
+// PUT /users/photos/order
+func (h *UserHandler) ReorderPhotos(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ReorderPhotosRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	photos, err := h.userService.ReorderPhotos(c.Request.Context(), userID, req.PhotoIDs)
+	if err != nil {
+		respondPhotoError(c, "調整照片順序失敗", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "照片順序已更新",
+		"photos":  photos,
+	})
+}
+
+// respondPhotoError 將照片管理錯誤對應到 HTTP 狀態碼
+func respondPhotoError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrPhotoForbidden):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "無權限操作",
+			"message": err.Error(),
+		})
+	case errors.Is(err, usecase.ErrPhotoNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   message,
+			"message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   message,
+			"message": err.Error(),
+		})
+	}
+}
+
+// GetUserInterests 獲取自己的興趣
+// GET /users/interests
+func (h *UserHandler) GetUserInterests(c *gin.Context) {
+	// 從 JWT token 中獲取用戶 ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未授權訪問",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "用戶ID格式錯誤",
+		})
+		return
+	}
+
+	interests, err := h.userService.GetUserInterests(c.Request.Context(), userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取用戶興趣失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"interests": interests,
+	})
+}
+
+// UpdateUserInterestsRequest 更新興趣請求結構，空陣列表示清除所有興趣
+type UpdateUserInterestsRequest struct {
+	InterestIDs []uint `json:"interest_ids"`
+}
+
+// UpdateUserInterests 以新的興趣取代自己所有興趣
+// PUT /users/interests
+func (h *UserHandler) UpdateUserInterests(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateUserInterestsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	interests, err := h.userService.SetUserInterests(c.Request.Context(), userID, req.InterestIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "更新興趣失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "興趣已更新",
+		"interests": interests,
+	})
+}
+
+// GetAttributeOptions 獲取結構化屬性的可選值
+// GET /users/attributes/options
+func (h *UserHandler) GetAttributeOptions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"options": entity.AttributeOptions(),
+	})
+}
+
+// GetAttributePreferences 獲取自己的屬性配對條件
+// GET /users/preferences/attributes
+func (h *UserHandler) GetAttributePreferences(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	preferences, err := h.userService.GetAttributePreferences(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "獲取屬性配對條件失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"preferences": preferences,
+	})
+}
+
+// UpdateAttributePreferences 更新自己的屬性配對條件，整份取代現有條件
+// PUT /users/preferences/attributes
+func (h *UserHandler) UpdateAttributePreferences(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req usecase.AttributePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "請求資料格式錯誤",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	preferences, err := h.userService.UpdateAttributePreferences(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, usecase.ErrAttributePreferencesUnavailable) {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "更新屬性配對條件失敗",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "屬性配對條件已更新",
+		"preferences": preferences,
+	})
+}
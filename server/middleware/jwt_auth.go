@@ -1,6 +1,10 @@
 package middleware
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -8,20 +12,64 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+
+	"golang_dev_docker/domain/entity"
+)
+
+// token 驗證錯誤
+var (
+	errTokenRevoked = errors.New("token 已被撤銷")
+	errTokenPurpose = errors.New("token 用途不符")
 )
 
+// mfaTokenPurpose 兩步驟驗證暫時權杖的用途標記
+const mfaTokenPurpose = "mfa_pending"
+
+// TokenRevocationChecker 權杖撤銷檢查介面
+type TokenRevocationChecker interface {
+	IsTokenRevoked(tokenID, sessionID string) (bool, error)
+}
+
+// EmailVerificationChecker 電子郵件驗證狀態檢查介面
+type EmailVerificationChecker interface {
+	IsEmailVerified(ctx context.Context, userID uint) (bool, error)
+}
+
+// AccountStatusChecker 帳號停權狀態檢查介面
+type AccountStatusChecker interface {
+	GetAccountStatus(ctx context.Context, userID uint) (entity.AccountStatus, *time.Time, error)
+}
+
 // JWTAuthMiddleware JWT 認證中間件
 type JWTAuthMiddleware struct {
-	jwtSecret []byte
+	keys              *KeySet
+	revocationChecker TokenRevocationChecker
+	emailChecker      EmailVerificationChecker
+	statusChecker     AccountStatusChecker
 }
 
 // NewJWTAuthMiddleware 建立新的 JWT 認證中間件
-func NewJWTAuthMiddleware(jwtSecret string) *JWTAuthMiddleware {
+func NewJWTAuthMiddleware(keys *KeySet) *JWTAuthMiddleware {
 	return &JWTAuthMiddleware{
-		jwtSecret: []byte(jwtSecret),
+		keys: keys,
 	}
 }
 
+// SetRevocationChecker 設定權杖撤銷檢查器
+func (m *JWTAuthMiddleware) SetRevocationChecker(checker TokenRevocationChecker) {
+	m.revocationChecker = checker
+}
+
+// SetEmailVerificationChecker 設定電子郵件驗證狀態檢查器
+func (m *JWTAuthMiddleware) SetEmailVerificationChecker(checker EmailVerificationChecker) {
+	m.emailChecker = checker
+}
+
+// SetAccountStatusChecker 設定帳號停權狀態檢查器
+func (m *JWTAuthMiddleware) SetAccountStatusChecker(checker AccountStatusChecker) {
+	m.statusChecker = checker
+}
+
 // AuthMiddleware JWT 認證中間件
 func (m *JWTAuthMiddleware) AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -51,6 +99,14 @@ func (m *JWTAuthMiddleware) AuthMiddleware() gin.HandlerFunc {
 
 		// 驗證 token
 		claims, err := m.validateToken(tokenString)
+		if errors.Is(err, errTokenRevoked) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "token 已被撤銷",
+				"code":  "TOKEN_REVOKED",
+			})
+			c.Abort()
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error": fmt.Sprintf("無效的 token: %v", err),
@@ -71,10 +127,7 @@ func (m *JWTAuthMiddleware) AuthMiddleware() gin.HandlerFunc {
 		}
 
 		// 將用戶資訊存儲到上下文中
-		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("email", claims.Email)
-		c.Set("jwt_claims", claims)
+		setClaimsToContext(c, claims)
 
 		c.Next()
 	}
@@ -105,10 +158,7 @@ func (m *JWTAuthMiddleware) OptionalAuthMiddleware() gin.HandlerFunc {
 		}
 
 		// token 有效，設置用戶資訊
-		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("email", claims.Email)
-		c.Set("jwt_claims", claims)
+		setClaimsToContext(c, claims)
 
 		c.Next()
 	}
@@ -124,13 +174,14 @@ func (m *JWTAuthMiddleware) RefreshTokenMiddleware() gin.HandlerFunc {
 			if jwtClaims.ExpiresAt != nil {
 				timeUntilExpiry := time.Until(jwtClaims.ExpiresAt.Time)
 
-				if timeUntilExpiry < 30*time.Minute {
-					// 生成新的 token
-					newToken, err := m.GenerateToken(
+				if timeUntilExpiry < 30*time.Minute && jwtClaims.IssuedAt != nil {
+					// 以相同會話與原有效期生成新的 token
+					newToken, _, err := m.IssueAccessToken(
 						jwtClaims.UserID,
-						jwtClaims.Username,
 						jwtClaims.Email,
-						24*time.Hour, // 24小時有效期
+						jwtClaims.Role,
+						jwtClaims.SessionID,
+						jwtClaims.ExpiresAt.Time.Sub(jwtClaims.IssuedAt.Time),
 					)
 
 					if err == nil {
@@ -155,8 +206,7 @@ func (m *JWTAuthMiddleware) AdminAuthMiddleware() gin.HandlerFunc {
 		}
 
 		// 檢查用戶是否為管理員
-		userID, exists := c.Get("user_id")
-		if !exists {
+		if _, exists := c.Get("user_id"); !exists {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error": "用戶資訊不存在",
 				"code":  "USER_INFO_MISSING",
@@ -165,18 +215,45 @@ func (m *JWTAuthMiddleware) AdminAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// TODO: 檢查用戶是否為管理員
-		// isAdmin := userService.IsAdmin(userID.(uint))
-		// if !isAdmin {
-		//     c.JSON(http.StatusForbidden, gin.H{
-		//         "error": "需要管理員權限",
-		//         "code":  "ADMIN_REQUIRED",
-		//     })
-		//     c.Abort()
-		//     return
-		// }
+		if !GetRoleFromContext(c).AtLeast(entity.RoleAdmin) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "需要管理員權限",
+				"code":  "ADMIN_REQUIRED",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequirePermission 權限檢查中間件，需在 AuthMiddleware 之後使用
+// 用戶角色必須擁有所有指定權限才能繼續
+func (m *JWTAuthMiddleware) RequirePermission(permissions ...entity.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get("user_id"); !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "需要登入",
+				"code":  "LOGIN_REQUIRED",
+			})
+			c.Abort()
+			return
+		}
+
+		role := GetRoleFromContext(c)
+		for _, permission := range permissions {
+			if !role.HasPermission(permission) {
+				c.JSON(http.StatusForbidden, gin.H{
+					"error":      "權限不足",
+					"code":       "PERMISSION_DENIED",
+					"permission": permission,
+				})
+				c.Abort()
+				return
+			}
+		}
 
-		fmt.Printf("管理員認證通過: 用戶 %v\n", userID)
 		c.Next()
 	}
 }
@@ -210,7 +287,50 @@ func (m *JWTAuthMiddleware) AgeVerificationMiddleware() gin.HandlerFunc {
 	}
 }
 
+// EmailVerificationMiddleware 電子郵件驗證中間件
+// 未完成電子郵件驗證的用戶無法使用滑動配對與聊天功能
+func (m *JWTAuthMiddleware) EmailVerificationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "需要登入",
+				"code":  "LOGIN_REQUIRED",
+			})
+			c.Abort()
+			return
+		}
+
+		if m.emailChecker == nil {
+			c.Next()
+			return
+		}
+
+		verified, err := m.emailChecker.IsEmailVerified(c.Request.Context(), userID.(uint))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "無法確認電子郵件驗證狀態",
+				"code":  "EMAIL_STATUS_UNAVAILABLE",
+			})
+			c.Abort()
+			return
+		}
+
+		if !verified {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "請先完成電子郵件驗證",
+				"code":  "EMAIL_NOT_VERIFIED",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // UserStatusMiddleware 用戶狀態檢查中間件
+// 被暫停或封禁的帳號即使持有有效 token 也無法存取 API
 func (m *JWTAuthMiddleware) UserStatusMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, exists := c.Get("user_id")
@@ -219,42 +339,61 @@ func (m *JWTAuthMiddleware) UserStatusMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// TODO: 檢查用戶狀態
-		// user := userService.GetUser(userID.(uint))
-		// if user.Status == "banned" {
-		//     c.JSON(http.StatusForbidden, gin.H{
-		//         "error": "帳戶已被封禁",
-		//         "code":  "ACCOUNT_BANNED",
-		//     })
-		//     c.Abort()
-		//     return
-		// }
-		//
-		// if user.Status == "suspended" {
-		//     c.JSON(http.StatusForbidden, gin.H{
-		//         "error": "帳戶已被暫停",
-		//         "code":  "ACCOUNT_SUSPENDED",
-		//     })
-		//     c.Abort()
-		//     return
-		// }
+		if !enforceAccountStatus(c, m.statusChecker, userID.(uint)) {
+			return
+		}
 
 		// 更新最後活動時間
 		c.Set("last_activity", time.Now())
-
-		fmt.Printf("用戶狀態檢查通過: 用戶 %v\n", userID)
 		c.Next()
 	}
 }
 
+// enforceAccountStatus 檢查帳號停權狀態，不可使用時寫入錯誤回應並中止請求
+// 未設定檢查器時直接放行
+func enforceAccountStatus(c *gin.Context, checker AccountStatusChecker, userID uint) bool {
+	if checker == nil {
+		return true
+	}
+
+	status, suspendedUntil, err := checker.GetAccountStatus(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "無法確認帳戶狀態",
+			"code":  "ACCOUNT_STATUS_UNAVAILABLE",
+		})
+		c.Abort()
+		return false
+	}
+
+	switch status {
+	case entity.AccountStatusSuspended:
+		response := gin.H{
+			"error": "帳戶已被暫停",
+			"code":  "ACCOUNT_SUSPENDED",
+		}
+		if suspendedUntil != nil {
+			response["suspended_until"] = suspendedUntil
+		}
+		c.JSON(http.StatusForbidden, response)
+		c.Abort()
+		return false
+
+	case entity.AccountStatusBanned:
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "帳戶已被封禁",
+			"code":  "ACCOUNT_BANNED",
+		})
+		c.Abort()
+		return false
+	}
+
+	return true
+}
+
 // validateToken 驗證 JWT token
 func (m *JWTAuthMiddleware) validateToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("意外的簽名方法: %v", token.Header["alg"])
-		}
-		return m.jwtSecret, nil
-	})
+	token, err := m.keys.Parse(tokenString, &JWTClaims{})
 
 	if err != nil {
 		return nil, fmt.Errorf("解析 token 失敗: %w", err)
@@ -269,9 +408,135 @@ func (m *JWTAuthMiddleware) validateToken(tokenString string) (*JWTClaims, error
 		return nil, fmt.Errorf("無法獲取 token claims")
 	}
 
+	// 特殊用途權杖（例如兩步驟驗證暫時權杖）不能用於存取 API
+	if claims.Purpose != "" {
+		return nil, errTokenPurpose
+	}
+
+	// 檢查 token 是否已被撤銷
+	if err := checkRevocation(m.revocationChecker, claims); err != nil {
+		return nil, err
+	}
+
 	return claims, nil
 }
 
+// checkRevocation 透過撤銷檢查器確認 token 仍然有效
+func checkRevocation(checker TokenRevocationChecker, claims *JWTClaims) error {
+	if checker == nil {
+		return nil
+	}
+
+	revoked, err := checker.IsTokenRevoked(claims.ID, claims.SessionID)
+	if err != nil {
+		return fmt.Errorf("檢查 token 撤銷狀態失敗: %w", err)
+	}
+	if revoked {
+		return errTokenRevoked
+	}
+	return nil
+}
+
+// setClaimsToContext 將 token 聲明存入請求上下文
+func setClaimsToContext(c *gin.Context, claims *JWTClaims) {
+	c.Set("user_id", claims.UserID)
+	c.Set("username", claims.Username)
+	c.Set("email", claims.Email)
+	c.Set("role", roleFromClaims(claims))
+	c.Set("session_id", claims.SessionID)
+	c.Set("token_id", claims.ID)
+	if claims.ExpiresAt != nil {
+		c.Set("token_expires_at", claims.ExpiresAt.Time)
+	}
+	c.Set("jwt_claims", claims)
+}
+
+// roleFromClaims 從 token 聲明解析角色，無效或缺少時視為一般用戶
+func roleFromClaims(claims *JWTClaims) entity.UserRole {
+	role := entity.UserRole(claims.Role)
+	if !role.IsValid() {
+		return entity.RoleUser
+	}
+	return role
+}
+
+// IssueAccessToken 簽發綁定會話的存取權杖，返回 token 與其唯一識別碼（jti）
+func (m *JWTAuthMiddleware) IssueAccessToken(userID uint, email, role, sessionID string, duration time.Duration) (string, string, error) {
+	tokenID, err := generateTokenID()
+	if err != nil {
+		return "", "", fmt.Errorf("生成 token ID 失敗: %w", err)
+	}
+
+	now := time.Now()
+	claims := &JWTClaims{
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "dating-app",
+			Subject:   fmt.Sprintf("%d", userID),
+		},
+	}
+
+	signed, err := m.keys.Sign(claims)
+	if err != nil {
+		return "", "", err
+	}
+	return signed, tokenID, nil
+}
+
+// IssueMFAToken 簽發兩步驟驗證暫時權杖，只能用於完成登入第二步
+func (m *JWTAuthMiddleware) IssueMFAToken(userID uint, duration time.Duration) (string, error) {
+	tokenID, err := generateTokenID()
+	if err != nil {
+		return "", fmt.Errorf("生成 token ID 失敗: %w", err)
+	}
+
+	now := time.Now()
+	claims := &JWTClaims{
+		UserID:  userID,
+		Purpose: mfaTokenPurpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "dating-app",
+			Subject:   fmt.Sprintf("%d", userID),
+		},
+	}
+
+	return m.keys.Sign(claims)
+}
+
+// ParseMFAToken 驗證兩步驟驗證暫時權杖，返回用戶 ID 與 jti
+func (m *JWTAuthMiddleware) ParseMFAToken(tokenString string) (uint, string, error) {
+	token, err := m.keys.Parse(tokenString, &JWTClaims{})
+	if err != nil {
+		return 0, "", fmt.Errorf("解析 token 失敗: %w", err)
+	}
+
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid || claims.Purpose != mfaTokenPurpose || claims.UserID == 0 {
+		return 0, "", fmt.Errorf("無效的兩步驟驗證 token")
+	}
+	return claims.UserID, claims.ID, nil
+}
+
+// generateTokenID 生成隨機的 token 唯一識別碼
+func generateTokenID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
 // GenerateToken 生成 JWT token
 func (m *JWTAuthMiddleware) GenerateToken(userID uint, username, email string, duration time.Duration) (string, error) {
 	claims := &JWTClaims{
@@ -287,8 +552,7 @@ func (m *JWTAuthMiddleware) GenerateToken(userID uint, username, email string, d
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(m.jwtSecret)
+	return m.keys.Sign(claims)
 }
 
 // ExtractTokenFromRequest 從請求中提取 token
@@ -351,6 +615,16 @@ func GetUserFromContext(c *gin.Context) (uint, string, string, bool) {
 	return userID.(uint), username.(string), email.(string), true
 }
 
+// GetRoleFromContext 從上下文獲取用戶角色，未設定時視為一般用戶
+func GetRoleFromContext(c *gin.Context) entity.UserRole {
+	if role, ok := c.Get("role"); ok {
+		if userRole, ok := role.(entity.UserRole); ok {
+			return userRole
+		}
+	}
+	return entity.RoleUser
+}
+
 // RequireAuth 檢查是否已認證的工具函數
 func RequireAuth(c *gin.Context) bool {
 	_, exists := c.Get("user_id")
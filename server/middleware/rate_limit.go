@@ -1,7 +1,12 @@
 package middleware
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"sync"
 	"time"
@@ -328,6 +333,32 @@ func UserKeyFunc(c *gin.Context) string {
 	return fmt.Sprintf("ip:%s", c.ClientIP())
 }
 
+// maxRefreshBodySize 解析刷新權杖時讀取的請求主體上限
+const maxRefreshBodySize = 4 << 10
+
+// refreshTokenKeyFunc 以請求主體中刷新權杖的雜湊作為鍵，沒有權杖時改用 IP
+// 讀取的內容會放回請求主體，不影響後續處理
+func refreshTokenKeyFunc(c *gin.Context) string {
+	if c.Request.Body == nil {
+		return fmt.Sprintf("refresh:ip:%s", c.ClientIP())
+	}
+
+	head, _ := io.ReadAll(io.LimitReader(c.Request.Body, maxRefreshBodySize))
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), c.Request.Body), c.Request.Body}
+
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.Unmarshal(head, &body); err != nil || body.RefreshToken == "" {
+		return fmt.Sprintf("refresh:ip:%s", c.ClientIP())
+	}
+	sum := sha256.Sum256([]byte(body.RefreshToken))
+	return "refresh:" + hex.EncodeToString(sum[:])
+}
+
 // RouteKeyFunc 基於路由的鍵生成函數
 func RouteKeyFunc(c *gin.Context) string {
 	return fmt.Sprintf("%s:%s:%s", c.ClientIP(), c.Request.Method, c.FullPath())
@@ -349,6 +380,43 @@ func CreateLoginRateLimiter() *RateLimitMiddleware {
 	})
 }
 
+// CreateMFARateLimiter 建立登入第二步驗證速率限制器
+// 帳號層級的失敗次數另由登入限制服務累計，這裡只限制單一來源的請求量
+func CreateMFARateLimiter() *RateLimitMiddleware {
+	// 每 15 分鐘每 IP 20 次
+	limiter := NewSlidingWindowLimiter(20, 15*time.Minute)
+	return NewRateLimitMiddleware(limiter).WithKeyFunc(func(c *gin.Context) string {
+		return fmt.Sprintf("mfa:%s", c.ClientIP())
+	})
+}
+
+// CreateRefreshRateLimiter 建立刷新權杖速率限制器
+// 依刷新權杖的雜湊計算，共用 IP 的用戶各自的會話不互相影響
+func CreateRefreshRateLimiter() *RateLimitMiddleware {
+	// 每分鐘每個刷新權杖 10 次
+	limiter := NewSlidingWindowLimiter(10, time.Minute)
+	return NewRateLimitMiddleware(limiter).WithKeyFunc(refreshTokenKeyFunc)
+}
+
+// CreateOIDCRateLimiter 建立外部身分登入速率限制器，開始與回呼分開計算
+func CreateOIDCRateLimiter() *RateLimitMiddleware {
+	// 每 15 分鐘每 IP 每個端點 20 次
+	limiter := NewSlidingWindowLimiter(20, 15*time.Minute)
+	return NewRateLimitMiddleware(limiter).WithKeyFunc(func(c *gin.Context) string {
+		return fmt.Sprintf("oidc:%s:%s", c.ClientIP(), c.FullPath())
+	})
+}
+
+// CreateSensitiveActionRateLimiter 建立需要密碼或驗證碼的帳號操作速率限制器
+// 每個操作各自建立限制器；已登入時依用戶計算，未登入時依 IP 計算
+func CreateSensitiveActionRateLimiter(action string) *RateLimitMiddleware {
+	// 每 15 分鐘 10 次
+	limiter := NewSlidingWindowLimiter(10, 15*time.Minute)
+	return NewRateLimitMiddleware(limiter).WithKeyFunc(func(c *gin.Context) string {
+		return fmt.Sprintf("%s:%s", action, UserKeyFunc(c))
+	})
+}
+
 // CreateMessageRateLimiter 建立訊息發送速率限制器
 func CreateMessageRateLimiter() *RateLimitMiddleware {
 	// 每分鐘 30 條訊息
@@ -379,6 +447,40 @@ func CreateRegistrationRateLimiter() *RateLimitMiddleware {
 	})
 }
 
+// CreateReportRateLimiter 建立檢舉速率限制器
+func CreateReportRateLimiter() *RateLimitMiddleware {
+	// 每天 10 次檢舉
+	limiter := NewSlidingWindowLimiter(10, 24*time.Hour)
+	return NewRateLimitMiddleware(limiter).WithKeyFunc(UserKeyFunc)
+}
+
+// CreateEmailVerificationRateLimiter 建立電子郵件驗證速率限制器
+func CreateEmailVerificationRateLimiter() *RateLimitMiddleware {
+	// 每 15 分鐘每 IP 10 次驗證嘗試
+	limiter := NewSlidingWindowLimiter(10, 15*time.Minute)
+	return NewRateLimitMiddleware(limiter).WithKeyFunc(func(c *gin.Context) string {
+		return fmt.Sprintf("verify_email:%s", c.ClientIP())
+	})
+}
+
+// CreateResendVerificationRateLimiter 建立重新發送驗證信速率限制器
+func CreateResendVerificationRateLimiter() *RateLimitMiddleware {
+	// 每小時每 IP 5 次重新發送
+	limiter := NewSlidingWindowLimiter(5, time.Hour)
+	return NewRateLimitMiddleware(limiter).WithKeyFunc(func(c *gin.Context) string {
+		return fmt.Sprintf("resend_email:%s", c.ClientIP())
+	})
+}
+
+// CreatePasswordResetRateLimiter 建立密碼重設速率限制器
+func CreatePasswordResetRateLimiter() *RateLimitMiddleware {
+	// 每小時每 IP 5 次重設請求
+	limiter := NewSlidingWindowLimiter(5, time.Hour)
+	return NewRateLimitMiddleware(limiter).WithKeyFunc(func(c *gin.Context) string {
+		return fmt.Sprintf("password_reset:%s", c.ClientIP())
+	})
+}
+
 // CleanupExpired 清理過期的限制記錄
 func CleanupExpired(limiter RateLimiter) {
 	// 這個函數需要根據具體的限制器實現來清理過期記錄
@@ -0,0 +1,348 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSAKeyBits RSA 金鑰最小長度
+const minRSAKeyBits = 2048
+
+// KeyConfig 單一 JWT 金鑰配置
+// 私鑰與公鑰皆可由 PEM 內容（通常來自環境變數）或檔案路徑提供；只有公鑰的金鑰僅用於驗證
+type KeyConfig struct {
+	ID             string
+	PrivateKey     string // PEM 內容
+	PrivateKeyFile string
+	PublicKey      string // PEM 內容
+	PublicKeyFile  string
+}
+
+// SigningKey JWT 簽章金鑰
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer // nil 表示僅用於驗證
+	Public  crypto.PublicKey
+}
+
+// KeySet JWT 金鑰組
+// 以其中一把私鑰簽發權杖，並依 kid 以所有金鑰驗證，輪替期間舊金鑰簽發的權杖仍然有效
+type KeySet struct {
+	mu      sync.RWMutex
+	signing *SigningKey
+	keys    map[string]*SigningKey
+}
+
+// NewKeySet 建立空的金鑰組
+func NewKeySet() *KeySet {
+	return &KeySet{keys: make(map[string]*SigningKey)}
+}
+
+// LoadKeySet 依配置載入金鑰組
+// signingKeyID 留空時使用第一把含私鑰的金鑰簽發
+func LoadKeySet(configs []KeyConfig, signingKeyID string) (*KeySet, error) {
+	set := NewKeySet()
+	for _, config := range configs {
+		key, err := loadKey(config)
+		if err != nil {
+			return nil, fmt.Errorf("載入 JWT 金鑰 %s 失敗: %w", config.ID, err)
+		}
+		if err := set.Add(key); err != nil {
+			return nil, err
+		}
+		if signingKeyID == "" && key.Private != nil {
+			signingKeyID = key.ID
+		}
+	}
+
+	if signingKeyID == "" {
+		return nil, errors.New("未配置可用於簽發的 JWT 私鑰")
+	}
+	if err := set.SetSigningKey(signingKeyID); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// GenerateEd25519Key 產生新的 Ed25519 簽章金鑰
+func GenerateEd25519Key(id string) (*SigningKey, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("產生 Ed25519 金鑰失敗: %w", err)
+	}
+	return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Private: private, Public: public}, nil
+}
+
+// NewEphemeralKeySet 建立只存在於記憶體的金鑰組，重新啟動後先前簽發的權杖全部失效
+// 僅供開發與測試使用
+func NewEphemeralKeySet() (*KeySet, error) {
+	key, err := GenerateEd25519Key("ephemeral")
+	if err != nil {
+		return nil, err
+	}
+	set := NewKeySet()
+	if err := set.Add(key); err != nil {
+		return nil, err
+	}
+	if err := set.SetSigningKey(key.ID); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// Add 加入金鑰，kid 不可重複
+func (s *KeySet) Add(key *SigningKey) error {
+	if key.ID == "" {
+		return errors.New("JWT 金鑰缺少 ID")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.keys[key.ID]; exists {
+		return fmt.Errorf("JWT 金鑰 ID 重複: %s", key.ID)
+	}
+	s.keys[key.ID] = key
+	return nil
+}
+
+// SetSigningKey 切換簽發用的金鑰，舊金鑰保留用於驗證
+func (s *KeySet) SetSigningKey(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, exists := s.keys[id]
+	if !exists {
+		return fmt.Errorf("找不到 JWT 金鑰: %s", id)
+	}
+	if key.Private == nil {
+		return fmt.Errorf("JWT 金鑰 %s 沒有私鑰，無法用於簽發", id)
+	}
+	s.signing = key
+	return nil
+}
+
+// Remove 移除金鑰，使用該金鑰簽發的權杖將無法再驗證
+func (s *KeySet) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.signing != nil && s.signing.ID == id {
+		return fmt.Errorf("無法移除簽發中的 JWT 金鑰: %s", id)
+	}
+	delete(s.keys, id)
+	return nil
+}
+
+// SigningKeyID 目前簽發用金鑰的 ID
+func (s *KeySet) SigningKeyID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.signing == nil {
+		return ""
+	}
+	return s.signing.ID
+}
+
+// Sign 以目前的簽發金鑰簽署聲明，並在標頭加入 kid
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	s.mu.RLock()
+	key := s.signing
+	s.mu.RUnlock()
+
+	if key == nil {
+		return "", errors.New("未設定 JWT 簽發金鑰")
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// Parse 依 kid 選擇金鑰驗證權杖，演算法必須與金鑰相符
+func (s *KeySet) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, s.keyFunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodRS256.Alg()}),
+	)
+}
+
+// keyFunc 依權杖標頭的 kid 返回驗證用公鑰
+func (s *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token 缺少 kid")
+	}
+
+	s.mu.RLock()
+	key, exists := s.keys[kid]
+	s.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("未知的簽章金鑰: %s", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("意外的簽名方法: %v", token.Header["alg"])
+	}
+	return key.Public, nil
+}
+
+// JSONWebKey JWKS 中的單一公鑰
+type JSONWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// JSONWebKeySet 公開的 JWKS 文件
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JWKS 匯出所有驗證用公鑰，供其他服務驗證本服務簽發的權杖
+func (s *KeySet) JWKS() JSONWebKeySet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.keys))
+	for id := range s.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	set := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(ids))}
+	for _, id := range ids {
+		key := s.keys[id]
+		jwk := JSONWebKey{Kid: key.ID, Alg: key.Method.Alg(), Use: "sig"}
+		switch public := key.Public.(type) {
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// loadKey 由 PEM 內容或檔案載入金鑰
+func loadKey(config KeyConfig) (*SigningKey, error) {
+	privatePEM, err := readPEM(config.PrivateKey, config.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if privatePEM != nil {
+		return ParsePrivateKeyPEM(config.ID, privatePEM)
+	}
+
+	publicPEM, err := readPEM(config.PublicKey, config.PublicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if publicPEM != nil {
+		return ParsePublicKeyPEM(config.ID, publicPEM)
+	}
+	return nil, errors.New("未提供私鑰或公鑰")
+}
+
+// readPEM 優先使用直接提供的內容，否則讀取檔案
+func readPEM(content, path string) ([]byte, error) {
+	if content != "" {
+		return []byte(content), nil
+	}
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("讀取金鑰檔案失敗: %w", err)
+	}
+	return data, nil
+}
+
+// ParsePrivateKeyPEM 解析 PKCS#8（Ed25519 或 RSA）或 PKCS#1（RSA）私鑰
+func ParsePrivateKeyPEM(id string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("無效的 PEM 格式")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("不支援的私鑰格式: %s", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析私鑰失敗: %w", err)
+	}
+
+	switch private := parsed.(type) {
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Private: private, Public: private.Public()}, nil
+	case *rsa.PrivateKey:
+		if private.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA 金鑰長度不得小於 %d 位元", minRSAKeyBits)
+		}
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, Private: private, Public: &private.PublicKey}, nil
+	default:
+		return nil, errors.New("僅支援 Ed25519 或 RSA 私鑰")
+	}
+}
+
+// ParsePublicKeyPEM 解析 PKIX 公鑰，僅用於驗證輪替前簽發的權杖
+func ParsePublicKeyPEM(id string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("無效的 PEM 格式")
+	}
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("不支援的公鑰格式: %s", block.Type)
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析公鑰失敗: %w", err)
+	}
+
+	switch public := parsed.(type) {
+	case ed25519.PublicKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Public: public}, nil
+	case *rsa.PublicKey:
+		if public.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA 金鑰長度不得小於 %d 位元", minRSAKeyBits)
+		}
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, Public: public}, nil
+	default:
+		return nil, errors.New("僅支援 Ed25519 或 RSA 公鑰")
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -12,21 +13,36 @@ import (
 
 // WebSocketAuthMiddleware WebSocket 認證中間件
 type WebSocketAuthMiddleware struct {
-	jwtSecret []byte
+	keys              *KeySet
+	revocationChecker TokenRevocationChecker
+	statusChecker     AccountStatusChecker
 }
 
 // NewWebSocketAuthMiddleware 建立新的 WebSocket 認證中間件
-func NewWebSocketAuthMiddleware(jwtSecret string) *WebSocketAuthMiddleware {
+func NewWebSocketAuthMiddleware(keys *KeySet) *WebSocketAuthMiddleware {
 	return &WebSocketAuthMiddleware{
-		jwtSecret: []byte(jwtSecret),
+		keys: keys,
 	}
 }
 
+// SetRevocationChecker 設定權杖撤銷檢查器
+func (m *WebSocketAuthMiddleware) SetRevocationChecker(checker TokenRevocationChecker) {
+	m.revocationChecker = checker
+}
+
+// SetAccountStatusChecker 設定帳號停權狀態檢查器
+func (m *WebSocketAuthMiddleware) SetAccountStatusChecker(checker AccountStatusChecker) {
+	m.statusChecker = checker
+}
+
 // JWTClaims JWT 聲明結構
 type JWTClaims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	UserID    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	Role      string `json:"role,omitempty"`
+	SessionID string `json:"sid,omitempty"`
+	Purpose   string `json:"purpose,omitempty"` // 非空時為特殊用途權杖（例如兩步驟驗證），不能用於存取 API
 	jwt.RegisteredClaims
 }
 
@@ -68,6 +84,14 @@ func (m *WebSocketAuthMiddleware) AuthenticateWebSocket() gin.HandlerFunc {
 
 		// 5. 驗證和解析 token
 		claims, err := m.validateToken(token)
+		if errors.Is(err, errTokenRevoked) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "token 已被撤銷",
+				"code":  "TOKEN_REVOKED",
+			})
+			c.Abort()
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error": fmt.Sprintf("無效的 token: %v", err),
@@ -88,10 +112,7 @@ func (m *WebSocketAuthMiddleware) AuthenticateWebSocket() gin.HandlerFunc {
 		}
 
 		// 7. 將用戶資訊存儲到上下文中
-		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("email", claims.Email)
-		c.Set("jwt_claims", claims)
+		setClaimsToContext(c, claims)
 
 		// 8. 記錄認證成功
 		c.Header("X-Authenticated-User", fmt.Sprintf("%d", claims.UserID))
@@ -102,14 +123,8 @@ func (m *WebSocketAuthMiddleware) AuthenticateWebSocket() gin.HandlerFunc {
 
 // validateToken 驗證 JWT token
 func (m *WebSocketAuthMiddleware) validateToken(tokenString string) (*JWTClaims, error) {
-	// 解析 token
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// 驗證簽名方法
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("意外的簽名方法: %v", token.Header["alg"])
-		}
-		return m.jwtSecret, nil
-	})
+	// 解析 token，依 kid 選擇驗證金鑰
+	token, err := m.keys.Parse(tokenString, &JWTClaims{})
 
 	if err != nil {
 		return nil, fmt.Errorf("解析 token 失敗: %w", err)
@@ -126,6 +141,16 @@ func (m *WebSocketAuthMiddleware) validateToken(tokenString string) (*JWTClaims,
 		return nil, fmt.Errorf("無法獲取 token claims")
 	}
 
+	// 特殊用途權杖不能用於建立連接
+	if claims.Purpose != "" {
+		return nil, errTokenPurpose
+	}
+
+	// 檢查 token 是否已被撤銷
+	if err := checkRevocation(m.revocationChecker, claims); err != nil {
+		return nil, err
+	}
+
 	return claims, nil
 }
 
@@ -223,16 +248,14 @@ func (m *WebSocketAuthMiddleware) UserStatusMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// TODO: 這裡應該檢查用戶是否被封鎖、停用等
-		// userService.IsUserActive(userID.(uint))
-		// userService.IsUserBlocked(userID.(uint))
+		// 被暫停或封禁的帳號不允許建立連接
+		if !enforceAccountStatus(c, m.statusChecker, userID.(uint)) {
+			return
+		}
 
 		// 設置用戶最後活動時間
 		c.Set("last_activity", time.Now())
 
-		// 暫時記錄用戶狀態檢查
-		fmt.Printf("用戶狀態檢查: 用戶 %v\n", userID)
-
 		c.Next()
 	}
 } // WebSocketSecurityMiddleware 安全中間件
@@ -371,6 +394,5 @@ func (m *WebSocketAuthMiddleware) GenerateToken(userID uint, username, email str
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(m.jwtSecret)
+	return m.keys.Sign(claims)
 }
@@ -2,17 +2,26 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"golang_dev_docker/domain/entity"
 	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/infrastructure/breach"
+	"golang_dev_docker/infrastructure/imaging"
+	"golang_dev_docker/infrastructure/mail"
 	"golang_dev_docker/infrastructure/mysql"
+	"golang_dev_docker/infrastructure/oidc"
 	"golang_dev_docker/infrastructure/redis"
+	"golang_dev_docker/infrastructure/storage"
+	"golang_dev_docker/server/handler"
 	"golang_dev_docker/server/middleware"
 	"golang_dev_docker/server/websocket"
 
@@ -69,20 +78,44 @@ func (w *WebSocketNotifierAdapter) BroadcastToUsers(userIDs []uint, message inte
 	return nil
 }
 
+// DisconnectUser 中斷用戶的即時連線
+// 實作 AccountModerationService 所需的 ConnectionTerminator 介面
+func (w *WebSocketNotifierAdapter) DisconnectUser(userID uint) {
+	if w.manager != nil {
+		w.manager.DisconnectUser(userID)
+	}
+}
+
 // ServerConfig 伺服器配置
 type ServerConfig struct {
-	Port                    int           `yaml:"port"`
-	Mode                    string        `yaml:"mode"` // gin 模式: debug, release, test
-	ReadTimeout             time.Duration `yaml:"read_timeout"`
-	WriteTimeout            time.Duration `yaml:"write_timeout"`
-	IdleTimeout             time.Duration `yaml:"idle_timeout"`
-	MaxHeaderBytes          int           `yaml:"max_header_bytes"`
-	GracefulShutdownTimeout time.Duration `yaml:"graceful_shutdown_timeout"`
-	EnablePprof             bool          `yaml:"enable_pprof"`
-	EnableMetrics           bool          `yaml:"enable_metrics"`
-	StaticPath              string        `yaml:"static_path"`
-	UploadPath              string        `yaml:"upload_path"`
-	JWTSecret               string        `yaml:"jwt_secret"`
+	Port                    int                    `yaml:"port"`
+	Mode                    string                 `yaml:"mode"` // gin 模式: debug, release, test
+	ReadTimeout             time.Duration          `yaml:"read_timeout"`
+	WriteTimeout            time.Duration          `yaml:"write_timeout"`
+	IdleTimeout             time.Duration          `yaml:"idle_timeout"`
+	MaxHeaderBytes          int                    `yaml:"max_header_bytes"`
+	GracefulShutdownTimeout time.Duration          `yaml:"graceful_shutdown_timeout"`
+	EnablePprof             bool                   `yaml:"enable_pprof"`
+	EnableMetrics           bool                   `yaml:"enable_metrics"`
+	StaticPath              string                 `yaml:"static_path"`
+	UploadPath              string                 `yaml:"upload_path"` // 本機檔案儲存目錄，Storage 未指定目錄時使用
+	JWTSecret               string                 `yaml:"jwt_secret"`  // 衍生下載連結簽章金鑰
+	JWTKeys                 *middleware.KeySet     `yaml:"-"`           // 權杖簽章金鑰組，未設定時使用臨時金鑰
+	AccessTokenTTL          time.Duration          `yaml:"access_token_ttl"`
+	RefreshTokenTTL         time.Duration          `yaml:"refresh_token_ttl"`
+	BaseURL                 string                 `yaml:"base_url"`                 // 對外網址，用於郵件中的連結
+	RequireStaffTwoFactor   bool                   `yaml:"require_staff_two_factor"` // 審核員與管理員必須啟用兩步驟驗證
+	DeletionGracePeriod     time.Duration          `yaml:"deletion_grace_period"`    // 帳號刪除寬限期
+	Mail                    mail.MailConfig        `yaml:"mail"`
+	OIDCProviders           []oidc.ProviderConfig  `yaml:"oidc_providers"` // 外部身分登入提供者
+	PasswordPolicy          usecase.PasswordPolicy `yaml:"password_policy"`
+	BreachedPasswordDir     string                 `yaml:"breached_password_dir"` // 完整外洩密碼清單目錄，留空時僅使用內建清單
+	MaxPhotoSize            int64                  `yaml:"max_photo_size"`        // 單張照片上傳大小上限（位元組）
+	Imaging                 imaging.Config         `yaml:"imaging"`
+	PhotoDuplicateDistance  int                    `yaml:"photo_duplicate_distance"` // 視為相同照片的最大漢明距離
+	PhotoLimits             usecase.PhotoLimits    `yaml:"photo_limits"`             // 各訂閱方案的照片數量上限
+	Storage                 storage.Config         `yaml:"storage"`                  // 上傳檔案儲存，多個副本時使用 S3 相容儲存
+	FileURLTTL              time.Duration          `yaml:"file_url_ttl"`             // 上傳檔案下載連結有效時間
 }
 
 // DefaultServerConfig 預設伺服器配置
@@ -100,6 +133,19 @@ func DefaultServerConfig() *ServerConfig {
 		StaticPath:              "./static",
 		UploadPath:              "./uploads",
 		JWTSecret:               "your-secret-key",
+		AccessTokenTTL:          15 * time.Minute,
+		RefreshTokenTTL:         7 * 24 * time.Hour,
+		BaseURL:                 "http://localhost:8080",
+		DeletionGracePeriod:     usecase.DefaultDeletionGracePeriod,
+		PasswordPolicy:          usecase.DefaultPasswordPolicy(),
+		MaxPhotoSize:            usecase.DefaultMaxPhotoSize,
+		Imaging:                 imaging.DefaultConfig(),
+		PhotoDuplicateDistance:  usecase.DefaultDuplicateDistance,
+		PhotoLimits:             usecase.PhotoLimits{Default: usecase.DefaultMaxPhotos},
+		FileURLTTL:              usecase.DefaultFileURLTTL,
+		Mail: mail.MailConfig{
+			Driver: "log",
+		},
 	}
 }
 
@@ -115,15 +161,51 @@ type Server struct {
 	chatHandler  *websocket.ChatHandler
 
 	// 業務服務
-	userService     *usecase.UserService
-	matchingService *usecase.MatchingService
-	chatService     *usecase.ChatService
+	userService       *usecase.UserService
+	matchingService   *usecase.MatchingService
+	chatService       *usecase.ChatService
+	reportService     *usecase.ReportService
+	authService       *usecase.AuthService
+	emailService      *usecase.EmailVerificationService
+	passwordService   *usecase.PasswordService
+	roleService       *usecase.RoleService
+	accountService    *usecase.AccountModerationService
+	twoFactorService  *usecase.TwoFactorService
+	deletionService   *usecase.AccountDeletionService
+	exportService     *usecase.DataExportService
+	oidcService       *usecase.OIDCService
+	promptService     *usecase.PromptService
+	interestService   *usecase.InterestService
+	fileService       *usecase.FileService
+	photoModeration   *usecase.PhotoModerationService
+	photoFingerprints *usecase.PhotoFingerprintService
+	privateAlbums     *usecase.PrivateAlbumService
+
+	// HTTP 處理器
+	authHandler      *handler.AuthHandler
+	userHandler      *handler.UserHandler
+	matchingHandler  *handler.MatchingHandler
+	chatAPIHandler   *handler.ChatHandler
+	reportHandler    *handler.ReportHandler
+	adminHandler     *handler.AdminHandler
+	twoFactorHandler *handler.TwoFactorHandler
+	accountHandler   *handler.AccountHandler
+	exportHandler    *handler.DataExportHandler
+	oidcHandler      *handler.OIDCHandler
+	promptHandler    *handler.PromptHandler
+	interestHandler  *handler.InterestHandler
+	fileHandler      *handler.FileHandler
+	photoModHandler  *handler.PhotoModerationHandler
+	albumHandler     *handler.PrivateAlbumHandler
 
 	// 中間件
 	jwtAuth        *middleware.JWTAuthMiddleware
 	wsAuth         *middleware.WebSocketAuthMiddleware
 	corsMiddleware *middleware.CORSMiddleware
 	rateLimiters   map[string]*middleware.RateLimitMiddleware
+
+	// 背景排程
+	jobCancel context.CancelFunc
 }
 
 // NewServer 建立新的伺服器實例
@@ -137,6 +219,21 @@ func NewServer(config *ServerConfig) *Server {
 		rateLimiters: make(map[string]*middleware.RateLimitMiddleware),
 	}
 
+	// 未配置金鑰時使用僅存在於記憶體的金鑰，重新啟動後需重新登入
+	if config.JWTKeys == nil {
+		keys, err := middleware.NewEphemeralKeySet()
+		if err != nil {
+			log.Fatalf("產生 JWT 臨時金鑰失敗: %v", err)
+		}
+		log.Println("警告: 未配置 JWT 簽章金鑰，使用臨時金鑰")
+		config.JWTKeys = keys
+	}
+
+	// JWT 認證中間件同時負責簽發權杖，需在業務服務初始化之前建立
+	// 兩個中間件共用同一金鑰組，輪替後兩者同時生效
+	server.jwtAuth = middleware.NewJWTAuthMiddleware(config.JWTKeys)
+	server.wsAuth = middleware.NewWebSocketAuthMiddleware(config.JWTKeys)
+
 	return server
 }
 
@@ -199,17 +296,58 @@ func (s *Server) InitializeServices() error {
 	// 創建儲存庫實例
 	userRepo := mysql.NewUserRepository(db)
 	userProfileRepo := mysql.NewUserProfileRepository(db)
+	photoRepo := mysql.NewPhotoRepository(db)
+	blockedImageRepo := mysql.NewBlockedImageRepository(db)
+	privateAlbumRepo := mysql.NewPrivateAlbumRepository(db)
+	interestRepo := mysql.NewInterestRepository(db)
+	ageVerificationRepo := mysql.NewAgeVerificationRepository(db)
+	attributePreferenceRepo := mysql.NewAttributePreferenceRepository(db)
+	promptRepo := mysql.NewPromptRepository(db)
+	profilePromptRepo := mysql.NewProfilePromptRepository(db)
 	matchRepo := mysql.NewMatchRepository(db)
+	algorithmRepo := mysql.NewMatchingAlgorithmRepository(db)
 	chatRepo := mysql.NewChatRepository(db)
 	chatListRepo := mysql.NewChatListRepository(db)
 	websocketRepo := mysql.NewWebSocketRepository(db)
+	reportRepo := mysql.NewReportRepository(db)
+	blockRepo := mysql.NewBlockRepository(db)
+	moderationRepo := mysql.NewModerationRepository(db)
+	twoFactorRepo := mysql.NewTwoFactorRepository(db)
+	erasureRepo := mysql.NewAccountErasureRepository(db)
+	exportRepo := mysql.NewDataExportRepository(db)
+	personalDataRepo := mysql.NewPersonalDataRepository(db)
+
+	// 初始化上傳檔案儲存，檔案只能透過有期限的簽名連結下載
+	storageConfig := s.config.Storage
+	if storageConfig.LocalDir == "" {
+		storageConfig.LocalDir = s.config.UploadPath
+	}
+	blobStore, err := storage.New(storageConfig)
+	if err != nil {
+		return fmt.Errorf("初始化檔案儲存失敗: %w", err)
+	}
+	s.fileService = usecase.NewFileService(blobStore, s.config.JWTSecret, s.config.BaseURL, s.config.FileURLTTL)
+	loginAttemptRepo := mysql.NewLoginAttemptRepository(db)
+	identityRepo := mysql.NewUserIdentityRepository(db)
+
+	// 權杖有效期（未配置時使用預設值）
+	accessTokenTTL := s.config.AccessTokenTTL
+	if accessTokenTTL <= 0 {
+		accessTokenTTL = 15 * time.Minute
+	}
+	refreshTokenTTL := s.config.RefreshTokenTTL
+	if refreshTokenTTL <= 0 {
+		refreshTokenTTL = 7 * 24 * time.Hour
+	}
 
 	// 創建 Redis 快取服務（如果可用）
 	var matchingCache *redis.MatchingCacheService
+	var sessionCache *redis.SessionCacheService
 
 	if s.redisClient != nil {
-		// 初始化會話快取服務但暫時不存儲引用（將在後續整合到認證中間件）
-		_ = redis.NewSessionCacheService(s.redisClient)
+		// 初始化會話快取服務，會話有效期與刷新權杖一致
+		sessionCache = redis.NewSessionCacheService(s.redisClient)
+		sessionCache.SetSessionTTL(refreshTokenTTL)
 
 		// 初始化配對快取服務
 		matchingCache = redis.NewMatchingCacheService(s.redisClient)
@@ -217,28 +355,60 @@ func (s *Server) InitializeServices() error {
 		log.Println("Redis 快取服務初始化成功")
 	}
 
+	// 初始化密碼規則，外洩密碼清單皆在本機查詢
+	breachedPasswords, err := breach.NewDefaultChecker(s.config.BreachedPasswordDir)
+	if err != nil {
+		return fmt.Errorf("初始化外洩密碼清單失敗: %w", err)
+	}
+	passwordValidator := usecase.NewPasswordValidator(s.config.PasswordPolicy, breachedPasswords)
+
 	// 初始化用戶服務
-	// TODO: 需要添加缺少的儲存庫（PhotoRepository, InterestRepository, AgeVerificationRepository）
 	s.userService = usecase.NewUserService(
 		userRepo,
 		userProfileRepo,
-		nil, // photoRepo - 需要實作
-		nil, // interestRepo - 需要實作
-		nil, // ageVerificationRepo - 需要實作
+		photoRepo,
+		interestRepo,
+		ageVerificationRepo,
 	)
+	s.userService.SetPasswordValidator(passwordValidator)
+	s.userService.SetAttributePreferenceRepository(attributePreferenceRepo)
+	s.userService.SetProfilePromptRepository(profilePromptRepo)
+	s.userService.SetPhotoProcessing(imaging.NewProcessor(s.config.Imaging), s.fileService, s.config.MaxPhotoSize)
+	// 年齡驗證文件只允許本人與審核人員下載
+	s.fileService.AddAccessChecker(s.userService)
+	// 尚無訂閱系統可查詢方案，所有用戶套用預設上限
+	s.userService.SetPhotoLimits(s.config.PhotoLimits, nil)
+
+	// 初始化照片指紋服務，上傳時比對其他帳號的照片與禁用圖片清單
+	s.photoFingerprints = usecase.NewPhotoFingerprintService(photoRepo, blockedImageRepo, userRepo, moderationRepo)
+	s.photoFingerprints.SetMaxDistance(s.config.PhotoDuplicateDistance)
+	s.userService.SetPhotoFingerprints(s.photoFingerprints)
+
+	// 初始化私人相簿服務，私人照片的下載連結綁定查看者並在下載時檢查授權
+	s.privateAlbums = usecase.NewPrivateAlbumService(privateAlbumRepo, matchRepo, userRepo)
+	s.userService.SetPrivateAlbums(s.privateAlbums)
+	s.fileService.AddAccessChecker(s.privateAlbums)
+
+	// 初始化檔案問答服務，新的回答會寫入審核日誌等待審核
+	s.promptService = usecase.NewPromptService(promptRepo, profilePromptRepo, userRepo, moderationRepo)
+
+	// 初始化興趣標籤目錄服務
+	s.interestService = usecase.NewInterestService(interestRepo)
 
 	// 初始化配對服務
-	// TODO: 需要添加 MatchingAlgorithmRepository
 	s.matchingService = usecase.NewMatchingService(
 		matchRepo,
-		nil, // algorithmRepo - 需要實作
+		algorithmRepo,
 		userRepo,
 		userProfileRepo,
 	)
+	s.matchingService.SetAttributePreferenceRepository(attributePreferenceRepo)
+	s.matchingService.SetPrivateAlbums(s.privateAlbums)
 
 	// 設定配對快取（如果可用）
 	if matchingCache != nil {
 		s.matchingService.SetCache(matchingCache)
+		s.userService.SetMatchingCache(matchingCache)
 		log.Println("配對服務快取整合完成")
 	}
 
@@ -257,29 +427,199 @@ func (s *Server) InitializeServices() error {
 		s.chatService.SetWebSocketNotifier(wsNotifier)
 		log.Println("聊天服務 WebSocket 通知整合完成")
 	}
+	s.chatService.SetFileService(s.fileService)
+	s.fileService.AddAccessChecker(s.chatService)
+
+	// 初始化認證服務
+	s.authService = usecase.NewAuthService(s.userService, s.jwtAuth, accessTokenTTL, refreshTokenTTL)
+	if sessionCache != nil {
+		s.authService.SetSessionStore(&SessionStoreAdapter{sessions: sessionCache})
+	}
+	if s.cacheService != nil {
+		s.authService.SetTokenBlacklist(s.cacheService)
+	}
+	s.jwtAuth.SetRevocationChecker(s.authService)
+	s.wsAuth.SetRevocationChecker(s.authService)
+
+	// 整合兩步驟驗證，暫時權杖同樣由 JWT 中間件簽發
+	s.twoFactorService = usecase.NewTwoFactorService(userRepo, twoFactorRepo, "Dating App")
+	s.twoFactorService.SetRequireForStaff(s.config.RequireStaffTwoFactor)
+	s.authService.SetTwoFactor(s.twoFactorService, s.jwtAuth)
+	log.Println("認證服務初始化完成")
+
+	// 初始化電子郵件驗證服務
+	mailer, err := mail.NewMailer(&s.config.Mail)
+	if err != nil {
+		return fmt.Errorf("初始化郵件服務失敗: %w", err)
+	}
+	var verificationCodes usecase.VerificationCodeStore
+	if s.cacheService != nil {
+		verificationCodes = s.cacheService
+	}
+	s.emailService = usecase.NewEmailVerificationService(userRepo, mailer, verificationCodes, s.config.BaseURL)
+	s.jwtAuth.SetEmailVerificationChecker(s.emailService)
+
+	// 初始化密碼管理服務
+	var resetTokens usecase.PasswordResetStore
+	if s.cacheService != nil {
+		resetTokens = s.cacheService
+	}
+	s.passwordService = usecase.NewPasswordService(userRepo, mailer, resetTokens, s.authService)
+	s.passwordService.SetPasswordValidator(passwordValidator)
+	s.passwordService.SetProfileRepository(userProfileRepo)
+
+	// 初始化登入暴力破解防護，依帳號與來源 IP 追蹤失敗次數
+	loginThrottle := usecase.NewLoginThrottleService(loginAttemptRepo, userRepo, usecase.DefaultLoginThrottlePolicy())
+	if s.cacheService != nil {
+		loginThrottle.SetAttemptStore(s.cacheService)
+	} else {
+		log.Println("警告: Redis 不可用，登入失敗次數限制已停用")
+	}
+	loginThrottle.SetMailer(mailer)
+	s.authService.SetLoginThrottle(loginThrottle)
+
+	// 初始化照片審核服務，照片被拒絕時以即時通知與郵件告知用戶
+	s.photoModeration = usecase.NewPhotoModerationService(photoRepo, userRepo, moderationRepo)
+	s.photoModeration.SetFileService(s.fileService)
+	s.photoModeration.SetMailer(mailer)
+	if s.wsManager != nil {
+		s.photoModeration.SetNotifier(&WebSocketNotifierAdapter{manager: s.wsManager})
+	}
+
+	// 初始化外部身分登入，授權流程狀態保存於 Redis
+	oidcProviders := make([]usecase.OIDCProvider, 0, len(s.config.OIDCProviders))
+	for _, providerConfig := range s.config.OIDCProviders {
+		if providerConfig.RedirectURL == "" {
+			providerConfig.RedirectURL = strings.TrimSuffix(s.config.BaseURL, "/") + "/oidc/callback"
+		}
+		provider, err := oidc.NewProvider(providerConfig, nil)
+		if err != nil {
+			return fmt.Errorf("初始化 OIDC 提供者 %s 失敗: %w", providerConfig.Name, err)
+		}
+		oidcProviders = append(oidcProviders, provider)
+	}
+	var oidcStates usecase.OIDCStateStore
+	if s.cacheService != nil {
+		oidcStates = s.cacheService
+	}
+	s.oidcService = usecase.NewOIDCService(userRepo, identityRepo, oidcStates, oidcProviders...)
+
+	// 初始化角色權限服務
+	s.roleService = usecase.NewRoleService(userRepo, s.authService)
+	s.roleService.SetPasswordValidator(passwordValidator)
+
+	// 初始化帳號停權服務，並在中間件中強制檢查停權狀態
+	s.accountService = usecase.NewAccountModerationService(userRepo, moderationRepo, s.authService)
+	if s.wsManager != nil {
+		s.accountService.SetConnectionTerminator(&WebSocketNotifierAdapter{manager: s.wsManager})
+	}
+	s.jwtAuth.SetAccountStatusChecker(s.accountService)
+	s.wsAuth.SetAccountStatusChecker(s.accountService)
+
+	// 啟動到期暫停自動恢復排程
+	jobCtx, jobCancel := context.WithCancel(context.Background())
+	s.jobCancel = jobCancel
+	s.accountService.StartReinstatementJob(jobCtx, time.Minute)
+	loginThrottle.StartCleanupJob(jobCtx, 24*time.Hour)
+
+	// 初始化帳號刪除服務，寬限期後清除個人資料與上傳檔案
+	s.deletionService = usecase.NewAccountDeletionService(userRepo, erasureRepo, s.authService, s.config.DeletionGracePeriod)
+	s.deletionService.SetFileRemover(s.fileService)
+	archiveStore := NewBlobArchiveStore(blobStore)
+	s.deletionService.SetArchiveStore(archiveStore)
+	if s.cacheService != nil {
+		s.deletionService.SetUserDataCache(&UserDataCacheAdapter{cache: s.cacheService, matching: matchingCache})
+	}
+	if s.wsManager != nil {
+		s.deletionService.SetConnectionTerminator(&WebSocketNotifierAdapter{manager: s.wsManager})
+	}
+	if s.cacheService != nil {
+		s.deletionService.SetConfirmationMailer(mailer, s.cacheService)
+	}
+	s.deletionService.StartErasureJob(jobCtx, time.Hour)
+
+	// 初始化個人資料匯出服務，下載連結以 JWT 金鑰衍生的金鑰簽名
+	s.exportService = usecase.NewDataExportService(exportRepo, personalDataRepo, archiveStore, s.config.JWTSecret, s.config.BaseURL)
+	s.exportService.SetSessionLister(s.authService)
+	s.exportService.StartExportJob(jobCtx, 5*time.Minute)
+
+	// 初始化檢舉服務
+	s.reportService = usecase.NewReportService(
+		reportRepo,
+		blockRepo,
+		moderationRepo,
+		userRepo,
+		matchRepo,
+		s.accountService,
+	)
+	s.reportService.SetPrivateAlbums(s.privateAlbums)
+
+	// 初始化 HTTP 處理器（依賴注入）
+	s.authHandler = handler.NewAuthHandler(s.userService, s.authService, s.emailService, s.passwordService)
+	s.userHandler = handler.NewUserHandler(s.userService)
+	s.matchingHandler = handler.NewMatchingHandler(s.matchingService)
+	s.chatAPIHandler = handler.NewChatHandler(s.chatService, s.matchingService)
+	s.reportHandler = handler.NewReportHandler(s.reportService)
+	s.adminHandler = handler.NewAdminHandler(s.reportService, s.roleService, s.accountService)
+	s.twoFactorHandler = handler.NewTwoFactorHandler(s.twoFactorService)
+	s.accountHandler = handler.NewAccountHandler(s.deletionService)
+	s.exportHandler = handler.NewDataExportHandler(s.exportService)
+	s.fileHandler = handler.NewFileHandler(s.fileService)
+	s.oidcHandler = handler.NewOIDCHandler(s.oidcService, s.authService)
+	s.promptHandler = handler.NewPromptHandler(s.promptService)
+	s.interestHandler = handler.NewInterestHandler(s.interestService)
+	s.photoModHandler = handler.NewPhotoModerationHandler(s.photoModeration, s.photoFingerprints)
+	s.albumHandler = handler.NewPrivateAlbumHandler(s.privateAlbums)
 
 	log.Println("業務服務初始化成功")
 	return nil
 }
 
-// InitializeMiddleware 初始化中間件
-func (s *Server) InitializeMiddleware(env string) {
-	// JWT 認證中間件
-	s.jwtAuth = middleware.NewJWTAuthMiddleware(s.config.JWTSecret)
+// BootstrapAdmin 建立初始超級管理員（需在 InitializeServices 之後呼叫）
+// 系統中已有管理員時不做任何變更
+func (s *Server) BootstrapAdmin(email, password string) error {
+	if s.roleService == nil {
+		return fmt.Errorf("業務服務尚未初始化")
+	}
 
-	// WebSocket 認證中間件
-	s.wsAuth = middleware.NewWebSocketAuthMiddleware(s.config.JWTSecret)
+	created, err := s.roleService.BootstrapAdmin(context.Background(), email, password)
+	if errors.Is(err, usecase.ErrBootstrapNotOwned) {
+		// 不因他人註冊的帳號阻止服務啟動，由管理者確認後改用其他信箱
+		log.Printf("略過初始管理員建立: %v", err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("建立初始管理員失敗: %w", err)
+	}
+	if !created {
+		log.Println("已存在管理員，略過初始管理員建立")
+	}
+	return nil
+}
 
+// InitializeMiddleware 初始化中間件
+func (s *Server) InitializeMiddleware(env string) {
 	// CORS 中間件
 	s.corsMiddleware = middleware.CreateCORSMiddleware(env)
 
 	// 速率限制中間件
 	s.rateLimiters["api"] = middleware.CreateAPIRateLimiter()
 	s.rateLimiters["login"] = middleware.CreateLoginRateLimiter()
+	s.rateLimiters["mfa"] = middleware.CreateMFARateLimiter()
+	s.rateLimiters["refresh"] = middleware.CreateRefreshRateLimiter()
+	s.rateLimiters["oidc"] = middleware.CreateOIDCRateLimiter()
+	s.rateLimiters["password_change"] = middleware.CreateSensitiveActionRateLimiter("password_change")
+	s.rateLimiters["account_deletion"] = middleware.CreateSensitiveActionRateLimiter("account_deletion")
+	s.rateLimiters["identity"] = middleware.CreateSensitiveActionRateLimiter("identity")
+	s.rateLimiters["two_factor"] = middleware.CreateSensitiveActionRateLimiter("two_factor")
 	s.rateLimiters["message"] = middleware.CreateMessageRateLimiter()
 	s.rateLimiters["swipe"] = middleware.CreateSwipeRateLimiter()
 	s.rateLimiters["photo"] = middleware.CreatePhotoUploadRateLimiter()
 	s.rateLimiters["register"] = middleware.CreateRegistrationRateLimiter()
+	s.rateLimiters["report"] = middleware.CreateReportRateLimiter()
+	s.rateLimiters["verify_email"] = middleware.CreateEmailVerificationRateLimiter()
+	s.rateLimiters["resend_email"] = middleware.CreateResendVerificationRateLimiter()
+	s.rateLimiters["password_reset"] = middleware.CreatePasswordResetRateLimiter()
 
 	log.Println("中間件初始化成功")
 }
@@ -292,8 +632,8 @@ func (s *Server) SetupRoutes() {
 	s.engine.Use(s.corsMiddleware.Handler())
 
 	// 靜態檔案服務
+	// 上傳檔案不公開，改由 /api/files 以簽名連結下載
 	s.engine.Static("/static", s.config.StaticPath)
-	s.engine.Static("/uploads", s.config.UploadPath)
 
 	// 首頁路由 - 直接提供 index.html 內容，保持 URL 為 /
 	s.engine.GET("/", func(c *gin.Context) {
@@ -304,10 +644,13 @@ func (s *Server) SetupRoutes() {
 	s.engine.GET("/health", s.healthCheckHandler)
 	s.engine.GET("/api/health", s.apiHealthCheckHandler)
 
+	// 公開驗證金鑰，供其他服務驗證本服務簽發的權杖
+	s.engine.GET("/.well-known/jwks.json", s.jwksHandler)
+
 	// WebSocket 端點
 	wsGroup := s.engine.Group("/ws")
 	wsGroup.Use(s.wsAuth.CreateAuthMiddlewareChain()...)
-	wsGroup.GET("/chat", s.wsManager.HandleWebSocket)
+	wsGroup.GET("/chat", s.jwtAuth.EmailVerificationMiddleware(), s.wsManager.HandleWebSocket)
 
 	// API 路由組
 	apiGroup := s.engine.Group("/api")
@@ -315,56 +658,176 @@ func (s *Server) SetupRoutes() {
 
 	// 認證路由（不需要 JWT 認證）
 	authGroup := apiGroup.Group("/auth")
-	authGroup.Use(s.rateLimiters["login"].Handler())
 	{
-		// TODO: 添加認證相關的路由
-		// authGroup.POST("/register", registerHandler)
-		// authGroup.POST("/login", loginHandler)
-		// authGroup.POST("/logout", logoutHandler)
+		authGroup.POST("/register", s.rateLimiters["register"].Handler(), s.authHandler.Register)
+		authGroup.POST("/login", s.rateLimiters["login"].Handler(), s.authHandler.Login)
+		authGroup.POST("/login/2fa", s.rateLimiters["mfa"].Handler(), s.authHandler.VerifyMFA)
+		authGroup.POST("/login/2fa/enroll", s.rateLimiters["mfa"].Handler(), s.authHandler.BeginMFAEnrollment)
+		authGroup.POST("/refresh", s.rateLimiters["refresh"].Handler(), s.authHandler.Refresh)
+		authGroup.POST("/verify-email", s.rateLimiters["verify_email"].Handler(), s.authHandler.VerifyEmail)
+		authGroup.GET("/verify-email", s.rateLimiters["verify_email"].Handler(), s.authHandler.VerifyEmail)
+		authGroup.POST("/verify-email/resend", s.rateLimiters["resend_email"].Handler(), s.authHandler.ResendVerification)
+		authGroup.POST("/password/forgot", s.rateLimiters["password_reset"].Handler(), s.authHandler.ForgotPassword)
+		authGroup.POST("/password/reset", s.rateLimiters["password_reset"].Handler(), s.authHandler.ResetPassword)
+		authGroup.POST("/account/cancel-deletion", s.rateLimiters["account_deletion"].Handler(), s.accountHandler.CancelDeletion)
+		authGroup.POST("/account/cancel-deletion/email", s.rateLimiters["account_deletion"].Handler(), s.accountHandler.SendCancellationConfirmation)
+		authGroup.POST("/account/cancel-deletion/confirm", s.rateLimiters["account_deletion"].Handler(), s.accountHandler.ConfirmCancellation)
+		authGroup.POST("/account/confirm-deletion", s.rateLimiters["account_deletion"].Handler(), s.accountHandler.ConfirmDeletion)
+
+		// 外部身分登入路由
+		authGroup.GET("/oidc/providers", s.oidcHandler.ListProviders)
+		authGroup.POST("/oidc/:provider/start", s.rateLimiters["oidc"].Handler(), s.oidcHandler.BeginLogin)
+		authGroup.POST("/oidc/:provider/callback", s.rateLimiters["oidc"].Handler(), s.oidcHandler.CompleteLogin)
 	}
 
+	// 個人資料匯出下載（由連結簽名授權，不需要 JWT 認證）
+	apiGroup.GET("/exports/:id/download", s.exportHandler.Download)
+
+	// 上傳檔案下載（由連結簽名授權，不需要 JWT 認證）
+	apiGroup.GET("/files/*key", s.fileHandler.Download)
+
 	// 需要認證的路由
 	protectedGroup := apiGroup.Group("")
-	protectedGroup.Use(s.jwtAuth.AuthMiddleware())
+	protectedGroup.Use(s.jwtAuth.AuthMiddleware(), s.jwtAuth.UserStatusMiddleware())
 	{
+		// 登出相關路由
+		protectedGroup.POST("/auth/logout", s.authHandler.Logout)
+		protectedGroup.POST("/auth/logout-all", s.authHandler.LogoutAll)
+		protectedGroup.GET("/auth/sessions", s.authHandler.ListSessions)
+		protectedGroup.DELETE("/auth/sessions/:id", s.authHandler.RevokeSession)
+
 		// 用戶相關路由
-		userGroup := protectedGroup.Group("/user")
-		_ = userGroup // TODO: 添加用戶相關的路由
+		userGroup := protectedGroup.Group("/users")
 		{
-			// TODO: 添加用戶相關的路由
-			// userGroup.GET("/profile", getUserProfileHandler)
-			// userGroup.PUT("/profile", updateUserProfileHandler)
+			userGroup.GET("/profile", s.userHandler.GetProfile)
+			userGroup.PUT("/profile", s.userHandler.UpdateProfile)
+			userGroup.PUT("/registration", s.userHandler.CompleteRegistration)
+			userGroup.GET("/age-verification", s.userHandler.GetAgeVerification)
+			userGroup.POST("/age-verification", s.rateLimiters["photo"].Handler(), s.userHandler.SubmitAgeVerification)
+			userGroup.PUT("/password", s.rateLimiters["password_change"].Handler(), s.authHandler.ChangePassword)
+			userGroup.DELETE("/account", s.rateLimiters["account_deletion"].Handler(), s.accountHandler.RequestDeletion)
+			userGroup.POST("/account/deletion-confirmation", s.rateLimiters["account_deletion"].Handler(), s.accountHandler.SendDeletionConfirmation)
+			userGroup.POST("/data-export", s.exportHandler.RequestExport)
+			userGroup.GET("/data-export", s.exportHandler.GetExportStatus)
+			userGroup.GET("/interests", s.userHandler.GetUserInterests)
+			userGroup.PUT("/interests", s.userHandler.UpdateUserInterests)
+			userGroup.GET("/attributes/options", s.userHandler.GetAttributeOptions)
+			userGroup.GET("/preferences/attributes", s.userHandler.GetAttributePreferences)
+			userGroup.PUT("/preferences/attributes", s.userHandler.UpdateAttributePreferences)
+			userGroup.GET("/prompts", s.promptHandler.GetProfilePrompts)
+			userGroup.PUT("/prompts", s.promptHandler.UpdateProfilePrompts)
+
+			// 外部帳號連結路由
+			identityGroup := userGroup.Group("/identities")
+			{
+				identityGroup.GET("", s.oidcHandler.ListIdentities)
+				identityGroup.POST("/:provider/link", s.rateLimiters["identity"].Handler(), s.oidcHandler.BeginLink)
+				identityGroup.POST("/:provider/link/callback", s.rateLimiters["identity"].Handler(), s.oidcHandler.CompleteLink)
+				identityGroup.DELETE("/:provider", s.rateLimiters["identity"].Handler(), s.oidcHandler.Unlink)
+			}
+
+			// 兩步驟驗證設定路由（需驗證碼的操作套用獨立的頻率限制）
+			twoFactorGroup := userGroup.Group("/2fa")
+			{
+				twoFactorGroup.GET("", s.twoFactorHandler.GetStatus)
+				twoFactorGroup.POST("/setup", s.twoFactorHandler.BeginEnrollment)
+				twoFactorGroup.POST("/confirm", s.rateLimiters["two_factor"].Handler(), s.twoFactorHandler.ConfirmEnrollment)
+				twoFactorGroup.DELETE("", s.rateLimiters["two_factor"].Handler(), s.twoFactorHandler.Disable)
+				twoFactorGroup.POST("/recovery-codes", s.rateLimiters["two_factor"].Handler(), s.twoFactorHandler.RegenerateRecoveryCodes)
+			}
+			userGroup.GET("/:id/photos", s.userHandler.GetUserPhotosByID)
+
+			// 照片相關路由
+			photoGroup := userGroup.Group("/photos")
+			{
+				photoGroup.GET("", s.userHandler.GetUserPhotos)
+				photoGroup.POST("", s.rateLimiters["photo"].Handler(), s.userHandler.UploadPhoto)
+				photoGroup.PUT("/order", s.userHandler.ReorderPhotos)
+				photoGroup.PATCH("/:photoId", s.userHandler.UpdatePhoto)
+				photoGroup.PUT("/:photoId/primary", s.userHandler.SetPrimaryPhoto)
+				photoGroup.DELETE("/:photoId", s.userHandler.DeletePhoto)
+			}
+
+			// 私人相簿授權路由，只能授權配對中的用戶
+			albumGroup := userGroup.Group("/albums/private/access")
+			{
+				albumGroup.GET("", s.albumHandler.ListGrants)
+				albumGroup.PUT("/:userId", s.albumHandler.GrantAccess)
+				albumGroup.DELETE("/:userId", s.albumHandler.RevokeAccess)
+			}
 		}
 
+		// 興趣相關路由
+		protectedGroup.GET("/interests", s.interestHandler.ListInterests)
+		protectedGroup.GET("/interests/search", s.interestHandler.SearchInterests)
+
+		// 檔案問答題目
+		protectedGroup.GET("/prompts", s.promptHandler.ListPrompts)
+
 		// 配對相關路由
-		matchGroup := protectedGroup.Group("/match")
-		matchGroup.Use(s.rateLimiters["swipe"].Handler())
-		_ = matchGroup // TODO: 添加配對相關的路由
+		matchGroup := protectedGroup.Group("/matching")
 		{
-			// TODO: 添加配對相關的路由
-			// matchGroup.GET("/candidates", getMatchCandidatesHandler)
-			// matchGroup.POST("/swipe", swipeHandler)
+			matchGroup.GET("/potential", s.matchingHandler.GetPotentialMatches)
+			matchGroup.POST("/swipe", s.jwtAuth.EmailVerificationMiddleware(), s.rateLimiters["swipe"].Handler(), s.matchingHandler.Swipe)
+			matchGroup.GET("/matches", s.matchingHandler.GetUserMatches)
+			matchGroup.DELETE("/matches/:userId", s.matchingHandler.Unmatch)
+			matchGroup.GET("/stats", s.matchingHandler.GetMatchingStats)
 		}
 
 		// 聊天相關路由
 		chatGroup := protectedGroup.Group("/chat")
-		chatGroup.Use(s.rateLimiters["message"].Handler())
-		_ = chatGroup // TODO: 添加聊天相關的路由
+		chatGroup.Use(s.jwtAuth.EmailVerificationMiddleware())
 		{
-			// TODO: 添加聊天相關的路由
-			// chatGroup.GET("/list", getChatListHandler)
-			// chatGroup.GET("/:chat_id/messages", getChatMessagesHandler)
-			// chatGroup.POST("/:chat_id/messages", sendMessageHandler)
+			chatGroup.GET("/matches", s.chatAPIHandler.GetChatMatches)
+			chatGroup.GET("/matches/:matchId/messages", s.chatAPIHandler.GetChatHistory)
+			chatGroup.PUT("/matches/:matchId/read", s.chatAPIHandler.MarkMessagesAsRead)
+			chatGroup.POST("/messages", s.rateLimiters["message"].Handler(), s.chatAPIHandler.SendChatMessage)
+			chatGroup.POST("/matches/:matchId/attachments", s.rateLimiters["message"].Handler(), s.chatAPIHandler.SendAttachment)
 		}
 
-		// 照片上傳路由
-		photoGroup := protectedGroup.Group("/photos")
-		photoGroup.Use(s.rateLimiters["photo"].Handler())
-		_ = photoGroup // TODO: 添加照片相關的路由
+		// 檢舉相關路由
+		reportGroup := protectedGroup.Group("/reports")
 		{
-			// TODO: 添加照片相關的路由
-			// photoGroup.POST("/upload", uploadPhotoHandler)
-			// photoGroup.DELETE("/:photo_id", deletePhotoHandler)
+			reportGroup.GET("", s.reportHandler.GetMyReports)
+			reportGroup.POST("", s.rateLimiters["report"].Handler(), s.reportHandler.SubmitReport)
+		}
+
+		// 封鎖相關路由
+		blockGroup := protectedGroup.Group("/blocks")
+		{
+			blockGroup.GET("", s.reportHandler.GetBlockList)
+			blockGroup.POST("", s.reportHandler.BlockUser)
+			blockGroup.DELETE("/:userId", s.reportHandler.UnblockUser)
+		}
+
+		// 管理後台路由（依權限控管）
+		adminGroup := protectedGroup.Group("/admin")
+		{
+			adminGroup.GET("/reports/pending", s.jwtAuth.RequirePermission(entity.PermissionReportReview), s.adminHandler.GetPendingReports)
+			adminGroup.PUT("/reports/:id/review", s.jwtAuth.RequirePermission(entity.PermissionReportReview), s.adminHandler.ReviewReport)
+			adminGroup.GET("/reports/stats", s.jwtAuth.RequirePermission(entity.PermissionReportStats), s.adminHandler.GetReportStats)
+			adminGroup.PUT("/users/:id/role", s.jwtAuth.RequirePermission(entity.PermissionRoleAssign), s.adminHandler.AssignRole)
+			adminGroup.PUT("/users/:id/suspend", s.jwtAuth.RequirePermission(entity.PermissionUserSuspend), s.adminHandler.SuspendUser)
+			adminGroup.PUT("/users/:id/ban", s.jwtAuth.RequirePermission(entity.PermissionUserBan), s.adminHandler.BanUser)
+			adminGroup.PUT("/users/:id/reinstate", s.jwtAuth.RequirePermission(entity.PermissionUserSuspend), s.adminHandler.ReinstateUser)
+			adminGroup.GET("/prompts", s.jwtAuth.RequirePermission(entity.PermissionPromptManage), s.promptHandler.ListAllPrompts)
+			adminGroup.POST("/prompts", s.jwtAuth.RequirePermission(entity.PermissionPromptManage), s.promptHandler.CreatePrompt)
+			adminGroup.PUT("/prompts/:id", s.jwtAuth.RequirePermission(entity.PermissionPromptManage), s.promptHandler.UpdatePrompt)
+			adminGroup.GET("/interests", s.jwtAuth.RequirePermission(entity.PermissionInterestManage), s.interestHandler.ListAllInterests)
+			adminGroup.POST("/interests", s.jwtAuth.RequirePermission(entity.PermissionInterestManage), s.interestHandler.CreateInterest)
+			adminGroup.GET("/interests/export", s.jwtAuth.RequirePermission(entity.PermissionInterestManage), s.interestHandler.ExportInterests)
+			adminGroup.POST("/interests/import", s.jwtAuth.RequirePermission(entity.PermissionInterestManage), s.interestHandler.ImportInterests)
+			adminGroup.PUT("/interests/:id", s.jwtAuth.RequirePermission(entity.PermissionInterestManage), s.interestHandler.UpdateInterest)
+			adminGroup.DELETE("/interests/:id", s.jwtAuth.RequirePermission(entity.PermissionInterestManage), s.interestHandler.DeleteInterest)
+			adminGroup.GET("/prompt-answers/pending", s.jwtAuth.RequirePermission(entity.PermissionContentReview), s.promptHandler.GetPendingAnswers)
+			adminGroup.PUT("/prompt-answers/:id/review", s.jwtAuth.RequirePermission(entity.PermissionContentReview), s.promptHandler.ReviewAnswer)
+			adminGroup.GET("/photos/pending", s.jwtAuth.RequirePermission(entity.PermissionContentReview), s.photoModHandler.GetQueue)
+			adminGroup.POST("/photos/:id/claim", s.jwtAuth.RequirePermission(entity.PermissionContentReview), s.photoModHandler.ClaimPhoto)
+			adminGroup.DELETE("/photos/:id/claim", s.jwtAuth.RequirePermission(entity.PermissionContentReview), s.photoModHandler.ReleasePhoto)
+			adminGroup.PUT("/photos/:id/review", s.jwtAuth.RequirePermission(entity.PermissionContentReview), s.photoModHandler.ReviewPhoto)
+			adminGroup.GET("/blocked-images", s.jwtAuth.RequirePermission(entity.PermissionContentReview), s.photoModHandler.ListBlockedImages)
+			adminGroup.POST("/blocked-images", s.jwtAuth.RequirePermission(entity.PermissionContentReview), s.photoModHandler.BlockImage)
+			adminGroup.DELETE("/blocked-images/:id", s.jwtAuth.RequirePermission(entity.PermissionContentReview), s.photoModHandler.UnblockImage)
 		}
 	}
 
@@ -380,6 +843,12 @@ func (s *Server) healthCheckHandler(c *gin.Context) {
 	})
 }
 
+// jwksHandler 返回所有驗證用公鑰
+func (s *Server) jwksHandler(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, s.config.JWTKeys.JWKS())
+}
+
 // apiHealthCheckHandler API 健康檢查（包含依賴服務）
 func (s *Server) apiHealthCheckHandler(c *gin.Context) {
 	health := gin.H{
@@ -485,6 +954,11 @@ func (s *Server) Shutdown() error {
 		log.Printf("伺服器關閉錯誤: %v", err)
 	}
 
+	// 停止背景排程
+	if s.jobCancel != nil {
+		s.jobCancel()
+	}
+
 	// 關閉 WebSocket 管理器
 	if s.wsManager != nil {
 		s.wsManager.Shutdown()
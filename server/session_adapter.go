@@ -0,0 +1,110 @@
+package server
+
+import (
+	"time"
+
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/infrastructure/redis"
+)
+
+// SessionStoreAdapter 會話快取適配器
+// 將 Redis 會話快取轉接為認證服務使用的 usecase.AuthSessionStore
+type SessionStoreAdapter struct {
+	sessions *redis.SessionCacheService
+}
+
+// CreateSession 建立會話
+func (a *SessionStoreAdapter) CreateSession(session *usecase.AuthSession) error {
+	return a.sessions.StoreUserSession(session.UserID, session.SessionID, &redis.SessionData{
+		UserID:     session.UserID,
+		Email:      session.Email,
+		Role:       session.Role,
+		LoginTime:  session.LoginTime,
+		LastActive: session.LastActive,
+		IPAddress:  session.IPAddress,
+		UserAgent:  session.UserAgent,
+		TokenID:    session.TokenID,
+	})
+}
+
+// GetSession 獲取會話
+func (a *SessionStoreAdapter) GetSession(sessionID string) (*usecase.AuthSession, error) {
+	data, err := a.sessions.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAuthSession(sessionID, data), nil
+}
+
+// ListUserSessions 列出用戶所有有效會話
+func (a *SessionStoreAdapter) ListUserSessions(userID uint) ([]*usecase.AuthSession, error) {
+	sessions, err := a.sessions.GetUserSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*usecase.AuthSession, 0, len(sessions))
+	for sessionID, data := range sessions {
+		result = append(result, toAuthSession(sessionID, data))
+	}
+	return result, nil
+}
+
+// TouchSession 延長會話有效期並更新最後活躍時間
+func (a *SessionStoreAdapter) TouchSession(userID uint, sessionID, tokenID string) error {
+	return a.sessions.RefreshUserSession(userID, sessionID, tokenID)
+}
+
+// DeleteSession 刪除會話
+func (a *SessionStoreAdapter) DeleteSession(userID uint, sessionID string) error {
+	return a.sessions.DeleteUserSession(userID, sessionID)
+}
+
+// DeleteAllUserSessions 刪除用戶所有會話
+func (a *SessionStoreAdapter) DeleteAllUserSessions(userID uint) error {
+	return a.sessions.DeleteAllUserSessions(userID)
+}
+
+// DeleteOtherUserSessions 刪除用戶除指定會話以外的所有會話
+func (a *SessionStoreAdapter) DeleteOtherUserSessions(userID uint, keepSessionID string) error {
+	return a.sessions.DeleteOtherUserSessions(userID, keepSessionID)
+}
+
+// StoreRefreshToken 儲存刷新權杖
+func (a *SessionStoreAdapter) StoreRefreshToken(tokenHash string, record *usecase.RefreshTokenRecord, ttl time.Duration) error {
+	return a.sessions.StoreRefreshToken(tokenHash, &redis.RefreshTokenData{
+		UserID:    record.UserID,
+		SessionID: record.SessionID,
+		IssuedAt:  record.IssuedAt,
+	}, ttl)
+}
+
+// ConsumeRefreshToken 消耗刷新權杖
+func (a *SessionStoreAdapter) ConsumeRefreshToken(tokenHash string) (*usecase.RefreshTokenRecord, bool, error) {
+	data, firstUse, err := a.sessions.ConsumeRefreshToken(tokenHash)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &usecase.RefreshTokenRecord{
+		UserID:    data.UserID,
+		SessionID: data.SessionID,
+		IssuedAt:  data.IssuedAt,
+	}, firstUse, nil
+}
+
+// toAuthSession 將 Redis 會話資料轉換為認證服務的會話
+func toAuthSession(sessionID string, data *redis.SessionData) *usecase.AuthSession {
+	return &usecase.AuthSession{
+		SessionID:  sessionID,
+		UserID:     data.UserID,
+		Email:      data.Email,
+		Role:       data.Role,
+		IPAddress:  data.IPAddress,
+		UserAgent:  data.UserAgent,
+		TokenID:    data.TokenID,
+		LoginTime:  data.LoginTime,
+		LastActive: data.LastActive,
+	}
+}
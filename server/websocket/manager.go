@@ -416,6 +416,22 @@ func (m *Manager) IsUserOnline(userID uint) bool {
 	return exists
 }
 
+// DisconnectUser 強制中斷用戶的 WebSocket 連接（例如帳號被停權時）
+func (m *Manager) DisconnectUser(userID uint) {
+	m.mu.RLock()
+	client, exists := m.userClients[userID]
+	m.mu.RUnlock()
+
+	if !exists {
+		return
+	}
+
+	select {
+	case m.unregister <- client:
+	case <-m.ctx.Done():
+	}
+}
+
 // GetOnlineUsers 獲取在線用戶列表
 func (m *Manager) GetOnlineUsers() []uint {
 	m.mu.RLock()
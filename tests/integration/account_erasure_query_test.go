@@ -0,0 +1,35 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang_dev_docker/infrastructure/mysql"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEraseUserRedactsFreeText 測試清除帳號時以佔位值取代檢舉與審核日誌的自由文字，保留記錄本身
+func TestEraseUserRedactsFreeText(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := mysql.NewAccountErasureRepository(db)
+
+	_, err := repo.EraseUser(context.Background(), 42, time.Now())
+	require.NoError(t, err)
+
+	reports, ok := recorder.find("UPDATE `reports`", "`description`=", "`review_notes`=")
+	require.True(t, ok, "檢舉說明與審核備註應被清除")
+	assert.Contains(t, reports.SQL, "reporter_id = ? OR reported_id = ?")
+	assert.Contains(t, reports.Args, "[已清除]")
+	assert.NotContains(t, reports.SQL, "DELETE")
+
+	logs, ok := recorder.find("UPDATE `moderation_logs`", "`reason`=", "`notes`=")
+	require.True(t, ok, "審核日誌的原因與備註應被清除")
+	assert.Contains(t, logs.SQL, "user_id = ?")
+	assert.NotContains(t, logs.SQL, "`action`")
+
+	_, deleted := recorder.find("DELETE FROM `reports`")
+	assert.False(t, deleted, "檢舉記錄保留供稽核")
+}
@@ -0,0 +1,41 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/infrastructure/mysql"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSimilarHashQueryUsesBandIndexes 測試相似雜湊查詢先以區段索引篩選，且候選包含距離以內的所有區段值
+func TestSimilarHashQueryUsesBandIndexes(t *testing.T) {
+	const hash = uint64(0x0123456789abcdef)
+	db, recorder := newRecordingDB(t)
+
+	_, err := mysql.NewPhotoRepository(db).FindSimilar(context.Background(), hash, usecase.DefaultDuplicateDistance, 7, 5)
+	require.NoError(t, err)
+	_, err = mysql.NewBlockedImageRepository(db).FindSimilar(context.Background(), hash, usecase.DefaultDuplicateDistance)
+	require.NoError(t, err)
+
+	for _, table := range []string{"FROM `photos`", "FROM `blocked_images`"} {
+		statement, ok := recorder.find(table, "BIT_COUNT")
+		require.True(t, ok, table)
+		sql := strings.Join(strings.Fields(statement.SQL), " ")
+		for _, column := range []string{"hash_band0 IN (", "hash_band1 IN (", "hash_band2 IN (", "hash_band3 IN ("} {
+			assert.Contains(t, sql, column, table)
+		}
+
+		// 距離 10 時每個區段至少有一個距離不超過 2，候選值需涵蓋 1 + 16 + 120 個區段值
+		bands := entity.HashBands(hash)
+		assert.Contains(t, statement.Args, int64(bands[0]))
+		assert.Contains(t, statement.Args, int64(bands[1]^0x0101))
+		assert.NotContains(t, statement.Args, int64(bands[2]^0x0111), "區段距離 3 的值不在候選中")
+		assert.GreaterOrEqual(t, len(statement.Args), entity.HashBandCount*137)
+	}
+}
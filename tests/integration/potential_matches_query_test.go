@@ -0,0 +1,63 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+	"golang_dev_docker/infrastructure/mysql"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPotentialMatchesQueryIsMutual 測試潛在配對查詢同時套用雙方的性別、年齡與距離偏好
+func TestPotentialMatchesQueryIsMutual(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := mysql.NewMatchingAlgorithmRepository(db)
+
+	minAge, maxAge := 25, 35
+	_, err := repo.GetPotentialMatches(context.Background(), 7, repository.PotentialMatchParams{
+		MinAge:              &minAge,
+		MaxAge:              &maxAge,
+		PreferredGenders:    []entity.Gender{entity.GenderFemale},
+		ExcludeSwipedUsers:  true,
+		ExcludeBlockedUsers: true,
+		Limit:               20,
+	})
+	require.NoError(t, err)
+
+	statement, ok := recorder.find("FROM `users`", "seeker_profile")
+	require.True(t, ok, "應查詢用戶與雙方檔案")
+	sql := strings.Join(strings.Fields(statement.SQL), " ")
+
+	// 查詢者以參數綁定，不可拼接進 SQL
+	assert.Contains(t, sql, "INNER JOIN users AS seeker ON seeker.id = ?")
+	assert.EqualValues(t, 7, statement.Args[0])
+	assert.Contains(t, sql, "users.id != ?")
+
+	// 性別偏好雙向比對：對方符合查詢者的偏好，查詢者也符合對方的偏好
+	assert.Contains(t, sql, "FIND_IN_SET(user_profiles.gender, seeker_profile.interested_in)")
+	assert.Contains(t, sql, "FIND_IN_SET(seeker_profile.gender, user_profiles.interested_in)")
+
+	// 查詢者的年齡在對方設定的範圍內，對方的年齡在本次查詢的範圍內
+	assert.Contains(t, sql, "YEAR(NOW()) - YEAR(seeker.birth_date) BETWEEN user_profiles.age_range_min AND user_profiles.age_range_max")
+	assert.Contains(t, sql, "YEAR(NOW()) - YEAR(users.birth_date) >= ?")
+	assert.Contains(t, sql, "YEAR(NOW()) - YEAR(users.birth_date) <= ?")
+	assert.Contains(t, statement.Args, int64(minAge))
+	assert.Contains(t, statement.Args, int64(maxAge))
+
+	// 距離以對方設定的最大距離判斷，查詢者未設定位置時不限
+	assert.Contains(t, sql, "seeker_profile.location_lat IS NULL OR seeker_profile.location_lng IS NULL")
+	assert.Contains(t, sql, "POINT(seeker_profile.location_lng, seeker_profile.location_lat) ) <= user_profiles.max_distance * 1000")
+
+	// 只推薦可配對的帳號，並排除已滑動與封鎖的用戶
+	assert.Contains(t, sql, "users.is_active = ? AND users.is_verified = ? AND users.registration_pending = ? AND users.account_status = ?")
+	assert.Contains(t, sql, "FROM matches")
+	assert.Contains(t, sql, "FROM blocks")
+	assert.Contains(t, sql, "user_profiles.gender IN (?)")
+	assert.Contains(t, statement.Args, string(entity.GenderFemale))
+	assert.Contains(t, sql, "LIMIT ?")
+}
@@ -0,0 +1,125 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordedStatement 測試用資料庫記錄的 SQL 與參數，參數已轉換為資料庫驅動的型別（整數為 int64）
+type recordedStatement struct {
+	SQL  string
+	Args []interface{}
+}
+
+// sqlRecorder 測試用資料庫連線，記錄執行的 SQL，查詢一律返回空結果
+// 用於檢查儲存庫產生的查詢條件，不需要實際的 MySQL
+type sqlRecorder struct {
+	mu         sync.Mutex
+	statements []recordedStatement
+}
+
+// newRecordingDB 建立使用 sqlRecorder 的 gorm 連線
+func newRecordingDB(t *testing.T) (*gorm.DB, *sqlRecorder) {
+	recorder := &sqlRecorder{}
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sql.OpenDB(recorder),
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	return db, recorder
+}
+
+// find 返回包含所有關鍵字的第一筆 SQL
+func (r *sqlRecorder) find(keywords ...string) (recordedStatement, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, statement := range r.statements {
+		matched := true
+		for _, keyword := range keywords {
+			if !strings.Contains(statement.SQL, keyword) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return statement, true
+		}
+	}
+	return recordedStatement{}, false
+}
+
+func (r *sqlRecorder) record(query string, args []driver.NamedValue) {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	r.mu.Lock()
+	r.statements = append(r.statements, recordedStatement{SQL: query, Args: values})
+	r.mu.Unlock()
+}
+
+func (r *sqlRecorder) Connect(ctx context.Context) (driver.Conn, error) { return recorderConn{r}, nil }
+func (r *sqlRecorder) Driver() driver.Driver                            { return nil }
+
+type recorderConn struct{ recorder *sqlRecorder }
+
+func (c recorderConn) Prepare(query string) (driver.Stmt, error) {
+	return recorderStmt{c.recorder, query}, nil
+}
+func (c recorderConn) Close() error              { return nil }
+func (c recorderConn) Begin() (driver.Tx, error) { return recorderTx{}, nil }
+
+func (c recorderConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.recorder.record(query, args)
+	return driver.RowsAffected(0), nil
+}
+
+func (c recorderConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.recorder.record(query, args)
+	return emptyRows{}, nil
+}
+
+type recorderStmt struct {
+	recorder *sqlRecorder
+	query    string
+}
+
+func (s recorderStmt) Close() error  { return nil }
+func (s recorderStmt) NumInput() int { return -1 }
+
+func (s recorderStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return recorderConn{s.recorder}.ExecContext(context.Background(), s.query, namedValues(args))
+}
+
+func (s recorderStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return recorderConn{s.recorder}.QueryContext(context.Background(), s.query, namedValues(args))
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+type recorderTx struct{}
+
+func (recorderTx) Commit() error   { return nil }
+func (recorderTx) Rollback() error { return nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return nil }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
@@ -0,0 +1,68 @@
+package security
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang_dev_docker/server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRefreshRateLimitPerToken 測試刷新權杖的頻率限制依權杖計算，共用 IP 的其他會話不受影響，且請求主體仍可讀取
+func TestRefreshRateLimitPerToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/refresh", middleware.CreateRefreshRateLimiter().Handler(), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+
+	refresh := func(token string) *httptest.ResponseRecorder {
+		body := `{"refresh_token":"` + token + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/refresh", strings.NewReader(body))
+		req.RemoteAddr = "198.51.100.7:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 10; i++ {
+		w := refresh("token-a")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"refresh_token":"token-a"}`, w.Body.String(), "處理函式可讀取完整的請求主體")
+	}
+	assert.Equal(t, http.StatusTooManyRequests, refresh("token-a").Code)
+	assert.Equal(t, http.StatusOK, refresh("token-b").Code, "同一 IP 的其他會話不受影響")
+}
+
+// TestSensitiveActionRateLimitsAreSeparate 測試各項敏感操作使用獨立的限制器，並依登入用戶計算
+func TestSensitiveActionRateLimitsAreSeparate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	asUser := func(c *gin.Context) {
+		c.Set("user_id", uint(len(c.GetHeader("X-User"))))
+	}
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.PUT("/password", asUser, middleware.CreateSensitiveActionRateLimiter("password_change").Handler(), ok)
+	router.DELETE("/account", asUser, middleware.CreateSensitiveActionRateLimiter("account_deletion").Handler(), ok)
+
+	call := func(method, path, user string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("X-User", user)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, http.StatusOK, call(http.MethodPut, "/password", "a"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, call(http.MethodPut, "/password", "a"))
+	assert.Equal(t, http.StatusOK, call(http.MethodDelete, "/account", "a"), "其他操作不共用額度")
+	assert.Equal(t, http.StatusOK, call(http.MethodPut, "/password", "bb"), "其他用戶不受影響")
+}
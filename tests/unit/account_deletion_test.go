@@ -0,0 +1,261 @@
+package unit_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/infrastructure/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var deletionTokenPattern = regexp.MustCompile(`確認權杖為：([0-9a-f]{64})`)
+
+// memoryErasureRepository 測試用資料清除儲存庫，將用戶匿名化並返回預設的檔案與配對
+type memoryErasureRepository struct {
+	users    *memoryUserRepository
+	files    map[uint][]string
+	matches  map[uint][]uint
+	erasedID []uint
+	failIDs  map[uint]bool
+}
+
+func (r *memoryErasureRepository) EraseUser(ctx context.Context, userID uint, erasedAt time.Time) (*repository.ErasureResult, error) {
+	if r.failIDs[userID] {
+		return nil, errors.New("資料庫錯誤")
+	}
+	user, err := r.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Email = ""
+	user.PasswordHash = ""
+	user.DeletionScheduledAt = nil
+	user.ErasedAt = &erasedAt
+	r.erasedID = append(r.erasedID, userID)
+	return &repository.ErasureResult{FilePaths: r.files[userID], MatchIDs: r.matches[userID]}, nil
+}
+
+// recordingFileRemover 測試用檔案刪除器，記錄刪除的路徑
+type recordingFileRemover struct {
+	removed []string
+}
+
+func (r *recordingFileRemover) RemoveFile(path string) error {
+	r.removed = append(r.removed, path)
+	return nil
+}
+
+// recordingUserCache 測試用快取，記錄清除的用戶與聊天
+type recordingUserCache struct {
+	users []uint
+	chats []uint
+}
+
+func (c *recordingUserCache) ClearUserCache(userID uint) error {
+	c.users = append(c.users, userID)
+	return nil
+}
+
+func (c *recordingUserCache) ClearChatCache(chatID uint) error {
+	c.chats = append(c.chats, chatID)
+	return nil
+}
+
+// setupAccountDeletion 建立測試用帳號刪除服務與一位設有密碼的用戶
+func setupAccountDeletion(t *testing.T) (*usecase.AccountDeletionService, *usecase.AuthService, *memoryErasureRepository, *entity.User) {
+	repo := newMemoryUserRepository()
+	user := createUserWithRole(t, repo, "carol@example.com", entity.RoleUser)
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user.PasswordHash = string(hash)
+
+	authService, _, _ := newTestAuthService()
+	erasure := &memoryErasureRepository{users: repo, files: map[uint][]string{}, matches: map[uint][]uint{}}
+	service := usecase.NewAccountDeletionService(repo, erasure, authService, 7*24*time.Hour)
+	return service, authService, erasure, user
+}
+
+// TestAccountDeletionGracePeriod 測試申請刪除後帳號停用、登出，且寬限期內可取消
+func TestAccountDeletionGracePeriod(t *testing.T) {
+	ctx := context.Background()
+	service, authService, _, user := setupAccountDeletion(t)
+
+	tokens, err := authService.StartSession(ctx, user.ID, user.Email, "user", usecase.ClientInfo{})
+	require.NoError(t, err)
+
+	_, err = service.RequestDeletion(ctx, user.ID, "wrong-password")
+	assert.ErrorIs(t, err, usecase.ErrIncorrectPassword)
+	assert.True(t, user.IsActive)
+
+	scheduledAt, err := service.RequestDeletion(ctx, user.ID, "correct-password")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), scheduledAt, time.Minute)
+	assert.False(t, user.IsActive, "寬限期間帳號停用，不出現在配對中")
+	assert.True(t, user.IsPendingDeletion())
+
+	revoked, _ := authService.IsTokenRevoked(tokens.TokenID, tokens.SessionID)
+	assert.True(t, revoked, "申請刪除後所有裝置都應登出")
+
+	_, err = service.RequestDeletion(ctx, user.ID, "correct-password")
+	assert.ErrorIs(t, err, usecase.ErrAccountPendingDeletion)
+
+	assert.ErrorIs(t, service.CancelDeletion(ctx, user.Email, "wrong-password"), usecase.ErrInvalidCredentials)
+	require.NoError(t, service.CancelDeletion(ctx, "Carol@Example.com", "correct-password"))
+	assert.True(t, user.IsActive)
+	assert.False(t, user.IsPendingDeletion())
+
+	assert.ErrorIs(t, service.CancelDeletion(ctx, user.Email, "correct-password"), usecase.ErrDeletionNotScheduled)
+}
+
+// TestEraseDueAccounts 測試寬限期後清除資料、檔案與快取
+func TestEraseDueAccounts(t *testing.T) {
+	ctx := context.Background()
+	service, _, erasure, user := setupAccountDeletion(t)
+
+	files := &recordingFileRemover{}
+	cache := &recordingUserCache{}
+	service.SetFileRemover(files)
+	service.SetUserDataCache(cache)
+	erasure.files[user.ID] = []string{"/uploads/photos/a.jpg", "/uploads/documents/id.jpg"}
+	erasure.matches[user.ID] = []uint{7, 9}
+
+	_, err := service.RequestDeletion(ctx, user.ID, "correct-password")
+	require.NoError(t, err)
+
+	count, err := service.EraseDueAccounts(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, count, "寬限期內不應清除")
+
+	count, err = service.EraseDueAccounts(ctx, time.Now().Add(8*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []uint{user.ID}, erasure.erasedID)
+	assert.True(t, user.IsErased())
+	assert.ElementsMatch(t, []string{"/uploads/photos/a.jpg", "/uploads/documents/id.jpg"}, files.removed)
+	assert.Equal(t, []uint{user.ID}, cache.users)
+	assert.ElementsMatch(t, []uint{7, 9}, cache.chats)
+
+	count, err = service.EraseDueAccounts(ctx, time.Now().Add(30*24*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, count, "已清除的帳號不應重複處理")
+}
+
+// TestEraseDueAccountsSkipsFailures 測試單一帳號清除失敗時略過並繼續清除其他帳號，下次排程重試
+func TestEraseDueAccountsSkipsFailures(t *testing.T) {
+	ctx := context.Background()
+	service, _, erasure, user := setupAccountDeletion(t)
+	hash, err := bcrypt.GenerateFromPassword([]byte("other-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+	other := createUserWithRole(t, erasure.users, "dave@example.com", entity.RoleUser)
+	other.PasswordHash = string(hash)
+
+	_, err = service.RequestDeletion(ctx, user.ID, "correct-password")
+	require.NoError(t, err)
+	_, err = service.RequestDeletion(ctx, other.ID, "other-password")
+	require.NoError(t, err)
+
+	erasure.failIDs = map[uint]bool{user.ID: true}
+	count, err := service.EraseDueAccounts(ctx, time.Now().Add(8*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []uint{other.ID}, erasure.erasedID)
+	assert.False(t, user.IsErased())
+
+	erasure.failIDs = nil
+	count, err = service.EraseDueAccounts(ctx, time.Now().Add(8*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "失敗的帳號於下次排程重試")
+	assert.True(t, user.IsErased())
+}
+
+// TestFileRemoverStaysInsideUploadDir 測試帳號清除使用的檔案刪除不會刪除上傳目錄以外的檔案
+func TestFileRemoverStaysInsideUploadDir(t *testing.T) {
+	base := t.TempDir()
+	uploads := filepath.Join(base, "uploads")
+	require.NoError(t, os.MkdirAll(filepath.Join(uploads, "photos"), 0o755))
+
+	photo := filepath.Join(uploads, "photos", "a.jpg")
+	legacy := filepath.Join(uploads, "photos", "b.jpg")
+	outside := filepath.Join(base, "secret.txt")
+	require.NoError(t, os.WriteFile(photo, []byte("photo"), 0o644))
+	require.NoError(t, os.WriteFile(legacy, []byte("photo"), 0o644))
+	require.NoError(t, os.WriteFile(outside, []byte("secret"), 0o644))
+
+	remover := usecase.NewFileService(storage.NewLocalBlobStore(uploads), "secret", "http://localhost:8080", 0)
+
+	require.NoError(t, remover.RemoveFile("photos/a.jpg"))
+	assert.NoFileExists(t, photo)
+	require.NoError(t, remover.RemoveFile("/uploads/photos/b.jpg"), "舊版路徑去除前綴後刪除")
+	assert.NoFileExists(t, legacy)
+
+	assert.NoError(t, remover.RemoveFile("photos/missing.jpg"), "不存在的檔案視為已刪除")
+	assert.NoError(t, remover.RemoveFile("https://cdn.example.com/a.jpg"), "外部網址略過")
+
+	assert.Error(t, remover.RemoveFile("/uploads/../secret.txt"))
+	assert.Error(t, remover.RemoveFile("../secret.txt"))
+	assert.Error(t, remover.RemoveFile(outside))
+	assert.FileExists(t, outside)
+}
+
+// TestPasswordlessAccountDeletion 測試未設定密碼的帳號以 Email 確認信刪除與取消刪除，權杖只能使用一次
+func TestPasswordlessAccountDeletion(t *testing.T) {
+	ctx := context.Background()
+	service, authService, erasure, _ := setupAccountDeletion(t)
+	user := createUserWithRole(t, erasure.users, "oidc@example.com", entity.RoleUser)
+	user.PasswordHash = ""
+
+	_, err := service.RequestDeletion(ctx, user.ID, "")
+	assert.ErrorIs(t, err, usecase.ErrDeletionConfirmationRequired)
+	assert.ErrorIs(t, service.SendDeletionConfirmation(ctx, user.ID), usecase.ErrDeletionConfirmationUnavailable)
+
+	mailer := &recordingMailer{}
+	service.SetConfirmationMailer(mailer, newMemoryCodeStore())
+
+	tokens, err := authService.StartSession(ctx, user.ID, user.Email, "user", usecase.ClientInfo{})
+	require.NoError(t, err)
+
+	require.NoError(t, service.SendDeletionConfirmation(ctx, user.ID))
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, user.Email, mailer.last().To)
+	token := deletionTokenPattern.FindStringSubmatch(mailer.last().Body)[1]
+	assert.True(t, user.IsActive, "確認前不停用帳號")
+
+	assert.ErrorIs(t, service.ConfirmCancellation(ctx, token), usecase.ErrInvalidDeletionToken, "刪除權杖不可用於取消刪除")
+
+	scheduledAt, err := service.ConfirmDeletion(ctx, token)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), scheduledAt, time.Minute)
+	assert.True(t, user.IsPendingDeletion())
+	revoked, _ := authService.IsTokenRevoked(tokens.TokenID, tokens.SessionID)
+	assert.True(t, revoked)
+
+	_, err = service.ConfirmDeletion(ctx, token)
+	assert.ErrorIs(t, err, usecase.ErrInvalidDeletionToken, "權杖只能使用一次")
+
+	// 取消刪除以 Email 識別帳號，不存在的帳號靜默成功
+	require.NoError(t, service.SendCancellationConfirmation(ctx, "nobody@example.com"))
+	require.Len(t, mailer.sent, 1)
+	require.NoError(t, service.SendCancellationConfirmation(ctx, "OIDC@example.com"))
+	require.Len(t, mailer.sent, 2)
+	assert.ErrorIs(t, service.CancelDeletion(ctx, user.Email, ""), usecase.ErrInvalidCredentials)
+
+	require.NoError(t, service.ConfirmCancellation(ctx, deletionTokenPattern.FindStringSubmatch(mailer.last().Body)[1]))
+	assert.True(t, user.IsActive)
+	assert.False(t, user.IsPendingDeletion())
+
+	// 已設定密碼的帳號仍以密碼確認
+	passwordUser := createUserWithRole(t, erasure.users, "pw@example.com", entity.RoleUser)
+	passwordUser.PasswordHash = "hash"
+	assert.ErrorIs(t, service.SendDeletionConfirmation(ctx, passwordUser.ID), usecase.ErrDeletionPasswordRequired)
+}
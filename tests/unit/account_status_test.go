@@ -0,0 +1,140 @@
+package unit_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAccountModeration 建立測試用的帳號停權服務
+func setupAccountModeration() (*usecase.AccountModerationService, *usecase.AuthService, *memoryUserRepository, *memoryModerationRepository) {
+	repo := newMemoryUserRepository()
+	logs := &memoryModerationRepository{}
+	authService, _, _ := newTestAuthService()
+	return usecase.NewAccountModerationService(repo, logs, authService), authService, repo, logs
+}
+
+// TestSuspensionExpiresAndIsReinstated 測試暫停期限到後由排程自動恢復並寫入日誌
+func TestSuspensionExpiresAndIsReinstated(t *testing.T) {
+	ctx := context.Background()
+	service, authService, repo, logs := setupAccountModeration()
+
+	moderator := createUserWithRole(t, repo, "mod@example.com", entity.RoleModerator)
+	member := createUserWithRole(t, repo, "member@example.com", entity.RoleUser)
+
+	session, err := authService.StartSession(ctx, member.ID, member.Email, "user", usecase.ClientInfo{})
+	require.NoError(t, err)
+
+	require.NoError(t, service.SuspendUser(ctx, member.ID, &moderator.ID, 3, "騷擾其他用戶"))
+
+	status, until, err := service.GetAccountStatus(ctx, member.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.AccountStatusSuspended, status)
+	require.NotNil(t, until)
+
+	revoked, _ := authService.IsTokenRevoked("", session.SessionID)
+	assert.True(t, revoked, "暫停後既有會話應撤銷")
+
+	// 期限未到不應恢復
+	count, err := service.ReinstateExpiredSuspensions(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	count, err = service.ReinstateExpiredSuspensions(ctx, until.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	stored, _ := repo.GetByID(ctx, member.ID)
+	assert.Equal(t, entity.AccountStatusActive, stored.AccountStatus)
+	assert.Nil(t, stored.SuspendedUntil)
+
+	last := logs.logs[len(logs.logs)-1]
+	assert.Equal(t, "reinstated", last.Action)
+	assert.True(t, last.IsAutomatic)
+	assert.Nil(t, last.ModeratorID)
+}
+
+// TestAccountModerationHierarchy 測試停權操作的權限與參數檢查
+func TestAccountModerationHierarchy(t *testing.T) {
+	ctx := context.Background()
+	service, _, repo, _ := setupAccountModeration()
+
+	admin := createUserWithRole(t, repo, "admin@example.com", entity.RoleAdmin)
+	moderator := createUserWithRole(t, repo, "mod@example.com", entity.RoleModerator)
+	member := createUserWithRole(t, repo, "member@example.com", entity.RoleUser)
+
+	assert.ErrorIs(t, service.BanUser(ctx, member.ID, &moderator.ID, "垃圾訊息"), usecase.ErrPermissionDenied, "審核員不能永久封禁")
+	assert.ErrorIs(t, service.SuspendUser(ctx, admin.ID, &moderator.ID, 1, "測試"), usecase.ErrPermissionDenied, "不能處分等級較高的用戶")
+	assert.ErrorIs(t, service.SuspendUser(ctx, member.ID, &member.ID, 1, "測試"), usecase.ErrPermissionDenied)
+	assert.ErrorIs(t, service.SuspendUser(ctx, member.ID, &moderator.ID, 1, "  "), usecase.ErrModerationReason)
+	assert.ErrorIs(t, service.SuspendUser(ctx, member.ID, &moderator.ID, 0, "測試"), usecase.ErrInvalidSuspension)
+
+	require.NoError(t, service.BanUser(ctx, member.ID, &admin.ID, "詐騙"))
+	assert.ErrorIs(t, service.SuspendUser(ctx, member.ID, &moderator.ID, 1, "測試"), usecase.ErrAccountBanned, "暫停不能覆蓋封禁")
+	assert.ErrorIs(t, service.ReinstateUser(ctx, member.ID, &moderator.ID, "申訴成功"), usecase.ErrPermissionDenied, "解除封禁需要封禁權限")
+
+	require.NoError(t, service.ReinstateUser(ctx, member.ID, &admin.ID, "申訴成功"))
+	assert.ErrorIs(t, service.ReinstateUser(ctx, member.ID, &admin.ID, "重複恢復"), usecase.ErrAccountAlreadyOpen)
+}
+
+// TestUserStatusMiddleware 測試停權帳號持有有效 token 也會被拒絕
+func TestUserStatusMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	service, _, repo, _ := setupAccountModeration()
+
+	admin := createUserWithRole(t, repo, "admin@example.com", entity.RoleAdmin)
+	suspended := createUserWithRole(t, repo, "suspended@example.com", entity.RoleUser)
+	banned := createUserWithRole(t, repo, "banned@example.com", entity.RoleUser)
+	member := createUserWithRole(t, repo, "member@example.com", entity.RoleUser)
+
+	require.NoError(t, service.SuspendUser(ctx, suspended.ID, &admin.ID, 7, "不當內容"))
+	require.NoError(t, service.BanUser(ctx, banned.ID, &admin.ID, "詐騙"))
+
+	jwtAuth := middleware.NewJWTAuthMiddleware(newTestKeySet())
+	jwtAuth.SetAccountStatusChecker(service)
+
+	router := gin.New()
+	router.GET("/profile",
+		jwtAuth.AuthMiddleware(),
+		jwtAuth.UserStatusMiddleware(),
+		func(c *gin.Context) { c.Status(http.StatusOK) },
+	)
+
+	request := func(user *entity.User) (int, map[string]interface{}) {
+		token, _, err := jwtAuth.IssueAccessToken(user.ID, user.Email, "user", "", time.Minute)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/profile", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var body map[string]interface{}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body
+	}
+
+	code, _ := request(member)
+	assert.Equal(t, http.StatusOK, code)
+
+	code, body := request(suspended)
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, "ACCOUNT_SUSPENDED", body["code"])
+	assert.NotEmpty(t, body["suspended_until"])
+
+	code, body = request(banned)
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, "ACCOUNT_BANNED", body["code"])
+}
@@ -0,0 +1,220 @@
+package unit_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/infrastructure/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryChatRepository 測試用聊天儲存庫，只保存訊息
+type memoryChatRepository struct {
+	repository.ChatRepository
+	messages []*entity.ChatMessage
+	failNext bool
+}
+
+func (r *memoryChatRepository) CreateMessage(ctx context.Context, message *entity.ChatMessage) error {
+	if r.failNext {
+		r.failNext = false
+		return errors.New("資料庫錯誤")
+	}
+	message.ID = uint(len(r.messages) + 1)
+	message.CreatedAt = time.Now()
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+func (r *memoryChatRepository) GetMessagesByMatch(ctx context.Context, matchID uint, limit int, before *time.Time) ([]*entity.ChatMessage, error) {
+	var messages []*entity.ChatMessage
+	for _, message := range r.messages {
+		if message.MatchID == matchID {
+			copied := *message
+			messages = append(messages, &copied)
+		}
+	}
+	return messages, nil
+}
+
+// discardChatListRepository 測試用聊天列表儲存庫，不記錄活躍狀態
+type discardChatListRepository struct {
+	repository.ChatListRepository
+}
+
+func (r *discardChatListRepository) UpdateChatActivity(ctx context.Context, matchID uint, lastMessageTime time.Time) error {
+	return nil
+}
+
+// memoryAgeVerificationRepository 測試用年齡驗證儲存庫，每位用戶一筆記錄
+type memoryAgeVerificationRepository struct {
+	repository.AgeVerificationRepository
+	verifications map[uint]*entity.AgeVerification
+}
+
+func (r *memoryAgeVerificationRepository) Create(ctx context.Context, verification *entity.AgeVerification) error {
+	verification.ID = uint(len(r.verifications) + 1)
+	copied := *verification
+	r.verifications[verification.UserID] = &copied
+	return nil
+}
+
+func (r *memoryAgeVerificationRepository) GetByUserID(ctx context.Context, userID uint) (*entity.AgeVerification, error) {
+	verification, ok := r.verifications[userID]
+	if !ok {
+		return nil, errors.New("年齡驗證記錄不存在")
+	}
+	copied := *verification
+	return &copied, nil
+}
+
+func (r *memoryAgeVerificationRepository) Update(ctx context.Context, verification *entity.AgeVerification) error {
+	copied := *verification
+	r.verifications[verification.UserID] = &copied
+	return nil
+}
+
+// TestChatAttachments 測試聊天附件寫入伺服器產生的路徑，不接受客戶端指定檔案路徑，下載連結只簽發給配對雙方
+func TestChatAttachments(t *testing.T) {
+	ctx := context.Background()
+	users := newMemoryUserRepository()
+	matches := &memoryMatchRepository{}
+	messages := &memoryChatRepository{}
+	notifier := &recordingNotifier{sent: make(map[uint][]interface{})}
+	root := t.TempDir()
+	blobs := storage.NewLocalBlobStore(root)
+
+	chat := usecase.NewChatService(messages, &discardChatListRepository{}, nil, matches, users)
+	files := usecase.NewFileService(blobs, "test-secret", "http://localhost:8080", 0)
+	chat.SetFileService(files)
+	chat.SetWebSocketNotifier(notifier)
+	files.AddAccessChecker(chat)
+
+	sender := createUserWithRole(t, users, "sender@example.com", entity.RoleUser)
+	receiver := createUserWithRole(t, users, "receiver@example.com", entity.RoleUser)
+	stranger := createUserWithRole(t, users, "stranger@example.com", entity.RoleUser)
+	moderator := createUserWithRole(t, users, "mod@example.com", entity.RoleModerator)
+	require.NoError(t, matches.CreateSwipe(ctx, &entity.Match{User1ID: sender.ID, User2ID: receiver.ID, Status: entity.MatchStatusMatched}))
+	require.NoError(t, matches.CreateSwipe(ctx, &entity.Match{User1ID: sender.ID, User2ID: stranger.ID, Status: entity.MatchStatusPending}))
+
+	// 文字訊息不可夾帶檔案，圖片與檔案類型只能經由附件上傳
+	response, err := chat.SendMessage(ctx, &usecase.SendMessageRequest{
+		SenderID: sender.ID, ReceiverID: receiver.ID, MatchID: 1, Type: entity.MessageTypeImage, Content: "看這張",
+	})
+	require.NoError(t, err)
+	assert.False(t, response.Success)
+	assert.Empty(t, messages.messages)
+
+	png := encodePNGBytes(t, wavePattern(40, 30, false))
+	message, err := chat.SendAttachment(ctx, &usecase.SendAttachmentRequest{
+		SenderID: sender.ID, MatchID: 1, FileName: "../../photos/2/avatar.png", Data: png,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entity.MessageTypeImage, message.Type)
+	assert.Equal(t, receiver.ID, message.ReceiverID)
+	assert.Equal(t, "avatar.png", *message.FileName, "顯示檔名去除路徑")
+	assert.True(t, strings.HasPrefix(*message.FilePath, "chat/1/"), "附件存放於配對的路徑下")
+	assert.True(t, strings.HasSuffix(*message.FilePath, ".png"))
+	require.NotNil(t, message.FileURL)
+	assert.NoError(t, openPhotoLink(t, files, *message.FileURL), "發送者可下載")
+
+	// 通知接收者的訊息附上綁定接收者的連結
+	require.Len(t, notifier.sent[receiver.ID], 1)
+	delivered := notifier.sent[receiver.ID][0].(map[string]interface{})["message"].(*entity.ChatMessage)
+	assert.Contains(t, *delivered.FileURL, "viewer=2")
+	assert.NoError(t, openPhotoLink(t, files, *delivered.FileURL))
+
+	pdf := append([]byte("%PDF-1.4\n"), make([]byte, 64)...)
+	document, err := chat.SendAttachment(ctx, &usecase.SendAttachmentRequest{SenderID: receiver.ID, MatchID: 1, Caption: "菜單", Data: pdf})
+	require.NoError(t, err)
+	assert.Equal(t, entity.MessageTypeFile, document.Type)
+	assert.Equal(t, "菜單", document.Content)
+
+	_, err = chat.SendAttachment(ctx, &usecase.SendAttachmentRequest{SenderID: sender.ID, MatchID: 1, Data: []byte("#!/bin/sh\nrm -rf /\n")})
+	assert.ErrorIs(t, err, usecase.ErrUnsupportedAttachmentType)
+	_, err = chat.SendAttachment(ctx, &usecase.SendAttachmentRequest{SenderID: stranger.ID, MatchID: 1, Data: png})
+	assert.ErrorIs(t, err, usecase.ErrChatForbidden)
+	_, err = chat.SendAttachment(ctx, &usecase.SendAttachmentRequest{SenderID: sender.ID, MatchID: 2, Data: png})
+	assert.ErrorIs(t, err, usecase.ErrChatNotMatched)
+	_, err = chat.SendAttachment(ctx, &usecase.SendAttachmentRequest{SenderID: sender.ID, MatchID: 1, Data: make([]byte, usecase.DefaultMaxAttachmentSize+1)})
+	assert.ErrorIs(t, err, usecase.ErrAttachmentTooLarge)
+
+	// 保存訊息失敗時刪除已寫入的附件
+	messages.failNext = true
+	_, err = chat.SendAttachment(ctx, &usecase.SendAttachmentRequest{SenderID: sender.ID, MatchID: 1, Data: png})
+	assert.Error(t, err)
+	assert.Len(t, messages.messages, 2)
+	stored, err := os.ReadDir(filepath.Join(root, "chat", "1"))
+	require.NoError(t, err)
+	assert.Len(t, stored, 2)
+
+	// 配對以外的用戶即使取得連結也無法下載，審核人員可以下載以處理檢舉
+	strangerLink := files.SignedURLFor(ctx, *message.FilePath, stranger.ID)
+	assert.ErrorIs(t, openPhotoLink(t, files, strangerLink), usecase.ErrFileAccessDenied)
+	assert.NoError(t, openPhotoLink(t, files, files.SignedURLFor(ctx, *message.FilePath, moderator.ID)))
+	assert.Empty(t, files.SignedURL(ctx, *message.FilePath), "聊天附件不簽發未綁定查看者的連結")
+
+	// 舊資料中指向其他路徑的檔案訊息不簽發連結
+	legacy := "photos/2/pending.png"
+	messages.messages = append(messages.messages, &entity.ChatMessage{
+		ID: 99, MatchID: 1, SenderID: sender.ID, ReceiverID: receiver.ID, Type: entity.MessageTypeImage, FilePath: &legacy,
+	})
+	history, err := chat.GetChatHistory(ctx, &usecase.ChatHistoryRequest{UserID: receiver.ID, MatchID: 1})
+	require.NoError(t, err)
+	require.Len(t, history.Messages, 3)
+	assert.Contains(t, *history.Messages[0].FileURL, "viewer=2")
+	assert.Nil(t, history.Messages[2].FileURL)
+}
+
+// TestAgeVerificationDocuments 測試年齡驗證文件寫入伺服器產生的路徑，只有本人與審核人員可以下載，重新提交時刪除舊文件
+func TestAgeVerificationDocuments(t *testing.T) {
+	ctx := context.Background()
+	users := newMemoryUserRepository()
+	verifications := &memoryAgeVerificationRepository{verifications: make(map[uint]*entity.AgeVerification)}
+	blobs := storage.NewLocalBlobStore(t.TempDir())
+
+	service := usecase.NewUserService(users, nil, nil, nil, verifications)
+	_, err := service.SubmitAgeVerification(ctx, 1, entity.VerificationMethodPassport, "A123", []byte("data"))
+	assert.ErrorIs(t, err, usecase.ErrVerificationUploadUnavailable)
+
+	files := usecase.NewFileService(blobs, "test-secret", "http://localhost:8080", 0)
+	service.SetPhotoProcessing(nil, files, 0)
+	files.AddAccessChecker(service)
+
+	owner := createUserWithRole(t, users, "owner@example.com", entity.RoleUser)
+	other := createUserWithRole(t, users, "other@example.com", entity.RoleUser)
+	moderator := createUserWithRole(t, users, "mod@example.com", entity.RoleModerator)
+
+	png := encodePNGBytes(t, wavePattern(40, 30, false))
+	_, err = service.SubmitAgeVerification(ctx, owner.ID, entity.VerificationMethodPassport, "A123", []byte("not a document"))
+	assert.ErrorIs(t, err, usecase.ErrUnsupportedVerificationType)
+	_, err = service.SubmitAgeVerification(ctx, owner.ID, "fingerprint", "A123", png)
+	assert.ErrorIs(t, err, usecase.ErrInvalidVerification)
+
+	first, err := service.SubmitAgeVerification(ctx, owner.ID, entity.VerificationMethodPassport, " A123 ", png)
+	require.NoError(t, err)
+	assert.Equal(t, "A123", first.DocumentNumber)
+	assert.Equal(t, entity.VerificationStatusPending, first.Status)
+	assert.True(t, strings.HasPrefix(first.DocumentImagePath, "verifications/1/"))
+
+	assert.NoError(t, openPhotoLink(t, files, files.SignedURLFor(ctx, first.DocumentImagePath, owner.ID)))
+	assert.NoError(t, openPhotoLink(t, files, files.SignedURLFor(ctx, first.DocumentImagePath, moderator.ID)))
+	assert.ErrorIs(t, openPhotoLink(t, files, files.SignedURLFor(ctx, first.DocumentImagePath, other.ID)), usecase.ErrFileAccessDenied)
+
+	second, err := service.SubmitAgeVerification(ctx, owner.ID, entity.VerificationMethodID, "B456", png)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID, "重新提交更新原記錄")
+	assert.NotEqual(t, first.DocumentImagePath, second.DocumentImagePath)
+	_, err = blobs.Open(ctx, first.DocumentImagePath)
+	assert.ErrorIs(t, err, usecase.ErrFileNotFound, "舊文件已刪除")
+}
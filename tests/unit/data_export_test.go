@@ -0,0 +1,383 @@
+package unit_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+	"golang_dev_docker/domain/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryDataExportRepository 測試用匯出記錄儲存庫
+type memoryDataExportRepository struct {
+	exports map[uint]*entity.DataExport
+	nextID  uint
+}
+
+func newMemoryDataExportRepository() *memoryDataExportRepository {
+	return &memoryDataExportRepository{exports: map[uint]*entity.DataExport{}, nextID: 1}
+}
+
+func (r *memoryDataExportRepository) Create(ctx context.Context, export *entity.DataExport) error {
+	export.ID = r.nextID
+	export.CreatedAt = time.Now()
+	r.nextID++
+	r.exports[export.ID] = export
+	return nil
+}
+
+func (r *memoryDataExportRepository) GetByID(ctx context.Context, id uint) (*entity.DataExport, error) {
+	return r.exports[id], nil
+}
+
+func (r *memoryDataExportRepository) GetLatestByUserID(ctx context.Context, userID uint) (*entity.DataExport, error) {
+	var latest *entity.DataExport
+	for _, export := range r.exports {
+		if export.UserID == userID && (latest == nil || export.ID > latest.ID) {
+			latest = export
+		}
+	}
+	return latest, nil
+}
+
+func (r *memoryDataExportRepository) GetPending(ctx context.Context, staleBefore time.Time, limit int) ([]*entity.DataExport, error) {
+	var exports []*entity.DataExport
+	for _, export := range r.filter(limit, func(export *entity.DataExport) bool {
+		return claimableExport(export, staleBefore)
+	}) {
+		copied := *export
+		exports = append(exports, &copied)
+	}
+	return exports, nil
+}
+
+func (r *memoryDataExportRepository) ClaimPending(ctx context.Context, id uint, now, staleBefore time.Time) (bool, error) {
+	export, exists := r.exports[id]
+	if !exists || !claimableExport(export, staleBefore) {
+		return false, nil
+	}
+	export.Status = entity.DataExportStatusProcessing
+	export.ProcessingStartedAt = &now
+	export.Attempts++
+	return true, nil
+}
+
+// claimableExport 等待中或處理中斷的匯出
+func claimableExport(export *entity.DataExport, staleBefore time.Time) bool {
+	if export.Status == entity.DataExportStatusPending {
+		return true
+	}
+	return export.Status == entity.DataExportStatusProcessing &&
+		(export.ProcessingStartedAt == nil || !export.ProcessingStartedAt.After(staleBefore))
+}
+
+func (r *memoryDataExportRepository) Update(ctx context.Context, export *entity.DataExport) error {
+	*r.exports[export.ID] = *export
+	return nil
+}
+
+func (r *memoryDataExportRepository) GetExpired(ctx context.Context, now time.Time, limit int) ([]*entity.DataExport, error) {
+	return r.filter(limit, func(export *entity.DataExport) bool {
+		return export.Status == entity.DataExportStatusCompleted && !export.ExpiresAt.After(now)
+	}), nil
+}
+
+func (r *memoryDataExportRepository) filter(limit int, match func(*entity.DataExport) bool) []*entity.DataExport {
+	var result []*entity.DataExport
+	for _, export := range r.exports {
+		if match(export) {
+			result = append(result, export)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// staticPersonalDataRepository 測試用個人資料來源，返回預設資料
+type staticPersonalDataRepository struct {
+	data map[uint]*repository.PersonalData
+}
+
+func (r *staticPersonalDataRepository) CollectUserData(ctx context.Context, userID uint) (*repository.PersonalData, error) {
+	data, exists := r.data[userID]
+	if !exists {
+		return nil, fmt.Errorf("用戶 %d 不存在", userID)
+	}
+	return data, nil
+}
+
+// memoryArchiveStore 測試用封存檔儲存
+type memoryArchiveStore struct {
+	archives map[string][]byte
+}
+
+func (s *memoryArchiveStore) SaveArchive(name string, data []byte) error {
+	s.archives[name] = data
+	return nil
+}
+
+func (s *memoryArchiveStore) OpenArchive(name string) (io.ReadCloser, error) {
+	data, exists := s.archives[name]
+	if !exists {
+		return nil, fmt.Errorf("封存檔 %s 不存在", name)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryArchiveStore) RemoveArchive(name string) error {
+	delete(s.archives, name)
+	return nil
+}
+
+// setupDataExport 建立測試用匯出服務，用戶 1 有配對、訊息、封鎖與已審核的檢舉
+func setupDataExport(t *testing.T) (*usecase.DataExportService, *memoryDataExportRepository, *memoryArchiveStore) {
+	like := entity.SwipeActionLike
+	pass := entity.SwipeActionPass
+	notes := "內部審核備註"
+	rejection := "照片模糊"
+	reviewerID := uint(99)
+	matchedAt := time.Now().Add(-time.Hour)
+
+	data := &repository.PersonalData{
+		User:    &entity.User{ID: 1, Email: "dana@example.com", PasswordHash: "secret-hash", StatusActorID: &reviewerID},
+		Profile: &entity.UserProfile{UserID: 1, DisplayName: "Dana"},
+		Photos: []*entity.Photo{
+			{ID: 1, UserID: 1, FilePath: "/uploads/1.jpg", Status: entity.PhotoStatusApproved, ReviewerID: &reviewerID, ReviewNotes: &notes},
+			{ID: 2, UserID: 1, FilePath: "/uploads/2.jpg", Status: entity.PhotoStatusRejected, ReviewerID: &reviewerID, ReviewNotes: &rejection},
+		},
+		Matches: []*entity.Match{
+			// 用戶先滑動，對方回應 pass
+			{ID: 10, User1ID: 1, User2ID: 2, User1Action: like, User2Action: &pass, Status: entity.MatchStatusUnmatched},
+			// 雙向配對成功
+			{ID: 11, User1ID: 3, User2ID: 1, User1Action: like, User2Action: &like, Status: entity.MatchStatusMatched, MatchedAt: &matchedAt},
+			// 對方已滑動，用戶尚未回應
+			{ID: 12, User1ID: 4, User2ID: 1, User1Action: like, Status: entity.MatchStatusPending},
+		},
+		Messages: []*entity.ChatMessage{{ID: 1, MatchID: 11, SenderID: 1, ReceiverID: 3, Content: "hi"}},
+		Blocks:   []*entity.Block{{ID: 1, BlockerID: 1, BlockedID: 5}},
+		Reports:  []*entity.Report{{ID: 1, ReporterID: 1, ReportedID: 5, ReviewerID: &reviewerID, ReviewNotes: &notes}},
+	}
+
+	exportRepo := newMemoryDataExportRepository()
+	archives := &memoryArchiveStore{archives: map[string][]byte{}}
+	service := usecase.NewDataExportService(
+		exportRepo,
+		&staticPersonalDataRepository{data: map[uint]*repository.PersonalData{1: data}},
+		archives,
+		"test-secret",
+		"https://dating.example.com/",
+	)
+
+	authService, _, _ := newTestAuthService()
+	_, err := authService.StartSession(context.Background(), 1, "dana@example.com", "user", usecase.ClientInfo{IPAddress: "10.0.0.1", UserAgent: "phone"})
+	require.NoError(t, err)
+	service.SetSessionLister(authService)
+
+	return service, exportRepo, archives
+}
+
+// parseDownloadURL 從下載連結解析匯出 ID、期限與簽名
+func parseDownloadURL(t *testing.T, raw string) (uint, int64, string) {
+	parsed, err := url.Parse(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "dating.example.com", parsed.Host)
+
+	id, err := strconv.ParseUint(path.Base(path.Dir(parsed.Path)), 10, 32)
+	require.NoError(t, err)
+	expires, err := strconv.ParseInt(parsed.Query().Get("expires"), 10, 64)
+	require.NoError(t, err)
+	return uint(id), expires, parsed.Query().Get("signature")
+}
+
+// TestDataExportArchiveContents 測試匯出封存檔內容與對方資料的保護
+func TestDataExportArchiveContents(t *testing.T) {
+	ctx := context.Background()
+	service, _, _ := setupDataExport(t)
+
+	export, err := service.RequestExport(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, entity.DataExportStatusPending, export.Status)
+
+	info, err := service.GetLatestExport(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, info.DownloadURL, "處理完成前不應簽發下載連結")
+
+	processed, err := service.ProcessPendingExports(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	info, err = service.GetLatestExport(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, entity.DataExportStatusCompleted, info.Status)
+	require.NotEmpty(t, info.DownloadURL)
+
+	id, expires, signature := parseDownloadURL(t, info.DownloadURL)
+	_, reader, err := service.OpenDownload(ctx, id, expires, signature)
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+	files := map[string][]byte{}
+	for _, file := range archive.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		files[file.Name], err = io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+	}
+
+	for _, name := range []string{"user.json", "profile.json", "photos.json", "interests.json", "swipes.json", "messages.json", "blocks.json", "reports.json", "sessions.json"} {
+		assert.Contains(t, files, name)
+	}
+	assert.NotContains(t, string(files["user.json"]), "secret-hash", "不應匯出密碼雜湊")
+	assert.NotContains(t, string(files["user.json"]), "status_actor_id", "不應匯出執行停權的管理員")
+	assert.NotContains(t, string(files["reports.json"]), "內部審核備註", "不應匯出審核備註")
+	assert.NotContains(t, string(files["photos.json"]), "reviewer_id", "不應匯出照片審核員")
+	assert.NotContains(t, string(files["photos.json"]), "內部審核備註", "不應匯出審核備註")
+	assert.Contains(t, string(files["photos.json"]), "照片模糊", "已通知用戶的拒絕原因應保留")
+	assert.Contains(t, string(files["sessions.json"]), "phone")
+
+	var swipes []map[string]interface{}
+	require.NoError(t, json.Unmarshal(files["swipes.json"], &swipes))
+	require.Len(t, swipes, 2, "用戶尚未回應的滑動不屬於用戶的資料")
+	for _, swipe := range swipes {
+		assert.NotContains(t, swipe, "user1_action")
+		assert.NotContains(t, swipe, "user2_action")
+	}
+	assert.Equal(t, float64(2), swipes[0]["target_user_id"])
+	assert.Equal(t, "like", swipes[0]["action"])
+	assert.Equal(t, false, swipes[0]["matched"], "未配對時不揭露對方的動作")
+	assert.Equal(t, float64(3), swipes[1]["target_user_id"])
+	assert.Equal(t, true, swipes[1]["matched"])
+}
+
+// TestDataExportOncePerDay 測試每天只能申請一次匯出
+func TestDataExportOncePerDay(t *testing.T) {
+	ctx := context.Background()
+	service, exportRepo, _ := setupDataExport(t)
+
+	first, err := service.RequestExport(ctx, 1)
+	require.NoError(t, err)
+
+	_, err = service.RequestExport(ctx, 1)
+	assert.ErrorIs(t, err, usecase.ErrExportInProgress)
+
+	_, err = service.ProcessPendingExports(ctx, time.Now())
+	require.NoError(t, err)
+
+	_, err = service.RequestExport(ctx, 1)
+	assert.ErrorIs(t, err, usecase.ErrExportRateLimited)
+
+	// 失敗的匯出不計入次數
+	first.Status = entity.DataExportStatusFailed
+	_, err = service.RequestExport(ctx, 1)
+	require.NoError(t, err)
+
+	// 超過一天後可再次申請
+	for _, export := range exportRepo.exports {
+		export.Status = entity.DataExportStatusCompleted
+		export.CreatedAt = time.Now().Add(-25 * time.Hour)
+	}
+	_, err = service.RequestExport(ctx, 1)
+	assert.NoError(t, err)
+}
+
+// TestDataExportStaleProcessingRequeued 測試處理中斷的匯出逾時後重新處理，多次中斷後標記失敗，不會永久阻擋新申請
+func TestDataExportStaleProcessingRequeued(t *testing.T) {
+	ctx := context.Background()
+	service, exportRepo, _ := setupDataExport(t)
+
+	export, err := service.RequestExport(ctx, 1)
+	require.NoError(t, err)
+
+	// 模擬工作認領後程序中斷
+	now := time.Now()
+	claimed, err := exportRepo.ClaimPending(ctx, export.ID, now, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	count, err := service.ProcessPendingExports(ctx, now.Add(10*time.Minute))
+	require.NoError(t, err)
+	assert.Zero(t, count, "處理中的匯出未逾時前不重複處理")
+	_, err = service.RequestExport(ctx, 1)
+	assert.ErrorIs(t, err, usecase.ErrExportInProgress)
+
+	count, err = service.ProcessPendingExports(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "逾時後重新處理")
+	assert.Equal(t, entity.DataExportStatusCompleted, export.Status)
+	assert.Equal(t, 2, export.Attempts)
+
+	// 每次處理都中斷，超過次數上限後標記失敗，用戶可重新申請
+	stuck := &entity.DataExport{UserID: 1, Status: entity.DataExportStatusProcessing, Attempts: 3}
+	require.NoError(t, exportRepo.Create(ctx, stuck))
+	_, err = service.RequestExport(ctx, 1)
+	assert.ErrorIs(t, err, usecase.ErrExportInProgress)
+
+	count, err = service.ProcessPendingExports(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.Equal(t, entity.DataExportStatusFailed, stuck.Status)
+	assert.NotEmpty(t, stuck.Error)
+
+	_, err = service.RequestExport(ctx, 1)
+	assert.NoError(t, err)
+}
+
+// TestDataExportDownloadLinkValidation 測試下載連結簽名、期限與封存檔過期
+func TestDataExportDownloadLinkValidation(t *testing.T) {
+	ctx := context.Background()
+	service, exportRepo, archives := setupDataExport(t)
+
+	_, err := service.RequestExport(ctx, 1)
+	require.NoError(t, err)
+	_, err = service.ProcessPendingExports(ctx, time.Now())
+	require.NoError(t, err)
+
+	info, err := service.GetLatestExport(ctx, 1)
+	require.NoError(t, err)
+	id, expires, signature := parseDownloadURL(t, info.DownloadURL)
+	assert.LessOrEqual(t, expires, time.Now().Add(time.Hour).Unix(), "下載連結期限不應超過一小時")
+
+	_, _, err = service.OpenDownload(ctx, id, expires, signature+"00")
+	assert.ErrorIs(t, err, usecase.ErrInvalidDownloadLink, "竄改的簽名應被拒絕")
+
+	_, _, err = service.OpenDownload(ctx, id, expires+3600, signature)
+	assert.ErrorIs(t, err, usecase.ErrInvalidDownloadLink, "延長期限應使簽名失效")
+
+	_, _, err = service.OpenDownload(ctx, id+1, expires, signature)
+	assert.ErrorIs(t, err, usecase.ErrInvalidDownloadLink, "簽名不能用於其他匯出")
+
+	_, _, err = service.OpenDownload(ctx, id, time.Now().Add(-time.Minute).Unix(), signature)
+	assert.ErrorIs(t, err, usecase.ErrInvalidDownloadLink, "過期的連結應被拒絕")
+
+	// 封存檔保留期過後清除檔案，有效連結也無法下載
+	purged, err := service.PurgeExpiredExports(ctx, time.Now().Add(8*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Empty(t, archives.archives)
+	assert.Equal(t, entity.DataExportStatusExpired, exportRepo.exports[id].Status)
+
+	_, _, err = service.OpenDownload(ctx, id, expires, signature)
+	assert.ErrorIs(t, err, usecase.ErrExportNotReady)
+}
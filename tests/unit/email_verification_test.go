@@ -0,0 +1,102 @@
+package unit_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var verificationCodePattern = regexp.MustCompile(`驗證碼為：(\d{6})`)
+
+// setupEmailVerification 建立測試用的電子郵件驗證服務與未驗證用戶
+func setupEmailVerification(t *testing.T) (*usecase.EmailVerificationService, *memoryUserRepository, *memoryCodeStore, *recordingMailer, *entity.User) {
+	repo := newMemoryUserRepository()
+	codes := newMemoryCodeStore()
+	mailer := &recordingMailer{}
+
+	user := &entity.User{
+		Email:     "alice@example.com",
+		BirthDate: time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC),
+		IsActive:  true,
+	}
+	require.NoError(t, repo.Create(context.Background(), user))
+
+	service := usecase.NewEmailVerificationService(repo, mailer, codes, "https://example.com")
+	return service, repo, codes, mailer, user
+}
+
+// TestEmailVerificationCodeNotStoredInPlaintext 測試驗證碼只以雜湊形式儲存
+func TestEmailVerificationCodeNotStoredInPlaintext(t *testing.T) {
+	ctx := context.Background()
+	service, _, codes, mailer, user := setupEmailVerification(t)
+
+	require.NoError(t, service.SendVerification(ctx, user.ID))
+	match := verificationCodePattern.FindStringSubmatch(mailer.last().Body)
+	require.Len(t, match, 2, "驗證信應包含 6 位數驗證碼")
+
+	for _, stored := range codes.codes {
+		assert.NotEqual(t, match[1], stored, "驗證碼不應以明文儲存")
+	}
+
+	// 短時間內重複發送應被拒絕
+	assert.ErrorIs(t, service.SendVerification(ctx, user.ID), usecase.ErrVerificationResendTooSoon)
+}
+
+// TestEmailVerificationConfirm 測試驗證碼確認流程
+func TestEmailVerificationConfirm(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _, mailer, user := setupEmailVerification(t)
+
+	require.NoError(t, service.SendVerification(ctx, user.ID))
+	code := verificationCodePattern.FindStringSubmatch(mailer.last().Body)[1]
+
+	assert.ErrorIs(t, service.ConfirmEmail(ctx, user.Email, "000000x"), usecase.ErrInvalidVerificationCode)
+	require.NoError(t, service.ConfirmEmail(ctx, "ALICE@example.com", code))
+
+	verified, err := service.IsEmailVerified(ctx, user.ID)
+	require.NoError(t, err)
+	assert.True(t, verified)
+
+	stored, _ := repo.GetByID(ctx, user.ID)
+	assert.False(t, stored.IsVerified, "電子郵件驗證不應影響年齡驗證狀態")
+}
+
+// TestEmailVerificationAttemptLimit 測試暴力猜測驗證碼會使驗證碼作廢
+func TestEmailVerificationAttemptLimit(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, mailer, user := setupEmailVerification(t)
+
+	require.NoError(t, service.SendVerification(ctx, user.ID))
+	code := verificationCodePattern.FindStringSubmatch(mailer.last().Body)[1]
+
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	for i := 0; i < 5; i++ {
+		assert.ErrorIs(t, service.ConfirmEmail(ctx, user.Email, wrong), usecase.ErrInvalidVerificationCode)
+	}
+
+	assert.ErrorIs(t, service.ConfirmEmail(ctx, user.Email, code), usecase.ErrTooManyVerifyAttempts)
+	assert.ErrorIs(t, service.ConfirmEmail(ctx, user.Email, code), usecase.ErrInvalidVerificationCode, "驗證碼作廢後即使正確也無法使用")
+}
+
+// TestResendVerificationDoesNotLeakAccounts 測試重新發送不洩漏帳號是否存在
+func TestResendVerificationDoesNotLeakAccounts(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, mailer, _ := setupEmailVerification(t)
+
+	assert.NoError(t, service.ResendVerification(ctx, "nobody@example.com"))
+	assert.Empty(t, mailer.sent)
+
+	assert.NoError(t, service.ResendVerification(ctx, "alice@example.com"))
+	assert.NoError(t, service.ResendVerification(ctx, "alice@example.com"), "頻率限制同樣不應透露帳號狀態")
+	assert.Len(t, mailer.sent, 1)
+}
@@ -0,0 +1,360 @@
+package unit_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+	"golang_dev_docker/domain/usecase"
+)
+
+// memoryUserRepository 測試用記憶體用戶儲存庫
+type memoryUserRepository struct {
+	mu     sync.Mutex
+	users  map[uint]*entity.User
+	nextID uint
+}
+
+func newMemoryUserRepository() *memoryUserRepository {
+	return &memoryUserRepository{users: make(map[uint]*entity.User), nextID: 1}
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user.ID = r.nextID
+	r.nextID++
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *memoryUserRepository) GetByID(ctx context.Context, id uint) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("用戶不存在")
+	}
+	return user, nil
+}
+
+func (r *memoryUserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			return user, nil
+		}
+	}
+	return nil, errors.New("用戶不存在")
+}
+
+func (r *memoryUserRepository) Update(ctx context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *memoryUserRepository) Delete(ctx context.Context, id uint) error {
+	return r.SetActive(ctx, id, false)
+}
+
+func (r *memoryUserRepository) SetVerified(ctx context.Context, id uint, verified bool) error {
+	user, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	user.IsVerified = verified
+	return nil
+}
+
+func (r *memoryUserRepository) SetEmailVerified(ctx context.Context, id uint) error {
+	user, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	user.MarkEmailVerified()
+	return nil
+}
+
+func (r *memoryUserRepository) UpdatePassword(ctx context.Context, id uint, passwordHash string) error {
+	user, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = passwordHash
+	return nil
+}
+
+func (r *memoryUserRepository) SetActive(ctx context.Context, id uint, active bool) error {
+	user, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	user.IsActive = active
+	return nil
+}
+
+func (r *memoryUserRepository) UpdateRole(ctx context.Context, id uint, role entity.UserRole) error {
+	user, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	user.Role = role
+	return nil
+}
+
+func (r *memoryUserRepository) UpdateAccountStatus(ctx context.Context, user *entity.User) error {
+	stored, err := r.GetByID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	stored.AccountStatus = user.AccountStatus
+	stored.SuspendedUntil = user.SuspendedUntil
+	stored.StatusReason = user.StatusReason
+	stored.StatusActorID = user.StatusActorID
+	stored.StatusChangedAt = user.StatusChangedAt
+	return nil
+}
+
+func (r *memoryUserRepository) GetExpiredSuspensions(ctx context.Context, now time.Time, limit int) ([]*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var users []*entity.User
+	for _, user := range r.users {
+		if user.AccountStatus == entity.AccountStatusSuspended && user.SuspendedUntil != nil && !user.SuspendedUntil.After(now) {
+			users = append(users, user)
+		}
+		if len(users) >= limit {
+			break
+		}
+	}
+	return users, nil
+}
+
+func (r *memoryUserRepository) UpdateDeletionSchedule(ctx context.Context, user *entity.User) error {
+	stored, err := r.GetByID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	stored.IsActive = user.IsActive
+	stored.DeletionRequestedAt = user.DeletionRequestedAt
+	stored.DeletionScheduledAt = user.DeletionScheduledAt
+	return nil
+}
+
+func (r *memoryUserRepository) GetDueDeletions(ctx context.Context, now time.Time, afterID uint, limit int) ([]*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var users []*entity.User
+	for _, user := range r.users {
+		if user.ID > afterID && user.IsPendingDeletion() && !user.DeletionScheduledAt.After(now) {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	if len(users) > limit {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+func (r *memoryUserRepository) CountByRoles(ctx context.Context, roles ...entity.UserRole) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, user := range r.users {
+		for _, role := range roles {
+			if user.Role == role {
+				count++
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
+// memoryCodeStore 測試用記憶體驗證碼與計數儲存
+type memoryCodeStore struct {
+	codes    map[string]string
+	counters map[string]int64
+}
+
+func newMemoryCodeStore() *memoryCodeStore {
+	return &memoryCodeStore{codes: make(map[string]string), counters: make(map[string]int64)}
+}
+
+func (s *memoryCodeStore) SetVerificationCode(identifier, code string, expiration time.Duration) error {
+	s.codes[identifier] = code
+	return nil
+}
+
+func (s *memoryCodeStore) GetVerificationCode(identifier string) (string, error) {
+	code, ok := s.codes[identifier]
+	if !ok {
+		return "", errors.New("驗證碼不存在")
+	}
+	return code, nil
+}
+
+func (s *memoryCodeStore) ConsumeVerificationCode(identifier string) (string, error) {
+	code, err := s.GetVerificationCode(identifier)
+	if err != nil {
+		return "", err
+	}
+	delete(s.codes, identifier)
+	return code, nil
+}
+
+func (s *memoryCodeStore) DeleteVerificationCode(identifier string) error {
+	delete(s.codes, identifier)
+	return nil
+}
+
+func (s *memoryCodeStore) SetRateLimit(identifier string, count int64, expiration time.Duration) error {
+	s.counters[identifier] = count
+	return nil
+}
+
+func (s *memoryCodeStore) IncrementRateLimit(identifier string, expiration time.Duration) (int64, error) {
+	s.counters[identifier]++
+	return s.counters[identifier], nil
+}
+
+// recordingMailer 測試用郵件發送器，記錄所有寄出的郵件
+type recordingMailer struct {
+	sent []*usecase.EmailMessage
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg *usecase.EmailMessage) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func (m *recordingMailer) last() *usecase.EmailMessage {
+	if len(m.sent) == 0 {
+		return nil
+	}
+	return m.sent[len(m.sent)-1]
+}
+
+// memoryModerationRepository 測試用記憶體審核日誌儲存庫
+type memoryModerationRepository struct {
+	logs []*repository.ModerationLog
+}
+
+func (r *memoryModerationRepository) CreateModerationLog(ctx context.Context, log *repository.ModerationLog) error {
+	log.ID = uint(len(r.logs) + 1)
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+func (r *memoryModerationRepository) GetModerationHistory(ctx context.Context, contentType string, contentID uint) ([]*repository.ModerationLog, error) {
+	var logs []*repository.ModerationLog
+	for _, log := range r.logs {
+		if log.ContentType == contentType && log.ContentID == contentID {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func (r *memoryModerationRepository) GetUserModerationHistory(ctx context.Context, userID uint, limit int) ([]*repository.ModerationLog, error) {
+	var logs []*repository.ModerationLog
+	for _, log := range r.logs {
+		if log.UserID == userID && len(logs) < limit {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func (r *memoryModerationRepository) UpdateModerationAction(ctx context.Context, logID uint, action repository.ModerationAction, notes string) error {
+	return nil
+}
+
+func (r *memoryModerationRepository) GetPendingModerations(ctx context.Context, contentType string, limit int) ([]*repository.ModerationLog, error) {
+	return nil, nil
+}
+
+func (r *memoryModerationRepository) GetModerationStats(ctx context.Context, params repository.ModerationStatsParams) (*repository.ModerationStats, error) {
+	return &repository.ModerationStats{}, nil
+}
+
+// memoryTwoFactorRepository 測試用記憶體兩步驟驗證儲存庫
+type memoryTwoFactorRepository struct {
+	credentials map[uint]*entity.TwoFactorCredential
+	codes       map[uint][]*entity.RecoveryCode
+}
+
+func newMemoryTwoFactorRepository() *memoryTwoFactorRepository {
+	return &memoryTwoFactorRepository{
+		credentials: map[uint]*entity.TwoFactorCredential{},
+		codes:       map[uint][]*entity.RecoveryCode{},
+	}
+}
+
+func (r *memoryTwoFactorRepository) GetByUserID(ctx context.Context, userID uint) (*entity.TwoFactorCredential, error) {
+	credential, ok := r.credentials[userID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *credential
+	return &copied, nil
+}
+
+func (r *memoryTwoFactorRepository) Save(ctx context.Context, credential *entity.TwoFactorCredential) error {
+	copied := *credential
+	r.credentials[credential.UserID] = &copied
+	return nil
+}
+
+func (r *memoryTwoFactorRepository) Delete(ctx context.Context, userID uint) error {
+	delete(r.credentials, userID)
+	delete(r.codes, userID)
+	return nil
+}
+
+func (r *memoryTwoFactorRepository) MarkStepUsed(ctx context.Context, userID uint, step int64) (bool, error) {
+	credential, ok := r.credentials[userID]
+	if !ok || credential.LastUsedStep >= step {
+		return false, nil
+	}
+	credential.LastUsedStep = step
+	return true, nil
+}
+
+func (r *memoryTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error {
+	r.codes[userID] = nil
+	for _, hash := range codeHashes {
+		r.codes[userID] = append(r.codes[userID], &entity.RecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	return nil
+}
+
+func (r *memoryTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	for _, code := range r.codes[userID] {
+		if code.CodeHash == codeHash && !code.IsUsed() {
+			now := time.Now()
+			code.UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryTwoFactorRepository) CountUnusedRecoveryCodes(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	for _, code := range r.codes[userID] {
+		if !code.IsUsed() {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,89 @@
+package unit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMatchingCache 測試用配對快取，記錄被清除的用戶
+type recordingMatchingCache struct {
+	invalidated []uint
+}
+
+func (c *recordingMatchingCache) InvalidateUserCache(userID uint) error {
+	c.invalidated = append(c.invalidated, userID)
+	return nil
+}
+
+// TestGenderSetStorage 測試性別偏好以逗號分隔字串儲存，並在讀回後保持一致
+func TestGenderSetStorage(t *testing.T) {
+	set, err := entity.NewGenderSet(entity.GenderMale, entity.GenderFemale, entity.GenderMale)
+	require.NoError(t, err)
+	assert.Equal(t, entity.GenderSet{entity.GenderFemale, entity.GenderMale}, set, "去除重複並排序")
+
+	value, err := set.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "female,male", value)
+
+	var scanned entity.GenderSet
+	require.NoError(t, scanned.Scan([]byte("female,male")))
+	assert.Equal(t, set, scanned)
+
+	var empty entity.GenderSet
+	require.NoError(t, empty.Scan(""))
+	assert.Empty(t, empty)
+	assert.True(t, empty.Includes(entity.GenderOther), "空集合表示不限性別")
+
+	raw, err := json.Marshal(empty)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(raw))
+
+	_, err = entity.NewGenderSet("robot")
+	assert.Error(t, err)
+}
+
+// TestUpdateProfileGenderPreferences 測試更新檔案時儲存多選的性別偏好
+func TestUpdateProfileGenderPreferences(t *testing.T) {
+	ctx := context.Background()
+	users := newMemoryUserRepository()
+	user := &entity.User{Email: "dana@example.com", BirthDate: time.Date(1992, 5, 1, 0, 0, 0, 0, time.UTC), IsActive: true}
+	require.NoError(t, users.Create(ctx, user))
+
+	profiles := &memoryProfileRepository{profiles: map[uint]*entity.UserProfile{
+		user.ID: {UserID: user.ID, DisplayName: "Dana", Gender: entity.GenderFemale, MaxDistance: 50, AgeRangeMin: 18, AgeRangeMax: 99},
+	}}
+	service := usecase.NewUserService(users, profiles, nil, nil, nil)
+	cache := &recordingMatchingCache{}
+	service.SetMatchingCache(cache)
+
+	response, err := service.UpdateProfile(ctx, user.ID, &usecase.UpdateProfileRequest{
+		InterestedIn: []entity.Gender{entity.GenderOther, entity.GenderFemale},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{user.ID}, cache.invalidated, "偏好變更後清除潛在配對快取")
+	assert.Equal(t, entity.GenderSet{entity.GenderFemale, entity.GenderOther}, response.Profile.InterestedIn)
+	assert.True(t, profiles.profiles[user.ID].IsInterestedIn(entity.GenderOther))
+	assert.False(t, profiles.profiles[user.ID].IsInterestedIn(entity.GenderMale))
+
+	// 未傳入時不變更
+	_, err = service.UpdateProfile(ctx, user.ID, &usecase.UpdateProfileRequest{})
+	require.NoError(t, err)
+	assert.Len(t, profiles.profiles[user.ID].InterestedIn, 2)
+
+	// 空陣列表示不限
+	_, err = service.UpdateProfile(ctx, user.ID, &usecase.UpdateProfileRequest{InterestedIn: []entity.Gender{}})
+	require.NoError(t, err)
+	assert.True(t, profiles.profiles[user.ID].IsInterestedIn(entity.GenderMale))
+
+	_, err = service.UpdateProfile(ctx, user.ID, &usecase.UpdateProfileRequest{InterestedIn: []entity.Gender{"robot"}})
+	assert.Error(t, err)
+	assert.Len(t, cache.invalidated, 3, "驗證失敗時不清除快取")
+}
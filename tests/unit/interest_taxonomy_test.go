@@ -0,0 +1,241 @@
+package unit_test
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+	"golang_dev_docker/domain/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryInterestRepository 以記憶體保存興趣與用戶選擇，讀取時返回複本
+type memoryInterestRepository struct {
+	interests     map[uint]*entity.Interest
+	userInterests map[uint][]uint
+	nextID        uint
+}
+
+func newMemoryInterestRepository() *memoryInterestRepository {
+	return &memoryInterestRepository{interests: make(map[uint]*entity.Interest), userInterests: make(map[uint][]uint), nextID: 1}
+}
+
+func (r *memoryInterestRepository) copyOf(interest *entity.Interest) *entity.Interest {
+	copied := *interest
+	return &copied
+}
+
+func (r *memoryInterestRepository) GetAll(ctx context.Context, activeOnly bool) ([]*entity.Interest, error) {
+	var interests []*entity.Interest
+	for _, interest := range r.interests {
+		if !activeOnly || interest.IsActive {
+			interests = append(interests, r.copyOf(interest))
+		}
+	}
+	sort.Slice(interests, func(i, j int) bool { return interests[i].ID < interests[j].ID })
+	return interests, nil
+}
+
+func (r *memoryInterestRepository) GetByID(ctx context.Context, id uint) (*entity.Interest, error) {
+	if interest, ok := r.interests[id]; ok {
+		return r.copyOf(interest), nil
+	}
+	return nil, nil
+}
+
+func (r *memoryInterestRepository) GetByIDs(ctx context.Context, ids []uint) ([]*entity.Interest, error) {
+	var interests []*entity.Interest
+	for _, id := range ids {
+		if interest, ok := r.interests[id]; ok {
+			interests = append(interests, r.copyOf(interest))
+		}
+	}
+	return interests, nil
+}
+
+func (r *memoryInterestRepository) GetByName(ctx context.Context, name string) (*entity.Interest, error) {
+	for _, interest := range r.interests {
+		if strings.EqualFold(interest.Name, name) {
+			return r.copyOf(interest), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryInterestRepository) Search(ctx context.Context, prefix string, limit int) ([]*entity.Interest, error) {
+	all, _ := r.GetAll(ctx, true)
+	var interests []*entity.Interest
+	for _, interest := range all {
+		if interest.MatchesPrefix(prefix) {
+			interests = append(interests, interest)
+		}
+	}
+	sort.SliceStable(interests, func(i, j int) bool { return interests[i].UsageCount > interests[j].UsageCount })
+	if len(interests) > limit {
+		interests = interests[:limit]
+	}
+	return interests, nil
+}
+
+func (r *memoryInterestRepository) GetByUserID(ctx context.Context, userID uint) ([]*entity.Interest, error) {
+	return r.GetByIDs(ctx, r.userInterests[userID])
+}
+
+func (r *memoryInterestRepository) SetUserInterests(ctx context.Context, userID uint, interestIDs []uint) error {
+	r.userInterests[userID] = append([]uint(nil), interestIDs...)
+	for _, interest := range r.interests {
+		interest.UsageCount = 0
+	}
+	for _, ids := range r.userInterests {
+		for _, id := range ids {
+			r.interests[id].UsageCount++
+		}
+	}
+	return nil
+}
+
+func (r *memoryInterestRepository) Create(ctx context.Context, interest *entity.Interest) error {
+	interest.ID = r.nextID
+	r.nextID++
+	r.interests[interest.ID] = r.copyOf(interest)
+	return nil
+}
+
+func (r *memoryInterestRepository) Update(ctx context.Context, interest *entity.Interest) error {
+	stored := r.copyOf(interest)
+	stored.UsageCount = r.interests[interest.ID].UsageCount
+	r.interests[interest.ID] = stored
+	return nil
+}
+
+func (r *memoryInterestRepository) Delete(ctx context.Context, id uint) error {
+	delete(r.interests, id)
+	return nil
+}
+
+var _ repository.InterestRepository = (*memoryInterestRepository)(nil)
+
+// TestInterestTaxonomy 測試興趣目錄維護、在地化名稱與同義詞搜尋、CSV 匯入匯出，以及用戶選擇時的數量上限與使用人數
+func TestInterestTaxonomy(t *testing.T) {
+	ctx := context.Background()
+	interests := newMemoryInterestRepository()
+	catalog := usecase.NewInterestService(interests)
+	users := usecase.NewUserService(newMemoryUserRepository(), nil, nil, interests, nil)
+
+	running, err := catalog.CreateInterest(ctx, &usecase.InterestRequest{
+		Name:         " 跑步 ",
+		Category:     "sports",
+		Translations: map[string]string{"en": "Running", "ja": "ランニング"},
+		Synonyms:     []string{"慢跑", "Jogging"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "跑步", running.Name)
+	assert.True(t, running.IsActive)
+	music, err := catalog.CreateInterest(ctx, &usecase.InterestRequest{Name: "音樂", Category: "音樂", Translations: map[string]string{"EN": "Music"}})
+	require.NoError(t, err)
+	assert.Equal(t, entity.InterestCategoryMusic, music.Category, "類別可使用顯示名稱")
+
+	_, err = catalog.CreateInterest(ctx, &usecase.InterestRequest{Name: "電影", Category: "娛樂"})
+	assert.Error(t, err, "無效的類別")
+	_, err = catalog.CreateInterest(ctx, &usecase.InterestRequest{Name: "跑步", Category: "sports"})
+	assert.ErrorIs(t, err, usecase.ErrInterestNameTaken)
+	_, err = catalog.CreateInterest(ctx, &usecase.InterestRequest{Name: "游泳", Category: "sports", Synonyms: []string{"泳", "泳"}})
+	assert.Error(t, err, "同義詞重複")
+	_, err = catalog.UpdateInterest(ctx, running.ID, &usecase.InterestRequest{Name: "音樂", Category: "sports"})
+	assert.ErrorIs(t, err, usecase.ErrInterestNameTaken)
+	assert.ErrorIs(t, catalog.DeleteInterest(ctx, 99), usecase.ErrInterestNotFound)
+
+	// 名稱依語系顯示，沒有翻譯時使用標準名稱
+	localized, err := catalog.ListInterests(ctx, "en-US")
+	require.NoError(t, err)
+	require.Len(t, localized, 2)
+	assert.Equal(t, "Running", localized[0].Name)
+	assert.Equal(t, "運動", localized[0].CategoryName)
+	localized, err = catalog.ListInterests(ctx, "ko")
+	require.NoError(t, err)
+	assert.Equal(t, "跑步", localized[0].Name)
+
+	// 字首搜尋比對名稱、在地化名稱與同義詞
+	for _, query := range []string{"跑", "run", "jog", "慢"} {
+		found, err := catalog.SearchInterests(ctx, query, "zh-tw", 0)
+		require.NoError(t, err)
+		require.Len(t, found, 1, query)
+		assert.Equal(t, running.ID, found[0].ID, query)
+	}
+	found, err := catalog.SearchInterests(ctx, "  ", "zh-tw", 0)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	// 選擇興趣時去除重複、維護使用人數並限制數量
+	selected, err := users.SetUserInterests(ctx, 1, []uint{running.ID, music.ID, running.ID})
+	require.NoError(t, err)
+	assert.Len(t, selected, 2)
+	_, err = users.SetUserInterests(ctx, 2, []uint{running.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 2, interests.interests[running.ID].UsageCount)
+	_, err = users.SetUserInterests(ctx, 1, []uint{music.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, interests.interests[running.ID].UsageCount)
+	assert.Equal(t, 1, interests.interests[music.ID].UsageCount)
+
+	tooMany := make([]uint, 0, entity.MaxUserInterests+1)
+	for i := 0; i <= entity.MaxUserInterests; i++ {
+		created, err := catalog.CreateInterest(ctx, &usecase.InterestRequest{Name: "興趣" + string(rune('A'+i)), Category: "other"})
+		require.NoError(t, err)
+		tooMany = append(tooMany, created.ID)
+	}
+	_, err = users.SetUserInterests(ctx, 1, tooMany)
+	assert.ErrorIs(t, err, usecase.ErrTooManyInterests)
+	_, err = users.SetUserInterests(ctx, 1, []uint{music.ID, 999})
+	assert.ErrorIs(t, err, usecase.ErrInterestNotFound)
+
+	// 停用的興趣不再提供選擇與搜尋，既有選擇可以保留
+	inactive := false
+	_, err = catalog.UpdateInterest(ctx, music.ID, &usecase.InterestRequest{Name: "音樂", Category: "music", IsActive: &inactive})
+	require.NoError(t, err)
+	assert.Equal(t, 1, interests.interests[music.ID].UsageCount, "修改興趣不影響使用人數")
+	_, err = users.SetUserInterests(ctx, 2, []uint{running.ID, music.ID})
+	assert.ErrorIs(t, err, usecase.ErrInterestInactive)
+	_, err = users.SetUserInterests(ctx, 1, []uint{music.ID, running.ID})
+	require.NoError(t, err)
+	found, err = catalog.SearchInterests(ctx, "mus", "en", 0)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	// 匯出後匯入相同內容只會更新，不影響使用人數
+	var exported bytes.Buffer
+	require.NoError(t, catalog.ExportCSV(ctx, &exported))
+	assert.Contains(t, exported.String(), "跑步,sports,,true,en=Running|ja=ランニング,慢跑|Jogging")
+	result, err := catalog.ImportCSV(ctx, bytes.NewReader(exported.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Created)
+	assert.Equal(t, len(interests.interests), result.Updated)
+	assert.Equal(t, 2, interests.interests[running.ID].UsageCount)
+
+	result, err = catalog.ImportCSV(ctx, strings.NewReader("name,category,translations,synonyms\n攝影,藝術,en=Photography,拍照\n跑步,sports,en=Run,\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, "Run", interests.interests[running.ID].LocalizedName("en"))
+	assert.Empty(t, interests.interests[running.ID].Synonyms, "匯入以 CSV 內容取代同義詞")
+
+	// 任何一列有誤時整份不匯入，並回報各列的錯誤
+	count := len(interests.interests)
+	result, err = catalog.ImportCSV(ctx, strings.NewReader("name,category\n書法,藝術\n電影,娛樂\n書法,art\n,art\n"))
+	assert.ErrorIs(t, err, usecase.ErrInvalidInterestImport)
+	require.NotNil(t, result)
+	var lines []int
+	for _, importErr := range result.Errors {
+		lines = append(lines, importErr.Line)
+	}
+	assert.Equal(t, []int{3, 4, 5}, lines)
+	assert.Len(t, interests.interests, count)
+	_, err = catalog.ImportCSV(ctx, strings.NewReader("title,category\n書法,art\n"))
+	assert.ErrorIs(t, err, usecase.ErrInvalidInterestImport)
+}
@@ -0,0 +1,267 @@
+package unit_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang_dev_docker/config"
+	"golang_dev_docker/server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestKeySet 建立測試用臨時 JWT 金鑰組
+func newTestKeySet() *middleware.KeySet {
+	keys, err := middleware.NewEphemeralKeySet()
+	if err != nil {
+		panic(err)
+	}
+	return keys
+}
+
+// rsaKeyPEM 產生指定長度的 RSA 私鑰 PEM（PKCS#8）
+func rsaKeyPEM(t *testing.T, bits int) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+// authStatus 以存取權杖呼叫受保護的路由並返回狀態碼
+func authStatus(jwtAuth *middleware.JWTAuthMiddleware, token string) int {
+	router := gin.New()
+	router.GET("/me", jwtAuth.AuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func tokenHeader(t *testing.T, token string) map[string]interface{} {
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	return parsed.Header
+}
+
+// TestKeyRotationKeepsLiveTokensValid 測試切換簽發金鑰後，舊金鑰簽發的權杖在金鑰移除前仍然有效
+func TestKeyRotationKeepsLiveTokensValid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	oldKey, err := middleware.GenerateEd25519Key("2026-04")
+	require.NoError(t, err)
+	keys := middleware.NewKeySet()
+	require.NoError(t, keys.Add(oldKey))
+	require.NoError(t, keys.SetSigningKey("2026-04"))
+	jwtAuth := middleware.NewJWTAuthMiddleware(keys)
+
+	oldToken, _, err := jwtAuth.IssueAccessToken(1, "user@example.com", "user", "", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-04", tokenHeader(t, oldToken)["kid"])
+	assert.Equal(t, "EdDSA", tokenHeader(t, oldToken)["alg"])
+
+	// 加入 RSA 新金鑰並切換簽發
+	newKey, err := middleware.ParsePrivateKeyPEM("2026-10", rsaKeyPEM(t, 2048))
+	require.NoError(t, err)
+	require.NoError(t, keys.Add(newKey))
+	require.NoError(t, keys.SetSigningKey("2026-10"))
+
+	newToken, _, err := jwtAuth.IssueAccessToken(1, "user@example.com", "user", "", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10", tokenHeader(t, newToken)["kid"])
+	assert.Equal(t, "RS256", tokenHeader(t, newToken)["alg"])
+
+	assert.Equal(t, http.StatusOK, authStatus(jwtAuth, oldToken), "輪替後舊權杖仍然有效")
+	assert.Equal(t, http.StatusOK, authStatus(jwtAuth, newToken))
+
+	// 舊金鑰移除後其簽發的權杖失效
+	require.Error(t, keys.Remove("2026-10"), "簽發中的金鑰不可移除")
+	require.NoError(t, keys.Remove("2026-04"))
+	assert.Equal(t, http.StatusUnauthorized, authStatus(jwtAuth, oldToken))
+	assert.Equal(t, http.StatusOK, authStatus(jwtAuth, newToken))
+}
+
+// TestForgedTokensRejected 測試缺少 kid、未知 kid 與演算法混淆的權杖一律拒絕
+func TestForgedTokensRejected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	key, err := middleware.GenerateEd25519Key("k1")
+	require.NoError(t, err)
+	keys := middleware.NewKeySet()
+	require.NoError(t, keys.Add(key))
+	require.NoError(t, keys.SetSigningKey("k1"))
+	jwtAuth := middleware.NewJWTAuthMiddleware(keys)
+
+	claims := &middleware.JWTClaims{
+		UserID: 1,
+		Role:   "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+
+	// 以公鑰作為 HMAC 密鑰簽署（演算法混淆攻擊）
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	hmacToken.Header["kid"] = "k1"
+	forged, err := hmacToken.SignedString([]byte(key.Public.(ed25519.PublicKey)))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, authStatus(jwtAuth, forged))
+
+	// 舊版以共用密鑰簽發、沒有 kid 的權杖
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("dating-app-secret-key"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, authStatus(jwtAuth, legacy))
+
+	// 其他金鑰簽署但宣稱使用已知 kid
+	_, otherPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	foreign := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	foreign.Header["kid"] = "k1"
+	forged, err = foreign.SignedString(otherPrivate)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, authStatus(jwtAuth, forged))
+
+	foreign.Header["kid"] = "unknown"
+	forged, err = foreign.SignedString(otherPrivate)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, authStatus(jwtAuth, forged))
+}
+
+// TestJWKSPublishesVerificationKeys 測試 JWKS 只公開公鑰，且可用於驗證簽發的權杖
+func TestJWKSPublishesVerificationKeys(t *testing.T) {
+	keys := newTestKeySet()
+	retired, err := middleware.ParsePrivateKeyPEM("retired", rsaKeyPEM(t, 2048))
+	require.NoError(t, err)
+	require.NoError(t, keys.Add(retired))
+
+	jwks := keys.JWKS()
+	require.Len(t, jwks.Keys, 2)
+
+	raw, err := json.Marshal(jwks)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), `"d"`, "不可公開私鑰參數")
+
+	var okp *middleware.JSONWebKey
+	for i := range jwks.Keys {
+		if jwks.Keys[i].Kid == keys.SigningKeyID() {
+			okp = &jwks.Keys[i]
+		}
+	}
+	require.NotNil(t, okp)
+	assert.Equal(t, "OKP", okp.Kty)
+	assert.Equal(t, "Ed25519", okp.Crv)
+	assert.Equal(t, "EdDSA", okp.Alg)
+
+	// 其他服務僅憑 JWKS 即可驗證權杖
+	jwtAuth := middleware.NewJWTAuthMiddleware(keys)
+	token, _, err := jwtAuth.IssueAccessToken(7, "user@example.com", "user", "", time.Minute)
+	require.NoError(t, err)
+
+	x, err := base64.RawURLEncoding.DecodeString(okp.X)
+	require.NoError(t, err)
+	parsed, err := jwt.ParseWithClaims(token, &middleware.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return ed25519.PublicKey(x), nil
+	}, jwt.WithValidMethods([]string{"EdDSA"}))
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), parsed.Claims.(*middleware.JWTClaims).UserID)
+}
+
+// TestLoadKeySetFromConfig 測試由檔案與 PEM 內容載入金鑰，並拒絕無法簽發或強度不足的配置
+func TestLoadKeySetFromConfig(t *testing.T) {
+	dir := t.TempDir()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	privateFile := filepath.Join(dir, "current.pem")
+	require.NoError(t, os.WriteFile(privateFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(&oldKey.PublicKey)
+	require.NoError(t, err)
+	publicPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+
+	keys, err := middleware.LoadKeySet([]middleware.KeyConfig{
+		{ID: "old", PublicKey: publicPEM},
+		{ID: "current", PrivateKeyFile: privateFile},
+	}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "current", keys.SigningKeyID(), "未指定時使用第一把含私鑰的金鑰")
+	assert.Len(t, keys.JWKS().Keys, 2)
+
+	// 舊金鑰簽發的權杖可由只含公鑰的設定驗證
+	legacy := jwt.NewWithClaims(jwt.SigningMethodRS256, &middleware.JWTClaims{
+		UserID:           3,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+	})
+	legacy.Header["kid"] = "old"
+	signed, err := legacy.SignedString(oldKey)
+	require.NoError(t, err)
+	_, err = keys.Parse(signed, &middleware.JWTClaims{})
+	assert.NoError(t, err)
+
+	_, err = middleware.LoadKeySet([]middleware.KeyConfig{{ID: "old", PublicKey: publicPEM}}, "old")
+	assert.Error(t, err, "只有公鑰的金鑰不能用於簽發")
+
+	_, err = middleware.LoadKeySet([]middleware.KeyConfig{
+		{ID: "a", PrivateKeyFile: privateFile},
+		{ID: "a", PublicKey: publicPEM},
+	}, "")
+	assert.Error(t, err, "金鑰 ID 不可重複")
+
+	_, err = middleware.LoadKeySet([]middleware.KeyConfig{{ID: "weak", PrivateKey: string(rsaKeyPEM(t, 1024))}}, "")
+	assert.Error(t, err, "RSA 金鑰長度不足")
+
+	_, err = middleware.LoadKeySet([]middleware.KeyConfig{{ID: "missing", PrivateKeyFile: filepath.Join(dir, "missing.pem")}}, "")
+	assert.Error(t, err)
+}
+
+// TestLoadKeySetFromProductionConfig 測試生產配置以環境變數提供金鑰 ID 與私鑰時可以載入並簽發
+func TestLoadKeySetFromProductionConfig(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	t.Setenv("JWT_SIGNING_KEY_ID", "2026-10")
+	t.Setenv("JWT_PRIVATE_KEY", string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+
+	cfg, err := config.LoadConfigFromPath(filepath.Join("..", "..", "config", "production.yaml"))
+	require.NoError(t, err)
+
+	signingKeyID, keys := cfg.JWT.SigningKeys()
+	require.Len(t, keys, 1)
+	assert.Equal(t, "2026-10", signingKeyID)
+	assert.Equal(t, "2026-10", keys[0].ID)
+
+	keyConfigs := make([]middleware.KeyConfig, 0, len(keys))
+	for _, key := range keys {
+		keyConfigs = append(keyConfigs, middleware.KeyConfig{
+			ID:             key.ID,
+			PrivateKey:     key.PrivateKey,
+			PrivateKeyFile: key.PrivateKeyFile,
+			PublicKey:      key.PublicKey,
+			PublicKeyFile:  key.PublicKeyFile,
+		})
+	}
+	keySet, err := middleware.LoadKeySet(keyConfigs, signingKeyID)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10", keySet.SigningKeyID())
+	require.Len(t, keySet.JWKS().Keys, 1)
+}
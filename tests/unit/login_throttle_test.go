@@ -0,0 +1,259 @@
+package unit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/server/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// memoryLoginAttemptStore 測試用登入失敗計數儲存，鎖定不會自動過期
+type memoryLoginAttemptStore struct {
+	failures map[string]int64
+	locks    map[string]memoryLoginLock
+}
+
+type memoryLoginLock struct {
+	kind     string
+	duration time.Duration
+}
+
+func newMemoryLoginAttemptStore() *memoryLoginAttemptStore {
+	return &memoryLoginAttemptStore{failures: map[string]int64{}, locks: map[string]memoryLoginLock{}}
+}
+
+func (s *memoryLoginAttemptStore) IncrementLoginFailures(identifier string, window time.Duration) (int64, error) {
+	s.failures[identifier]++
+	return s.failures[identifier], nil
+}
+
+func (s *memoryLoginAttemptStore) ResetLoginFailures(identifier string) error {
+	delete(s.failures, identifier)
+	return nil
+}
+
+func (s *memoryLoginAttemptStore) LockLogin(identifier, kind string, duration time.Duration) error {
+	s.locks[identifier] = memoryLoginLock{kind: kind, duration: duration}
+	return nil
+}
+
+func (s *memoryLoginAttemptStore) GetLoginLock(identifier string) (string, time.Duration, error) {
+	lock, exists := s.locks[identifier]
+	if !exists {
+		return "", 0, nil
+	}
+	return lock.kind, lock.duration, nil
+}
+
+// expireLocks 模擬所有延遲與鎖定到期
+func (s *memoryLoginAttemptStore) expireLocks() {
+	s.locks = map[string]memoryLoginLock{}
+}
+
+// memoryLoginAttemptRepository 測試用登入嘗試記錄儲存庫
+type memoryLoginAttemptRepository struct {
+	attempts []*entity.LoginAttempt
+}
+
+func (r *memoryLoginAttemptRepository) Create(ctx context.Context, attempt *entity.LoginAttempt) error {
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+func (r *memoryLoginAttemptRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *memoryLoginAttemptRepository) reasons() []entity.LoginFailureReason {
+	reasons := make([]entity.LoginFailureReason, 0, len(r.attempts))
+	for _, attempt := range r.attempts {
+		reasons = append(reasons, attempt.FailureReason)
+	}
+	return reasons
+}
+
+// emptyProfileRepository 測試用用戶檔案儲存庫，所有用戶都沒有檔案
+type emptyProfileRepository struct {
+	repository.UserProfileRepository
+}
+
+func (r *emptyProfileRepository) GetByUserID(ctx context.Context, userID uint) (*entity.UserProfile, error) {
+	return nil, errors.New("用戶檔案不存在")
+}
+
+type loginThrottleFixture struct {
+	auth     *usecase.AuthService
+	store    *memoryLoginAttemptStore
+	attempts *memoryLoginAttemptRepository
+	mailer   *recordingMailer
+	user     *entity.User
+	users    *memoryUserRepository
+}
+
+// setupLoginThrottle 建立啟用登入限制的認證服務與一位設有密碼的用戶
+func setupLoginThrottle(t *testing.T, policy usecase.LoginThrottlePolicy) *loginThrottleFixture {
+	repo := newMemoryUserRepository()
+	user := createUserWithRole(t, repo, "erin@example.com", entity.RoleUser)
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user.PasswordHash = string(hash)
+
+	fixture := &loginThrottleFixture{
+		store:    newMemoryLoginAttemptStore(),
+		attempts: &memoryLoginAttemptRepository{},
+		mailer:   &recordingMailer{},
+		user:     user,
+		users:    repo,
+	}
+
+	throttle := usecase.NewLoginThrottleService(fixture.attempts, repo, policy)
+	throttle.SetAttemptStore(fixture.store)
+	throttle.SetMailer(fixture.mailer)
+
+	userService := usecase.NewUserService(repo, &emptyProfileRepository{}, nil, nil, nil)
+	fixture.auth = usecase.NewAuthService(userService, &fakeTokenIssuer{}, 15*time.Minute, 7*24*time.Hour)
+	fixture.auth.SetLoginThrottle(throttle)
+	return fixture
+}
+
+func (f *loginThrottleFixture) login(email, password, ip string) error {
+	_, err := f.auth.Login(context.Background(), &usecase.LoginRequest{Email: email, Password: password}, usecase.ClientInfo{IPAddress: ip, UserAgent: "test"})
+	return err
+}
+
+func testThrottlePolicy() usecase.LoginThrottlePolicy {
+	return usecase.LoginThrottlePolicy{
+		AccountFreeAttempts: 2,
+		IPFreeAttempts:      100,
+		BaseDelay:           2 * time.Second,
+		MaxDelay:            30 * time.Second,
+		AccountLockAfter:    5,
+		IPLockAfter:         100,
+		LockoutDuration:     15 * time.Minute,
+		FailureWindow:       time.Hour,
+	}
+}
+
+// TestLoginProgressiveDelayAndLockout 測試帳號連續失敗後逐步延遲並鎖定，鎖定時通知帳號擁有者
+func TestLoginProgressiveDelayAndLockout(t *testing.T) {
+	f := setupLoginThrottle(t, testThrottlePolicy())
+
+	// 前兩次失敗不延遲，每個請求來自不同 IP 模擬分散式撞庫
+	require.ErrorIs(t, f.login("erin@example.com", "wrong", "10.0.0.1"), usecase.ErrInvalidCredentials)
+	require.ErrorIs(t, f.login("erin@example.com", "wrong", "10.0.0.2"), usecase.ErrInvalidCredentials)
+	assert.Empty(t, f.store.locks)
+
+	// 第三次失敗後開始延遲，延遲期間即使密碼正確也拒絕
+	require.ErrorIs(t, f.login("erin@example.com", "wrong", "10.0.0.3"), usecase.ErrInvalidCredentials)
+	err := f.login("erin@example.com", "correct-password", "10.0.0.4")
+	var throttleErr *usecase.LoginThrottleError
+	require.ErrorAs(t, err, &throttleErr)
+	assert.ErrorIs(t, err, usecase.ErrLoginThrottled)
+	assert.Equal(t, 2*time.Second, throttleErr.RetryAfter)
+
+	// 延遲逐次加倍
+	f.store.expireLocks()
+	require.ErrorIs(t, f.login("ERIN@example.com", "wrong", "10.0.0.5"), usecase.ErrInvalidCredentials, "信箱大小寫不同仍計入同一帳號")
+	require.ErrorAs(t, f.login("erin@example.com", "wrong", "10.0.0.6"), &throttleErr)
+	assert.Equal(t, 4*time.Second, throttleErr.RetryAfter)
+	assert.Empty(t, f.mailer.sent)
+
+	// 達到門檻後鎖定並通知
+	f.store.expireLocks()
+	require.ErrorIs(t, f.login("erin@example.com", "wrong", "10.0.0.7"), usecase.ErrInvalidCredentials)
+	err = f.login("erin@example.com", "correct-password", "10.0.0.8")
+	require.ErrorAs(t, err, &throttleErr)
+	assert.ErrorIs(t, err, usecase.ErrLoginLocked)
+	assert.Equal(t, 15*time.Minute, throttleErr.RetryAfter)
+
+	require.Len(t, f.mailer.sent, 1, "鎖定時應通知帳號擁有者")
+	assert.Equal(t, "erin@example.com", f.mailer.last().To)
+	assert.Contains(t, f.mailer.last().Body, "10.0.0.7")
+
+	assert.Equal(t, []entity.LoginFailureReason{
+		entity.LoginFailureInvalidCredentials,
+		entity.LoginFailureInvalidCredentials,
+		entity.LoginFailureInvalidCredentials,
+		entity.LoginFailureThrottled,
+		entity.LoginFailureInvalidCredentials,
+		entity.LoginFailureThrottled,
+		entity.LoginFailureInvalidCredentials,
+		entity.LoginFailureLocked,
+	}, f.attempts.reasons(), "每次嘗試都應記錄")
+	require.NotNil(t, f.attempts.attempts[0].UserID)
+	assert.Equal(t, f.user.ID, *f.attempts.attempts[0].UserID)
+}
+
+// TestLoginSuccessResetsAccountFailures 測試登入成功後清除帳號失敗計數
+func TestLoginSuccessResetsAccountFailures(t *testing.T) {
+	f := setupLoginThrottle(t, testThrottlePolicy())
+
+	require.ErrorIs(t, f.login("erin@example.com", "wrong", "10.0.0.1"), usecase.ErrInvalidCredentials)
+	require.ErrorIs(t, f.login("erin@example.com", "wrong", "10.0.0.1"), usecase.ErrInvalidCredentials)
+	require.NoError(t, f.login("erin@example.com", "correct-password", "10.0.0.1"))
+
+	last := f.attempts.attempts[len(f.attempts.attempts)-1]
+	assert.True(t, last.Success)
+
+	require.ErrorIs(t, f.login("erin@example.com", "wrong", "10.0.0.1"), usecase.ErrInvalidCredentials)
+	assert.Empty(t, f.store.locks, "成功登入後失敗次數應重新計算")
+	assert.Equal(t, int64(3), f.store.failures["ip:10.0.0.1"], "IP 計數不因成功登入而清除")
+}
+
+// TestLoginFailuresResetOnlyAfterSession 測試需要第二步驗證時，只通過密碼不會清除失敗計數，完成驗證後才清除
+func TestLoginFailuresResetOnlyAfterSession(t *testing.T) {
+	ctx := context.Background()
+	f := setupLoginThrottle(t, testThrottlePolicy())
+	twoFactor := usecase.NewTwoFactorService(f.users, newMemoryTwoFactorRepository(), "Dating App")
+	f.auth.SetTwoFactor(twoFactor, middleware.NewJWTAuthMiddleware(newTestKeySet()))
+	secret, _ := enableTwoFactor(t, twoFactor, f.user.ID)
+
+	require.ErrorIs(t, f.login("erin@example.com", "wrong", "10.0.0.1"), usecase.ErrInvalidCredentials)
+	require.ErrorIs(t, f.login("erin@example.com", "wrong", "10.0.0.1"), usecase.ErrInvalidCredentials)
+
+	client := usecase.ClientInfo{IPAddress: "10.0.0.1", UserAgent: "test"}
+	result, err := f.auth.Login(ctx, &usecase.LoginRequest{Email: "erin@example.com", Password: "correct-password"}, client)
+	require.NoError(t, err)
+	require.NotNil(t, result.MFAChallenge)
+	assert.Nil(t, result.Tokens)
+	assert.Equal(t, int64(2), f.store.failures["account:erin@example.com"], "只通過密碼時不清除失敗計數")
+
+	result, err = f.auth.CompleteMFALogin(ctx, result.MFAChallenge.Token, totpAt(t, secret, time.Now()), client)
+	require.NoError(t, err)
+	require.NotNil(t, result.Tokens)
+	assert.Zero(t, f.store.failures["account:erin@example.com"], "建立會話後清除失敗計數")
+}
+
+// TestLoginThrottleUnknownAccountAndIP 測試不存在的帳號同樣被限制，且同一 IP 嘗試多個帳號會被鎖定
+func TestLoginThrottleUnknownAccountAndIP(t *testing.T) {
+	policy := testThrottlePolicy()
+	policy.IPFreeAttempts = 3
+	policy.IPLockAfter = 4
+	f := setupLoginThrottle(t, policy)
+
+	// 不存在的帳號與存在的帳號行為一致，但不會發送通知
+	for i := 0; i < 3; i++ {
+		require.ErrorIs(t, f.login("nobody@example.com", "wrong", "10.0.1.1"), usecase.ErrInvalidCredentials)
+	}
+	assert.ErrorIs(t, f.login("nobody@example.com", "wrong", "10.0.2.2"), usecase.ErrLoginThrottled)
+	assert.Nil(t, f.attempts.attempts[0].UserID)
+
+	// 同一 IP 換帳號嘗試仍累計 IP 失敗次數
+	f.store.expireLocks()
+	require.ErrorIs(t, f.login("someone@example.com", "wrong", "10.0.1.1"), usecase.ErrInvalidCredentials)
+	err := f.login("erin@example.com", "correct-password", "10.0.1.1")
+	assert.ErrorIs(t, err, usecase.ErrLoginLocked, "來源 IP 已鎖定")
+	assert.Empty(t, f.mailer.sent, "IP 鎖定不通知任何帳號")
+
+	// 其他 IP 不受影響
+	assert.NoError(t, f.login("erin@example.com", "correct-password", "10.0.3.3"))
+}
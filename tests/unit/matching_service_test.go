@@ -269,8 +269,9 @@ func TestMatchingService_GetPotentialMatches_Success(t *testing.T) {
 
 	userID := uint(1)
 	user := &entity.User{
-		ID:       userID,
-		IsActive: true,
+		ID:         userID,
+		IsActive:   true,
+		IsVerified: true,
 	}
 
 	profile := &entity.UserProfile{
@@ -288,10 +289,13 @@ func TestMatchingService_GetPotentialMatches_Success(t *testing.T) {
 	// Mock expectations
 	userRepo.On("GetByID", ctx, userID).Return(user, nil)
 	profileRepo.On("GetByUserID", ctx, userID).Return(profile, nil)
-	algorithmRepo.On("GetPotentialMatches", ctx, userID, 50, 20, 30, 20).Return(potentialUsers, nil)
+	algorithmRepo.On("GetPotentialMatches", ctx, userID, mock.MatchedBy(func(params repository.PotentialMatchParams) bool {
+		return params.Limit == 20 && *params.MinAge == 20 && *params.MaxAge == 30 &&
+			params.ExcludeSwipedUsers && params.ExcludeBlockedUsers
+	})).Return(potentialUsers, nil)
 
 	// Execute
-	result, err := service.GetPotentialMatches(ctx, userID, 20)
+	result, err := service.GetPotentialMatches(ctx, &usecase.PotentialMatchRequest{UserID: userID, Limit: 20})
 
 	// Assert
 	assert.NoError(t, err)
@@ -316,129 +320,147 @@ func TestMatchingService_GetPotentialMatches_UserNotFound(t *testing.T) {
 	userRepo.On("GetByID", ctx, userID).Return((*entity.User)(nil), errors.New("user not found"))
 
 	// Execute
-	result, err := service.GetPotentialMatches(ctx, userID, 20)
+	result, err := service.GetPotentialMatches(ctx, &usecase.PotentialMatchRequest{UserID: userID, Limit: 20})
 
 	// Assert
 	assert.Error(t, err)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "獲取用戶資料失敗")
+	assert.Contains(t, err.Error(), "用戶不存在")
 
 	// Verify expectations
 	userRepo.AssertExpectations(t)
 }
 
-func TestMatchingService_SwipeRight_Success_CreateMatch(t *testing.T) {
-	service, matchRepo, _, _, _, _ := setupMatchingService()
+func TestMatchingService_ProcessSwipe_Like(t *testing.T) {
+	service, matchRepo, _, userRepo, _, _ := setupMatchingService()
 	ctx := context.Background()
 
 	userID := uint(1)
 	targetUserID := uint(2)
+	pending := &entity.Match{ID: 1, User1ID: userID, User2ID: targetUserID, Status: entity.MatchStatusPending, User1Action: entity.SwipeActionLike}
 
 	// Mock expectations - 沒有現有配對
-	matchRepo.On("GetMatchBetweenUsers", ctx, userID, targetUserID).Return((*entity.Match)(nil), errors.New("match not found"))
-	matchRepo.On("GetMatchBetweenUsers", ctx, targetUserID, userID).Return((*entity.Match)(nil), errors.New("match not found"))
-	matchRepo.On("Create", ctx, mock.AnythingOfType("*entity.Match")).Return(nil)
+	userRepo.On("GetByID", ctx, targetUserID).Return(&entity.User{ID: targetUserID, IsActive: true, IsVerified: true}, nil)
+	matchRepo.On("HasUserSwiped", ctx, userID, targetUserID).Return(false, nil)
+	matchRepo.On("ProcessSwipe", ctx, userID, targetUserID, entity.SwipeActionLike).Return(pending, false, nil)
 
 	// Execute
-	result, err := service.SwipeRight(ctx, userID, targetUserID)
+	result, err := service.ProcessSwipe(ctx, &usecase.SwipeRequest{UserID: userID, TargetUserID: targetUserID, Action: entity.SwipeActionLike})
 
 	// Assert
 	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Equal(t, userID, result.UserID)
-	assert.Equal(t, targetUserID, result.TargetUserID)
-	assert.Equal(t, entity.MatchStatusPending, result.Status)
+	assert.True(t, result.Success)
+	assert.False(t, result.IsMatch)
+	assert.Equal(t, entity.MatchStatusPending, result.Match.Status)
+	assert.Equal(t, "已送出喜歡", result.Message)
 
 	// Verify expectations
+	userRepo.AssertExpectations(t)
 	matchRepo.AssertExpectations(t)
 }
 
-func TestMatchingService_SwipeRight_Success_MutualMatch(t *testing.T) {
-	service, matchRepo, _, _, _, _ := setupMatchingService()
+func TestMatchingService_ProcessSwipe_MutualMatch(t *testing.T) {
+	service, matchRepo, _, userRepo, _, _ := setupMatchingService()
 	ctx := context.Background()
 
 	userID := uint(1)
 	targetUserID := uint(2)
-
-	existingMatch := &entity.Match{
-		ID:           1,
-		UserID:       targetUserID,
-		TargetUserID: userID,
-		Status:       entity.MatchStatusPending,
-	}
+	like := entity.SwipeActionLike
+	matched := &entity.Match{ID: 1, User1ID: targetUserID, User2ID: userID, Status: entity.MatchStatusMatched, User1Action: like, User2Action: &like}
 
 	// Mock expectations - 目標用戶已經向我滑右
-	matchRepo.On("GetMatchBetweenUsers", ctx, userID, targetUserID).Return((*entity.Match)(nil), errors.New("match not found"))
-	matchRepo.On("GetMatchBetweenUsers", ctx, targetUserID, userID).Return(existingMatch, nil)
-	matchRepo.On("Create", ctx, mock.AnythingOfType("*entity.Match")).Return(nil)
-	matchRepo.On("UpdateStatus", ctx, uint(1), entity.MatchStatusMatched).Return(nil)
+	userRepo.On("GetByID", ctx, targetUserID).Return(&entity.User{ID: targetUserID, IsActive: true, IsVerified: true}, nil)
+	matchRepo.On("HasUserSwiped", ctx, userID, targetUserID).Return(false, nil)
+	matchRepo.On("ProcessSwipe", ctx, userID, targetUserID, entity.SwipeActionLike).Return(matched, true, nil)
 
 	// Execute
-	result, err := service.SwipeRight(ctx, userID, targetUserID)
+	result, err := service.ProcessSwipe(ctx, &usecase.SwipeRequest{UserID: userID, TargetUserID: targetUserID, Action: entity.SwipeActionLike})
 
 	// Assert
 	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Equal(t, entity.MatchStatusMatched, result.Status)
+	assert.True(t, result.IsMatch)
+	assert.Equal(t, entity.MatchStatusMatched, result.Match.Status)
 
 	// Verify expectations
 	matchRepo.AssertExpectations(t)
 }
 
-func TestMatchingService_SwipeLeft_Success(t *testing.T) {
-	service, matchRepo, _, _, _, _ := setupMatchingService()
+func TestMatchingService_ProcessSwipe_Pass(t *testing.T) {
+	service, matchRepo, _, userRepo, _, _ := setupMatchingService()
 	ctx := context.Background()
 
 	userID := uint(1)
 	targetUserID := uint(2)
+	passed := &entity.Match{ID: 1, User1ID: userID, User2ID: targetUserID, Status: entity.MatchStatusPending, User1Action: entity.SwipeActionPass}
 
 	// Mock expectations - 沒有現有配對
-	matchRepo.On("GetMatchBetweenUsers", ctx, userID, targetUserID).Return((*entity.Match)(nil), errors.New("match not found"))
-	matchRepo.On("Create", ctx, mock.AnythingOfType("*entity.Match")).Return(nil)
+	userRepo.On("GetByID", ctx, targetUserID).Return(&entity.User{ID: targetUserID, IsActive: true, IsVerified: true}, nil)
+	matchRepo.On("HasUserSwiped", ctx, userID, targetUserID).Return(false, nil)
+	matchRepo.On("ProcessSwipe", ctx, userID, targetUserID, entity.SwipeActionPass).Return(passed, false, nil)
 
 	// Execute
-	result, err := service.SwipeLeft(ctx, userID, targetUserID)
+	result, err := service.ProcessSwipe(ctx, &usecase.SwipeRequest{UserID: userID, TargetUserID: targetUserID, Action: entity.SwipeActionPass})
 
 	// Assert
 	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Equal(t, userID, result.UserID)
-	assert.Equal(t, targetUserID, result.TargetUserID)
-	assert.Equal(t, entity.MatchStatusRejected, result.Status)
+	assert.True(t, result.Success)
+	assert.False(t, result.IsMatch)
+	assert.Equal(t, "已跳過", result.Message)
 
 	// Verify expectations
 	matchRepo.AssertExpectations(t)
 }
 
+func TestMatchingService_ProcessSwipe_Rejected(t *testing.T) {
+	service, matchRepo, _, userRepo, _, _ := setupMatchingService()
+	ctx := context.Background()
+
+	// Mock expectations - 已滑動過的對象
+	userRepo.On("GetByID", ctx, uint(2)).Return(&entity.User{ID: 2, IsActive: true, IsVerified: true}, nil)
+	userRepo.On("GetByID", ctx, uint(3)).Return(&entity.User{ID: 3, IsActive: true}, nil)
+	matchRepo.On("HasUserSwiped", ctx, uint(1), uint(2)).Return(true, nil)
+
+	// Execute & Assert
+	for _, targetUserID := range []uint{1, 2, 3} {
+		result, err := service.ProcessSwipe(ctx, &usecase.SwipeRequest{UserID: 1, TargetUserID: targetUserID, Action: entity.SwipeActionLike})
+		assert.NoError(t, err)
+		assert.False(t, result.Success, "對自己、已滑動過與未驗證的用戶不能滑動 (目標 %d)", targetUserID)
+	}
+
+	// Verify expectations
+	matchRepo.AssertNotCalled(t, "ProcessSwipe", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestMatchingService_GetUserMatches_Success(t *testing.T) {
-	service, matchRepo, _, _, _, _ := setupMatchingService()
+	service, matchRepo, _, userRepo, _, _ := setupMatchingService()
 	ctx := context.Background()
 
 	userID := uint(1)
 	expectedMatches := []*entity.Match{
-		{ID: 1, UserID: userID, TargetUserID: 2, Status: entity.MatchStatusMatched},
-		{ID: 2, UserID: userID, TargetUserID: 3, Status: entity.MatchStatusMatched},
+		{ID: 1, User1ID: userID, User2ID: 2, Status: entity.MatchStatusMatched},
+		{ID: 2, User1ID: 3, User2ID: userID, Status: entity.MatchStatusMatched},
 	}
 
 	// Mock expectations
-	matchRepo.On("GetMutualMatches", ctx, userID, 20, 0).Return(expectedMatches, nil)
+	userRepo.On("GetByID", ctx, userID).Return(&entity.User{ID: userID, IsActive: true}, nil)
+	matchRepo.On("GetUserMatches", ctx, userID, entity.MatchStatusMatched).Return(expectedMatches, nil)
 
 	// Execute
-	result, err := service.GetUserMatches(ctx, userID, 20, 0)
+	result, err := service.GetUserMatches(ctx, userID, entity.MatchStatusMatched)
 
 	// Assert
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.Len(t, result, 2)
-	assert.Equal(t, uint(1), result[0].ID)
-	assert.Equal(t, uint(2), result[1].ID)
+	assert.Equal(t, 2, result.TotalCount)
+	assert.Equal(t, uint(1), result.Matches[0].ID)
+	assert.Equal(t, uint(2), result.Matches[1].ID)
 
 	// Verify expectations
 	matchRepo.AssertExpectations(t)
 }
 
-func TestMatchingService_CalculateCompatibility_Success(t *testing.T) {
-	service, _, algorithmRepo, _, _, _ := setupMatchingService()
+func TestMatchingService_CalculateCompatibilityScore_Success(t *testing.T) {
+	service, _, algorithmRepo, userRepo, _, _ := setupMatchingService()
 	ctx := context.Background()
 
 	user1ID := uint(1)
@@ -446,10 +468,12 @@ func TestMatchingService_CalculateCompatibility_Success(t *testing.T) {
 	expectedScore := 0.85
 
 	// Mock expectations
+	userRepo.On("GetByID", ctx, user1ID).Return(&entity.User{ID: user1ID}, nil)
+	userRepo.On("GetByID", ctx, user2ID).Return(&entity.User{ID: user2ID}, nil)
 	algorithmRepo.On("CalculateCompatibilityScore", ctx, user1ID, user2ID).Return(expectedScore, nil)
 
 	// Execute
-	result, err := service.CalculateCompatibility(ctx, user1ID, user2ID)
+	result, err := service.CalculateCompatibilityScore(ctx, user1ID, user2ID)
 
 	// Assert
 	assert.NoError(t, err)
@@ -0,0 +1,382 @@
+package unit_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/infrastructure/oidc"
+	"golang_dev_docker/infrastructure/oidc/oidctest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryIdentityRepository 測試用外部身分連結儲存庫
+type memoryIdentityRepository struct {
+	users      *memoryUserRepository
+	identities []*entity.UserIdentity
+	nextID     uint
+	failCreate bool
+}
+
+func (r *memoryIdentityRepository) Create(ctx context.Context, identity *entity.UserIdentity) error {
+	r.nextID++
+	identity.ID = r.nextID
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *memoryIdentityRepository) CreateWithUser(ctx context.Context, user *entity.User, identity *entity.UserIdentity) error {
+	if r.failCreate {
+		return errors.New("資料庫錯誤")
+	}
+	if err := r.users.Create(ctx, user); err != nil {
+		return err
+	}
+	identity.UserID = user.ID
+	return r.Create(ctx, identity)
+}
+
+func (r *memoryIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryIdentityRepository) ListByUserID(ctx context.Context, userID uint) ([]*entity.UserIdentity, error) {
+	var result []*entity.UserIdentity
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			result = append(result, identity)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryIdentityRepository) Update(ctx context.Context, identity *entity.UserIdentity) error {
+	return nil
+}
+
+func (r *memoryIdentityRepository) Delete(ctx context.Context, userID uint, provider string) (bool, error) {
+	for i, identity := range r.identities {
+		if identity.UserID == userID && identity.Provider == provider {
+			r.identities = append(r.identities[:i], r.identities[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// memoryProfileRepository 測試用用戶檔案儲存庫
+type memoryProfileRepository struct {
+	repository.UserProfileRepository
+	profiles map[uint]*entity.UserProfile
+}
+
+func (r *memoryProfileRepository) Create(ctx context.Context, profile *entity.UserProfile) error {
+	profile.ID = uint(len(r.profiles) + 1)
+	r.profiles[profile.UserID] = profile
+	return nil
+}
+
+func (r *memoryProfileRepository) GetByUserID(ctx context.Context, userID uint) (*entity.UserProfile, error) {
+	return r.profiles[userID], nil
+}
+
+func (r *memoryProfileRepository) Update(ctx context.Context, profile *entity.UserProfile) error {
+	r.profiles[profile.UserID] = profile
+	return nil
+}
+
+// discardAgeVerificationRepository 測試用年齡驗證儲存庫，不保存任何記錄
+type discardAgeVerificationRepository struct {
+	repository.AgeVerificationRepository
+}
+
+func (r *discardAgeVerificationRepository) Create(ctx context.Context, verification *entity.AgeVerification) error {
+	return nil
+}
+
+type oidcFixture struct {
+	fake       *oidctest.Server
+	provider   *oidc.Provider
+	service    *usecase.OIDCService
+	users      *memoryUserRepository
+	identities *memoryIdentityRepository
+	userSvc    *usecase.UserService
+}
+
+// setupOIDC 建立連線至假 OIDC 提供者的外部登入服務
+func setupOIDC(t *testing.T) *oidcFixture {
+	fake := oidctest.NewServer("dating-app", "client-secret")
+	t.Cleanup(fake.Close)
+
+	provider, err := oidc.NewProvider(oidc.ProviderConfig{
+		Name:         "fake",
+		DisplayName:  "Fake IdP",
+		Issuer:       fake.Issuer(),
+		ClientID:     "dating-app",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://app.test/oidc/callback",
+	}, fake.Client())
+	require.NoError(t, err)
+
+	f := &oidcFixture{
+		fake:     fake,
+		provider: provider,
+		users:    newMemoryUserRepository(),
+	}
+	f.identities = &memoryIdentityRepository{users: f.users}
+	f.service = usecase.NewOIDCService(f.users, f.identities, newMemoryCodeStore(), provider)
+	f.userSvc = usecase.NewUserService(f.users, &memoryProfileRepository{profiles: map[uint]*entity.UserProfile{}}, nil, nil, &discardAgeVerificationRepository{})
+	return f
+}
+
+// authorize 開始流程並模擬用戶在提供者頁面同意授權
+func (f *oidcFixture) authorize(t *testing.T, begin func() (*usecase.OIDCAuthorization, error)) (code, state string) {
+	authorization, err := begin()
+	require.NoError(t, err)
+
+	code, state, err = f.fake.Authorize(authorization.AuthorizationURL)
+	require.NoError(t, err)
+	require.Equal(t, authorization.State, state)
+	return code, state
+}
+
+func (f *oidcFixture) login(t *testing.T) (*entity.User, bool, error) {
+	ctx := context.Background()
+	code, state := f.authorize(t, func() (*usecase.OIDCAuthorization, error) {
+		return f.service.BeginLogin(ctx, "fake")
+	})
+	return f.service.CompleteLogin(ctx, "fake", code, state)
+}
+
+// TestOIDCRegistrationIsAtomic 測試建立外部身分連結失敗時不留下無法登入的用戶
+func TestOIDCRegistrationIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	f := setupOIDC(t)
+	f.fake.SetUser(oidctest.User{Subject: "sub-789", Email: "omar@example.com", EmailVerified: true})
+
+	f.identities.failCreate = true
+	_, _, err := f.login(t)
+	require.Error(t, err)
+	_, err = f.users.GetByEmail(ctx, "omar@example.com")
+	assert.Error(t, err, "交易失敗時不建立用戶")
+
+	f.identities.failCreate = false
+	user, created, err := f.login(t)
+	require.NoError(t, err)
+	assert.True(t, created, "重試時以相同信箱建立帳號")
+	identities, err := f.identities.ListByUserID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Len(t, identities, 1)
+}
+
+// TestOIDCLoginCreatesPendingAccount 測試首次外部登入建立待補填出生日期的帳號，補填前不符合使用條件
+func TestOIDCLoginCreatesPendingAccount(t *testing.T) {
+	ctx := context.Background()
+	f := setupOIDC(t)
+	f.fake.SetUser(oidctest.User{Subject: "sub-123", Email: "Nina@Example.com", EmailVerified: true, Name: "Nina"})
+
+	authorization, err := f.service.BeginLogin(ctx, "fake")
+	require.NoError(t, err)
+	authURL, err := url.Parse(authorization.AuthorizationURL)
+	require.NoError(t, err)
+	assert.Equal(t, "S256", authURL.Query().Get("code_challenge_method"))
+	assert.NotEmpty(t, authURL.Query().Get("nonce"))
+	assert.NotContains(t, authorization.AuthorizationURL, "code_verifier", "PKCE 驗證碼不可離開伺服器")
+
+	user, created, err := f.login(t)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "nina@example.com", user.Email)
+	assert.True(t, user.EmailVerified, "提供者已驗證的信箱直接標記為已驗證")
+	assert.Empty(t, user.PasswordHash)
+	assert.True(t, user.RegistrationPending)
+	assert.False(t, user.IsEligible(), "補填出生日期前不符合使用條件")
+
+	// 登入回應不顯示佔位出生日期推算的年齡
+	auth := usecase.NewAuthService(f.userSvc, &fakeTokenIssuer{}, 15*time.Minute, 7*24*time.Hour)
+	result, err := auth.LoginWithIdentity(ctx, user, usecase.ClientInfo{})
+	require.NoError(t, err)
+	assert.True(t, result.User.RegistrationPending)
+	assert.Zero(t, result.User.Age)
+
+	// 未成年不可完成註冊
+	_, err = f.userSvc.CompleteRegistration(ctx, user.ID, &usecase.CompleteRegistrationRequest{
+		BirthDate: time.Now().AddDate(-17, 0, 0), DisplayName: "Nina", Gender: "female",
+	})
+	require.Error(t, err)
+	assert.True(t, user.RegistrationPending)
+
+	response, err := f.userSvc.CompleteRegistration(ctx, user.ID, &usecase.CompleteRegistrationRequest{
+		BirthDate: time.Date(1995, 6, 1, 0, 0, 0, 0, time.UTC), DisplayName: "Nina", Gender: "female",
+	})
+	require.NoError(t, err)
+	assert.False(t, response.RegistrationPending)
+	assert.NotZero(t, response.Age)
+	require.NotNil(t, response.Profile)
+	assert.Equal(t, "Nina", response.Profile.DisplayName)
+
+	user.IsVerified = true
+	assert.True(t, user.IsEligible())
+
+	_, err = f.userSvc.CompleteRegistration(ctx, user.ID, &usecase.CompleteRegistrationRequest{
+		BirthDate: time.Date(1995, 6, 1, 0, 0, 0, 0, time.UTC), DisplayName: "Nina", Gender: "female",
+	})
+	assert.ErrorIs(t, err, usecase.ErrRegistrationCompleted)
+
+	// 再次登入沿用同一帳號
+	again, created, err := f.login(t)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, user.ID, again.ID)
+	assert.Len(t, f.identities.identities, 1)
+}
+
+// TestOIDCStateIsSingleUse 測試授權狀態只能使用一次，偽造或用途不符的狀態一律拒絕
+func TestOIDCStateIsSingleUse(t *testing.T) {
+	ctx := context.Background()
+	f := setupOIDC(t)
+	f.fake.SetUser(oidctest.User{Subject: "sub-1", Email: "omar@example.com", EmailVerified: true})
+
+	code, state := f.authorize(t, func() (*usecase.OIDCAuthorization, error) {
+		return f.service.BeginLogin(ctx, "fake")
+	})
+
+	_, _, err := f.service.CompleteLogin(ctx, "fake", code, "forged-state")
+	assert.ErrorIs(t, err, usecase.ErrOIDCInvalidState)
+
+	_, _, err = f.service.CompleteLogin(ctx, "unknown", code, state)
+	assert.ErrorIs(t, err, usecase.ErrOIDCProviderNotFound)
+
+	_, _, err = f.service.CompleteLogin(ctx, "fake", code, state)
+	require.NoError(t, err)
+
+	_, _, err = f.service.CompleteLogin(ctx, "fake", code, state)
+	assert.ErrorIs(t, err, usecase.ErrOIDCInvalidState, "重送相同狀態應被拒絕")
+
+	// 連結流程的狀態不可用於登入
+	code, state = f.authorize(t, func() (*usecase.OIDCAuthorization, error) {
+		return f.service.BeginLink(ctx, 1, "fake")
+	})
+	_, _, err = f.service.CompleteLogin(ctx, "fake", code, state)
+	assert.ErrorIs(t, err, usecase.ErrOIDCInvalidState)
+}
+
+// TestOIDCProviderVerifiesPKCEAndClient 測試授權碼交換必須提供正確的 PKCE 驗證碼與用戶端密鑰
+func TestOIDCProviderVerifiesPKCEAndClient(t *testing.T) {
+	ctx := context.Background()
+	f := setupOIDC(t)
+	f.fake.SetUser(oidctest.User{Subject: "sub-2", Email: "pia@example.com"})
+
+	// S256("verifier-verifier-verifier-verifier-verifier")
+	challenge := "fD3GLJ_KzCDB0w6uDOSFNNdflrHwtv8Cizh6P5pJkfQ"
+	authURL, err := f.provider.AuthorizationURL(ctx, "state", "nonce", challenge)
+	require.NoError(t, err)
+
+	code, _, err := f.fake.Authorize(authURL)
+	require.NoError(t, err)
+	_, err = f.provider.Exchange(ctx, code, "some-other-verifier")
+	assert.Error(t, err, "PKCE 驗證碼不符")
+
+	wrongClient, err := oidc.NewProvider(oidc.ProviderConfig{
+		Name:         "fake",
+		Issuer:       f.fake.Issuer(),
+		ClientID:     "dating-app",
+		ClientSecret: "wrong-secret",
+		RedirectURL:  "http://app.test/oidc/callback",
+	}, f.fake.Client())
+	require.NoError(t, err)
+	authURL, err = wrongClient.AuthorizationURL(ctx, "state", "nonce", challenge)
+	require.NoError(t, err)
+	code, _, err = f.fake.Authorize(authURL)
+	require.NoError(t, err)
+	_, err = wrongClient.Exchange(ctx, code, "verifier-verifier-verifier-verifier-verifier")
+	assert.Error(t, err, "用戶端密鑰錯誤")
+}
+
+// TestOIDCDoesNotTakeOverExistingEmail 測試外部帳號信箱已註冊時不自動連結
+func TestOIDCDoesNotTakeOverExistingEmail(t *testing.T) {
+	f := setupOIDC(t)
+	createUserWithRole(t, f.users, "quinn@example.com", entity.RoleUser)
+	f.fake.SetUser(oidctest.User{Subject: "attacker", Email: "QUINN@example.com", EmailVerified: true})
+
+	_, _, err := f.login(t)
+	assert.ErrorIs(t, err, usecase.ErrOIDCEmailInUse)
+	assert.Empty(t, f.identities.identities)
+
+	f.fake.SetUser(oidctest.User{Subject: "no-email"})
+	_, _, err = f.login(t)
+	assert.ErrorIs(t, err, usecase.ErrOIDCEmailRequired)
+}
+
+// TestOIDCLinkAndUnlink 測試已登入用戶連結與解除外部帳號，且不可移除唯一的登入方式
+func TestOIDCLinkAndUnlink(t *testing.T) {
+	ctx := context.Background()
+	f := setupOIDC(t)
+
+	owner := createUserWithRole(t, f.users, "rosa@example.com", entity.RoleUser)
+	owner.PasswordHash = "hashed"
+	other := createUserWithRole(t, f.users, "sam@example.com", entity.RoleUser)
+
+	f.fake.SetUser(oidctest.User{Subject: "rosa-sub", Email: "rosa@work.example"})
+	code, state := f.authorize(t, func() (*usecase.OIDCAuthorization, error) {
+		return f.service.BeginLink(ctx, owner.ID, "fake")
+	})
+
+	// 他人不可使用此連結流程
+	_, err := f.service.CompleteLink(ctx, other.ID, "fake", code, state)
+	assert.ErrorIs(t, err, usecase.ErrOIDCInvalidState)
+
+	code, state = f.authorize(t, func() (*usecase.OIDCAuthorization, error) {
+		return f.service.BeginLink(ctx, owner.ID, "fake")
+	})
+	identity, err := f.service.CompleteLink(ctx, owner.ID, "fake", code, state)
+	require.NoError(t, err)
+	assert.Equal(t, owner.ID, identity.UserID)
+
+	// 連結後可直接以外部帳號登入
+	user, created, err := f.login(t)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, owner.ID, user.ID)
+
+	// 同一外部帳號不可再連結給其他用戶
+	code, state = f.authorize(t, func() (*usecase.OIDCAuthorization, error) {
+		return f.service.BeginLink(ctx, other.ID, "fake")
+	})
+	_, err = f.service.CompleteLink(ctx, other.ID, "fake", code, state)
+	assert.ErrorIs(t, err, usecase.ErrOIDCIdentityLinked)
+
+	require.NoError(t, f.service.Unlink(ctx, owner.ID, "fake"))
+	assert.ErrorIs(t, f.service.Unlink(ctx, owner.ID, "fake"), usecase.ErrIdentityNotFound)
+
+	// 只以外部帳號註冊的用戶不可解除唯一的登入方式
+	f.fake.SetUser(oidctest.User{Subject: "tess-sub", Email: "tess@example.com", EmailVerified: true})
+	tess, _, err := f.login(t)
+	require.NoError(t, err)
+	assert.ErrorIs(t, f.service.Unlink(ctx, tess.ID, "fake"), usecase.ErrLastLoginMethod)
+}
+
+// TestOIDCBlockedAccountCannotLogin 測試停權帳號無法以外部帳號登入
+func TestOIDCBlockedAccountCannotLogin(t *testing.T) {
+	f := setupOIDC(t)
+	f.fake.SetUser(oidctest.User{Subject: "uma-sub", Email: "uma@example.com", EmailVerified: true})
+
+	user, _, err := f.login(t)
+	require.NoError(t, err)
+
+	user.AccountStatus = entity.AccountStatusBanned
+	_, _, err = f.login(t)
+	assert.ErrorIs(t, err, usecase.ErrAccountBanned)
+}
@@ -0,0 +1,118 @@
+package unit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/infrastructure/breach"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPasswordStrengthEstimate 測試常見密碼與可預測的組合評分偏低，隨機的長密碼評分偏高
+func TestPasswordStrengthEstimate(t *testing.T) {
+	weak := []string{"password", "qwertyuiop", "Summer2024!", "P@ssw0rd!", "abcabcabcabc", "1qaz2wsx3edc"}
+	for _, password := range weak {
+		assert.Less(t, usecase.EstimatePasswordStrength(password).Score, 3, password)
+	}
+
+	strong := []string{"Violet-Harbor-72", "Sunset-Pizza-Galaxy", "correct horse battery staple"}
+	for _, password := range strong {
+		assert.Equal(t, 4, usecase.EstimatePasswordStrength(password).Score, password)
+	}
+
+	// 個人資料視為已知單字
+	assert.Less(t,
+		usecase.EstimatePasswordStrength("erinsmith1990", "erinsmith").GuessesLog10,
+		usecase.EstimatePasswordStrength("erinsmith1990").GuessesLog10,
+	)
+}
+
+// TestPasswordPolicyRules 測試長度、字元類別、個人資料與強度規則
+func TestPasswordPolicyRules(t *testing.T) {
+	validator := usecase.NewDefaultPasswordValidator()
+	email, displayName := "erin.smith@example.com", "Erin"
+
+	rejected := map[string]string{
+		"Ab1!":                     "長度不足",
+		"violetharborlantern":      "只有一種字元類別",
+		"Smith-Violet-72":          "包含 Email 帳號名稱",
+		"Violet-Erin-Harbor":       "包含顯示名稱",
+		"Summer2024!":              "常見單字加年份",
+		strings.Repeat("Ab1-", 19): "超過 bcrypt 上限",
+	}
+	for password, reason := range rejected {
+		assert.ErrorIs(t, validator.Validate(password, email, displayName), usecase.ErrWeakPassword, reason)
+	}
+
+	assert.NoError(t, validator.Validate("Violet-Harbor-72", email, displayName))
+
+	// 規則可調整
+	relaxed := usecase.NewPasswordValidator(usecase.PasswordPolicy{MinLength: 12, MinCharClasses: 1}, nil)
+	assert.ErrorIs(t, relaxed.Validate("Violet-72"), usecase.ErrWeakPassword)
+	assert.NoError(t, relaxed.Validate("violetharborlantern"))
+}
+
+// TestBreachedPasswordList 測試以本地雜湊前綴清單比對外洩密碼
+func TestBreachedPasswordList(t *testing.T) {
+	bundled := breach.NewChecker(breach.NewBundledSource())
+	for _, password := range []string{"P@ssw0rd", "Password1", "iloveyou", "Dragon2024"} {
+		breached, err := bundled.IsBreached(password)
+		require.NoError(t, err)
+		assert.True(t, breached, password)
+	}
+	breached, err := bundled.IsBreached("Violet-Harbor-72")
+	require.NoError(t, err)
+	assert.False(t, breached)
+
+	// 完整清單以 HIBP 下載工具的格式依前綴分檔：「Violet-Harbor-72」的 SHA-1 前綴為 76790
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "76790.txt"),
+		[]byte("75D07EBB1898A7AC8F889FCFDF44589E5D6:1\r\n6DB6C1B72C5C8C52F0ED46A5E40EBB1AA47:3\r\n"), 0o600))
+	source, err := breach.NewDirectorySource(dir)
+	require.NoError(t, err)
+	checker := breach.NewChecker(breach.NewBundledSource(), source)
+
+	breached, err = checker.IsBreached("Violet-Harbor-72")
+	require.NoError(t, err)
+	assert.True(t, breached)
+	breached, err = checker.IsBreached("Sunset-Pizza-Galaxy")
+	require.NoError(t, err)
+	assert.False(t, breached, "沒有對應前綴檔案時視為未外洩")
+
+	_, err = breach.NewDirectorySource(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+
+	validator := usecase.NewPasswordValidator(usecase.DefaultPasswordPolicy(), checker)
+	assert.ErrorIs(t, validator.Validate("Violet-Harbor-72"), usecase.ErrBreachedPassword)
+	assert.NoError(t, validator.Validate("Sunset-Pizza-Galaxy"))
+}
+
+// TestPasswordPolicyAppliedOnReset 測試重設與變更密碼套用相同規則，且不合格時不消耗權杖
+func TestPasswordPolicyAppliedOnReset(t *testing.T) {
+	ctx := context.Background()
+	service, _, repo, _, mailer, user := setupPasswordService(t)
+
+	profiles := &memoryProfileRepository{profiles: map[uint]*entity.UserProfile{
+		user.ID: {UserID: user.ID, DisplayName: "Roberta"},
+	}}
+	service.SetProfileRepository(profiles)
+
+	require.NoError(t, service.RequestPasswordReset(ctx, user.Email))
+	token := resetTokenPattern.FindStringSubmatch(mailer.last().Body)[1]
+
+	assert.ErrorIs(t, service.ResetPassword(ctx, token, "Harbor-Roberta-72"), usecase.ErrWeakPassword, "不可包含顯示名稱")
+	assert.ErrorIs(t, service.ResetPassword(ctx, token, "Bob-Violet-Harbor"), usecase.ErrWeakPassword, "不可包含 Email 帳號名稱")
+	require.NoError(t, service.ResetPassword(ctx, token, "Violet-Harbor-72"), "權杖在密碼不合格時不應被消耗")
+
+	stored, _ := repo.GetByID(ctx, user.ID)
+	assert.True(t, passwordMatches(stored, "Violet-Harbor-72"))
+
+	assert.ErrorIs(t, service.ChangePassword(ctx, user.ID, "", "Violet-Harbor-72", "Password1!"), usecase.ErrWeakPassword)
+}
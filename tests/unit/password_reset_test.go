@@ -0,0 +1,129 @@
+package unit_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var resetTokenPattern = regexp.MustCompile(`密碼重設權杖為：([0-9a-f]{64})`)
+
+// setupPasswordService 建立測試用的密碼管理服務與用戶
+func setupPasswordService(t *testing.T) (*usecase.PasswordService, *usecase.AuthService, *memoryUserRepository, *memoryCodeStore, *recordingMailer, *entity.User) {
+	repo := newMemoryUserRepository()
+	codes := newMemoryCodeStore()
+	mailer := &recordingMailer{}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	user := &entity.User{
+		Email:        "bob@example.com",
+		PasswordHash: string(hash),
+		BirthDate:    time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		IsActive:     true,
+	}
+	require.NoError(t, repo.Create(context.Background(), user))
+
+	authService, _, _ := newTestAuthService()
+	service := usecase.NewPasswordService(repo, mailer, codes, authService)
+	return service, authService, repo, codes, mailer, user
+}
+
+func passwordMatches(user *entity.User, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) == nil
+}
+
+// TestPasswordResetTokenSingleUse 測試重設權杖只能使用一次且不以明文儲存
+func TestPasswordResetTokenSingleUse(t *testing.T) {
+	ctx := context.Background()
+	service, authService, repo, codes, mailer, user := setupPasswordService(t)
+
+	session, err := authService.StartSession(ctx, user.ID, user.Email, "user", usecase.ClientInfo{})
+	require.NoError(t, err)
+
+	require.NoError(t, service.RequestPasswordReset(ctx, "BOB@example.com"))
+	match := resetTokenPattern.FindStringSubmatch(mailer.last().Body)
+	require.Len(t, match, 2, "重設信應包含重設權杖")
+	token := match[1]
+
+	for key, stored := range codes.codes {
+		assert.NotContains(t, key, token, "重設權杖不應以明文儲存")
+		assert.NotEqual(t, token, stored, "重設權杖不應以明文儲存")
+	}
+
+	// 密碼不合格時不應消耗權杖
+	assert.Error(t, service.ResetPassword(ctx, token, "short"))
+
+	require.NoError(t, service.ResetPassword(ctx, token, "new-password-1"))
+	stored, _ := repo.GetByID(ctx, user.ID)
+	assert.True(t, passwordMatches(stored, "new-password-1"))
+
+	assert.ErrorIs(t, service.ResetPassword(ctx, token, "new-password-2"), usecase.ErrInvalidResetToken, "權杖使用後即失效")
+
+	revoked, _ := authService.IsTokenRevoked("", session.SessionID)
+	assert.True(t, revoked, "重設密碼後所有會話都應撤銷")
+	assert.Equal(t, "您的密碼已變更", mailer.last().Subject)
+}
+
+// TestPasswordResetNewRequestInvalidatesPrevious 測試重新申請會使舊權杖失效
+func TestPasswordResetNewRequestInvalidatesPrevious(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, codes, mailer, user := setupPasswordService(t)
+
+	require.NoError(t, service.RequestPasswordReset(ctx, user.Email))
+	first := resetTokenPattern.FindStringSubmatch(mailer.last().Body)[1]
+
+	// 頻率限制靜默處理，不會寄出新信
+	require.NoError(t, service.RequestPasswordReset(ctx, user.Email))
+	assert.Len(t, mailer.sent, 1)
+
+	// 模擬冷卻時間結束
+	delete(codes.counters, "password_reset_request:1")
+	require.NoError(t, service.RequestPasswordReset(ctx, user.Email))
+	second := resetTokenPattern.FindStringSubmatch(mailer.last().Body)[1]
+
+	assert.ErrorIs(t, service.ResetPassword(ctx, first, "new-password-1"), usecase.ErrInvalidResetToken)
+	assert.NoError(t, service.ResetPassword(ctx, second, "new-password-1"))
+}
+
+// TestPasswordResetDoesNotLeakAccounts 測試忘記密碼不洩漏帳號是否存在
+func TestPasswordResetDoesNotLeakAccounts(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, _, mailer, _ := setupPasswordService(t)
+
+	assert.NoError(t, service.RequestPasswordReset(ctx, "nobody@example.com"))
+	assert.Empty(t, mailer.sent)
+}
+
+// TestChangePasswordRevokesOtherSessions 測試變更密碼驗證原密碼並撤銷其他會話
+func TestChangePasswordRevokesOtherSessions(t *testing.T) {
+	ctx := context.Background()
+	service, authService, repo, _, _, user := setupPasswordService(t)
+
+	current, err := authService.StartSession(ctx, user.ID, user.Email, "user", usecase.ClientInfo{})
+	require.NoError(t, err)
+	other, err := authService.StartSession(ctx, user.ID, user.Email, "user", usecase.ClientInfo{})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, service.ChangePassword(ctx, user.ID, current.SessionID, "wrong-password", "new-password-1"), usecase.ErrIncorrectPassword)
+	assert.ErrorIs(t, service.ChangePassword(ctx, user.ID, current.SessionID, "old-password", "old-password"), usecase.ErrPasswordUnchanged)
+
+	require.NoError(t, service.ChangePassword(ctx, user.ID, current.SessionID, "old-password", "new-password-1"))
+	stored, _ := repo.GetByID(ctx, user.ID)
+	assert.True(t, passwordMatches(stored, "new-password-1"))
+	assert.False(t, passwordMatches(stored, "old-password"))
+
+	revoked, _ := authService.IsTokenRevoked("", current.SessionID)
+	assert.False(t, revoked, "目前會話應保留")
+	revoked, _ = authService.IsTokenRevoked("", other.SessionID)
+	assert.True(t, revoked, "其他會話應撤銷")
+}
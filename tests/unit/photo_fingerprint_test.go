@@ -0,0 +1,189 @@
+package unit_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"sort"
+	"testing"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/infrastructure/imaging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/draw"
+)
+
+// memoryBlockedImageRepository 測試用禁用圖片清單儲存庫
+type memoryBlockedImageRepository struct {
+	images map[uint]*entity.BlockedImage
+	nextID uint
+}
+
+func (r *memoryBlockedImageRepository) Create(ctx context.Context, image *entity.BlockedImage) error {
+	r.nextID++
+	image.ID = r.nextID
+	r.images[image.ID] = image
+	return nil
+}
+
+func (r *memoryBlockedImageRepository) GetByID(ctx context.Context, id uint) (*entity.BlockedImage, error) {
+	return r.images[id], nil
+}
+
+func (r *memoryBlockedImageRepository) List(ctx context.Context, limit, offset int) ([]*entity.BlockedImage, int64, error) {
+	var images []*entity.BlockedImage
+	for _, image := range r.images {
+		images = append(images, image)
+	}
+	return images, int64(len(images)), nil
+}
+
+func (r *memoryBlockedImageRepository) Delete(ctx context.Context, id uint) error {
+	delete(r.images, id)
+	return nil
+}
+
+func (r *memoryBlockedImageRepository) FindSimilar(ctx context.Context, hash uint64, maxDistance int) ([]*entity.BlockedImage, error) {
+	var images []*entity.BlockedImage
+	for _, image := range r.images {
+		if entity.HammingDistance(hash, image.PerceptualHash) <= maxDistance {
+			images = append(images, image)
+		}
+	}
+	sort.Slice(images, func(i, j int) bool {
+		return entity.HammingDistance(hash, images[i].PerceptualHash) < entity.HammingDistance(hash, images[j].PerceptualHash)
+	})
+	return images, nil
+}
+
+// wavePattern 產生平滑的波紋圖片，mirror 為 true 時左右翻轉
+func wavePattern(width, height int, mirror bool) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			fx := float64(x) / float64(width)
+			if mirror {
+				fx = 1 - fx
+			}
+			fy := float64(y) / float64(height)
+			v := uint8(128 + 100*math.Sin(fx*7)*math.Cos(fy*5))
+			img.Set(x, y, color.RGBA{R: v, G: v / 2, B: 255 - v, A: 255})
+		}
+	}
+	return img
+}
+
+func encodePNGBytes(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// TestDifferenceHashSurvivesRecompression 測試縮放與重新壓縮後雜湊接近，不同圖片雜湊差異大
+func TestDifferenceHashSurvivesRecompression(t *testing.T) {
+	original := wavePattern(800, 600, false)
+	hash := imaging.DifferenceHash(original)
+
+	// 縮小一半並以低品質 JPEG 重新壓縮
+	resized := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	draw.BiLinear.Scale(resized, resized.Bounds(), original, original.Bounds(), draw.Src, nil)
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 40}))
+	recompressed, err := jpeg.Decode(&buf)
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, entity.HammingDistance(hash, imaging.DifferenceHash(recompressed)), 4)
+	assert.Greater(t, entity.HammingDistance(hash, imaging.DifferenceHash(wavePattern(800, 600, true))), usecase.DefaultDuplicateDistance)
+}
+
+// TestPhotoUploadFlagsDuplicates 測試上傳他人照片或禁用清單中的圖片會被標記並寫入自動審核日誌
+func TestPhotoUploadFlagsDuplicates(t *testing.T) {
+	ctx := context.Background()
+	service, photos, _ := setupPhotoUpload(t, 0)
+	blocked := &memoryBlockedImageRepository{images: map[uint]*entity.BlockedImage{}}
+	users := newMemoryUserRepository()
+	logs := &memoryModerationRepository{}
+	fingerprints := usecase.NewPhotoFingerprintService(photos, blocked, users, logs)
+	service.SetPhotoFingerprints(fingerprints)
+
+	moderator := createUserWithRole(t, users, "mod@example.com", entity.RoleModerator)
+	member := createUserWithRole(t, users, "member@example.com", entity.RoleUser)
+
+	original := encodePNGBytes(t, wavePattern(800, 600, false))
+	other := encodePNGBytes(t, wavePattern(800, 600, true))
+
+	first, err := service.UploadPhoto(ctx, 101, &usecase.PhotoUpload{Data: original})
+	require.NoError(t, err)
+	require.NotNil(t, first.PerceptualHash)
+	assert.Nil(t, first.FlagScore)
+
+	again, err := service.UploadPhoto(ctx, 101, &usecase.PhotoUpload{Data: original})
+	require.NoError(t, err)
+	assert.Nil(t, again.FlagScore, "同一用戶重複上傳自己的照片不標記")
+
+	stolen, err := service.UploadPhoto(ctx, 102, &usecase.PhotoUpload{Data: original})
+	require.NoError(t, err)
+	assert.Equal(t, "fake_profile", stolen.FlagReason)
+	require.NotNil(t, stolen.FlagScore)
+	assert.Equal(t, 1.0, *stolen.FlagScore)
+
+	require.Len(t, logs.logs, 1)
+	flagged := logs.logs[0]
+	assert.Equal(t, "photo", flagged.ContentType)
+	assert.Equal(t, stolen.ID, flagged.ContentID)
+	assert.Equal(t, string(repository.ModerationActionFlagged), flagged.Action)
+	assert.Equal(t, "fake_profile", flagged.Reason)
+	assert.True(t, flagged.IsAutomatic)
+	assert.Nil(t, flagged.ModeratorID)
+	assert.Equal(t, 1.0, *flagged.Confidence)
+	assert.Contains(t, flagged.Notes, "用戶 101")
+
+	unrelated, err := service.UploadPhoto(ctx, 103, &usecase.PhotoUpload{Data: other})
+	require.NoError(t, err)
+	assert.Nil(t, unrelated.FlagScore, "不同的圖片不標記")
+
+	// 禁用清單只有審核員可以維護，刪除來源照片後仍會比對
+	_, err = fingerprints.BlockImage(ctx, &usecase.BlockImageRequest{PhotoID: unrelated.ID, ModeratorID: member.ID, Reason: "盜用網紅照片"})
+	assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+	_, err = fingerprints.BlockImage(ctx, &usecase.BlockImageRequest{PhotoID: unrelated.ID, ModeratorID: moderator.ID, Reason: " "})
+	assert.Error(t, err, "必須提供原因")
+	entry, err := fingerprints.BlockImage(ctx, &usecase.BlockImageRequest{PhotoID: unrelated.ID, ModeratorID: moderator.ID, Reason: "盜用網紅照片"})
+	require.NoError(t, err)
+	require.NoError(t, service.DeletePhoto(ctx, 103, unrelated.ID))
+
+	reused, err := service.UploadPhoto(ctx, 104, &usecase.PhotoUpload{Data: other})
+	require.NoError(t, err)
+	require.NotNil(t, reused.FlagScore)
+	assert.Contains(t, logs.logs[len(logs.logs)-1].Notes, "禁用圖片")
+
+	queue, err := usecase.NewPhotoModerationService(photos, users, logs).GetQueue(ctx, &usecase.PhotoQueueRequest{ModeratorID: moderator.ID, OnlyFlagged: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), queue.Total, "標記的照片可在審核佇列中篩選")
+
+	assert.ErrorIs(t, fingerprints.UnblockImage(ctx, member.ID, entry.ID), usecase.ErrPermissionDenied)
+	require.NoError(t, fingerprints.UnblockImage(ctx, moderator.ID, entry.ID))
+	assert.ErrorIs(t, fingerprints.UnblockImage(ctx, moderator.ID, entry.ID), usecase.ErrBlockedImageNotFound)
+}
+
+// TestHashBandsSplitFromLowBits 測試感知雜湊由低位元起切成四個區段
+func TestHashBandsSplitFromLowBits(t *testing.T) {
+	assert.Equal(t, [entity.HashBandCount]uint16{0xcdef, 0x89ab, 0x4567, 0x0123}, entity.HashBands(0x0123456789abcdef))
+
+	hash := uint64(0x0123456789abcdef)
+	photo := &entity.Photo{PerceptualHash: &hash}
+	photo.SyncHashBands()
+	require.NotNil(t, photo.HashBand3)
+	assert.Equal(t, uint16(0x0123), *photo.HashBand3)
+
+	photo.PerceptualHash = nil
+	photo.SyncHashBands()
+	assert.Nil(t, photo.HashBand0)
+}
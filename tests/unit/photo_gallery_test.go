@@ -0,0 +1,111 @@
+package unit_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/infrastructure/imaging"
+	"golang_dev_docker/infrastructure/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedTierResolver 測試用訂閱方案查詢，未列出的用戶沒有訂閱
+type fixedTierResolver map[uint]string
+
+func (r fixedTierResolver) SubscriptionTier(ctx context.Context, userID uint) (string, error) {
+	return r[userID], nil
+}
+
+// TestPhotoGalleryManagement 測試照片數量依訂閱方案限制、主要照片必須已通過審核，以及排序、說明與刪除後遞補主要照片
+func TestPhotoGalleryManagement(t *testing.T) {
+	ctx := context.Background()
+	users := newMemoryUserRepository()
+	photos := newMemoryPhotoRepository()
+	tiers := fixedTierResolver{}
+
+	service := usecase.NewUserService(users, nil, photos, nil, nil)
+	files := usecase.NewFileService(storage.NewLocalBlobStore(t.TempDir()), "test-secret", "http://localhost:8080", 0)
+	service.SetPhotoProcessing(imaging.NewProcessor(imaging.DefaultConfig()), files, 0)
+	service.SetPhotoLimits(usecase.PhotoLimits{Default: 3, Tiers: map[string]int{"premium": 4}}, tiers)
+	moderation := usecase.NewPhotoModerationService(photos, users, &memoryModerationRepository{})
+
+	owner := createUserWithRole(t, users, "owner@example.com", entity.RoleUser)
+	other := createUserWithRole(t, users, "other@example.com", entity.RoleUser)
+	moderator := createUserWithRole(t, users, "mod@example.com", entity.RoleModerator)
+	review := func(photoID uint, approve bool) *entity.Photo {
+		photo, err := moderation.ReviewPhoto(ctx, &usecase.ReviewPhotoRequest{PhotoID: photoID, ReviewerID: moderator.ID, Approve: approve, Notes: "不符規範"})
+		require.NoError(t, err)
+		return photo
+	}
+
+	data := encodePNGBytes(t, wavePattern(120, 90, false))
+	first, err := service.UploadPhoto(ctx, owner.ID, &usecase.PhotoUpload{Data: data, Caption: "  海邊  "})
+	require.NoError(t, err)
+	assert.Equal(t, "海邊", first.Caption)
+	assert.False(t, first.IsMain, "待審核的照片不可作為主要照片")
+	_, err = service.UploadPhoto(ctx, owner.ID, &usecase.PhotoUpload{Data: data, Type: entity.PhotoTypeGallery})
+	require.NoError(t, err)
+	_, err = service.UploadPhoto(ctx, owner.ID, &usecase.PhotoUpload{Data: data})
+	require.NoError(t, err)
+	_, err = service.UploadPhoto(ctx, owner.ID, &usecase.PhotoUpload{Data: data, Caption: strings.Repeat("字", entity.MaxPhotoCaptionLength+1)})
+	assert.ErrorIs(t, err, usecase.ErrPhotoLimitReached, "未訂閱套用預設上限")
+
+	tiers[owner.ID] = "premium"
+	assert.Equal(t, 4, service.PhotoLimit(ctx, owner.ID))
+	_, err = service.UploadPhoto(ctx, owner.ID, &usecase.PhotoUpload{Data: data, Caption: strings.Repeat("字", entity.MaxPhotoCaptionLength+1)})
+	assert.Error(t, err, "說明過長")
+	_, err = service.UploadPhoto(ctx, owner.ID, &usecase.PhotoUpload{Data: data})
+	require.NoError(t, err, "訂閱方案提高上限")
+
+	// 主要照片必須是已通過審核的個人檔案照片，通過審核時遞補空缺
+	assert.ErrorIs(t, service.SetPrimaryPhoto(ctx, owner.ID, 1), usecase.ErrPhotoCannotBeMain)
+	assert.False(t, review(2, true).IsMain, "相簿照片不可作為主要照片")
+	assert.True(t, review(3, true).IsMain, "第一張通過審核的個人檔案照片成為主要照片")
+	assert.False(t, review(1, true).IsMain, "已有主要照片時不變更")
+	assert.ErrorIs(t, service.SetPrimaryPhoto(ctx, owner.ID, 2), usecase.ErrPhotoCannotBeMain)
+	assert.ErrorIs(t, service.SetPrimaryPhoto(ctx, other.ID, 1), usecase.ErrPhotoForbidden)
+	require.NoError(t, service.SetPrimaryPhoto(ctx, owner.ID, 1))
+	assert.True(t, photos.photos[1].IsMain)
+	assert.False(t, photos.photos[3].IsMain)
+
+	// 排序必須包含所有照片且不可重複
+	_, err = service.ReorderPhotos(ctx, owner.ID, []uint{4, 3, 2})
+	assert.ErrorIs(t, err, usecase.ErrInvalidPhotoOrder)
+	_, err = service.ReorderPhotos(ctx, owner.ID, []uint{4, 3, 2, 2})
+	assert.ErrorIs(t, err, usecase.ErrInvalidPhotoOrder)
+	reordered, err := service.ReorderPhotos(ctx, owner.ID, []uint{4, 3, 2, 1})
+	require.NoError(t, err)
+	var order []uint
+	for _, photo := range reordered {
+		order = append(order, photo.ID)
+	}
+	assert.Equal(t, []uint{4, 3, 2, 1}, order)
+
+	// 主要照片改為相簿照片時，依新順序由下一張符合條件的照片遞補
+	caption := "日落"
+	gallery := entity.PhotoTypeGallery
+	updated, err := service.UpdatePhoto(ctx, owner.ID, 1, &usecase.UpdatePhotoRequest{Caption: &caption, Type: &gallery})
+	require.NoError(t, err)
+	assert.Equal(t, "日落", updated.Caption)
+	assert.False(t, updated.IsMain)
+	assert.True(t, photos.photos[3].IsMain)
+	private := entity.PhotoTypePrivate
+	_, err = service.UpdatePhoto(ctx, owner.ID, 2, &usecase.UpdatePhotoRequest{Type: &private})
+	assert.ErrorIs(t, err, usecase.ErrPhotoTypeChange)
+	_, err = service.UpdatePhoto(ctx, other.ID, 2, &usecase.UpdatePhotoRequest{Caption: &caption})
+	assert.ErrorIs(t, err, usecase.ErrPhotoForbidden)
+
+	// 刪除主要照片時遞補，主要照片被拒絕且沒有其他符合條件的照片時取消設定
+	review(4, true)
+	require.NoError(t, service.DeletePhoto(ctx, owner.ID, 3))
+	assert.True(t, photos.photos[4].IsMain)
+	assert.False(t, review(4, false).IsMain)
+	for _, photo := range photos.photos {
+		assert.False(t, photo.IsMain, "照片 %d", photo.ID)
+	}
+}
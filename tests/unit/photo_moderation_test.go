@@ -0,0 +1,157 @@
+package unit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+	"golang_dev_docker/domain/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNotifier 測試用即時通知器，記錄發送給各用戶的訊息
+type recordingNotifier struct {
+	sent map[uint][]interface{}
+}
+
+func (n *recordingNotifier) SendToUser(userID uint, message interface{}) error {
+	n.sent[userID] = append(n.sent[userID], message)
+	return nil
+}
+
+func (n *recordingNotifier) BroadcastToUsers(userIDs []uint, message interface{}) error {
+	for _, userID := range userIDs {
+		n.SendToUser(userID, message)
+	}
+	return nil
+}
+
+// setupPhotoModeration 建立照片審核服務與一位會員的兩張待審核照片
+func setupPhotoModeration(t *testing.T) (*usecase.PhotoModerationService, *memoryPhotoRepository, *memoryUserRepository, *memoryModerationRepository) {
+	users := newMemoryUserRepository()
+	photos := newMemoryPhotoRepository()
+	logs := &memoryModerationRepository{}
+	service := usecase.NewPhotoModerationService(photos, users, logs)
+
+	owner := createUserWithRole(t, users, "owner@example.com", entity.RoleUser)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, photos.Create(context.Background(), &entity.Photo{
+			UserID:   owner.ID,
+			Type:     entity.PhotoTypeProfile,
+			FilePath: "photos/1/a.jpg",
+			Status:   entity.PhotoStatusPending,
+		}))
+	}
+	return service, photos, users, logs
+}
+
+// TestPhotoModerationClaimAndQueue 測試認領後其他審核員看不到也無法審核該照片，逾時後回到佇列
+func TestPhotoModerationClaimAndQueue(t *testing.T) {
+	ctx := context.Background()
+	service, photos, users, _ := setupPhotoModeration(t)
+	alice := createUserWithRole(t, users, "alice@example.com", entity.RoleModerator)
+	bob := createUserWithRole(t, users, "bob@example.com", entity.RoleModerator)
+	member := createUserWithRole(t, users, "member@example.com", entity.RoleUser)
+
+	queue, err := service.GetQueue(ctx, &usecase.PhotoQueueRequest{ModeratorID: alice.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), queue.Total)
+
+	_, err = service.ClaimPhoto(ctx, 1, member.ID)
+	assert.ErrorIs(t, err, usecase.ErrPermissionDenied, "一般會員不可認領")
+	_, err = service.ClaimPhoto(ctx, 99, alice.ID)
+	assert.ErrorIs(t, err, usecase.ErrPhotoNotFound)
+
+	claimed, err := service.ClaimPhoto(ctx, 1, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, alice.ID, *claimed.ClaimedBy)
+	_, err = service.ClaimPhoto(ctx, 1, alice.ID)
+	assert.NoError(t, err, "同一審核員可重新認領以延長時間")
+
+	_, err = service.ClaimPhoto(ctx, 1, bob.ID)
+	assert.ErrorIs(t, err, usecase.ErrPhotoClaimed)
+	_, err = service.ReviewPhoto(ctx, &usecase.ReviewPhotoRequest{PhotoID: 1, ReviewerID: bob.ID, Approve: true})
+	assert.ErrorIs(t, err, usecase.ErrPhotoClaimed, "不可審核他人認領中的照片")
+
+	queue, err = service.GetQueue(ctx, &usecase.PhotoQueueRequest{ModeratorID: bob.ID})
+	require.NoError(t, err)
+	require.Len(t, queue.Photos, 1, "他人認領中的照片不在佇列中")
+	assert.Equal(t, uint(2), queue.Photos[0].ID)
+
+	queue, err = service.GetQueue(ctx, &usecase.PhotoQueueRequest{ModeratorID: alice.ID, OnlyMine: true})
+	require.NoError(t, err)
+	require.Len(t, queue.Photos, 1)
+	assert.Equal(t, uint(1), queue.Photos[0].ID)
+
+	// 認領逾時後其他審核員可再認領
+	expired := time.Now().Add(-usecase.PhotoClaimTTL - time.Minute)
+	photos.photos[1].ClaimedAt = &expired
+	_, err = service.ClaimPhoto(ctx, 1, bob.ID)
+	assert.NoError(t, err)
+
+	invalid := entity.PhotoType("selfie")
+	_, err = service.GetQueue(ctx, &usecase.PhotoQueueRequest{ModeratorID: bob.ID, Type: &invalid})
+	assert.Error(t, err)
+}
+
+// TestPhotoModerationReview 測試審核寫入日誌、拒絕時通知用戶，且公開檔案只顯示通過的照片
+func TestPhotoModerationReview(t *testing.T) {
+	ctx := context.Background()
+	service, photos, users, logs := setupPhotoModeration(t)
+	moderator := createUserWithRole(t, users, "mod@example.com", entity.RoleModerator)
+	owner, err := users.GetByID(ctx, 1)
+	require.NoError(t, err)
+
+	notifier := &recordingNotifier{sent: map[uint][]interface{}{}}
+	mailer := &recordingMailer{}
+	service.SetNotifier(notifier)
+	service.SetMailer(mailer)
+
+	// 管理員也不可審核自己的照片
+	admin := createUserWithRole(t, users, "admin@example.com", entity.RoleAdmin)
+	require.NoError(t, photos.Create(ctx, &entity.Photo{UserID: admin.ID, Status: entity.PhotoStatusPending}))
+	_, err = service.ReviewPhoto(ctx, &usecase.ReviewPhotoRequest{PhotoID: 3, ReviewerID: admin.ID, Approve: true})
+	assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+
+	approved, err := service.ReviewPhoto(ctx, &usecase.ReviewPhotoRequest{PhotoID: 1, ReviewerID: moderator.ID, Approve: true})
+	require.NoError(t, err)
+	assert.True(t, approved.IsApproved())
+	assert.Empty(t, notifier.sent)
+
+	_, err = service.ReviewPhoto(ctx, &usecase.ReviewPhotoRequest{PhotoID: 2, ReviewerID: moderator.ID, Notes: "  "})
+	assert.Error(t, err, "拒絕必須提供原因")
+	assert.True(t, photos.photos[2].IsPending(), "驗證失敗不改變照片狀態")
+
+	rejected, err := service.ReviewPhoto(ctx, &usecase.ReviewPhotoRequest{PhotoID: 2, ReviewerID: moderator.ID, Notes: "照片中沒有本人"})
+	require.NoError(t, err)
+	assert.True(t, rejected.IsRejected())
+	assert.Nil(t, rejected.ClaimedBy, "審核完成釋出認領")
+
+	require.Len(t, logs.logs, 2)
+	assert.Equal(t, "photo", logs.logs[1].ContentType)
+	assert.Equal(t, uint(2), logs.logs[1].ContentID)
+	assert.Equal(t, owner.ID, logs.logs[1].UserID)
+	assert.Equal(t, string(repository.ModerationActionRejected), logs.logs[1].Action)
+	assert.Equal(t, "照片中沒有本人", logs.logs[1].Reason)
+	assert.Equal(t, moderator.ID, *logs.logs[1].ModeratorID)
+
+	require.Len(t, notifier.sent[owner.ID], 1)
+	assert.Equal(t, "photo_rejected", notifier.sent[owner.ID][0].(map[string]interface{})["type"])
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, owner.Email, mailer.last().To)
+	assert.Contains(t, mailer.last().Body, "照片中沒有本人")
+
+	// 公開檔案只顯示通過審核的照片，本人仍可看到全部照片
+	userService := usecase.NewUserService(users, nil, photos, nil, nil)
+	public, err := userService.GetPublicPhotos(ctx, owner.ID)
+	require.NoError(t, err)
+	require.Len(t, public, 1)
+	assert.Equal(t, uint(1), public[0].ID)
+	own, err := userService.GetUserPhotos(ctx, owner.ID)
+	require.NoError(t, err)
+	assert.Len(t, own, 2)
+}
@@ -0,0 +1,281 @@
+package unit_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/infrastructure/imaging"
+	"golang_dev_docker/infrastructure/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryPhotoRepository 測試用照片儲存庫
+type memoryPhotoRepository struct {
+	repository.PhotoRepository
+	photos map[uint]*entity.Photo
+	nextID uint
+}
+
+func newMemoryPhotoRepository() *memoryPhotoRepository {
+	return &memoryPhotoRepository{photos: map[uint]*entity.Photo{}}
+}
+
+func (r *memoryPhotoRepository) Create(ctx context.Context, photo *entity.Photo) error {
+	r.nextID++
+	photo.ID = r.nextID
+	r.photos[photo.ID] = photo
+	return nil
+}
+
+func (r *memoryPhotoRepository) GetByUserID(ctx context.Context, userID uint) ([]*entity.Photo, error) {
+	var photos []*entity.Photo
+	for _, photo := range r.photos {
+		if photo.UserID == userID {
+			photos = append(photos, photo)
+		}
+	}
+	sort.Slice(photos, func(i, j int) bool { return photos[i].DisplayOrder < photos[j].DisplayOrder })
+	return photos, nil
+}
+
+func (r *memoryPhotoRepository) GetByID(ctx context.Context, id uint) (*entity.Photo, error) {
+	return r.photos[id], nil
+}
+
+func (r *memoryPhotoRepository) Update(ctx context.Context, photo *entity.Photo) error {
+	r.photos[photo.ID] = photo
+	return nil
+}
+
+func (r *memoryPhotoRepository) Delete(ctx context.Context, id uint) error {
+	delete(r.photos, id)
+	return nil
+}
+
+func (r *memoryPhotoRepository) SetPrimary(ctx context.Context, userID, photoID uint) error {
+	for _, photo := range r.photos {
+		if photo.UserID == userID {
+			photo.IsMain = photo.ID == photoID
+		}
+	}
+	return nil
+}
+
+func (r *memoryPhotoRepository) UpdateOrder(ctx context.Context, userID uint, photoOrders []struct {
+	PhotoID uint
+	Order   int
+}) error {
+	for _, order := range photoOrders {
+		if photo := r.photos[order.PhotoID]; photo != nil && photo.UserID == userID {
+			photo.DisplayOrder = order.Order
+		}
+	}
+	return nil
+}
+
+func (r *memoryPhotoRepository) GetReviewQueue(ctx context.Context, params repository.PhotoQueueParams) ([]*entity.Photo, int64, error) {
+	var photos []*entity.Photo
+	for _, photo := range r.photos {
+		if !photo.IsPending() || (params.UserID != nil && photo.UserID != *params.UserID) {
+			continue
+		}
+		claimedByOther := photo.IsClaimedByOther(params.ModeratorID, params.StaleBefore)
+		mine := photo.ClaimedBy != nil && *photo.ClaimedBy == params.ModeratorID && photo.ClaimedAt.After(params.StaleBefore)
+		if (params.OnlyMine && !mine) || (!params.IncludeClaimed && claimedByOther) || (params.OnlyFlagged && photo.FlagScore == nil) {
+			continue
+		}
+		photos = append(photos, photo)
+	}
+	sort.Slice(photos, func(i, j int) bool { return photos[i].ID < photos[j].ID })
+	return photos, int64(len(photos)), nil
+}
+
+func (r *memoryPhotoRepository) Claim(ctx context.Context, photoID, moderatorID uint, claimedAt, staleBefore time.Time) (bool, error) {
+	photo := r.photos[photoID]
+	if photo == nil || !photo.IsPending() || photo.IsClaimedByOther(moderatorID, staleBefore) {
+		return false, nil
+	}
+	photo.ClaimedBy = &moderatorID
+	photo.ClaimedAt = &claimedAt
+	return true, nil
+}
+
+func (r *memoryPhotoRepository) UpdateReview(ctx context.Context, photo *entity.Photo) error {
+	r.photos[photo.ID] = photo
+	return nil
+}
+
+func (r *memoryPhotoRepository) FindSimilar(ctx context.Context, hash uint64, maxDistance int, excludeUserID uint, limit int) ([]*entity.Photo, error) {
+	var photos []*entity.Photo
+	for _, photo := range r.photos {
+		if photo.UserID != excludeUserID && photo.PerceptualHash != nil &&
+			entity.HammingDistance(hash, *photo.PerceptualHash) <= maxDistance {
+			photos = append(photos, photo)
+		}
+	}
+	sort.Slice(photos, func(i, j int) bool {
+		return entity.HammingDistance(hash, *photos[i].PerceptualHash) < entity.HammingDistance(hash, *photos[j].PerceptualHash)
+	})
+	if limit > 0 && len(photos) > limit {
+		photos = photos[:limit]
+	}
+	return photos, nil
+}
+
+func (r *memoryPhotoRepository) Flag(ctx context.Context, photoID uint, reason string, score float64) error {
+	r.photos[photoID].FlagReason = reason
+	r.photos[photoID].FlagScore = &score
+	return nil
+}
+
+// setupPhotoUpload 建立使用真實圖片處理器與暫存上傳目錄的用戶服務
+func setupPhotoUpload(t *testing.T, maxSize int64) (*usecase.UserService, *memoryPhotoRepository, string) {
+	uploadDir := t.TempDir()
+	photos := newMemoryPhotoRepository()
+	service := usecase.NewUserService(newMemoryUserRepository(), nil, photos, nil, nil)
+	files := usecase.NewFileService(storage.NewLocalBlobStore(uploadDir), "test-secret", "http://localhost:8080", 0)
+	service.SetPhotoProcessing(imaging.NewProcessor(imaging.DefaultConfig()), files, maxSize)
+	return service, photos, uploadDir
+}
+
+// jpegWithExif 產生含 EXIF 方向與 GPS 資料的 JPEG
+func jpegWithExif(t *testing.T, width, height int, orientation uint16) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var encoded bytes.Buffer
+	require.NoError(t, jpeg.Encode(&encoded, img, nil))
+
+	// TIFF（big-endian）：IFD0 只有方向一個欄位，後面附上模擬的 GPS 文字
+	var tiff bytes.Buffer
+	tiff.WriteString("MM\x00\x2a")
+	binary.Write(&tiff, binary.BigEndian, uint32(8))
+	binary.Write(&tiff, binary.BigEndian, uint16(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{0x0112, 3})
+	binary.Write(&tiff, binary.BigEndian, uint32(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{orientation, 0})
+	binary.Write(&tiff, binary.BigEndian, uint32(0))
+	tiff.WriteString("GPSLatitude=25.0330N;GPSLongitude=121.5654E")
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	var out bytes.Buffer
+	out.Write(encoded.Bytes()[:2]) // SOI
+	out.Write([]byte{0xFF, 0xE1})
+	binary.Write(&out, binary.BigEndian, uint16(len(segment)+2))
+	out.Write(segment)
+	out.Write(encoded.Bytes()[2:])
+	return out.Bytes()
+}
+
+// TestPhotoUploadStripsMetadata 測試上傳照片會套用方向、移除 EXIF 並產生縮圖
+func TestPhotoUploadStripsMetadata(t *testing.T) {
+	ctx := context.Background()
+	service, photos, uploadDir := setupPhotoUpload(t, 0)
+
+	original := jpegWithExif(t, 1200, 800, 6)
+	require.Contains(t, string(original), "GPSLatitude")
+
+	photo, err := service.UploadPhoto(ctx, 5, &usecase.PhotoUpload{Data: original})
+	require.NoError(t, err)
+	assert.Equal(t, entity.PhotoTypeProfile, photo.Type)
+	assert.Equal(t, "image/jpeg", photo.MimeType)
+	assert.Equal(t, 800, photo.Width, "方向 6 需順時針旋轉，寬高互換")
+	assert.Equal(t, 1200, photo.Height)
+	assert.False(t, photo.IsMain, "待審核的照片不可作為主要照片")
+	assert.Equal(t, entity.PhotoStatusPending, photo.Status)
+	assert.True(t, strings.HasPrefix(photo.FilePath, "photos/5/"))
+	require.Len(t, photo.Thumbnails, len(entity.ThumbnailSizes))
+	assert.True(t, strings.HasPrefix(photo.URL, "http://localhost:8080/api/files/photos/5/"), "回應附上簽名下載連結")
+	assert.Contains(t, photo.ThumbnailURLs[entity.ThumbnailSmall], "signature=")
+
+	for _, path := range photo.FilePaths() {
+		data, err := os.ReadFile(filepath.Join(uploadDir, path))
+		require.NoError(t, err, path)
+		assert.NotContains(t, string(data), "Exif", "重新編碼後不保留 EXIF")
+		assert.NotContains(t, string(data), "GPSLatitude")
+	}
+
+	stored, err := os.ReadFile(filepath.Join(uploadDir, photo.FilePath))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(stored)), photo.FileSize)
+
+	small, err := os.Open(filepath.Join(uploadDir, photo.Thumbnails[entity.ThumbnailSmall]))
+	require.NoError(t, err)
+	defer small.Close()
+	config, err := jpeg.DecodeConfig(small)
+	require.NoError(t, err)
+	assert.Equal(t, 160, config.Height, "縮圖最長邊符合尺寸設定")
+	assert.Equal(t, 106, config.Width)
+
+	// 沒有暫存檔殘留
+	entries, err := os.ReadDir(filepath.Join(uploadDir, "photos", "5"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1+len(entity.ThumbnailSizes))
+
+	// 刪除照片同時刪除原圖與縮圖
+	require.NoError(t, service.DeletePhoto(ctx, 5, photo.ID))
+	assert.Empty(t, photos.photos)
+	entries, err = os.ReadDir(filepath.Join(uploadDir, "photos", "5"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+// TestPhotoUploadRejectsInvalidContent 測試以檔案內容判斷格式，並拒絕過大、損毀與超過數量的照片
+func TestPhotoUploadRejectsInvalidContent(t *testing.T) {
+	ctx := context.Background()
+	service, photos, uploadDir := setupPhotoUpload(t, 64<<10)
+
+	var pngData bytes.Buffer
+	require.NoError(t, png.Encode(&pngData, image.NewNRGBA(image.Rect(0, 0, 40, 30))))
+
+	rejected := map[string]struct {
+		data []byte
+		err  error
+	}{
+		"空檔案":      {nil, usecase.ErrPhotoEmpty},
+		"偽裝成圖片的文字": {[]byte("<html><script>alert(1)</script></html>"), usecase.ErrUnsupportedImageType},
+		"PDF":      {[]byte("%PDF-1.7\n1 0 obj\n"), usecase.ErrUnsupportedImageType},
+		"超過大小上限":   {append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64<<10)...), usecase.ErrPhotoTooLarge},
+		"損毀的 PNG":  {pngData.Bytes()[:40], usecase.ErrInvalidImage},
+	}
+	for reason, tc := range rejected {
+		_, err := service.UploadPhoto(ctx, 9, &usecase.PhotoUpload{Data: tc.data})
+		assert.ErrorIs(t, err, tc.err, reason)
+	}
+	assert.Empty(t, photos.photos)
+	_, err := os.Stat(filepath.Join(uploadDir, "photos"))
+	assert.True(t, os.IsNotExist(err), "驗證失敗時不寫入任何檔案")
+
+	// PNG 保留 PNG 格式
+	photo, err := service.UploadPhoto(ctx, 9, &usecase.PhotoUpload{Data: pngData.Bytes(), Type: entity.PhotoTypeGallery})
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", photo.MimeType)
+	assert.Equal(t, entity.PhotoTypeGallery, photo.Type)
+	assert.Equal(t, 40, photo.Width)
+
+	for i := 1; i < 6; i++ {
+		_, err = service.UploadPhoto(ctx, 9, &usecase.PhotoUpload{Data: pngData.Bytes()})
+		require.NoError(t, err)
+	}
+	_, err = service.UploadPhoto(ctx, 9, &usecase.PhotoUpload{Data: pngData.Bytes()})
+	assert.ErrorIs(t, err, usecase.ErrPhotoLimitReached)
+}
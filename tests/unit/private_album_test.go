@@ -0,0 +1,234 @@
+package unit_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/infrastructure/imaging"
+	"golang_dev_docker/infrastructure/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryPrivateAlbumRepository 測試用私人相簿授權儲存庫
+type memoryPrivateAlbumRepository struct {
+	grants []*entity.PrivateAlbumGrant
+}
+
+func (r *memoryPrivateAlbumRepository) Grant(ctx context.Context, grant *entity.PrivateAlbumGrant) error {
+	if ok, _ := r.HasAccess(ctx, grant.OwnerID, grant.ViewerID); ok {
+		return nil
+	}
+	grant.ID = uint(len(r.grants) + 1)
+	r.grants = append(r.grants, grant)
+	return nil
+}
+
+func (r *memoryPrivateAlbumRepository) Revoke(ctx context.Context, ownerID, viewerID uint) error {
+	kept := r.grants[:0]
+	for _, grant := range r.grants {
+		if grant.OwnerID != ownerID || grant.ViewerID != viewerID {
+			kept = append(kept, grant)
+		}
+	}
+	r.grants = kept
+	return nil
+}
+
+func (r *memoryPrivateAlbumRepository) RevokeBetween(ctx context.Context, user1ID, user2ID uint) error {
+	r.Revoke(ctx, user1ID, user2ID)
+	return r.Revoke(ctx, user2ID, user1ID)
+}
+
+func (r *memoryPrivateAlbumRepository) HasAccess(ctx context.Context, ownerID, viewerID uint) (bool, error) {
+	for _, grant := range r.grants {
+		if grant.OwnerID == ownerID && grant.ViewerID == viewerID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryPrivateAlbumRepository) GetByOwnerID(ctx context.Context, ownerID uint) ([]*entity.PrivateAlbumGrant, error) {
+	var grants []*entity.PrivateAlbumGrant
+	for _, grant := range r.grants {
+		if grant.OwnerID == ownerID {
+			grants = append(grants, grant)
+		}
+	}
+	return grants, nil
+}
+
+// memoryMatchRepository 測試用配對儲存庫，只保存配對記錄
+type memoryMatchRepository struct {
+	matches []*entity.Match
+}
+
+func (r *memoryMatchRepository) CreateSwipe(ctx context.Context, match *entity.Match) error {
+	match.ID = uint(len(r.matches) + 1)
+	r.matches = append(r.matches, match)
+	return nil
+}
+
+func (r *memoryMatchRepository) GetMatch(ctx context.Context, user1ID, user2ID uint) (*entity.Match, error) {
+	for _, match := range r.matches {
+		if (match.User1ID == user1ID && match.User2ID == user2ID) || (match.User1ID == user2ID && match.User2ID == user1ID) {
+			return match, nil
+		}
+	}
+	return nil, errors.New("配對記錄不存在")
+}
+
+func (r *memoryMatchRepository) GetMatchByID(ctx context.Context, id uint) (*entity.Match, error) {
+	for _, match := range r.matches {
+		if match.ID == id {
+			return match, nil
+		}
+	}
+	return nil, errors.New("配對記錄不存在")
+}
+
+func (r *memoryMatchRepository) UpdateMatchStatus(ctx context.Context, matchID uint, status entity.MatchStatus) error {
+	match, err := r.GetMatchByID(ctx, matchID)
+	if err != nil {
+		return err
+	}
+	match.Status = status
+	return nil
+}
+
+func (r *memoryMatchRepository) ProcessSwipe(ctx context.Context, userID, targetUserID uint, action entity.SwipeAction) (*entity.Match, bool, error) {
+	return nil, false, errors.New("not implemented")
+}
+
+func (r *memoryMatchRepository) GetUserMatches(ctx context.Context, userID uint, status entity.MatchStatus) ([]*entity.Match, error) {
+	var matches []*entity.Match
+	for _, match := range r.matches {
+		if (match.User1ID == userID || match.User2ID == userID) && match.Status == status {
+			matches = append(matches, match)
+		}
+	}
+	return matches, nil
+}
+
+func (r *memoryMatchRepository) GetMatchedUsers(ctx context.Context, userID uint) ([]*entity.User, error) {
+	return nil, nil
+}
+
+func (r *memoryMatchRepository) HasUserSwiped(ctx context.Context, userID, targetUserID uint) (bool, error) {
+	match, err := r.GetMatch(ctx, userID, targetUserID)
+	return err == nil && match != nil, nil
+}
+
+func (r *memoryMatchRepository) Delete(ctx context.Context, id uint) error {
+	return nil
+}
+
+var _ repository.MatchRepository = (*memoryMatchRepository)(nil)
+
+// openPhotoLink 以簽名連結中的參數下載檔案
+func openPhotoLink(t *testing.T, files *usecase.FileService, link string) error {
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	expires, err := strconv.ParseInt(parsed.Query().Get("expires"), 10, 64)
+	require.NoError(t, err)
+	viewer, _ := strconv.ParseUint(parsed.Query().Get("viewer"), 10, 32)
+
+	key := strings.TrimPrefix(parsed.Path, "/api/files/")
+	reader, err := files.OpenSigned(context.Background(), key, expires, uint(viewer), parsed.Query().Get("signature"))
+	if err == nil {
+		reader.Close()
+	}
+	return err
+}
+
+// TestPrivateAlbumAccess 測試私人相簿只有獲得授權的配對對象可查看，下載連結綁定查看者，取消配對後授權與既有連結一併失效
+func TestPrivateAlbumAccess(t *testing.T) {
+	ctx := context.Background()
+	users := newMemoryUserRepository()
+	photos := newMemoryPhotoRepository()
+	matches := &memoryMatchRepository{}
+	grants := &memoryPrivateAlbumRepository{}
+
+	albums := usecase.NewPrivateAlbumService(grants, matches, users)
+	files := usecase.NewFileService(storage.NewLocalBlobStore(t.TempDir()), "test-secret", "http://localhost:8080", 0)
+	files.AddAccessChecker(albums)
+	service := usecase.NewUserService(users, nil, photos, nil, nil)
+	service.SetPhotoProcessing(imaging.NewProcessor(imaging.DefaultConfig()), files, 0)
+	service.SetPrivateAlbums(albums)
+	matching := usecase.NewMatchingService(matches, nil, users, nil)
+	matching.SetPrivateAlbums(albums)
+
+	owner := createUserWithRole(t, users, "owner@example.com", entity.RoleUser)
+	friend := createUserWithRole(t, users, "friend@example.com", entity.RoleUser)
+	stranger := createUserWithRole(t, users, "stranger@example.com", entity.RoleUser)
+	moderator := createUserWithRole(t, users, "mod@example.com", entity.RoleModerator)
+	require.NoError(t, matches.CreateSwipe(ctx, &entity.Match{User1ID: owner.ID, User2ID: friend.ID, Status: entity.MatchStatusMatched}))
+
+	data := encodePNGBytes(t, wavePattern(200, 150, false))
+	_, err := service.UploadPhoto(ctx, owner.ID, &usecase.PhotoUpload{Data: data, Type: entity.PhotoTypePrivate})
+	require.NoError(t, err)
+	_, err = service.UploadPhoto(ctx, owner.ID, &usecase.PhotoUpload{Data: data})
+	require.NoError(t, err)
+	private := photos.photos[1]
+	assert.True(t, strings.HasPrefix(private.FilePath, "private/"), "私人照片存放於獨立路徑")
+	assert.False(t, private.IsMain, "私人照片不可作為主要照片")
+	for _, photo := range photos.photos {
+		photo.Status = entity.PhotoStatusApproved
+	}
+
+	_, err = albums.GrantAccess(ctx, owner.ID, stranger.ID)
+	assert.ErrorIs(t, err, usecase.ErrPrivateAlbumNotMatched, "只能授權配對中的用戶")
+	_, err = albums.GrantAccess(ctx, owner.ID, owner.ID)
+	assert.Error(t, err)
+
+	visible, err := service.GetVisiblePhotos(ctx, owner.ID, friend.ID)
+	require.NoError(t, err)
+	assert.Len(t, visible, 1, "未授權時看不到私人照片")
+
+	_, err = albums.GrantAccess(ctx, owner.ID, friend.ID)
+	require.NoError(t, err)
+	_, err = albums.GrantAccess(ctx, owner.ID, friend.ID)
+	require.NoError(t, err, "重複授權視為成功")
+	list, err := albums.ListGrants(ctx, owner.ID)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	visible, err = service.GetVisiblePhotos(ctx, owner.ID, friend.ID)
+	require.NoError(t, err)
+	require.Len(t, visible, 2)
+	link := visible[0].URL
+	assert.Contains(t, link, "viewer="+strconv.Itoa(int(friend.ID)))
+	assert.NoError(t, openPhotoLink(t, files, link))
+	assert.NoError(t, openPhotoLink(t, files, visible[0].ThumbnailURLs[entity.ThumbnailSmall]))
+
+	// 竄改查看者使簽名失效，未獲授權的查看者即使有簽名連結也無法下載
+	tampered := strings.Replace(link, "viewer="+strconv.Itoa(int(friend.ID)), "viewer="+strconv.Itoa(int(stranger.ID)), 1)
+	assert.ErrorIs(t, openPhotoLink(t, files, tampered), usecase.ErrInvalidFileLink)
+	assert.ErrorIs(t, openPhotoLink(t, files, files.SignedURLFor(ctx, private.FilePath, stranger.ID)), usecase.ErrFileAccessDenied)
+	assert.NoError(t, openPhotoLink(t, files, files.SignedURLFor(ctx, private.FilePath, moderator.ID)), "審核人員可下載以審核照片")
+	assert.Empty(t, files.SignedURL(ctx, private.FilePath), "私人照片必須綁定查看者")
+
+	public, err := service.GetPublicPhotos(ctx, owner.ID)
+	require.NoError(t, err)
+	assert.Len(t, public, 1)
+
+	// 取消配對後授權撤銷，已簽發的連結也立即失效
+	require.NoError(t, matching.UnmatchUser(ctx, friend.ID, owner.ID))
+	assert.Empty(t, grants.grants)
+	assert.ErrorIs(t, openPhotoLink(t, files, link), usecase.ErrFileAccessDenied)
+
+	// 重新配對不會恢復授權
+	matches.matches[0].Status = entity.MatchStatusMatched
+	visible, err = service.GetVisiblePhotos(ctx, owner.ID, friend.ID)
+	require.NoError(t, err)
+	assert.Len(t, visible, 1)
+}
@@ -0,0 +1,155 @@
+package unit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAttributePreferenceRepository 以記憶體保存屬性配對條件
+type memoryAttributePreferenceRepository struct {
+	preferences map[uint][]*entity.AttributePreference
+}
+
+func (r *memoryAttributePreferenceRepository) GetByUserID(ctx context.Context, userID uint) ([]*entity.AttributePreference, error) {
+	return r.preferences[userID], nil
+}
+
+func (r *memoryAttributePreferenceRepository) ReplaceForUser(ctx context.Context, userID uint, preferences []*entity.AttributePreference) error {
+	r.preferences[userID] = preferences
+	return nil
+}
+
+// TestProfileAttributeVisibility 測試隱藏的屬性不會公開，也不會符合他人的條件
+func TestProfileAttributeVisibility(t *testing.T) {
+	attributes := entity.ProfileAttributes{
+		Height:           172,
+		Education:        "master",
+		Religion:         "buddhist",
+		Smoking:          "never",
+		Languages:        entity.StringList{"zh-tw", "en"},
+		HiddenAttributes: entity.StringList{string(entity.AttributeReligion)},
+	}
+	require.NoError(t, attributes.Validate())
+
+	public := attributes.Public()
+	assert.Equal(t, 172, public.Height)
+	assert.Empty(t, public.Religion, "隱藏的屬性不公開")
+	assert.Equal(t, entity.StringList{"zh-tw", "en"}, public.Languages)
+
+	religion := &entity.AttributePreference{Attribute: entity.AttributeReligion, Values: entity.StringList{"buddhist"}}
+	assert.False(t, religion.Accepts(&attributes), "隱藏的屬性不符合條件")
+
+	height := &entity.AttributePreference{Attribute: entity.AttributeHeight, MinHeight: 165, MaxHeight: 185}
+	assert.True(t, height.Accepts(&attributes))
+	assert.False(t, height.Accepts(&entity.ProfileAttributes{}), "未填寫的屬性不符合條件")
+
+	languages := &entity.AttributePreference{Attribute: entity.AttributeLanguages, Values: entity.StringList{"ja", "en"}}
+	assert.True(t, languages.Accepts(&attributes), "任一語言相符即可")
+
+	invalid := []entity.ProfileAttributes{
+		{Height: 300},
+		{Education: "wizard"},
+		{Languages: entity.StringList{"english!"}},
+		{HiddenAttributes: entity.StringList{"salary"}},
+	}
+	for _, attrs := range invalid {
+		assert.Error(t, attrs.Validate(), "%+v", attrs)
+	}
+}
+
+// TestAttributePreferenceScore 測試只有非硬性條件計入相容性分數
+func TestAttributePreferenceScore(t *testing.T) {
+	attributes := &entity.ProfileAttributes{Smoking: "never", Drinking: "regularly", Children: "want"}
+	preferences := []*entity.AttributePreference{
+		{Attribute: entity.AttributeSmoking, Values: entity.StringList{"never"}},
+		{Attribute: entity.AttributeDrinking, Values: entity.StringList{"never", "socially"}},
+		{Attribute: entity.AttributeChildren, Values: entity.StringList{"dont_want"}, Dealbreaker: true},
+	}
+
+	score, ok := entity.AttributePreferenceScore(preferences, attributes)
+	require.True(t, ok)
+	assert.InDelta(t, 0.5, score, 0.001)
+
+	_, ok = entity.AttributePreferenceScore(preferences[2:], attributes)
+	assert.False(t, ok, "只有硬性條件時不計分")
+}
+
+// TestUpdateProfileAttributesAndPreferences 測試更新檔案屬性與配對條件
+func TestUpdateProfileAttributesAndPreferences(t *testing.T) {
+	ctx := context.Background()
+	users := newMemoryUserRepository()
+	user := &entity.User{Email: "fay@example.com", BirthDate: time.Date(1994, 3, 1, 0, 0, 0, 0, time.UTC), IsActive: true}
+	require.NoError(t, users.Create(ctx, user))
+
+	profiles := &memoryProfileRepository{profiles: map[uint]*entity.UserProfile{
+		user.ID: {UserID: user.ID, DisplayName: "Fay", Gender: entity.GenderFemale, MaxDistance: 50, AgeRangeMin: 18, AgeRangeMax: 99},
+	}}
+	service := usecase.NewUserService(users, profiles, nil, nil, nil)
+
+	_, err := service.GetAttributePreferences(ctx, user.ID)
+	assert.ErrorIs(t, err, usecase.ErrAttributePreferencesUnavailable)
+
+	preferences := &memoryAttributePreferenceRepository{preferences: map[uint][]*entity.AttributePreference{}}
+	service.SetAttributePreferenceRepository(preferences)
+	cache := &recordingMatchingCache{}
+	service.SetMatchingCache(cache)
+
+	response, err := service.UpdateProfile(ctx, user.ID, &usecase.UpdateProfileRequest{
+		Attributes: &entity.ProfileAttributes{
+			Height:           168,
+			Occupation:       "  Engineer ",
+			Languages:        entity.StringList{"EN", "zh-TW", "en"},
+			HiddenAttributes: entity.StringList{"Height"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Engineer", response.Profile.Attributes.Occupation)
+	assert.Equal(t, entity.StringList{"en", "zh-tw"}, response.Profile.Attributes.Languages)
+	assert.False(t, response.Profile.Attributes.IsVisible(entity.AttributeHeight))
+
+	_, err = service.UpdateProfile(ctx, user.ID, &usecase.UpdateProfileRequest{
+		Attributes: &entity.ProfileAttributes{Smoking: "sometimes"},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 168, profiles.profiles[user.ID].Attributes.Height, "驗證失敗時不變更")
+
+	saved, err := service.UpdateAttributePreferences(ctx, user.ID, &usecase.AttributePreferencesRequest{
+		Preferences: []*entity.AttributePreference{
+			{Attribute: entity.AttributeChildren, Values: entity.StringList{"want", "have"}, Dealbreaker: true},
+			{Attribute: entity.AttributeLanguages, Values: entity.StringList{"ZH-TW"}},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, saved, 2)
+	assert.Equal(t, []uint{user.ID, user.ID}, cache.invalidated, "檔案屬性與配對條件變更後都清除潛在配對快取")
+	assert.Equal(t, user.ID, saved[0].UserID)
+	assert.Equal(t, entity.StringList{"zh-tw"}, saved[1].Values)
+
+	rejected := []*entity.AttributePreference{
+		{Attribute: entity.AttributeOccupation, Values: entity.StringList{"engineer"}},
+		{Attribute: entity.AttributeHeight, MinHeight: 190, MaxHeight: 170},
+		{Attribute: entity.AttributeReligion},
+	}
+	for _, preference := range rejected {
+		_, err := service.UpdateAttributePreferences(ctx, user.ID, &usecase.AttributePreferencesRequest{
+			Preferences: []*entity.AttributePreference{preference},
+		})
+		assert.Error(t, err, preference.Attribute)
+	}
+
+	_, err = service.UpdateAttributePreferences(ctx, user.ID, &usecase.AttributePreferencesRequest{
+		Preferences: []*entity.AttributePreference{
+			{Attribute: entity.AttributeSmoking, Values: entity.StringList{"never"}},
+			{Attribute: entity.AttributeSmoking, Values: entity.StringList{"socially"}},
+		},
+	})
+	assert.Error(t, err, "同一屬性不可重複設定")
+	assert.Len(t, preferences.preferences[user.ID], 2, "驗證失敗時保留原條件")
+}
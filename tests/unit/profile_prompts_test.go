@@ -0,0 +1,248 @@
+package unit_test
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryPromptRepository 以記憶體保存檔案問答題目
+type memoryPromptRepository struct {
+	prompts map[uint]*entity.Prompt
+}
+
+func (r *memoryPromptRepository) GetAll(ctx context.Context, activeOnly bool) ([]*entity.Prompt, error) {
+	var prompts []*entity.Prompt
+	for _, prompt := range r.prompts {
+		if !activeOnly || prompt.IsActive {
+			prompts = append(prompts, prompt)
+		}
+	}
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].ID < prompts[j].ID })
+	return prompts, nil
+}
+
+func (r *memoryPromptRepository) GetByID(ctx context.Context, id uint) (*entity.Prompt, error) {
+	return r.prompts[id], nil
+}
+
+func (r *memoryPromptRepository) Create(ctx context.Context, prompt *entity.Prompt) error {
+	prompt.ID = uint(len(r.prompts) + 1)
+	r.prompts[prompt.ID] = prompt
+	return nil
+}
+
+func (r *memoryPromptRepository) Update(ctx context.Context, prompt *entity.Prompt) error {
+	r.prompts[prompt.ID] = prompt
+	return nil
+}
+
+// memoryProfilePromptRepository 以記憶體保存用戶回答，讀取時返回複本
+type memoryProfilePromptRepository struct {
+	answers map[uint]*entity.ProfilePrompt
+	nextID  uint
+}
+
+func (r *memoryProfilePromptRepository) GetByUserID(ctx context.Context, userID uint) ([]*entity.ProfilePrompt, error) {
+	var answers []*entity.ProfilePrompt
+	for _, answer := range r.answers {
+		if answer.UserID == userID {
+			copied := *answer
+			answers = append(answers, &copied)
+		}
+	}
+	sort.Slice(answers, func(i, j int) bool { return answers[i].DisplayOrder < answers[j].DisplayOrder })
+	return answers, nil
+}
+
+func (r *memoryProfilePromptRepository) GetByID(ctx context.Context, id uint) (*entity.ProfilePrompt, error) {
+	answer, ok := r.answers[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *answer
+	return &copied, nil
+}
+
+func (r *memoryProfilePromptRepository) ReplaceForUser(ctx context.Context, userID uint, prompts []*entity.ProfilePrompt) error {
+	for id, answer := range r.answers {
+		if answer.UserID == userID {
+			delete(r.answers, id)
+		}
+	}
+	for _, prompt := range prompts {
+		if prompt.ID == 0 {
+			r.nextID++
+			prompt.ID = r.nextID
+		}
+		prompt.UserID = userID
+		copied := *prompt
+		r.answers[prompt.ID] = &copied
+	}
+	return nil
+}
+
+func (r *memoryProfilePromptRepository) GetPending(ctx context.Context, limit int) ([]*entity.ProfilePrompt, error) {
+	var answers []*entity.ProfilePrompt
+	for _, answer := range r.answers {
+		if answer.Status == entity.PromptAnswerStatusPending {
+			answers = append(answers, answer)
+		}
+	}
+	return answers, nil
+}
+
+func (r *memoryProfilePromptRepository) UpdateReview(ctx context.Context, prompt *entity.ProfilePrompt) error {
+	copied := *prompt
+	r.answers[prompt.ID] = &copied
+	return nil
+}
+
+// setupPromptService 建立含三個題目（第三題已停用）的檔案問答服務
+func setupPromptService() (*usecase.PromptService, *memoryProfilePromptRepository, *memoryModerationRepository, *memoryUserRepository) {
+	prompts := &memoryPromptRepository{prompts: map[uint]*entity.Prompt{}}
+	for i, key := range []string{"ideal_weekend", "simple_pleasures", "retired_prompt"} {
+		_ = prompts.Create(context.Background(), &entity.Prompt{
+			Key:      key,
+			IsActive: i < 2,
+			Translations: []entity.PromptTranslation{
+				{Locale: "zh-tw", Text: "題目" + key},
+				{Locale: "en", Text: "Prompt " + key},
+			},
+		})
+	}
+
+	answers := &memoryProfilePromptRepository{answers: map[uint]*entity.ProfilePrompt{}}
+	logs := &memoryModerationRepository{}
+	users := newMemoryUserRepository()
+	return usecase.NewPromptService(prompts, answers, users, logs), answers, logs, users
+}
+
+// TestPromptLocalization 測試題目依語系顯示並回退到預設語系
+func TestPromptLocalization(t *testing.T) {
+	prompt := &entity.Prompt{Translations: []entity.PromptTranslation{
+		{Locale: "en", Text: "My ideal weekend is"},
+		{Locale: "zh-tw", Text: "我理想的週末是"},
+	}}
+	assert.Equal(t, "My ideal weekend is", prompt.Text("en"))
+	assert.Equal(t, "My ideal weekend is", prompt.Text("en-GB"), "找不到地區時使用主要語言")
+	assert.Equal(t, "我理想的週末是", prompt.Text("zh_TW"))
+	assert.Equal(t, "我理想的週末是", prompt.Text("ja"), "不支援的語系使用預設語系")
+
+	service, _, _, _ := setupPromptService()
+	created, err := service.CreatePrompt(context.Background(), &usecase.PromptRequest{
+		Key:          "green_flag",
+		Translations: map[string]string{"EN": "A green flag I look for", "zh-TW": "會讓我心動的加分項"},
+	})
+	require.NoError(t, err)
+	assert.True(t, created.IsActive)
+
+	listed, err := service.ListPrompts(context.Background(), "en-us")
+	require.NoError(t, err)
+	require.Len(t, listed, 3, "停用的題目不列出")
+	assert.Equal(t, "A green flag I look for", listed[2].Text)
+
+	_, err = service.CreatePrompt(context.Background(), &usecase.PromptRequest{Key: "empty"})
+	assert.Error(t, err, "至少需要一種語系")
+}
+
+// TestUpdateProfilePrompts 測試回答數量、長度與題目限制，以及修改後重新送審
+func TestUpdateProfilePrompts(t *testing.T) {
+	ctx := context.Background()
+	service, answers, logs, _ := setupPromptService()
+	const userID = 7
+
+	saved, err := service.UpdateProfilePrompts(ctx, userID, []usecase.ProfilePromptAnswer{
+		{PromptID: 2, Answer: " 熱咖啡和一本好書 "},
+		{PromptID: 1, Answer: "去山上走走"},
+	})
+	require.NoError(t, err)
+	require.Len(t, saved, 2)
+	assert.Equal(t, uint(2), saved[0].PromptID, "依傳入順序排列")
+	assert.Equal(t, "熱咖啡和一本好書", saved[0].Answer)
+	assert.Equal(t, entity.PromptAnswerStatusPending, saved[0].Status)
+	require.Len(t, logs.logs, 2, "新的回答送入審核")
+	assert.Equal(t, "prompt_answer", logs.logs[0].ContentType)
+
+	// 通過審核後，未修改的回答保留審核結果
+	for _, answer := range answers.answers {
+		answer.Status = entity.PromptAnswerStatusApproved
+	}
+	saved, err = service.UpdateProfilePrompts(ctx, userID, []usecase.ProfilePromptAnswer{
+		{PromptID: 1, Answer: "去山上走走"},
+		{PromptID: 2, Answer: "熱茶和一本好書"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entity.PromptAnswerStatusApproved, saved[0].Status)
+	assert.Equal(t, entity.PromptAnswerStatusPending, saved[1].Status, "修改後重新審核")
+	assert.Len(t, logs.logs, 3)
+
+	// 已停用題目的既有回答可保留，但不能新選
+	answers.answers[saved[0].ID].PromptID = 3
+	_, err = service.UpdateProfilePrompts(ctx, userID, []usecase.ProfilePromptAnswer{{PromptID: 3, Answer: "去山上走走"}})
+	assert.NoError(t, err)
+	_, err = service.UpdateProfilePrompts(ctx, 8, []usecase.ProfilePromptAnswer{{PromptID: 3, Answer: "新回答"}})
+	assert.ErrorIs(t, err, usecase.ErrPromptInactive)
+
+	rejected := map[string][]usecase.ProfilePromptAnswer{
+		"超過三題":  {{PromptID: 1, Answer: "a"}, {PromptID: 2, Answer: "b"}, {PromptID: 3, Answer: "c"}, {PromptID: 4, Answer: "d"}},
+		"題目重複":  {{PromptID: 1, Answer: "a"}, {PromptID: 1, Answer: "b"}},
+		"回答空白":  {{PromptID: 1, Answer: "   "}},
+		"回答過長":  {{PromptID: 1, Answer: strings.Repeat("好", 101)}},
+		"題目不存在": {{PromptID: 99, Answer: "a"}},
+	}
+	for reason, request := range rejected {
+		_, err := service.UpdateProfilePrompts(ctx, userID, request)
+		assert.Error(t, err, reason)
+	}
+	remaining, _ := answers.GetByUserID(ctx, userID)
+	assert.Len(t, remaining, 1, "驗證失敗時保留原回答")
+}
+
+// TestReviewPromptAnswer 測試回答審核需要內容審核權限，且不可審核自己的回答
+func TestReviewPromptAnswer(t *testing.T) {
+	ctx := context.Background()
+	service, answers, logs, users := setupPromptService()
+
+	author := createUserWithRole(t, users, "author@example.com", entity.RoleUser)
+	moderator := createUserWithRole(t, users, "mod@example.com", entity.RoleModerator)
+
+	saved, err := service.UpdateProfilePrompts(ctx, author.ID, []usecase.ProfilePromptAnswer{{PromptID: 1, Answer: "去海邊"}})
+	require.NoError(t, err)
+	answerID := saved[0].ID
+
+	_, err = service.ReviewAnswer(ctx, &usecase.ReviewPromptAnswerRequest{AnswerID: answerID, ReviewerID: author.ID, Approve: true})
+	assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+
+	_, err = service.ReviewAnswer(ctx, &usecase.ReviewPromptAnswerRequest{AnswerID: answerID, ReviewerID: moderator.ID})
+	assert.Error(t, err, "拒絕必須提供原因")
+
+	reviewed, err := service.ReviewAnswer(ctx, &usecase.ReviewPromptAnswerRequest{AnswerID: answerID, ReviewerID: moderator.ID, Notes: "包含聯絡方式"})
+	require.NoError(t, err)
+	assert.Equal(t, entity.PromptAnswerStatusRejected, reviewed.Status)
+	assert.Equal(t, entity.PromptAnswerStatusRejected, answers.answers[answerID].Status)
+
+	last := logs.logs[len(logs.logs)-1]
+	assert.Equal(t, "rejected", last.Action)
+	assert.Equal(t, &moderator.ID, last.ModeratorID)
+
+	// 檔案回應包含問答
+	profiles := &memoryProfileRepository{profiles: map[uint]*entity.UserProfile{
+		author.ID: {UserID: author.ID, DisplayName: "Author", Gender: entity.GenderFemale, MaxDistance: 50, AgeRangeMin: 18, AgeRangeMax: 99},
+	}}
+	userService := usecase.NewUserService(users, profiles, nil, nil, nil)
+	userService.SetProfilePromptRepository(answers)
+	response, err := userService.GetProfile(ctx, author.ID)
+	require.NoError(t, err)
+	require.Len(t, response.Profile.Prompts, 1)
+	assert.Equal(t, "去海邊", response.Profile.Prompts[0].Answer)
+	assert.WithinDuration(t, time.Now(), *response.Profile.Prompts[0].ReviewedAt, time.Minute)
+}
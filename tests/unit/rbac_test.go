@@ -0,0 +1,166 @@
+package unit_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// createUserWithRole 建立指定角色的測試用戶
+func createUserWithRole(t *testing.T, repo *memoryUserRepository, email string, role entity.UserRole) *entity.User {
+	user := &entity.User{
+		Email:     email,
+		BirthDate: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		IsActive:  true,
+		Role:      role,
+	}
+	require.NoError(t, repo.Create(context.Background(), user))
+	return user
+}
+
+// TestRolePermissions 測試各角色的權限配置
+func TestRolePermissions(t *testing.T) {
+	assert.False(t, entity.RoleUser.HasPermission(entity.PermissionReportReview))
+	assert.True(t, entity.RoleModerator.HasPermission(entity.PermissionReportReview))
+	assert.False(t, entity.RoleModerator.HasPermission(entity.PermissionRoleAssign))
+	assert.True(t, entity.RoleAdmin.HasPermission(entity.PermissionRoleAssign))
+	assert.False(t, entity.UserRole("root").HasPermission(entity.PermissionReportReview), "未知角色不應擁有任何權限")
+
+	var legacy entity.User
+	assert.Equal(t, entity.RoleUser, legacy.GetRole(), "未設定角色的舊資料視為一般用戶")
+}
+
+// TestAssignRoleHierarchy 測試角色指派只能向下進行
+func TestAssignRoleHierarchy(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryUserRepository()
+	authService, _, _ := newTestAuthService()
+	service := usecase.NewRoleService(repo, authService)
+
+	admin := createUserWithRole(t, repo, "admin@example.com", entity.RoleAdmin)
+	otherAdmin := createUserWithRole(t, repo, "admin2@example.com", entity.RoleAdmin)
+	moderator := createUserWithRole(t, repo, "mod@example.com", entity.RoleModerator)
+	member := createUserWithRole(t, repo, "member@example.com", entity.RoleUser)
+
+	// 審核員沒有指派角色的權限
+	assert.ErrorIs(t, service.AssignRole(ctx, moderator.ID, member.ID, entity.RoleModerator), usecase.ErrPermissionDenied)
+
+	// 管理員不能授予與自己同級的角色，也不能變更同級用戶
+	assert.ErrorIs(t, service.AssignRole(ctx, admin.ID, member.ID, entity.RoleAdmin), usecase.ErrPermissionDenied)
+	assert.ErrorIs(t, service.AssignRole(ctx, admin.ID, otherAdmin.ID, entity.RoleUser), usecase.ErrPermissionDenied)
+	assert.ErrorIs(t, service.AssignRole(ctx, admin.ID, admin.ID, entity.RoleSuperAdmin), usecase.ErrCannotChangeOwnRole)
+	assert.ErrorIs(t, service.AssignRole(ctx, admin.ID, member.ID, entity.UserRole("root")), usecase.ErrInvalidRole)
+
+	// 角色變更後撤銷該用戶既有的會話
+	session, err := authService.StartSession(ctx, member.ID, member.Email, string(entity.RoleUser), usecase.ClientInfo{})
+	require.NoError(t, err)
+
+	require.NoError(t, service.AssignRole(ctx, admin.ID, member.ID, entity.RoleModerator))
+	stored, _ := repo.GetByID(ctx, member.ID)
+	assert.Equal(t, entity.RoleModerator, stored.Role)
+
+	revoked, _ := authService.IsTokenRevoked("", session.SessionID)
+	assert.True(t, revoked, "角色變更後舊權杖應失效")
+}
+
+// TestBootstrapAdminOnlyOnce 測試初始管理員只在尚無管理員時建立
+func TestBootstrapAdminOnlyOnce(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryUserRepository()
+	authService, _, _ := newTestAuthService()
+	service := usecase.NewRoleService(repo, authService)
+
+	_, err := service.BootstrapAdmin(ctx, "root@example.com", "short")
+	assert.Error(t, err, "初始管理員密碼也需符合密碼規則")
+
+	created, err := service.BootstrapAdmin(ctx, "Root@Example.com", "bootstrap-password")
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	admin, err := repo.GetByEmail(ctx, "root@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, entity.RoleSuperAdmin, admin.Role)
+
+	// 已有管理員後，即使設定被竄改也不會再提升其他帳號
+	member := createUserWithRole(t, repo, "member@example.com", entity.RoleUser)
+	created, err = service.BootstrapAdmin(ctx, member.Email, "")
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	stored, _ := repo.GetByID(ctx, member.ID)
+	assert.Equal(t, entity.RoleUser, stored.Role)
+}
+
+// TestBootstrapAdminRequiresOwnership 測試已註冊的初始管理員信箱必須已驗證且密碼相符才會提升權限
+func TestBootstrapAdminRequiresOwnership(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryUserRepository()
+	authService, _, _ := newTestAuthService()
+	service := usecase.NewRoleService(repo, authService)
+
+	squatter := createUserWithRole(t, repo, "root@example.com", entity.RoleUser)
+	hash, err := bcrypt.GenerateFromPassword([]byte("attacker-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+	squatter.PasswordHash = string(hash)
+	squatter.EmailVerified = true
+
+	_, err = service.BootstrapAdmin(ctx, "root@example.com", "bootstrap-password")
+	assert.ErrorIs(t, err, usecase.ErrBootstrapNotOwned, "密碼與設定不符")
+
+	hash, err = bcrypt.GenerateFromPassword([]byte("bootstrap-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+	squatter.PasswordHash = string(hash)
+	squatter.EmailVerified = false
+	_, err = service.BootstrapAdmin(ctx, "root@example.com", "bootstrap-password")
+	assert.ErrorIs(t, err, usecase.ErrBootstrapNotOwned, "信箱尚未驗證")
+	stored, _ := repo.GetByID(ctx, squatter.ID)
+	assert.Equal(t, entity.RoleUser, stored.Role)
+
+	squatter.EmailVerified = true
+	created, err := service.BootstrapAdmin(ctx, "root@example.com", "bootstrap-password")
+	require.NoError(t, err)
+	assert.True(t, created)
+	stored, _ = repo.GetByID(ctx, squatter.ID)
+	assert.Equal(t, entity.RoleSuperAdmin, stored.Role)
+}
+
+// TestRequirePermissionMiddleware 測試權限中間件依 token 中的角色放行
+func TestRequirePermissionMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtAuth := middleware.NewJWTAuthMiddleware(newTestKeySet())
+
+	router := gin.New()
+	router.GET("/admin/reports",
+		jwtAuth.AuthMiddleware(),
+		jwtAuth.RequirePermission(entity.PermissionReportReview),
+		func(c *gin.Context) { c.Status(http.StatusOK) },
+	)
+
+	request := func(role string) int {
+		token, _, err := jwtAuth.IssueAccessToken(1, "user@example.com", role, "", time.Minute)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/reports", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, request("user"))
+	assert.Equal(t, http.StatusForbidden, request(""))
+	assert.Equal(t, http.StatusForbidden, request("root"))
+	assert.Equal(t, http.StatusOK, request("moderator"))
+	assert.Equal(t, http.StatusOK, request("admin"))
+}
@@ -0,0 +1,104 @@
+package unit_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListAndRevokeSession 測試列出裝置與撤銷單一會話
+func TestListAndRevokeSession(t *testing.T) {
+	ctx := context.Background()
+	service, _, blacklist := newTestAuthService()
+
+	phone, err := service.StartSession(ctx, 1, "user@example.com", "user", usecase.ClientInfo{IPAddress: "10.0.0.1", UserAgent: "phone"})
+	require.NoError(t, err)
+	laptop, err := service.StartSession(ctx, 1, "user@example.com", "user", usecase.ClientInfo{IPAddress: "10.0.0.2", UserAgent: "laptop"})
+	require.NoError(t, err)
+	_, err = service.StartSession(ctx, 2, "other@example.com", "user", usecase.ClientInfo{})
+	require.NoError(t, err)
+
+	sessions, err := service.ListSessions(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2, "只列出自己的會話")
+
+	for _, session := range sessions {
+		if session.SessionID == phone.SessionID {
+			assert.Equal(t, phone.TokenID, session.TokenID, "會話應綁定登入時簽發的 jti")
+			assert.Equal(t, "phone", session.UserAgent)
+		}
+	}
+
+	// 不能撤銷其他用戶的會話
+	assert.ErrorIs(t, service.RevokeSession(ctx, 2, phone.SessionID), usecase.ErrSessionNotFound)
+
+	require.NoError(t, service.RevokeSession(ctx, 1, phone.SessionID))
+	assert.True(t, blacklist[phone.TokenID], "撤銷會話時應將目前的存取權杖列入黑名單")
+
+	revoked, _ := service.IsTokenRevoked(phone.TokenID, phone.SessionID)
+	assert.True(t, revoked)
+	revoked, _ = service.IsTokenRevoked(laptop.TokenID, laptop.SessionID)
+	assert.False(t, revoked, "其他裝置不受影響")
+
+	_, err = service.Refresh(ctx, phone.RefreshToken)
+	assert.ErrorIs(t, err, usecase.ErrInvalidRefreshToken, "已撤銷會話的刷新權杖不能再使用")
+}
+
+// TestRefreshRebindsSessionToken 測試刷新後會話綁定新的存取權杖
+func TestRefreshRebindsSessionToken(t *testing.T) {
+	ctx := context.Background()
+	service, store, _ := newTestAuthService()
+
+	tokens, err := service.StartSession(ctx, 1, "user@example.com", "user", usecase.ClientInfo{})
+	require.NoError(t, err)
+
+	rotated, err := service.Refresh(ctx, tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, tokens.TokenID, rotated.TokenID)
+	assert.Equal(t, rotated.TokenID, store.sessions[tokens.SessionID].TokenID)
+}
+
+// TestRevokedSessionRejectedByMiddleware 測試撤銷的會話在 HTTP 與 WebSocket 認證都會被拒絕
+func TestRevokedSessionRejectedByMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	keys := newTestKeySet()
+	jwtAuth := middleware.NewJWTAuthMiddleware(keys)
+	wsAuth := middleware.NewWebSocketAuthMiddleware(keys)
+	service := usecase.NewAuthService(nil, jwtAuth, 15*time.Minute, 7*24*time.Hour)
+	service.SetSessionStore(newMemorySessionStore())
+	jwtAuth.SetRevocationChecker(service)
+	wsAuth.SetRevocationChecker(service)
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router := gin.New()
+	router.GET("/api/profile", jwtAuth.AuthMiddleware(), ok)
+	router.GET("/ws/chat", wsAuth.AuthenticateWebSocket(), ok)
+
+	request := func(path, token string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	tokens, err := service.StartSession(ctx, 1, "user@example.com", "user", usecase.ClientInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, request("/api/profile", tokens.AccessToken))
+	assert.Equal(t, http.StatusOK, request("/ws/chat", tokens.AccessToken))
+
+	require.NoError(t, service.RevokeSession(ctx, 1, tokens.SessionID))
+	assert.Equal(t, http.StatusUnauthorized, request("/api/profile", tokens.AccessToken))
+	assert.Equal(t, http.StatusUnauthorized, request("/ws/chat", tokens.AccessToken))
+}
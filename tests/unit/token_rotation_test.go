@@ -0,0 +1,210 @@
+package unit_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTokenIssuer 測試用存取權杖簽發器
+type fakeTokenIssuer struct {
+	count int
+}
+
+func (f *fakeTokenIssuer) IssueAccessToken(userID uint, email, role, sessionID string, duration time.Duration) (string, string, error) {
+	f.count++
+	tokenID := fmt.Sprintf("jti-%d", f.count)
+	return fmt.Sprintf("access-%d-%s", userID, tokenID), tokenID, nil
+}
+
+// memorySessionStore 測試用記憶體會話儲存
+type memorySessionStore struct {
+	sessions map[string]*usecase.AuthSession
+	tokens   map[string]*usecase.RefreshTokenRecord
+	used     map[string]bool
+}
+
+func newMemorySessionStore() *memorySessionStore {
+	return &memorySessionStore{
+		sessions: make(map[string]*usecase.AuthSession),
+		tokens:   make(map[string]*usecase.RefreshTokenRecord),
+		used:     make(map[string]bool),
+	}
+}
+
+func (m *memorySessionStore) CreateSession(session *usecase.AuthSession) error {
+	m.sessions[session.SessionID] = session
+	return nil
+}
+
+func (m *memorySessionStore) GetSession(sessionID string) (*usecase.AuthSession, error) {
+	session, ok := m.sessions[sessionID]
+	if !ok {
+		return nil, errors.New("會話不存在")
+	}
+	return session, nil
+}
+
+func (m *memorySessionStore) ListUserSessions(userID uint) ([]*usecase.AuthSession, error) {
+	var sessions []*usecase.AuthSession
+	for _, session := range m.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *memorySessionStore) TouchSession(userID uint, sessionID, tokenID string) error {
+	session, ok := m.sessions[sessionID]
+	if !ok {
+		return errors.New("會話不存在")
+	}
+	session.LastActive = time.Now()
+	if tokenID != "" {
+		session.TokenID = tokenID
+	}
+	return nil
+}
+
+func (m *memorySessionStore) DeleteSession(userID uint, sessionID string) error {
+	delete(m.sessions, sessionID)
+	return nil
+}
+
+func (m *memorySessionStore) DeleteAllUserSessions(userID uint) error {
+	for id, session := range m.sessions {
+		if session.UserID == userID {
+			delete(m.sessions, id)
+		}
+	}
+	return nil
+}
+
+func (m *memorySessionStore) DeleteOtherUserSessions(userID uint, keepSessionID string) error {
+	for id, session := range m.sessions {
+		if session.UserID == userID && id != keepSessionID {
+			delete(m.sessions, id)
+		}
+	}
+	return nil
+}
+
+func (m *memorySessionStore) StoreRefreshToken(tokenHash string, record *usecase.RefreshTokenRecord, ttl time.Duration) error {
+	m.tokens[tokenHash] = record
+	return nil
+}
+
+func (m *memorySessionStore) ConsumeRefreshToken(tokenHash string) (*usecase.RefreshTokenRecord, bool, error) {
+	record, ok := m.tokens[tokenHash]
+	if !ok {
+		return nil, false, errors.New("刷新權杖不存在")
+	}
+	firstUse := !m.used[tokenHash]
+	m.used[tokenHash] = true
+	return record, firstUse, nil
+}
+
+// memoryBlacklist 測試用記憶體黑名單
+type memoryBlacklist map[string]bool
+
+func (b memoryBlacklist) AddTokenToBlacklist(tokenID string, expiration time.Duration) error {
+	b[tokenID] = true
+	return nil
+}
+
+func (b memoryBlacklist) IsTokenBlacklisted(tokenID string) (bool, error) {
+	return b[tokenID], nil
+}
+
+func newTestAuthService() (*usecase.AuthService, *memorySessionStore, memoryBlacklist) {
+	store := newMemorySessionStore()
+	blacklist := memoryBlacklist{}
+	service := usecase.NewAuthService(nil, &fakeTokenIssuer{}, 15*time.Minute, 7*24*time.Hour)
+	service.SetSessionStore(store)
+	service.SetTokenBlacklist(blacklist)
+	return service, store, blacklist
+}
+
+// TestRefreshTokenRotation 測試刷新權杖輪換後舊權杖不可再用
+func TestRefreshTokenRotation(t *testing.T) {
+	ctx := context.Background()
+	service, _, _ := newTestAuthService()
+
+	tokens, err := service.StartSession(ctx, 1, "user@example.com", "user", usecase.ClientInfo{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.RefreshToken)
+
+	rotated, err := service.Refresh(ctx, tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, tokens.RefreshToken, rotated.RefreshToken, "每次刷新都應換發新的刷新權杖")
+	assert.Equal(t, tokens.SessionID, rotated.SessionID, "輪換後仍屬於同一會話")
+
+	_, err = service.Refresh(ctx, "not-a-real-token")
+	assert.ErrorIs(t, err, usecase.ErrInvalidRefreshToken)
+}
+
+// TestRefreshTokenReuseRevokesFamily 測試重複使用刷新權杖會撤銷整個權杖家族
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	service, _, _ := newTestAuthService()
+
+	tokens, err := service.StartSession(ctx, 1, "user@example.com", "user", usecase.ClientInfo{})
+	require.NoError(t, err)
+
+	rotated, err := service.Refresh(ctx, tokens.RefreshToken)
+	require.NoError(t, err)
+
+	// 攻擊者重放已輪換的舊權杖
+	_, err = service.Refresh(ctx, tokens.RefreshToken)
+	assert.ErrorIs(t, err, usecase.ErrRefreshTokenReused)
+
+	// 合法用戶手上的最新權杖也應一併失效
+	_, err = service.Refresh(ctx, rotated.RefreshToken)
+	assert.ErrorIs(t, err, usecase.ErrInvalidRefreshToken)
+
+	revoked, err := service.IsTokenRevoked("jti-2", rotated.SessionID)
+	require.NoError(t, err)
+	assert.True(t, revoked, "會話撤銷後其存取權杖應視為已撤銷")
+}
+
+// TestLogoutRevokesTokens 測試登出與全部登出
+func TestLogoutRevokesTokens(t *testing.T) {
+	ctx := context.Background()
+	service, _, blacklist := newTestAuthService()
+
+	first, err := service.StartSession(ctx, 1, "user@example.com", "user", usecase.ClientInfo{})
+	require.NoError(t, err)
+	second, err := service.StartSession(ctx, 1, "user@example.com", "user", usecase.ClientInfo{})
+	require.NoError(t, err)
+
+	// 登出目前會話只影響該會話
+	err = service.Logout(ctx, 1, first.SessionID, "jti-1", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, blacklist["jti-1"], "目前的存取權杖應列入黑名單")
+
+	revoked, _ := service.IsTokenRevoked("jti-1", first.SessionID)
+	assert.True(t, revoked)
+	revoked, _ = service.IsTokenRevoked("jti-2", second.SessionID)
+	assert.False(t, revoked, "其他會話不應受影響")
+
+	// 全部登出後所有會話都失效
+	err = service.LogoutAll(ctx, 1, "jti-2", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	revoked, _ = service.IsTokenRevoked("jti-3", second.SessionID)
+	assert.True(t, revoked)
+
+	_, err = service.Refresh(ctx, second.RefreshToken)
+	assert.ErrorIs(t, err, usecase.ErrInvalidRefreshToken)
+
+	// 未綁定會話的權杖一律拒絕
+	revoked, _ = service.IsTokenRevoked("jti-x", "")
+	assert.True(t, revoked)
+}
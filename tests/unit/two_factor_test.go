@@ -0,0 +1,228 @@
+package unit_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/usecase"
+	"golang_dev_docker/server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// totpAt 以 RFC 6238 獨立計算指定時間的驗證碼，模擬驗證器 App
+func totpAt(t *testing.T, secret string, at time.Time) string {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	require.NoError(t, err)
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(at.Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", value%1000000)
+}
+
+// setupTwoFactor 建立測試用的兩步驟驗證服務與認證服務
+func setupTwoFactor() (*usecase.TwoFactorService, *usecase.AuthService, *middleware.JWTAuthMiddleware, *memoryUserRepository) {
+	repo := newMemoryUserRepository()
+	twoFactor := usecase.NewTwoFactorService(repo, newMemoryTwoFactorRepository(), "Dating App")
+	jwtAuth := middleware.NewJWTAuthMiddleware(newTestKeySet())
+
+	authService, _, _ := newTestAuthService()
+	authService.SetTwoFactor(twoFactor, jwtAuth)
+	return twoFactor, authService, jwtAuth, repo
+}
+
+// enableTwoFactor 為用戶完成綁定，返回金鑰與恢復碼
+func enableTwoFactor(t *testing.T, service *usecase.TwoFactorService, userID uint) (string, []string) {
+	enrollment, err := service.BeginEnrollment(context.Background(), userID)
+	require.NoError(t, err)
+
+	codes, err := service.ConfirmEnrollment(context.Background(), userID, totpAt(t, enrollment.Secret, time.Now().Add(-30*time.Second)))
+	require.NoError(t, err)
+	return enrollment.Secret, codes
+}
+
+// TestTwoFactorEnrollment 測試綁定流程與 otpauth URI
+func TestTwoFactorEnrollment(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, repo := setupTwoFactor()
+	user := createUserWithRole(t, repo, "alice@example.com", entity.RoleUser)
+
+	enrollment, err := service.BeginEnrollment(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.ProvisioningURI, "otpauth://totp/Dating%20App:alice@example.com?")
+	assert.Contains(t, enrollment.ProvisioningURI, "secret="+enrollment.Secret)
+
+	_, err = service.ConfirmEnrollment(ctx, user.ID, "000000")
+	assert.ErrorIs(t, err, usecase.ErrInvalidTwoFactorCode)
+
+	codes, err := service.ConfirmEnrollment(ctx, user.ID, totpAt(t, enrollment.Secret, time.Now()))
+	require.NoError(t, err)
+	assert.Len(t, codes, 10)
+
+	_, err = service.BeginEnrollment(ctx, user.ID)
+	assert.ErrorIs(t, err, usecase.ErrTwoFactorAlreadyEnabled)
+
+	status, err := service.GetStatus(ctx, user.ID)
+	require.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.EqualValues(t, 10, status.RecoveryCodesRemaining)
+}
+
+// TestMFALoginCodesAreSingleUse 測試第二步驗證碼與恢復碼都只能使用一次
+func TestMFALoginCodesAreSingleUse(t *testing.T) {
+	ctx := context.Background()
+	service, authService, jwtAuth, repo := setupTwoFactor()
+	user := createUserWithRole(t, repo, "bob@example.com", entity.RoleUser)
+	secret, recoveryCodes := enableTwoFactor(t, service, user.ID)
+
+	mfaToken, err := jwtAuth.IssueMFAToken(user.ID, time.Minute)
+	require.NoError(t, err)
+
+	code := totpAt(t, secret, time.Now())
+	result, err := authService.CompleteMFALogin(ctx, mfaToken, code, usecase.ClientInfo{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Tokens.AccessToken)
+	assert.Equal(t, user.ID, result.User.ID)
+
+	_, err = authService.CompleteMFALogin(ctx, mfaToken, recoveryCodes[0], usecase.ClientInfo{})
+	assert.ErrorIs(t, err, usecase.ErrInvalidMFAToken, "暫時權杖只能成功使用一次")
+
+	issue := func() string {
+		token, err := jwtAuth.IssueMFAToken(user.ID, time.Minute)
+		require.NoError(t, err)
+		return token
+	}
+	_, err = authService.CompleteMFALogin(ctx, issue(), code, usecase.ClientInfo{})
+	assert.ErrorIs(t, err, usecase.ErrInvalidTwoFactorCode, "同一驗證碼不能重放")
+
+	_, err = authService.CompleteMFALogin(ctx, issue(), recoveryCodes[0], usecase.ClientInfo{})
+	require.NoError(t, err)
+	_, err = authService.CompleteMFALogin(ctx, issue(), recoveryCodes[0], usecase.ClientInfo{})
+	assert.ErrorIs(t, err, usecase.ErrInvalidTwoFactorCode, "恢復碼只能使用一次")
+
+	_, err = authService.CompleteMFALogin(ctx, "not-a-token", code, usecase.ClientInfo{})
+	assert.ErrorIs(t, err, usecase.ErrInvalidMFAToken)
+}
+
+// TestMFAFailuresAreThrottled 測試第二步驗證失敗依帳號累計延遲與鎖定，同一暫時權杖失敗過多次後作廢
+func TestMFAFailuresAreThrottled(t *testing.T) {
+	ctx := context.Background()
+	service, authService, jwtAuth, repo := setupTwoFactor()
+	user := createUserWithRole(t, repo, "carol@example.com", entity.RoleUser)
+	secret, _ := enableTwoFactor(t, service, user.ID)
+
+	store := newMemoryLoginAttemptStore()
+	attempts := &memoryLoginAttemptRepository{}
+	policy := testThrottlePolicy()
+	policy.AccountFreeAttempts = 100
+	policy.AccountLockAfter = 8
+	throttle := usecase.NewLoginThrottleService(attempts, repo, policy)
+	throttle.SetAttemptStore(store)
+	authService.SetLoginThrottle(throttle)
+	client := usecase.ClientInfo{IPAddress: "203.0.113.9"}
+
+	mfaToken, err := jwtAuth.IssueMFAToken(user.ID, time.Minute)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		_, err = authService.CompleteMFALogin(ctx, mfaToken, "000000", client)
+		assert.ErrorIs(t, err, usecase.ErrInvalidTwoFactorCode)
+	}
+	_, err = authService.CompleteMFALogin(ctx, mfaToken, totpAt(t, secret, time.Now()), client)
+	assert.ErrorIs(t, err, usecase.ErrInvalidMFAToken, "失敗過多次的暫時權杖作廢")
+	assert.Equal(t, entity.LoginFailureInvalidSecondFactor, attempts.attempts[0].FailureReason)
+
+	// 換新的暫時權杖仍累計同一帳號的失敗次數，達到門檻後即使驗證碼正確也拒絕
+	for i := 0; i < 3; i++ {
+		mfaToken, err = jwtAuth.IssueMFAToken(user.ID, time.Minute)
+		require.NoError(t, err)
+		_, err = authService.CompleteMFALogin(ctx, mfaToken, "000000", client)
+		assert.ErrorIs(t, err, usecase.ErrInvalidTwoFactorCode)
+	}
+	mfaToken, err = jwtAuth.IssueMFAToken(user.ID, time.Minute)
+	require.NoError(t, err)
+	_, err = authService.CompleteMFALogin(ctx, mfaToken, totpAt(t, secret, time.Now()), client)
+	assert.ErrorIs(t, err, usecase.ErrLoginLocked)
+
+	// 鎖定解除後成功登入清除帳號的失敗計數
+	store.expireLocks()
+	result, err := authService.CompleteMFALogin(ctx, mfaToken, totpAt(t, secret, time.Now()), client)
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Tokens.AccessToken)
+	assert.Zero(t, store.failures["mfa:user:1"])
+}
+
+// TestMFATokenCannotAccessAPI 測試暫時權杖不能當作存取權杖使用
+func TestMFATokenCannotAccessAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtAuth := middleware.NewJWTAuthMiddleware(newTestKeySet())
+
+	router := gin.New()
+	router.GET("/profile", jwtAuth.AuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	mfaToken, err := jwtAuth.IssueMFAToken(1, time.Minute)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/profile", nil)
+	req.Header.Set("Authorization", "Bearer "+mfaToken)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	accessToken, _, err := jwtAuth.IssueAccessToken(1, "user@example.com", "user", "", time.Minute)
+	require.NoError(t, err)
+	_, _, err = jwtAuth.ParseMFAToken(accessToken)
+	assert.Error(t, err, "存取權杖不能用於完成第二步驗證")
+}
+
+// TestStaffForcedTwoFactorEnrollment 測試管理人員被強制在登入時綁定驗證器
+func TestStaffForcedTwoFactorEnrollment(t *testing.T) {
+	ctx := context.Background()
+	service, authService, jwtAuth, repo := setupTwoFactor()
+	service.SetRequireForStaff(true)
+
+	moderator := createUserWithRole(t, repo, "mod@example.com", entity.RoleModerator)
+	member := createUserWithRole(t, repo, "member@example.com", entity.RoleUser)
+
+	challenge, enrollmentRequired, err := service.RequiresChallenge(ctx, member.ID, member.Role)
+	require.NoError(t, err)
+	assert.False(t, challenge, "一般用戶不受強制政策影響")
+	assert.False(t, enrollmentRequired)
+
+	challenge, enrollmentRequired, err = service.RequiresChallenge(ctx, moderator.ID, moderator.Role)
+	require.NoError(t, err)
+	assert.True(t, challenge)
+	assert.True(t, enrollmentRequired)
+
+	mfaToken, err := jwtAuth.IssueMFAToken(moderator.ID, time.Minute)
+	require.NoError(t, err)
+
+	_, err = authService.CompleteMFALogin(ctx, mfaToken, "123456", usecase.ClientInfo{})
+	assert.ErrorIs(t, err, usecase.ErrTwoFactorNotStarted)
+
+	enrollment, err := authService.BeginMFAEnrollment(ctx, mfaToken)
+	require.NoError(t, err)
+
+	result, err := authService.CompleteMFALogin(ctx, mfaToken, totpAt(t, enrollment.Secret, time.Now()), usecase.ClientInfo{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Tokens.AccessToken)
+	assert.Len(t, result.RecoveryCodes, 10, "完成強制綁定時返回恢復碼")
+
+	assert.ErrorIs(t, service.Disable(ctx, moderator.ID, result.RecoveryCodes[0]), usecase.ErrTwoFactorRequired)
+}
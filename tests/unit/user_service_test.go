@@ -11,6 +11,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	"golang_dev_docker/domain/entity"
+	"golang_dev_docker/domain/repository"
 	"golang_dev_docker/domain/usecase"
 )
 
@@ -55,6 +56,46 @@ func (m *MockUserRepository) SetVerified(ctx context.Context, id uint, verified
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) SetEmailVerified(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id uint, passwordHash string) error {
+	args := m.Called(ctx, id, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateRole(ctx context.Context, id uint, role entity.UserRole) error {
+	args := m.Called(ctx, id, role)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateAccountStatus(ctx context.Context, user *entity.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetExpiredSuspensions(ctx context.Context, now time.Time, limit int) ([]*entity.User, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]*entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateDeletionSchedule(ctx context.Context, user *entity.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetDueDeletions(ctx context.Context, now time.Time, afterID uint, limit int) ([]*entity.User, error) {
+	args := m.Called(ctx, now, afterID, limit)
+	return args.Get(0).([]*entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) CountByRoles(ctx context.Context, roles ...entity.UserRole) (int64, error) {
+	args := m.Called(ctx, roles)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockUserRepository) SetActive(ctx context.Context, id uint, active bool) error {
 	args := m.Called(ctx, id, active)
 	return args.Error(0)
@@ -131,13 +172,46 @@ func (m *MockPhotoRepository) Delete(ctx context.Context, id uint) error {
 	return args.Error(0)
 }
 
-func (m *MockPhotoRepository) SetAllNonPrimary(ctx context.Context, userID uint) error {
-	args := m.Called(ctx, userID)
+func (m *MockPhotoRepository) SetPrimary(ctx context.Context, userID, photoID uint) error {
+	args := m.Called(ctx, userID, photoID)
 	return args.Error(0)
 }
 
-func (m *MockPhotoRepository) SetPrimary(ctx context.Context, userID, photoID uint) error {
-	args := m.Called(ctx, userID, photoID)
+func (m *MockPhotoRepository) UpdateOrder(ctx context.Context, userID uint, photoOrders []struct {
+	PhotoID uint
+	Order   int
+}) error {
+	args := m.Called(ctx, userID, photoOrders)
+	return args.Error(0)
+}
+
+func (m *MockPhotoRepository) GetReviewQueue(ctx context.Context, params repository.PhotoQueueParams) ([]*entity.Photo, int64, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]*entity.Photo), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPhotoRepository) Claim(ctx context.Context, photoID, moderatorID uint, claimedAt, staleBefore time.Time) (bool, error) {
+	args := m.Called(ctx, photoID, moderatorID, claimedAt, staleBefore)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPhotoRepository) ReleaseClaim(ctx context.Context, photoID, moderatorID uint) error {
+	args := m.Called(ctx, photoID, moderatorID)
+	return args.Error(0)
+}
+
+func (m *MockPhotoRepository) UpdateReview(ctx context.Context, photo *entity.Photo) error {
+	args := m.Called(ctx, photo)
+	return args.Error(0)
+}
+
+func (m *MockPhotoRepository) FindSimilar(ctx context.Context, hash uint64, maxDistance int, excludeUserID uint, limit int) ([]*entity.Photo, error) {
+	args := m.Called(ctx, hash, maxDistance, excludeUserID, limit)
+	return args.Get(0).([]*entity.Photo), args.Error(1)
+}
+
+func (m *MockPhotoRepository) Flag(ctx context.Context, photoID uint, reason string, score float64) error {
+	args := m.Called(ctx, photoID, reason, score)
 	return args.Error(0)
 }
 
@@ -146,13 +220,34 @@ type MockInterestRepository struct {
 	mock.Mock
 }
 
-func (m *MockInterestRepository) Create(ctx context.Context, interest *entity.Interest) error {
-	args := m.Called(ctx, interest)
-	return args.Error(0)
+func (m *MockInterestRepository) GetAll(ctx context.Context, activeOnly bool) ([]*entity.Interest, error) {
+	args := m.Called(ctx, activeOnly)
+	return args.Get(0).([]*entity.Interest), args.Error(1)
+}
+
+func (m *MockInterestRepository) GetByID(ctx context.Context, id uint) (*entity.Interest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Interest), args.Error(1)
 }
 
-func (m *MockInterestRepository) GetAll(ctx context.Context) ([]*entity.Interest, error) {
-	args := m.Called(ctx)
+func (m *MockInterestRepository) GetByIDs(ctx context.Context, ids []uint) ([]*entity.Interest, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]*entity.Interest), args.Error(1)
+}
+
+func (m *MockInterestRepository) GetByName(ctx context.Context, name string) (*entity.Interest, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Interest), args.Error(1)
+}
+
+func (m *MockInterestRepository) Search(ctx context.Context, prefix string, limit int) ([]*entity.Interest, error) {
+	args := m.Called(ctx, prefix, limit)
 	return args.Get(0).([]*entity.Interest), args.Error(1)
 }
 
@@ -161,13 +256,23 @@ func (m *MockInterestRepository) GetByUserID(ctx context.Context, userID uint) (
 	return args.Get(0).([]*entity.Interest), args.Error(1)
 }
 
-func (m *MockInterestRepository) AddUserInterest(ctx context.Context, userID uint, interestID uint) error {
-	args := m.Called(ctx, userID, interestID)
+func (m *MockInterestRepository) SetUserInterests(ctx context.Context, userID uint, interestIDs []uint) error {
+	args := m.Called(ctx, userID, interestIDs)
+	return args.Error(0)
+}
+
+func (m *MockInterestRepository) Create(ctx context.Context, interest *entity.Interest) error {
+	args := m.Called(ctx, interest)
+	return args.Error(0)
+}
+
+func (m *MockInterestRepository) Update(ctx context.Context, interest *entity.Interest) error {
+	args := m.Called(ctx, interest)
 	return args.Error(0)
 }
 
-func (m *MockInterestRepository) RemoveUserInterest(ctx context.Context, userID uint, interestID uint) error {
-	args := m.Called(ctx, userID, interestID)
+func (m *MockInterestRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
@@ -199,6 +304,11 @@ func (m *MockAgeVerificationRepository) GetPendingVerifications(ctx context.Cont
 	return args.Get(0).([]*entity.AgeVerification), args.Error(1)
 }
 
+func (m *MockAgeVerificationRepository) SetVerificationStatus(ctx context.Context, userID uint, status entity.VerificationStatus, reviewerID *uint, notes string) error {
+	args := m.Called(ctx, userID, status, reviewerID, notes)
+	return args.Error(0)
+}
+
 // Test setup helper
 func setupUserService() (*usecase.UserService, *MockUserRepository, *MockUserProfileRepository, *MockPhotoRepository, *MockInterestRepository, *MockAgeVerificationRepository) {
 	userRepo := &MockUserRepository{}
@@ -213,15 +323,15 @@ func setupUserService() (*usecase.UserService, *MockUserRepository, *MockUserPro
 }
 
 func TestUserService_Register_Success(t *testing.T) {
-	service, userRepo, userProfileRepo, _, _, _ := setupUserService()
+	service, userRepo, userProfileRepo, _, _, ageVerificationRepo := setupUserService()
 	ctx := context.Background()
 
 	req := &usecase.RegisterRequest{
 		Email:       "test@example.com",
-		Password:    "password123",
+		Password:    "Lantern-Orchid-72",
 		BirthDate:   time.Now().AddDate(-20, 0, 0),
 		DisplayName: "Test User",
-		Gender:      "男",
+		Gender:      string(entity.GenderMale),
 		Biography:   "Test biography",
 	}
 
@@ -232,6 +342,7 @@ func TestUserService_Register_Success(t *testing.T) {
 		user.ID = 1 // Simulate database ID assignment
 	})
 	userProfileRepo.On("Create", ctx, mock.AnythingOfType("*entity.UserProfile")).Return(nil)
+	ageVerificationRepo.On("Create", ctx, mock.AnythingOfType("*entity.AgeVerification")).Return(nil)
 
 	// Execute
 	result, err := service.Register(ctx, req)
@@ -240,12 +351,16 @@ func TestUserService_Register_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, "test@example.com", result.Email)
-	assert.Equal(t, "Test User", result.DisplayName)
+	assert.Equal(t, "Test User", result.Profile.DisplayName)
+	assert.Equal(t, "Test biography", result.Profile.Bio)
 	assert.True(t, result.IsActive)
+	assert.False(t, result.EmailVerified)
+	assert.Equal(t, entity.RoleUser, result.Role)
 
 	// Verify all expectations
 	userRepo.AssertExpectations(t)
 	userProfileRepo.AssertExpectations(t)
+	ageVerificationRepo.AssertExpectations(t)
 }
 
 func TestUserService_Register_EmailAlreadyExists(t *testing.T) {
@@ -254,10 +369,10 @@ func TestUserService_Register_EmailAlreadyExists(t *testing.T) {
 
 	req := &usecase.RegisterRequest{
 		Email:       "existing@example.com",
-		Password:    "password123",
+		Password:    "Lantern-Orchid-72",
 		BirthDate:   time.Now().AddDate(-20, 0, 0),
 		DisplayName: "Test User",
-		Gender:      "男",
+		Gender:      string(entity.GenderMale),
 	}
 
 	existingUser := &entity.User{
@@ -274,7 +389,7 @@ func TestUserService_Register_EmailAlreadyExists(t *testing.T) {
 	// Assert
 	assert.Error(t, err)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "電子郵件已被使用")
+	assert.Contains(t, err.Error(), "已被註冊")
 
 	// Verify expectations
 	userRepo.AssertExpectations(t)
@@ -286,10 +401,10 @@ func TestUserService_Register_UnderageUser(t *testing.T) {
 
 	req := &usecase.RegisterRequest{
 		Email:       "minor@example.com",
-		Password:    "password123",
+		Password:    "Lantern-Orchid-72",
 		BirthDate:   time.Now().AddDate(-16, 0, 0), // 16 years old
 		DisplayName: "Minor User",
-		Gender:      "女",
+		Gender:      string(entity.GenderFemale),
 	}
 
 	// Execute
@@ -298,7 +413,7 @@ func TestUserService_Register_UnderageUser(t *testing.T) {
 	// Assert
 	assert.Error(t, err)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "必須年滿 18 歲")
+	assert.Contains(t, err.Error(), "必須年滿18歲")
 }
 
 func TestUserService_Login_Success(t *testing.T) {
@@ -306,7 +421,7 @@ func TestUserService_Login_Success(t *testing.T) {
 	ctx := context.Background()
 
 	password := "password123"
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
 
 	user := &entity.User{
 		ID:           1,
@@ -339,7 +454,7 @@ func TestUserService_Login_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, "test@example.com", result.Email)
-	assert.Equal(t, "Test User", result.DisplayName)
+	assert.Equal(t, "Test User", result.Profile.DisplayName)
 
 	// Verify expectations
 	userRepo.AssertExpectations(t)
@@ -350,7 +465,7 @@ func TestUserService_Login_InvalidPassword(t *testing.T) {
 	service, userRepo, _, _, _, _ := setupUserService()
 	ctx := context.Background()
 
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("correctpassword"), bcrypt.DefaultCost)
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("correctpassword"), bcrypt.MinCost)
 
 	user := &entity.User{
 		ID:           1,
@@ -372,9 +487,8 @@ func TestUserService_Login_InvalidPassword(t *testing.T) {
 	result, err := service.Login(ctx, req)
 
 	// Assert
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, usecase.ErrInvalidCredentials)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "電子郵件或密碼錯誤")
 
 	// Verify expectations
 	userRepo.AssertExpectations(t)
@@ -396,9 +510,8 @@ func TestUserService_Login_UserNotFound(t *testing.T) {
 	result, err := service.Login(ctx, req)
 
 	// Assert
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, usecase.ErrInvalidCredentials)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "電子郵件或密碼錯誤")
 
 	// Verify expectations
 	userRepo.AssertExpectations(t)
@@ -419,7 +532,7 @@ func TestUserService_GetProfile_Success(t *testing.T) {
 		UserID:      1,
 		DisplayName: "Test User",
 		Gender:      entity.GenderMale,
-		Biography:   "Test bio",
+		Bio:         "Test bio",
 	}
 
 	// Mock expectations
@@ -433,7 +546,7 @@ func TestUserService_GetProfile_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, "test@example.com", result.Email)
-	assert.Equal(t, "Test User", result.DisplayName)
+	assert.Equal(t, "Test User", result.Profile.DisplayName)
 	assert.Equal(t, 25, result.Age)
 
 	// Verify expectations
@@ -456,13 +569,13 @@ func TestUserService_UpdateProfile_Success(t *testing.T) {
 		UserID:      1,
 		DisplayName: "Old Name",
 		Gender:      entity.GenderMale,
-		Biography:   "Old bio",
+		Bio:         "Old bio",
 	}
 
+	displayName, biography := "New Name", "New bio"
 	req := &usecase.UpdateProfileRequest{
-		DisplayName: "New Name",
-		Biography:   "New bio",
-		Location:    "New Location",
+		DisplayName: &displayName,
+		Biography:   &biography,
 	}
 
 	// Mock expectations
@@ -476,68 +589,62 @@ func TestUserService_UpdateProfile_Success(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.Equal(t, "New Name", result.DisplayName)
+	assert.Equal(t, "New Name", result.Profile.DisplayName)
+	assert.Equal(t, "New bio", result.Profile.Bio)
 
 	// Verify expectations
 	userRepo.AssertExpectations(t)
 	userProfileRepo.AssertExpectations(t)
 }
 
-func TestUserService_AddPhoto_Success(t *testing.T) {
+func TestUserService_SetPrimaryPhoto_Success(t *testing.T) {
 	service, _, _, photoRepo, _, _ := setupUserService()
 	ctx := context.Background()
 
+	photo := &entity.Photo{
+		ID:     1,
+		UserID: 1,
+		Type:   entity.PhotoTypeProfile,
+		Status: entity.PhotoStatusApproved,
+	}
+
 	// Mock expectations
-	photoRepo.On("GetByUserID", ctx, uint(1)).Return([]*entity.Photo{}, nil)
-	photoRepo.On("Create", ctx, mock.AnythingOfType("*entity.Photo")).Return(nil).Run(func(args mock.Arguments) {
-		photo := args.Get(1).(*entity.Photo)
-		photo.ID = 1 // Simulate database ID assignment
-	})
+	photoRepo.On("GetByID", ctx, uint(1)).Return(photo, nil)
+	photoRepo.On("SetPrimary", ctx, uint(1), uint(1)).Return(nil)
 
 	// Execute
-	result, err := service.AddPhoto(ctx, 1, "http://example.com/photo.jpg", "Test photo")
+	err := service.SetPrimaryPhoto(ctx, 1, 1)
 
 	// Assert
 	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Equal(t, uint(1), result.UserID)
-	assert.Equal(t, "http://example.com/photo.jpg", result.ImageURL)
-	assert.Equal(t, "Test photo", result.Description)
-	assert.True(t, result.IsPrimary) // First photo should be primary
 
 	// Verify expectations
 	photoRepo.AssertExpectations(t)
 }
 
-func TestUserService_AddPhoto_ExceedsLimit(t *testing.T) {
+func TestUserService_SetPrimaryPhoto_Rejected(t *testing.T) {
 	service, _, _, photoRepo, _, _ := setupUserService()
 	ctx := context.Background()
 
-	// Create 10 existing photos (max limit)
-	existingPhotos := make([]*entity.Photo, 10)
-	for i := 0; i < 10; i++ {
-		existingPhotos[i] = &entity.Photo{
-			ID:     uint(i + 1),
-			UserID: 1,
-		}
-	}
+	pending := &entity.Photo{ID: 1, UserID: 1, Type: entity.PhotoTypeProfile, Status: entity.PhotoStatusPending}
+	othersPhoto := &entity.Photo{ID: 2, UserID: 2, Type: entity.PhotoTypeProfile, Status: entity.PhotoStatusApproved}
 
 	// Mock expectations
-	photoRepo.On("GetByUserID", ctx, uint(1)).Return(existingPhotos, nil)
-
-	// Execute
-	result, err := service.AddPhoto(ctx, 1, "http://example.com/photo.jpg", "Test photo")
+	photoRepo.On("GetByID", ctx, uint(1)).Return(pending, nil)
+	photoRepo.On("GetByID", ctx, uint(2)).Return(othersPhoto, nil)
+	photoRepo.On("GetByID", ctx, uint(3)).Return(nil, nil)
 
-	// Assert
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "最多只能上傳 10 張照片")
+	// Execute & Assert
+	assert.ErrorIs(t, service.SetPrimaryPhoto(ctx, 1, 1), usecase.ErrPhotoCannotBeMain)
+	assert.ErrorIs(t, service.SetPrimaryPhoto(ctx, 1, 2), usecase.ErrPhotoForbidden)
+	assert.ErrorIs(t, service.SetPrimaryPhoto(ctx, 1, 3), usecase.ErrPhotoNotFound)
 
 	// Verify expectations
 	photoRepo.AssertExpectations(t)
+	photoRepo.AssertNotCalled(t, "SetPrimary", mock.Anything, mock.Anything, mock.Anything)
 }
 
-func TestUserService_GetAvailableInterests_Success(t *testing.T) {
+func TestUserService_GetUserInterests_Success(t *testing.T) {
 	service, _, _, _, interestRepo, _ := setupUserService()
 	ctx := context.Background()
 
@@ -547,10 +654,10 @@ func TestUserService_GetAvailableInterests_Success(t *testing.T) {
 	}
 
 	// Mock expectations
-	interestRepo.On("GetAll", ctx).Return(interests, nil)
+	interestRepo.On("GetByUserID", ctx, uint(1)).Return(interests, nil)
 
 	// Execute
-	result, err := service.GetAvailableInterests(ctx)
+	result, err := service.GetUserInterests(ctx, 1)
 
 	// Assert
 	assert.NoError(t, err)
@@ -563,20 +670,17 @@ func TestUserService_GetAvailableInterests_Success(t *testing.T) {
 	interestRepo.AssertExpectations(t)
 }
 
-func TestUserService_SubmitAgeVerification_Success(t *testing.T) {
+func TestUserService_SubmitAgeVerification_UploadUnavailable(t *testing.T) {
 	service, _, _, _, _, ageVerificationRepo := setupUserService()
 	ctx := context.Background()
 
-	// Mock expectations
-	ageVerificationRepo.On("GetByUserID", ctx, uint(1)).Return((*entity.AgeVerification)(nil), errors.New("not found"))
-	ageVerificationRepo.On("Create", ctx, mock.AnythingOfType("*entity.AgeVerification")).Return(nil)
-
 	// Execute
-	err := service.SubmitAgeVerification(ctx, 1, entity.VerificationID, "A123456789", "/path/to/document.jpg")
+	result, err := service.SubmitAgeVerification(ctx, 1, entity.VerificationMethodID, "A123456789", []byte("%PDF-1.4"))
 
 	// Assert
-	assert.NoError(t, err)
+	assert.ErrorIs(t, err, usecase.ErrVerificationUploadUnavailable)
+	assert.Nil(t, result)
 
 	// Verify expectations
-	ageVerificationRepo.AssertExpectations(t)
+	ageVerificationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
 }